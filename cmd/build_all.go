@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/spf13/cobra"
+)
+
+var BuildAllManifest string
+var BuildAllWorkers int
+var BuildAllDryRun bool
+var BuildAllUpdate bool
+
+// newBuildAllCmd is used to generate the build-all command for the CLI
+func newBuildAllCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build-all",
+		Short: "Build the maps listed in a manifest.",
+		Long:  "Build the maps listed in a manifest (YAML or JSON), each map having its own mapping file or filter, name, layout and style. The maps are built concurrently using a single session on the Zabbix server and a summary of the status of each map is output to the shell. The command exit with an error if at least one map could not be built.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the manifest flag was set correctly.
+			if BuildAllManifest == "" {
+				return failure.Errorf(failure.Config, "'manifest' flag is required and cannot be empty")
+			}
+
+			if err := checkFile(BuildAllManifest); err != nil {
+				return err
+			}
+
+			if BuildAllWorkers < 0 {
+				return failure.Errorf(failure.Config, "'workers' flag cannot be negative")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			options.DryRun = BuildAllDryRun
+			options.Update = BuildAllUpdate
+			options.MetricsFile = MetricsFile
+			setHostLookupOptions(options)
+
+			return app.RunBuildAll(BuildAllManifest, BuildAllWorkers, options, os.Stdout, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVarP(&BuildAllManifest, "manifest", "m", "", "file (YAML or JSON) listing the maps to build")
+	cmd.Flags().IntVar(&BuildAllWorkers, "workers", 0, "number of maps built concurrently, the value of the manifest (or 4) is used if not set")
+	cmd.Flags().BoolVar(&BuildAllDryRun, "dry-run", false, "output to the shell the definition of the maps without creating them on the server")
+	cmd.Flags().BoolVar(&BuildAllUpdate, "update", false, "update the maps with the same name if they already exist instead of creating new maps")
+	addHostLookupFlags(cmd)
+	addMetricsFlags(cmd)
+	cmd.MarkFlagRequired("manifest")
+
+	return cmd
+}
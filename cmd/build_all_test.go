@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeBuildAllManifest is used to write a manifest building two maps from a mapping file linking router-1 and router-2.
+// The map 'batch-switches' cannot be built since no host matches its filter.
+func writeBuildAllManifest(t *testing.T) string {
+	dir := t.TempDir()
+
+	mapping := `[{"local_host": "router-1", "local_interface": "eth0", "local_trigger_pattern": "Interface eth0(): Link down", "local_image": "Router_(64)", "remote_host": "router-2", "remote_interface": "eth0", "remote_trigger_pattern": "Interface eth0(): Link down", "remote_image": "Router_(64)"}]`
+	if err := os.WriteFile(filepath.Join(dir, "mapping.json"), []byte(mapping), 0644); err != nil {
+		t.Fatalf("error while writing the mapping file.\nReason : %v", err)
+	}
+
+	manifest := `
+defaults:
+  file: mapping.json
+maps:
+  - name: batch-routers
+    filter:
+      include_hosts: ["router-*"]
+  - name: batch-switches
+    filter:
+      include_hosts: ["switch-*"]
+`
+	file := filepath.Join(dir, "manifest.yaml")
+	if err := os.WriteFile(file, []byte(manifest), 0644); err != nil {
+		t.Fatalf("error while writing the manifest.\nReason : %v", err)
+	}
+
+	return file
+}
+
+func TestNewBuildAllCmd(t *testing.T) {
+	cmd := newBuildAllCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteBuildAll(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "build-all", "--manifest", os.Getenv("MANIFEST_FILE"), "--workers", "2")
+		Execute()
+
+		return
+	}
+
+	server := newTestingServer(t)
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecuteBuildAll", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("MANIFEST_FILE=%s", writeBuildAllManifest(t)))
+	out, err := cmd.Output()
+
+	if err == nil {
+		t.Fatalf("an error should be returned when the build of a map failed")
+	}
+
+	exit := err.(*exec.ExitError)
+	if exit.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if !strings.Contains(string(out), "1 map(s) built, 1 failed") {
+		t.Fatalf("wrong summary returned.\nReturned : %s", string(out))
+	}
+
+	maps := server.Maps()
+	if len(maps) != 1 || maps[0].Name != "batch-routers" {
+		t.Fatalf("only the map 'batch-routers' should be created.\nReturned : %v", maps)
+	}
+
+	// A single session is used to build all the maps
+	if logins := server.Requests("user.login"); len(logins) != 1 {
+		t.Fatalf("wrong number of sessions opened.\nExpected : 1\nReturned : %d", len(logins))
+	}
+}
+
+func TestExecuteBuildAllFailMissingManifest(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "build-all", "--manifest", "missing.yaml")
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteBuildAllFailMissingManifest$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	err := cmd.Run()
+
+	if err == nil {
+		t.Fatalf("expected an error to be returned, an nil pointer was returned instead")
+	}
+
+	exit := err.(*exec.ExitError)
+	if exit.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+}
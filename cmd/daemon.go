@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/spf13/cobra"
+)
+
+var DaemonConfig string
+var DaemonOnce bool
+
+// newDaemonCmd is used to generate the daemon command for the CLI
+func newDaemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Reconcile the maps on a schedule.",
+		Long:  "Run as a long-lived service reconciling the maps of a manifest on a schedule. Each run executes the discovery commands regenerating the mapping files, then creates or updates the maps on the Zabbix server. A lock file prevents overlapping runs and a state file records the last successful sync of each map. The service stops on SIGTERM once the current run is completed.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the config flag was set correctly.
+			if DaemonConfig == "" {
+				return failure.Errorf(failure.Config, "'config' flag is required and cannot be empty")
+			}
+
+			return checkFile(DaemonConfig)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			options.MetricsFile = MetricsFile
+			setHostLookupOptions(options)
+
+			return app.RunDaemon(DaemonConfig, DaemonOnce, options, stopOnSignal(), GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVar(&DaemonConfig, "config", "", "file (YAML or JSON) containing the schedule, the discovery commands, the manifest, the state file and the lock file")
+	cmd.Flags().BoolVar(&DaemonOnce, "once", false, "execute a single run and exit (to be used with cron or systemd timers for example)")
+	addHostLookupFlags(cmd)
+	addMetricsFlags(cmd)
+	cmd.MarkFlagRequired("config")
+
+	return cmd
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// writeDaemonConfig is used to write a daemon configuration reconciling a single map every hour.
+// The discovery command copies the example mapping file to the mapping file of the manifest.
+func writeDaemonConfig(t *testing.T) string {
+	dir := t.TempDir()
+
+	manifest := "maps:\n  - name: daemon-map\n    file: mapping.json\n"
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("error while writing the manifest.\nReason : %v", err)
+	}
+
+	// The subprocesses are executed without PATH
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Fatalf("error while searching the 'cat' command.\nReason : %v", err)
+	}
+
+	config := fmt.Sprintf("interval: 1h\ndiscovery:\n  - command: [%s, %s]\n    output: mapping.json\nmanifest: manifest.yaml\nstate_file: state.json\nlock_file: daemon.lock\n", cat, mappingFilePath)
+	file := filepath.Join(dir, "daemon.yaml")
+	if err := os.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatalf("error while writing the daemon configuration.\nReason : %v", err)
+	}
+
+	return file
+}
+
+func TestNewDaemonCmd(t *testing.T) {
+	cmd := newDaemonCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteDaemonOnce(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "daemon", "--config", os.Getenv("DAEMON_CONFIG"), "--once", "--metrics-file", os.Getenv("METRICS_FILE"))
+		Execute()
+
+		return
+	}
+
+	server := newTestingServer(t)
+	config := writeDaemonConfig(t)
+
+	// Execute test in a subprocess
+	metrics := filepath.Join(filepath.Dir(config), "zabbix_map_builder.prom")
+	cmd := newDeleteSubprocess("TestExecuteDaemonOnce", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("DAEMON_CONFIG=%s", config), fmt.Sprintf("METRICS_FILE=%s", metrics))
+	_, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if maps := server.Maps(); len(maps) != 1 || maps[0].Name != "daemon-map" {
+		t.Fatalf("the map 'daemon-map' should be created.\nReturned : %v", maps)
+	}
+
+	b, err := os.ReadFile(filepath.Join(filepath.Dir(config), "state.json"))
+	if err != nil {
+		t.Fatalf("error while reading the state file.\nReason : %v", err)
+	}
+
+	if !strings.Contains(string(b), `"daemon-map"`) || !strings.Contains(string(b), `"last_success"`) {
+		t.Fatalf("the sync of the map should be recorded.\nReturned : %s", string(b))
+	}
+
+	// The calls to the fake server are recorded in the metrics file
+	b, err = os.ReadFile(metrics)
+	if err != nil {
+		t.Fatalf("error while reading the metrics file.\nReason : %v", err)
+	}
+
+	for _, line := range []string{
+		`zabbix_map_builder_api_calls_total{method="map.create",outcome="success"} 1`,
+		`zabbix_map_builder_builds_total{map="daemon-map",outcome="success"} 1`,
+		`zabbix_map_builder_last_success_timestamp_seconds{map="daemon-map"}`,
+	} {
+		if !strings.Contains(string(b), line) {
+			t.Fatalf("missing metric.\nExpected : %s\nReturned :\n%s", line, string(b))
+		}
+	}
+}
+
+func TestExecuteDaemonStop(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "daemon", "--config", os.Getenv("DAEMON_CONFIG"))
+		Execute()
+
+		return
+	}
+
+	server := newTestingServer(t)
+	config := writeDaemonConfig(t)
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecuteDaemonStop", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("DAEMON_CONFIG=%s", config))
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("error while starting the subprocess.\nReason : %v", err)
+	}
+
+	// Wait for the first run to complete
+	state := filepath.Join(filepath.Dir(config), "state.json")
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(state); err == nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			t.Fatalf("the first run was not completed in time")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("error while stopping the subprocess.\nReason : %v", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("expected exit code 0.\nError returned : %v", err)
+	}
+
+	lock, err := os.Open(filepath.Join(filepath.Dir(config), "daemon.lock"))
+	if err != nil {
+		t.Fatalf("error while opening the lock file.\nReason : %v", err)
+	}
+
+	defer lock.Close()
+
+	if err = syscall.Flock(int(lock.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		t.Fatalf("the lock should be released.\nReason : %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var DeleteYes bool
+var DeleteDryRun bool
+
+// newDeleteCmd is used to generate the delete command for the CLI
+func newDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name or id>...",
+		Short: "Delete maps from the Zabbix server.",
+		Long:  "Delete the maps matching the given names or ids. Each value is first searched as a map name, then as a map id. The deletion must be confirmed unless the '--yes' flag is set.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			options.Yes = DeleteYes
+			options.DryRun = DeleteDryRun
+
+			return app.RunDelete(args, options, os.Stdin, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&DeleteYes, "yes", "y", false, "delete the maps without asking for confirmation")
+	cmd.Flags().BoolVar(&DeleteDryRun, "dry-run", false, "output the maps that would be deleted without deleting them")
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fakeserver"
+)
+
+// createTestingMaps is used to create maps with the given names on the fake server.
+func createTestingMaps(t *testing.T, server *fakeserver.Server, names ...string) {
+	service, err := api.InitApi(server.ApiUrl(), ZABBIX_USER, ZABBIX_PWD)
+	if err != nil {
+		t.Fatalf("error while executing InitApi function.\nReason : %v", err)
+	}
+
+	client := api.NewClient(service)
+	for _, name := range names {
+		m := &zabbixgosdk.MapCreateParameters{}
+		m.Name = name
+		m.Width = "800"
+		m.Height = "800"
+
+		if _, err = client.CreateMap(m); err != nil {
+			t.Fatalf("error while creating the map '%s'.\nReason : %v", name, err)
+		}
+	}
+}
+
+// newDeleteSubprocess is used to prepare the execution of the given test in a subprocess using the given server.
+func newDeleteSubprocess(test string, server *fakeserver.Server, stdin string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], fmt.Sprintf("-test.run=%s$", test))
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	cmd.Stdin = strings.NewReader(stdin)
+
+	return cmd
+}
+
+func TestNewDeleteCmd(t *testing.T) {
+	cmd := newDeleteCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteDelete(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "delete", "site-paris", "2")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	createTestingMaps(t, server, "site-paris", "site-lyon", "core")
+
+	// Execute test in a subprocess, the deletion is confirmed
+	out, err := newDeleteSubprocess("TestExecuteDelete", server, "y\n").Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if !strings.Contains(string(out), "2 map(s) deleted") {
+		t.Fatalf("wrong output returned.\nReturned : %s", string(out))
+	}
+
+	maps := server.Maps()
+	if len(maps) != 1 || maps[0].Name != "core" {
+		t.Fatalf("wrong maps kept on the server.\nExpected : [core]\nReturned : %v", maps)
+	}
+}
+
+func TestExecuteDeleteNotConfirmed(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "delete", "site-paris")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	createTestingMaps(t, server, "site-paris")
+
+	// Execute test in a subprocess, the deletion is refused
+	_, err := newDeleteSubprocess("TestExecuteDeleteNotConfirmed", server, "n\n").Output()
+
+	if err == nil {
+		t.Fatalf("expected an error to be returned, an nil pointer was returned instead")
+	}
+
+	exit := err.(*exec.ExitError)
+	if exit.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if len(server.Maps()) != 1 {
+		t.Fatal("the map should not be deleted when the deletion is not confirmed")
+	}
+}
+
+func TestExecuteDeleteFailUnknownMap(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "delete", "unknown", "--yes")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+
+	// Execute test in a subprocess
+	_, err := newDeleteSubprocess("TestExecuteDeleteFailUnknownMap", server, "").Output()
+
+	if err == nil {
+		t.Fatalf("expected an error to be returned, an nil pointer was returned instead")
+	}
+
+	exit := err.(*exec.ExitError)
+	if exit.ExitCode() != 4 {
+		t.Fatalf("expected exit code 4.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if len(server.Requests("map.delete")) != 0 {
+		t.Fatal("no map.delete request should be sent when a map is not found")
+	}
+}
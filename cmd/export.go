@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/spf13/cobra"
+)
+
+var ExportMap string
+var ExportOutFile string
+var ExportLayout string
+
+// newExportCmd is used to generate the export command for the CLI
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Build a mapping file from an existing map.",
+		Long:  "Build a mapping file from an existing map of the Zabbix server. Each link of the map is converted to a mapping, the triggers of the link are used as trigger patterns and to infer the interfaces. The position of the elements can be written to a layout file, used with the '--layout' flag to reproduce the map.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the map and output flags were set correctly.
+			if ExportMap == "" {
+				return failure.Errorf(failure.Config, "'map' flag is required and cannot be empty")
+			}
+
+			if ExportOutFile == "" {
+				return failure.Errorf(failure.Config, "'output' flag is required and cannot be empty")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			return app.RunExport(ExportMap, ExportOutFile, ExportLayout, options, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVar(&ExportMap, "map", "", "name or id of the map to export")
+	cmd.Flags().StringVarP(&ExportOutFile, "output", "o", "", "file used to store the mappings (JSON)")
+	cmd.Flags().StringVar(&ExportLayout, "layout", "", "file used to store the position of the elements and the size of the map (JSON)")
+	cmd.MarkFlagRequired("map")
+	cmd.MarkFlagRequired("output")
+
+	return cmd
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+)
+
+func TestNewExportCmd(t *testing.T) {
+	cmd := newExportCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteExport(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "export", "--map", "dc1", "--output", os.Getenv("MAPPING_FILE"), "--layout", os.Getenv("LAYOUT_FILE"))
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server and create a map linking router-1 and router-2
+	server := newTestingServer(t)
+	service, err := api.InitApi(server.ApiUrl(), ZABBIX_USER, ZABBIX_PWD)
+	if err != nil {
+		t.Fatalf("error while executing InitApi function.\nReason : %v", err)
+	}
+
+	m := &zabbixgosdk.MapCreateParameters{}
+	m.Name = "dc1"
+	m.Width = "800"
+	m.Height = "600"
+	m.Elements = []*zabbixgosdk.MapElement{
+		{Id: "1", ElementType: zabbixgosdk.MapHost, Elements: []zabbixgosdk.MapElementHost{{Id: "10501"}}, IconIdOff: "3", X: "100", Y: "100"},
+		{Id: "2", ElementType: zabbixgosdk.MapHost, Elements: []zabbixgosdk.MapElementHost{{Id: "10502"}}, IconIdOff: "3", X: "300", Y: "100"},
+	}
+	m.Links = []*zabbixgosdk.MapLink{
+		{
+			SelementId1: "1",
+			SelementId2: "2",
+			LinkTriggers: []*zabbixgosdk.MapLinkTrigger{
+				{TriggerId: "30001"},
+				{TriggerId: "30003"},
+			},
+		},
+	}
+
+	if _, err = api.NewClient(service).CreateMap(m); err != nil {
+		t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+	}
+
+	dir := t.TempDir()
+	mappingFile := filepath.Join(dir, "mapping.json")
+	layoutFile := filepath.Join(dir, "layout.json")
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecuteExport", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("MAPPING_FILE=%s", mappingFile))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("LAYOUT_FILE=%s", layoutFile))
+	_, err = cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	b, err := os.ReadFile(mappingFile)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", mappingFile, err)
+	}
+
+	for _, expected := range []string{`"local_host": "router-1"`, `"remote_host": "router-2"`, `"local_interface": "eth0"`, `"local_image": "Router_(64)"`} {
+		if !strings.Contains(string(b), expected) {
+			t.Fatalf("wrong mapping file written, missing '%s'.\nReturned : %s", expected, string(b))
+		}
+	}
+
+	b, err = os.ReadFile(layoutFile)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", layoutFile, err)
+	}
+
+	if !strings.Contains(string(b), `"x": 300`) || !strings.Contains(string(b), `"height": "600"`) {
+		t.Fatalf("wrong layout file written.\nReturned : %s", string(b))
+	}
+}
+
+func TestExecuteExportFailMissingOutput(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "export", "--map", "dc1", "--output", "")
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteExportFailMissingOutput$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	err := cmd.Run()
+
+	if err == nil {
+		t.Fatalf("expected an error to be returned, an nil pointer was returned instead")
+	}
+
+	exit := err.(*exec.ExitError)
+	if exit.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+}
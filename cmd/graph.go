@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var GraphFile string
+var GraphName string
+var GraphFormat string
+var GraphOutFile string
+var GraphSnapshot string
+var GraphInputFormat string
+
+// newGraphCmd is used to generate the graph command for the CLI
+func newGraphCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the topology described in a mapping file as a Graphviz DOT, Mermaid or GraphML document.",
+		Long:  "Export the topology described in the given mapping file as a Graphviz DOT, Mermaid or GraphML document. Host groups are drawn as clusters and interfaces names are used as edges label.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the file flag was set correctly.
+			return checkFile(GraphFile)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The Zabbix server is not used when a snapshot is set.
+			options := &app.Options{}
+			if GraphSnapshot == "" {
+				var err error
+				if options, err = getEnvironmentVariables(); err != nil {
+					return err
+				}
+			}
+
+			options.Snapshot = GraphSnapshot
+			options.Format = GraphInputFormat
+			setHostLookupOptions(options)
+
+			return app.RunGraph(GraphFile, options, &app.GraphOptions{
+				Name:    GraphName,
+				Format:  GraphFormat,
+				OutFile: GraphOutFile,
+			}, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVarP(&GraphFile, "file", "f", "", "file containing the hosts mapping")
+	cmd.Flags().StringVarP(&GraphName, "name", "n", "topology", "name of the graph")
+	cmd.Flags().StringVar(&GraphFormat, "format", "dot", "format of the graph (dot, mermaid or graphml)")
+	cmd.Flags().StringVar(&GraphInputFormat, "input-format", "", "format of the mapping file (json, yaml, csv or dot), detected from the file extension if not set")
+	cmd.Flags().StringVarP(&GraphOutFile, "output", "o", "", "file used to store the graph (the graph is output to the shell if not set)")
+	cmd.Flags().StringVar(&GraphSnapshot, "from-snapshot", "", "retrieve the hosts, host groups and triggers from the given snapshot file instead of the Zabbix server")
+	addHostLookupFlags(cmd)
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
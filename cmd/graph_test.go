@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewGraphCmd(t *testing.T) {
+	cmd := newGraphCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteGraph(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "graph", "--file", mappingFilePath, "--format", "mermaid", "--output", os.Getenv("GRAPH_FILE"))
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	graphFile := filepath.Join(t.TempDir(), "topology.mmd")
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteGraph$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("GRAPH_FILE=%s", graphFile),
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	cmd.Stderr = os.Stderr
+	// Run the command in the subprocess
+	err := cmd.Run()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	b, err := os.ReadFile(graphFile)
+	if err != nil {
+		t.Fatalf("error while reading the graph file '%s'.\nReason : %v", graphFile, err)
+	}
+
+	if !strings.Contains(string(b), "subgraph g0 [\"DC1\"]") {
+		t.Fatalf("the host groups were not drawn as subgraphs.\nReturned : %s", string(b))
+	}
+
+	if len(server.Requests("hostgroup.get")) == 0 {
+		t.Fatal("no 'hostgroup.get' request was sent to the server")
+	}
+}
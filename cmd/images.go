@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/spf13/cobra"
+)
+
+var ImagesSyncDir string
+var ImagesSyncDryRun bool
+
+// newImagesCmd is used to generate the images command for the CLI
+func newImagesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "images",
+		Short: "Manage the images used by the maps.",
+		Long:  "Manage the images used as icons by the maps.",
+	}
+
+	cmd.AddCommand(newImagesSyncCmd())
+
+	return cmd
+}
+
+// newImagesSyncCmd is used to generate the images sync command for the CLI
+func newImagesSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Upload the images of a local directory to the Zabbix server.",
+		Long:  "Upload the PNG, JPEG and GIF files of the given directory as icon images. Each file is named after the file without its extension, missing images are created and images whose content changed are updated.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the dir flag was set correctly.
+			return checkDir(ImagesSyncDir)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			options.DryRun = ImagesSyncDryRun
+
+			return app.RunImagesSync(ImagesSyncDir, options, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVarP(&ImagesSyncDir, "dir", "d", "", "directory containing the images to upload")
+	cmd.Flags().BoolVar(&ImagesSyncDryRun, "dry-run", false, "output the actions without creating or updating the images on the server")
+	cmd.MarkFlagRequired("dir")
+
+	return cmd
+}
+
+// checkDir is used to validate the 'dir' flag.
+func checkDir(dir string) error {
+	if dir == "" {
+		return failure.Errorf(failure.Config, "'dir' flag is required and cannot be empty")
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return failure.Errorf(failure.Config, "error while reading directory '%s'.", dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewImagesCmd(t *testing.T) {
+	cmd := newImagesCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteImagesSync(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "images", "sync", "--dir", os.Getenv("IMAGES_DIR"))
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Router_(96).png"), []byte("router"), 0644); err != nil {
+		t.Fatalf("error while writing test data to directory '%s'.\nReason : %v", dir, err)
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteImagesSync$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("IMAGES_DIR=%s", dir),
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	// Run the command in the subprocess
+	out, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if !strings.Contains(string(out), "Router_(96) : created (imageid 6)") {
+		t.Fatalf("wrong output returned.\nReturned : %s", string(out))
+	}
+
+	if len(server.Requests("image.create")) != 1 {
+		t.Fatalf("wrong number of image.create requests.\nExpected : 1\nReturned : %d", len(server.Requests("image.create")))
+	}
+}
+
+func TestExecuteImagesSyncFailMissingDir(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "images", "sync", "--dir", filepath.Join(os.TempDir(), "missing-images-dir"))
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteImagesSyncFailMissingDir$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	err := cmd.Run()
+
+	if err == nil {
+		t.Fatalf("expected an error to be returned, an nil pointer was returned instead")
+	}
+
+	exit := err.(*exec.ExitError)
+	if exit.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+}
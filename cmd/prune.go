@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/spf13/cobra"
+)
+
+var PrunePrefix string
+var PruneKeep []string
+var PruneKeepFile string
+var PruneManifest string
+var PruneYes bool
+var PruneDryRun bool
+
+// newPruneCmd is used to generate the prune command for the CLI
+func newPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the stale maps managed by the tool.",
+		Long:  "Delete the maps whose name starts with the given prefix (used as marker of the maps managed by the tool) and that are not part of the maps to keep (set with the '--keep', '--keep-file' or '--manifest' flags). The deletion must be confirmed unless the '--yes' flag is set.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the prefix flag was set correctly.
+			if PrunePrefix == "" {
+				return failure.Errorf(failure.Config, "'prefix' flag is required and cannot be empty")
+			}
+
+			// Check if the keep-file flag was set correctly.
+			if PruneKeepFile != "" {
+				if err := checkFile(PruneKeepFile); err != nil {
+					return err
+				}
+			}
+
+			// Check if the manifest flag was set correctly.
+			if PruneManifest != "" {
+				if err := checkFile(PruneManifest); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			options.Yes = PruneYes
+			options.DryRun = PruneDryRun
+
+			return app.RunPrune(PrunePrefix, PruneKeep, PruneKeepFile, PruneManifest, options, os.Stdin, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVar(&PrunePrefix, "prefix", "", "prefix of the name of the maps managed by the tool")
+	cmd.Flags().StringArrayVar(&PruneKeep, "keep", []string{}, "name of a map to keep (can be used multiple times)")
+	cmd.Flags().StringVar(&PruneKeepFile, "keep-file", "", "file containing the names of the maps to keep (one name per line, lines starting with '#' are ignored)")
+	cmd.Flags().StringVarP(&PruneManifest, "manifest", "m", "", "manifest (YAML or JSON) of the build-all command, the maps listed in the manifest are kept")
+	cmd.Flags().BoolVarP(&PruneYes, "yes", "y", false, "delete the maps without asking for confirmation")
+	cmd.Flags().BoolVar(&PruneDryRun, "dry-run", false, "output the maps that would be deleted without deleting them")
+	cmd.MarkFlagRequired("prefix")
+
+	return cmd
+}
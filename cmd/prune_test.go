@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewPruneCmd(t *testing.T) {
+	cmd := newPruneCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecutePrune(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "prune", "--prefix", "site-", "--keep", "site-paris", "--keep-file", os.Getenv("KEEP_FILE"), "--yes")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	createTestingMaps(t, server, "site-paris", "site-lyon", "site-nantes", "site-lille", "core")
+
+	keepFile := filepath.Join(t.TempDir(), "keep.txt")
+	if err := os.WriteFile(keepFile, []byte("site-lyon\n"), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", keepFile, err)
+	}
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecutePrune", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("KEEP_FILE=%s", keepFile))
+	out, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if !strings.Contains(string(out), "2 map(s) deleted") {
+		t.Fatalf("wrong output returned.\nReturned : %s", string(out))
+	}
+
+	names := make([]string, 0)
+	for _, m := range server.Maps() {
+		names = append(names, m.Name)
+	}
+
+	if strings.Join(names, ",") != "site-paris,site-lyon,core" {
+		t.Fatalf("wrong maps kept on the server.\nExpected : site-paris,site-lyon,core\nReturned : %s", strings.Join(names, ","))
+	}
+}
+
+func TestExecutePruneManifest(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "prune", "--prefix", "site-", "--manifest", os.Getenv("MANIFEST_FILE"), "--yes")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	createTestingMaps(t, server, "site-paris", "site-lyon", "site-nantes", "core")
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.yaml")
+	manifest := "defaults:\n  file: mapping.json\nmaps:\n  - name: site-paris\n  - name: site-lyon\n"
+	if err := os.WriteFile(manifestFile, []byte(manifest), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", manifestFile, err)
+	}
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecutePruneManifest", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("MANIFEST_FILE=%s", manifestFile))
+	out, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if !strings.Contains(string(out), "1 map(s) deleted") {
+		t.Fatalf("wrong output returned.\nReturned : %s", string(out))
+	}
+
+	names := make([]string, 0)
+	for _, m := range server.Maps() {
+		names = append(names, m.Name)
+	}
+
+	if strings.Join(names, ",") != "site-paris,site-lyon,core" {
+		t.Fatalf("wrong maps kept on the server.\nExpected : site-paris,site-lyon,core\nReturned : %s", strings.Join(names, ","))
+	}
+}
+
+func TestExecutePruneDryRun(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "prune", "--prefix", "site-", "--dry-run")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	createTestingMaps(t, server, "site-paris", "core")
+
+	// Execute test in a subprocess
+	out, err := newDeleteSubprocess("TestExecutePruneDryRun", server, "").Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if !strings.Contains(string(out), "site-paris (sysmapid") {
+		t.Fatalf("the maps to delete should be output.\nReturned : %s", string(out))
+	}
+
+	if len(server.Maps()) != 2 {
+		t.Fatal("no map should be deleted in dry-run mode")
+	}
+}
+
+func TestExecutePruneFailMissingPrefix(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "prune", "--prefix", "")
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecutePruneFailMissingPrefix$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	err := cmd.Run()
+
+	if err == nil {
+		t.Fatalf("expected an error to be returned, an nil pointer was returned instead")
+	}
+
+	exit := err.(*exec.ExitError)
+	if exit.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/spf13/cobra"
+)
+
+var RenderInput string
+var RenderMap string
+var RenderOutFile string
+var RenderPngFile string
+var RenderSnapshot string
+
+// newRenderCmd is used to generate the render command for the CLI
+func newRenderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Draw a map to an SVG or PNG file.",
+		Long:  "Draw a map to an SVG file (and optionally a PNG file) using the elements coordinates, links colors and labels. The map is read from a file created with the '--output' flag or retrieved from the Zabbix server.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if RenderInput == "" && RenderMap == "" {
+				return failure.Errorf(failure.Config, "'input' or 'map' flag is required and cannot be empty")
+			}
+
+			if RenderInput != "" {
+				if err := checkFile(RenderInput); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The Zabbix server is not used when rendering a map file with a snapshot.
+			options := &app.Options{}
+			if RenderSnapshot == "" || RenderInput == "" {
+				var err error
+				if options, err = getEnvironmentVariables(); err != nil {
+					return err
+				}
+			}
+
+			options.Snapshot = RenderSnapshot
+
+			return app.RunRender(options, &app.RenderOptions{
+				Input:   RenderInput,
+				Map:     RenderMap,
+				SvgFile: RenderOutFile,
+				PngFile: RenderPngFile,
+			}, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVarP(&RenderInput, "input", "i", "", "file containing the parameters used to create the map (see the 'output' flag of the root command)")
+	cmd.Flags().StringVar(&RenderMap, "map", "", "name of an existing map to retrieve from the Zabbix server")
+	cmd.Flags().StringVarP(&RenderOutFile, "output", "o", "", "SVG file used to store the rendered map")
+	cmd.Flags().StringVar(&RenderPngFile, "png", "", "PNG file used to store the rendered map")
+	cmd.Flags().StringVar(&RenderSnapshot, "from-snapshot", "", "retrieve the icons and hosts from the given snapshot file instead of the Zabbix server")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagsMutuallyExclusive("input", "map")
+
+	return cmd
+}
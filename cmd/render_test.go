@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+// writeTestingMap is used to write a map create request containing two linked hosts of the example dataset.
+func writeTestingMap(t *testing.T, file string) {
+	m := &zabbixgosdk.MapCreateParameters{}
+	m.Name = "test-map-builder"
+	m.Width = "400"
+	m.Height = "400"
+	m.Elements = []*zabbixgosdk.MapElement{
+		{
+			Id:          "1",
+			ElementType: zabbixgosdk.MapHost,
+			Elements:    []zabbixgosdk.MapElementHost{{Id: "10501"}},
+			IconIdOff:   "3",
+			X:           "50",
+			Y:           "50",
+		},
+		{
+			Id:          "2",
+			ElementType: zabbixgosdk.MapHost,
+			Elements:    []zabbixgosdk.MapElementHost{{Id: "10502"}},
+			IconIdOff:   "3",
+			X:           "250",
+			Y:           "250",
+		},
+	}
+	m.Links = []*zabbixgosdk.MapLink{
+		{
+			SelementId1: "1",
+			SelementId2: "2",
+			Color:       "7AC2E1",
+		},
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("error while encoding the map.\nReason : %v", err)
+	}
+
+	if err = os.WriteFile(file, b, 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+	}
+}
+
+func TestNewRenderCmd(t *testing.T) {
+	cmd := newRenderCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteRender(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "render", "--input", os.Getenv("INPUT_FILE"), "--output", os.Getenv("SVG_FILE"))
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "map.json")
+	svgFile := filepath.Join(dir, "map.svg")
+	writeTestingMap(t, inputFile)
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteRender$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("INPUT_FILE=%s", inputFile),
+		fmt.Sprintf("SVG_FILE=%s", svgFile),
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	cmd.Stderr = os.Stderr
+	// Run the command in the subprocess
+	err := cmd.Run()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	b, err := os.ReadFile(svgFile)
+	if err != nil {
+		t.Fatalf("error while reading the SVG file '%s'.\nReason : %v", svgFile, err)
+	}
+
+	if !strings.Contains(string(b), "router-2") {
+		t.Fatalf("the name of the hosts was not rendered.\nReturned : %s", string(b))
+	}
+
+	if len(server.Requests("image.get")) == 0 {
+		t.Fatal("no 'image.get' request was sent to the server")
+	}
+}
+
+func TestExecuteRenderMissingInput(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "render", "--output", "map.svg")
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteRenderMissingInput$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	err := cmd.Run()
+
+	if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nError returned : %v", err)
+	}
+}
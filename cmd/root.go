@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
 	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +29,44 @@ var StackHosts []bool
 var GlobalLogger *logging.Logger
 var Debug bool
 var DryRun bool
+var FromSnapshot string
+var Format string
+var HostLookup []string
+var HostTag string
+var HostRename string
+var HostReport string
+var IconRules string
+var ImagesDir string
+var LabelTemplate string
+var LabelLocation string
+var Urls []string
+var Layout string
+var Public bool
+var Owner string
+var ShareUsers []string
+var ShareGroups []string
+var SharingFile string
+var Update bool
+var IncludeHosts []string
+var IncludeGroups []string
+var Tags []string
+var Hops int
+var Stubs bool
+var StubImage string
+var Watch bool
+var WatchInterval time.Duration
+var WatchDebounce time.Duration
+var MetricsFile string
+var LogLevel string
+var LogFormat string
+var LogFile string
+var LogMaxSize int64
+var LogMaxBackups int
+var Verbose bool
+
+// started is set once the flags and the arguments of the command were validated by cobra.
+// The errors returned before are usage errors (unknown flag, missing required flag, invalid arguments, etc.).
+var started bool
 
 func init() {
 	// Init a new global logger
@@ -34,29 +79,30 @@ func newRootCmd() *cobra.Command {
 		Use:   "",
 		Short: "Build a zabbix map using the given host mapping.",
 		Long:  "This CLI tool is used to help administrator build a zabbix map using the given host mappings (network devices, etc.).",
-		PreRun: func(cmd *cobra.Command, args []string) {
+		// The errors are logged by Execute
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			started = true
+
+			// Configure the logger used by all the commands.
+			return configureLogger()
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Check if the file flag was set correctly.
-			if err := checkRequiredFlag(Name, File); err != "" {
-				GlobalLogger.Error(err)
-				os.Exit(1)
-			}
+			return checkRequiredFlag(Name, File)
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			// Enable debug logger level.
-			if Debug {
-				GlobalLogger.Level = logging.Debug
-			}
-
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// Retrieve the required environment variables.
-			GlobalLogger.Debug("retrieving environment variables")
-			options, err := app.GetEnvironmentVariables()
-			if err != nil {
-				GlobalLogger.Error("error when reading the required environment variables", fmt.Sprintf("reason : %s", err))
-				os.Exit(1)
+			// The Zabbix server is not used when building the map from a snapshot.
+			options := &app.Options{}
+			if FromSnapshot == "" {
+				var err error
+				if options, err = getEnvironmentVariables(); err != nil {
+					return err
+				}
 			}
 
-			GlobalLogger.Debug(fmt.Sprintf("using the following environment variables :\nZABBIX_URL => %s\nZABBIX_USER => %s\nZABBIX_PWD => <masked-for-security-reason>", options.ZabbixUrl, options.ZabbixUser))
-
 			options.Name = Name
 			options.OutFile = OutFile
 			options.Color = Color
@@ -66,55 +112,235 @@ func newRootCmd() *cobra.Command {
 			options.Spacer = Spacer
 			options.StackHosts = StackHosts[0]
 			options.DryRun = DryRun
+			options.Snapshot = FromSnapshot
+			options.Format = Format
+			options.HostReport = HostReport
+			options.MetricsFile = MetricsFile
+			options.IconRules = IconRules
+			options.ImagesDir = ImagesDir
+			options.LabelTemplate = LabelTemplate
+			options.LabelLocation = LabelLocation
+			options.Urls = Urls
+			options.Layout = Layout
+			options.Public = Public
+			options.Owner = Owner
+			options.ShareUsers = ShareUsers
+			options.ShareGroups = ShareGroups
+			options.SharingFile = SharingFile
+			options.Update = Update
+			options.Filter = &filter.Filter{
+				IncludeHosts:  IncludeHosts,
+				IncludeGroups: IncludeGroups,
+				Tags:          Tags,
+				Hops:          Hops,
+				Stubs:         Stubs,
+				StubImage:     StubImage,
+			}
+			setHostLookupOptions(options)
 
 			// Run the application.
-			err = app.RunApp(File, options, GlobalLogger)
-			if err != nil {
-				GlobalLogger.Error("error when executing the command", err)
-				os.Exit(1)
+			if Watch {
+				return app.RunWatch(File, options, &app.WatchOptions{Interval: WatchInterval, Debounce: WatchDebounce}, stopOnSignal(), os.Stdout, GlobalLogger)
 			}
+
+			return app.RunApp(File, options, GlobalLogger)
 		},
 	}
 
+	// Set the flags used to build the map
+	cmd.Flags().StringVar(&Name, "name", "", "name of the map")
+	cmd.Flags().StringVarP(&File, "file", "f", "", "file containing the hosts mapping")
+	cmd.Flags().StringVar(&Format, "format", "", "format of the mapping file (json, yaml, csv or dot), detected from the file extension if not set")
+	cmd.Flags().StringVarP(&OutFile, "output", "o", "", "output the parameters used to create the map to a file")
+	cmd.Flags().StringVarP(&Color, "color", "c", "000000", "color in hexadecimal used for the links between each hosts")
+	cmd.Flags().StringVar(&TriggerColor, "trigger-color", "DD0000", "color in hexadecimal used for the links between each hosts when a trigger is in problem state")
+	cmd.Flags().StringVar(&Height, "height", "800", "height in pixel of the map")
+	cmd.Flags().StringVar(&Width, "width", "800", "width in pixel of the map")
+	cmd.Flags().Int64Var(&Spacer, "spacer", 100, "space in pixel between each host (example : X_host2 = X_host1 + <value>)")
+	cmd.Flags().BoolSliceVar(&StackHosts, "stack-hosts", []bool{true}, "connect multiple links to a single host. If set to false, each mapping will have is own hosts (local and remote). This can be useful for infrastructure with redundant connexion")
+	cmd.Flags().BoolVar(&DryRun, "dry-run", false, "output to the shell the map definition without created it on the server")
+	cmd.Flags().StringVar(&FromSnapshot, "from-snapshot", "", "build the map using the given snapshot file instead of the Zabbix server (the map definition is output to the shell or to the output file)")
+	cmd.Flags().StringVar(&HostReport, "host-report", "", "write to the given file how each host was resolved (JSON)")
+	addMetricsFlags(cmd)
+	cmd.Flags().StringVar(&ImagesDir, "images-dir", "", "directory containing images (PNG, JPEG or GIF) uploaded to the server before building the map, missing images are created and images whose content changed are updated")
+	cmd.Flags().StringVar(&IconRules, "icon-rules", "", "file (YAML or JSON) containing the rules used to select the image of the hosts without one")
+	cmd.Flags().StringVar(&LabelTemplate, "label", "", "label of the elements without a label set in the mapping file, Zabbix macros ({HOST.NAME}, {HOST.IP}, {INVENTORY.*}) and mapping fields ({host}, {interface}, {image}, {type}) can be used")
+	cmd.Flags().StringVar(&LabelLocation, "label-location", "", "location of the labels of the elements (default, bottom, left, right or top), the location set for the map is used if not set")
+	cmd.Flags().StringArrayVar(&Urls, "url", []string{}, "URL added to the elements written as 'name=url' (can be used multiple times), Zabbix macros and mapping fields can be used")
+	cmd.Flags().StringVar(&Layout, "layout", "", "file (JSON) containing the position of the elements and the size of the map, as written by the 'export' command. Elements not part of the layout are placed automatically")
+	cmd.Flags().BoolVar(&Public, "public", false, "create a public map, maps are private by default")
+	cmd.Flags().StringVar(&Owner, "owner", "", "username of the owner of the map, the API user is used if not set")
+	cmd.Flags().StringArrayVar(&ShareUsers, "share-user", []string{}, "user the map is shared with, written as 'username:permission' with the permission 'read' or 'read-write' (can be used multiple times)")
+	cmd.Flags().StringArrayVar(&ShareGroups, "share-group", []string{}, "user group the map is shared with, written as 'name:permission' with the permission 'read' or 'read-write' (can be used multiple times)")
+	cmd.Flags().StringVar(&SharingFile, "sharing", "", "file (YAML or JSON) containing the sharing options of the map (private, owner, users and user_groups), completed by the sharing flags")
+	cmd.Flags().BoolVar(&Update, "update", false, "update the map with the same name if it already exists instead of creating a new map")
+	cmd.Flags().StringArrayVar(&IncludeHosts, "include-host", []string{}, "glob pattern (dc1-* for example) matching the name of the hosts to draw (can be used multiple times), all the hosts are drawn if no selection flag is used")
+	cmd.Flags().StringArrayVar(&IncludeGroups, "include-group", []string{}, "glob pattern matching the name of the host groups whose hosts are drawn (can be used multiple times)")
+	cmd.Flags().StringArrayVar(&Tags, "tag", []string{}, "tag of the hosts to draw written as 'name=value' (the value can be a glob pattern) or 'name' for any value (can be used multiple times)")
+	cmd.Flags().IntVar(&Hops, "hops", 0, "number of neighbors added around the selected hosts")
+	cmd.Flags().BoolVar(&Stubs, "stubs", false, "draw the links leaving the selection, the elements outside of the selection are replaced by image elements")
+	cmd.Flags().StringVar(&StubImage, "stub-image", "", "image of the stub elements, the image set in the mapping file is used if not set")
+	cmd.Flags().BoolVar(&Watch, "watch", false, "monitor the input files (mapping file, layout, icon rules, sharing and rename files) and update the map on each change, until interrupted (Ctrl+C)")
+	cmd.Flags().DurationVar(&WatchInterval, "watch-interval", time.Second, "delay between two checks of the input files in watch mode")
+	cmd.Flags().DurationVar(&WatchDebounce, "watch-debounce", time.Second, "delay without changes waited before rebuilding the map in watch mode")
+	addHostLookupFlags(cmd)
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("file")
+
 	// Set all the persistent flag
-	cmd.PersistentFlags().StringVar(&Name, "name", "", "name of the map")
-	cmd.PersistentFlags().StringVarP(&File, "file", "f", "", "file containing the hosts mapping")
-	cmd.PersistentFlags().StringVarP(&OutFile, "output", "o", "", "output the parameters used to create the map to a file")
-	cmd.PersistentFlags().StringVarP(&Color, "color", "c", "000000", "color in hexadecimal used for the links between each hosts")
-	cmd.PersistentFlags().StringVar(&TriggerColor, "trigger-color", "DD0000", "color in hexadecimal used for the links between each hosts when a trigger is in problem state")
-	cmd.PersistentFlags().StringVar(&Height, "height", "800", "height in pixel of the map")
-	cmd.PersistentFlags().StringVar(&Width, "width", "800", "width in pixel of the map")
-	cmd.PersistentFlags().Int64Var(&Spacer, "spacer", 100, "space in pixel between each host (example : X_host2 = X_host1 + <value>)")
-	cmd.PersistentFlags().BoolSliceVar(&StackHosts, "stack-hosts", []bool{true}, "connect multiple links to a single host. If set to false, each mapping will have is own hosts (local and remote). This can be useful for infrastructure with redundant connexion")
-	cmd.PersistentFlags().BoolVarP(&Debug, "debug", "v", false, "enable debug logging verbosity")
-	cmd.PersistentFlags().BoolVar(&DryRun, "dry-run", false, "output to the shell the map definition without created it on the server")
-	cmd.MarkPersistentFlagRequired("name")
-	cmd.MarkPersistentFlagRequired("file")
+	cmd.PersistentFlags().BoolVarP(&Debug, "debug", "v", false, "enable debug logging verbosity (same as '--log-level debug')")
+	cmd.PersistentFlags().StringVar(&LogLevel, "log-level", "warning", fmt.Sprintf("minimum level of the logged records %v", logging.Levels))
+	cmd.PersistentFlags().StringVar(&LogFormat, "log-format", "text", fmt.Sprintf("format of the logged records %v, JSON records include the map, the host and the API method they are about", logging.Formats))
+	cmd.PersistentFlags().StringVar(&LogFile, "log-file", "", "write the logs to the given file instead of the shell, the file is rotated once it reaches '--log-max-size'")
+	cmd.PersistentFlags().Int64Var(&LogMaxSize, "log-max-size", 10, "size in megabytes from which the log file is rotated (0 to disable the rotation)")
+	cmd.PersistentFlags().IntVar(&LogMaxBackups, "log-max-backups", 5, "number of rotated log files kept")
+	cmd.PersistentFlags().BoolVar(&Verbose, "verbose", false, "log the kind, the causes and the stack trace of the error ending the command")
+
+	// Add the sub commands
+	cmd.AddCommand(newSnapshotCmd())
+	cmd.AddCommand(newRenderCmd())
+	cmd.AddCommand(newGraphCmd())
+	cmd.AddCommand(newValidateCmd())
+	cmd.AddCommand(newImagesCmd())
+	cmd.AddCommand(newDeleteCmd())
+	cmd.AddCommand(newPruneCmd())
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newBuildAllCmd())
+	cmd.AddCommand(newDaemonCmd())
+	cmd.AddCommand(newServeCmd())
 
 	return cmd
 }
 
+// Execute is used to run the command matching the arguments of the process.
+// If an error is returned, the process exit with the code matching the kind of the error (see the failure package).
 func Execute() {
 	rootCmd := newRootCmd()
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	if !started {
+		// The name of the root command is empty, the name of the binary is used instead
+		path := append([]string{filepath.Base(os.Args[0])}, strings.Fields(cmd.CommandPath())...)
+		err = failure.Errorf(failure.Config, "%v\nRun '%s --help' for usage.", err, strings.Join(path, " "))
+	}
+
+	os.Exit(handleError(err))
+}
+
+// handleError is used to log the given error and to retrieve the exit code matching its kind.
+// The trace of the error (causes and stack trace) is also logged when the '--verbose' flag is set.
+func handleError(err error) int {
+	kind := failure.KindOf(err)
+	message := fmt.Sprintf("%s : %v", kind, err)
+
+	if Verbose {
+		GlobalLogger.Critical(message, failure.Trace(err))
+	} else {
+		GlobalLogger.Critical(message)
+	}
+
+	return kind.ExitCode()
+}
+
+// configureLogger is used to set the level, the format and the output of the global logger using the logging flags.
+func configureLogger() error {
+	level, err := logging.ParseLevel(LogLevel)
 	if err != nil {
-		log.Fatalf("error during command initialization.\nReason : %v", err)
+		return failure.New(failure.Config, err)
 	}
+
+	if Debug {
+		level = logging.Debug
+	}
+
+	var out io.Writer = os.Stderr
+	if LogFile != "" {
+		out, err = logging.NewRotatingFile(LogFile, LogMaxSize<<20, LogMaxBackups)
+		if err != nil {
+			return failure.New(failure.Config, err)
+		}
+	}
+
+	handler, err := logging.NewHandler(LogFormat, out)
+	if err != nil {
+		return failure.New(failure.Config, err)
+	}
+
+	GlobalLogger.Level = level
+	GlobalLogger.SetHandler(handler)
+
+	return nil
+}
+
+// getEnvironmentVariables is used to retrieve the required environment variables.
+// An error is returned if one of the variables is missing.
+func getEnvironmentVariables() (*app.Options, error) {
+	GlobalLogger.Debug("retrieving environment variables")
+	options, err := app.GetEnvironmentVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	GlobalLogger.Debug(fmt.Sprintf("using the following environment variables :\nZABBIX_URL => %s\nZABBIX_USER => %s\nZABBIX_PWD => <masked-for-security-reason>", options.ZabbixUrl, options.ZabbixUser))
+
+	return options, nil
+}
+
+// stopOnSignal is used to retrieve a channel closed when the process receives an interrupt or a termination signal.
+func stopOnSignal() <-chan struct{} {
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		GlobalLogger.Debug("signal received, stopping")
+		close(stop)
+	}()
+
+	return stop
+}
+
+// addMetricsFlags is used to add the flag writing the metrics of the run to a file.
+func addMetricsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&MetricsFile, "metrics-file", "", "write the metrics of the run (API calls, build duration, resolved hosts, etc.) to the given file using the Prometheus text format, for the textfile collector of the node exporter")
+}
+
+// addHostLookupFlags is used to add the flags defining how the hosts referenced in the mappings are resolved.
+func addHostLookupFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&HostLookup, "host-lookup", []string{"host"}, "strategies used in order to resolve the hosts (host, name, interface or tag)")
+	cmd.Flags().StringVar(&HostTag, "host-tag", "", "name of the tag used by the 'tag' host lookup strategy (sysName for example)")
+	cmd.Flags().StringVar(&HostRename, "host-rename", "", "file (JSON or YAML) associating the names used in the mappings to the values used to search the hosts")
+}
+
+// setHostLookupOptions is used to set the host lookup flags in the given options.
+func setHostLookupOptions(options *app.Options) {
+	options.HostLookup = HostLookup
+	options.HostTag = HostTag
+	options.HostRename = HostRename
 }
 
 // checkRequiredFlag is used to validate the required flags.
-func checkRequiredFlag(name string, file string) string {
+func checkRequiredFlag(name string, file string) error {
 	if name == "" {
-		return "'name' flag is required and cannot be empty"
+		return failure.Errorf(failure.Config, "'name' flag is required and cannot be empty")
 	}
 
+	return checkFile(file)
+}
+
+// checkFile is used to validate the 'file' flag.
+func checkFile(file string) error {
 	if file == "" {
-		return "'file' flag is required and cannot be empty"
+		return failure.Errorf(failure.Config, "'file' flag is required and cannot be empty")
 	}
 
 	if _, err := os.Stat(file); err != nil {
-		return fmt.Sprintf("error while reading file '%s'.", file)
+		return failure.Errorf(failure.Config, "error while reading file '%s'.", file)
 	}
 
-	return ""
+	return nil
 }
@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fakeserver"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
 )
 
 const (
@@ -17,10 +25,25 @@ const (
 )
 
 var mappingFilePath string
+var datasetFilePath string
 
 func init() {
 	pwd, _ := os.Getwd()
 	mappingFilePath = filepath.Join(pwd, "..", "examples", "mapping.json")
+	datasetFilePath = filepath.Join(pwd, "..", "examples", "fake_dataset.json")
+}
+
+// newTestingServer is used to start a fake Zabbix server using the example dataset.
+func newTestingServer(t *testing.T) *fakeserver.Server {
+	dataset, err := fakeserver.LoadDataset(datasetFilePath)
+	if err != nil {
+		t.Fatalf("error while loading the fake server dataset.\nReason : %v", err)
+	}
+
+	s := fakeserver.NewServer(dataset)
+	t.Cleanup(s.Close)
+
+	return s
 }
 
 // generateMapName is used to generate a random name for each map created during test.
@@ -41,7 +64,7 @@ func TestNewRootCmd(t *testing.T) {
 func TestExecute(t *testing.T) {
 	if os.Getenv("BE_CRASHER") == "1" {
 		// Set the required arguments
-		os.Args = append(os.Args, "--name", generateMapName())
+		os.Args = append(os.Args, "--name", "test-map-builder")
 		os.Args = append(os.Args, "--file", mappingFilePath)
 		os.Args = append(os.Args, "--color", "7AC2E1", "--trigger-color", "EE445B", "--width", "400", "--height", "400")
 		Execute()
@@ -49,14 +72,17 @@ func TestExecute(t *testing.T) {
 		return
 	}
 
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+
 	// Execute test in a subprocess
-	cmd := exec.Command(os.Args[0], "-test.run=TestExecute")
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecute$")
 	// Reset the subprocess environment variable
 	cmd.Env = []string{
 		"BE_CRASHER=1",
 	}
 	// Add the required environment variables
-	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", ZABBIX_URL))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
 	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
 	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
 	// Read the output of the command
@@ -69,6 +95,487 @@ func TestExecute(t *testing.T) {
 		exit := err.(*exec.ExitError)
 		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
 	}
+
+	// Validate the request sent to create the map
+	requests := server.Requests("map.create")
+	if len(requests) != 1 {
+		t.Fatalf("wrong number of 'map.create' requests sent.\nExpected : 1\nReturned : %d", len(requests))
+	}
+
+	params := struct {
+		Name     string        `json:"name"`
+		Elements []interface{} `json:"selements"`
+		Links    []interface{} `json:"links"`
+	}{}
+
+	if err = json.Unmarshal(requests[0].Params, &params); err != nil {
+		t.Fatalf("error while decoding the 'map.create' request.\nReason : %v", err)
+	}
+
+	if params.Name != "test-map-builder" {
+		t.Fatalf("wrong map name sent.\nExpected : 'test-map-builder'\nReturned : %s", params.Name)
+	}
+
+	if len(params.Elements) != 3 {
+		t.Fatalf("wrong number of elements sent.\nExpected : 3\nReturned : %d", len(params.Elements))
+	}
+
+	if len(params.Links) != 2 {
+		t.Fatalf("wrong number of links sent.\nExpected : 2\nReturned : %d", len(params.Links))
+	}
+
+	if len(server.Maps()) != 1 {
+		t.Fatalf("the map was not created on the server.\nExpected : 1\nReturned : %d", len(server.Maps()))
+	}
+}
+
+func TestExecuteDotFormat(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments, the DOT example is passed with a .txt extension to use the format flag
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", os.Getenv("MAPPING_FILE"), "--format", "dot")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+
+	b, err := os.ReadFile(filepath.Join(filepath.Dir(mappingFilePath), "mapping.dot"))
+	if err != nil {
+		t.Fatalf("error while reading the DOT example.\nReason : %v", err)
+	}
+
+	mappingFile := filepath.Join(t.TempDir(), "mapping.txt")
+	if err = os.WriteFile(mappingFile, b, 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", mappingFile, err)
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteDotFormat$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("MAPPING_FILE=%s", mappingFile),
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	cmd.Stderr = os.Stderr
+	// Run the command in the subprocess
+	err = cmd.Run()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if len(server.Maps()) != 1 {
+		t.Fatalf("the map was not created on the server.\nExpected : 1\nReturned : %d", len(server.Maps()))
+	}
+}
+
+func TestExecuteHostLookup(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", os.Getenv("MAPPING_FILE"))
+		os.Args = append(os.Args, "--host-lookup", "host,name,interface,tag", "--host-tag", "sysName")
+		os.Args = append(os.Args, "--host-report", os.Getenv("REPORT_FILE"))
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+
+	b, err := os.ReadFile(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while reading the mapping example.\nReason : %v", err)
+	}
+
+	// Reference the hosts using a tag, an IP address and a visible name
+	r := strings.NewReplacer("router-1", "rt1.dc1", "router-2", "192.168.1.2", "router-3", "Core router 3")
+	mappingFile := filepath.Join(t.TempDir(), "mapping.json")
+	if err = os.WriteFile(mappingFile, []byte(r.Replace(string(b))), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", mappingFile, err)
+	}
+
+	reportFile := filepath.Join(t.TempDir(), "report.json")
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteHostLookup$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("MAPPING_FILE=%s", mappingFile),
+		fmt.Sprintf("REPORT_FILE=%s", reportFile),
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	// Run the command in the subprocess
+	_, err = cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if len(server.Maps()) != 1 {
+		t.Fatalf("the map was not created on the server.\nExpected : 1\nReturned : %d", len(server.Maps()))
+	}
+
+	b, err = os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("error while reading the report file.\nReason : %v", err)
+	}
+
+	for _, strategy := range []string{`"strategy": "tag"`, `"strategy": "interface"`, `"strategy": "name"`} {
+		if !strings.Contains(string(b), strategy) {
+			t.Fatalf("the strategy %s was not found in the report.\nReturned : %s", strategy, string(b))
+		}
+	}
+}
+
+func TestExecuteIconRules(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", os.Getenv("MAPPING_FILE"))
+		os.Args = append(os.Args, "--icon-rules", filepath.Join("..", "examples", "icon_rules.yaml"))
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+
+	// No image is set, router-1 match a template, router-2 an inventory type and router-3 its CDP capabilities
+	mapping := `hosts:
+  router-3:
+    capabilities: R S I
+links:
+  - local: router-1:eth0
+    remote: router-2:eth0
+    trigger_pattern: "Interface {interface}(): Link down"
+  - local: router-1:eth1
+    remote: router-3:eth1
+    trigger_pattern: "Interface {interface}(): Link down"
+`
+	mappingFile := filepath.Join(t.TempDir(), "mapping.yaml")
+	if err := os.WriteFile(mappingFile, []byte(mapping), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", mappingFile, err)
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteIconRules$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("MAPPING_FILE=%s", mappingFile),
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	// Run the command in the subprocess
+	_, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	maps := server.Maps()
+	if len(maps) != 1 {
+		t.Fatalf("the map was not created on the server.\nExpected : 1\nReturned : %d", len(maps))
+	}
+
+	// Router_(64) is used for router-1 and router-3, Switch_(64) for router-2
+	elements := string(maps[0].Definition["selements"])
+	if strings.Count(elements, `"iconid_off":"3"`) != 2 || strings.Count(elements, `"iconid_off":"5"`) != 1 {
+		t.Fatalf("wrong images set for the elements.\nReturned : %s", elements)
+	}
+}
+
+func TestExecuteLabels(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", mappingFilePath)
+		os.Args = append(os.Args, "--label", "{HOST.NAME} ({interface})", "--label-location", "bottom")
+		os.Args = append(os.Args, "--url", "NetBox=https://netbox/dcim/devices/?q={HOST.HOST}")
+		os.Args = append(os.Args, "--url", "Grafana=https://grafana/d/device?var-host={host}")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteLabels$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	// Run the command in the subprocess
+	_, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	maps := server.Maps()
+	if len(maps) != 1 {
+		t.Fatalf("the map was not created on the server.\nExpected : 1\nReturned : %d", len(maps))
+	}
+
+	elements := string(maps[0].Definition["selements"])
+	expected := []string{
+		`"label":"{HOST.NAME} (eth0)"`,
+		`"label_location":"0"`,
+		`"url":"https://netbox/dcim/devices/?q={HOST.HOST}"`,
+		`"url":"https://grafana/d/device?var-host=router-1"`,
+	}
+
+	for _, value := range expected {
+		if !strings.Contains(elements, value) {
+			t.Fatalf("the value %s was not found in the elements.\nReturned : %s", value, elements)
+		}
+	}
+}
+
+func TestExecuteUpdateSharing(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", mappingFilePath)
+		os.Args = append(os.Args, "--update", "--public", "--owner", "noc")
+		os.Args = append(os.Args, "--share-user", "Admin:read-write", "--share-group", "Network team:read")
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server with an existing map using the same name
+	server := newTestingServer(t)
+	createTestingMaps(t, server, "test-map-builder")
+
+	// Execute test in a subprocess
+	_, err := newDeleteSubprocess("TestExecuteUpdateSharing", server, "").Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	maps := server.Maps()
+	if len(maps) != 1 || len(server.Requests("map.update")) != 1 {
+		t.Fatalf("the existing map should be updated.\nReturned : %d map(s), %d update(s)", len(maps), len(server.Requests("map.update")))
+	}
+
+	if len(maps[0].Definition["selements"]) == 0 {
+		t.Fatal("the elements of the existing map were not updated")
+	}
+
+	expected := map[string]string{
+		"private":    `"0"`,
+		"userid":     `"3"`,
+		"users":      `[{"userid":"1","permission":"3"}]`,
+		"userGroups": `[{"usrgrpid":"15","permission":"2"}]`,
+	}
+
+	for key, value := range expected {
+		if string(maps[0].Definition[key]) != value {
+			t.Fatalf("wrong value set for the property '%s'.\nExpected : %s\nReturned : %s", key, value, string(maps[0].Definition[key]))
+		}
+	}
+}
+
+func TestExecuteFilter(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", mappingFilePath)
+		os.Args = append(os.Args, "--include-group", "DC1", "--stubs", "--stub-image", "Cloud_(24)")
+		Execute()
+
+		return
+	}
+
+	server := newTestingServer(t)
+
+	// Execute test in a subprocess
+	_, err := newDeleteSubprocess("TestExecuteFilter", server, "").Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	maps := server.Maps()
+	if len(maps) != 1 {
+		t.Fatalf("wrong number of maps created.\nExpected : 1\nReturned : %d", len(maps))
+	}
+
+	// router-3 is not part of the group DC1 and is replaced by a stub using the image 'Cloud_(24)'
+	elements := string(maps[0].Definition["selements"])
+	if strings.Count(elements, `"elementtype":"0"`) != 2 || strings.Count(elements, `"elementtype":"4"`) != 1 || !strings.Contains(elements, `"iconid_off":"1"`) {
+		t.Fatalf("wrong elements created.\nReturned : %s", elements)
+	}
+
+	if strings.Count(string(maps[0].Definition["links"]), "selementid1") != 2 {
+		t.Fatalf("wrong links created.\nReturned : %s", string(maps[0].Definition["links"]))
+	}
+}
+
+func TestExecuteLogFile(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", mappingFilePath)
+		os.Args = append(os.Args, "--log-level", "debug", "--log-format", "json", "--log-file", os.Getenv("LOG_FILE"))
+		Execute()
+
+		return
+	}
+
+	server := newTestingServer(t)
+	file := filepath.Join(t.TempDir(), "map-builder.log")
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecuteLogFile", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("LOG_FILE=%s", file))
+	_, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("error while reading the log file.\nReason : %v", err)
+	}
+
+	// Each line is a JSON record, the records about the hosts and the API calls include the related field
+	fields := make(map[string]bool, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		record := make(map[string]interface{}, 0)
+		if err = json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("each line should be a JSON record.\nReason : %v\nReturned : %s", err, line)
+		}
+
+		for _, key := range []string{"level", "msg", "map", "host", "method"} {
+			if _, exist := record[key]; exist {
+				fields[key] = true
+			}
+		}
+	}
+
+	if len(fields) != 5 {
+		t.Fatalf("missing fields in the records.\nExpected : level, msg, map, host and method\nReturned : %v", fields)
+	}
+}
+
+func TestExecuteFailLogLevel(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "validate", "--file", mappingFilePath, "--log-level", "trace")
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteFailLogLevel$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	_, err := cmd.Output()
+
+	exit, ok := err.(*exec.ExitError)
+	if !ok || exit.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nReturned : %v", err)
+	}
+
+	if !strings.Contains(string(exit.Stderr), "unsupported log level 'trace'") {
+		t.Fatalf("wrong error returned.\nReturned : %s", string(exit.Stderr))
+	}
+}
+
+func TestExecuteWatch(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "--name", "test-map-builder")
+		os.Args = append(os.Args, "--file", os.Getenv("MAPPING_FILE"))
+		os.Args = append(os.Args, "--watch", "--watch-interval", "20ms", "--watch-debounce", "50ms")
+		Execute()
+
+		return
+	}
+
+	b, err := os.ReadFile(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", mappingFilePath, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "mapping.json")
+	if err = os.WriteFile(file, b, 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	server := newTestingServer(t)
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecuteWatch", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("MAPPING_FILE=%s", file))
+	if err = cmd.Start(); err != nil {
+		t.Fatalf("error while starting the subprocess.\nReason : %v", err)
+	}
+
+	waitFor := func(method string) {
+		deadline := time.Now().Add(10 * time.Second)
+		for len(server.Requests(method)) == 0 {
+			if time.Now().After(deadline) {
+				cmd.Process.Kill()
+				t.Fatalf("no '%s' request was received in time", method)
+			}
+
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	// The map is created, then updated when the mapping file changes
+	waitFor("map.create")
+
+	if err = os.WriteFile(file, []byte(strings.Replace(string(b), "Firewall_(64)", "Router_(64)", -1)), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	waitFor("map.update")
+
+	if err = cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("error while stopping the subprocess.\nReason : %v", err)
+	}
+
+	if err = cmd.Wait(); err != nil {
+		t.Fatalf("expected exit code 0.\nError returned : %v", err)
+	}
+
+	if maps := server.Maps(); len(maps) != 1 {
+		t.Fatalf("the existing map should be updated.\nExpected : 1\nReturned : %d", len(maps))
+	}
 }
 
 func TestExecuteFailMissingEnvironmentVariable(t *testing.T) {
@@ -83,7 +590,7 @@ func TestExecuteFailMissingEnvironmentVariable(t *testing.T) {
 	}
 
 	// Execute test in a subprocess
-	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteFailMissingEnvironmentVariable")
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteFailMissingEnvironmentVariable$")
 	// Reset the subprocess environment variable
 	cmd.Env = []string{
 		"BE_CRASHER=1",
@@ -106,8 +613,8 @@ func TestExecuteFailMissingEnvironmentVariable(t *testing.T) {
 	}
 
 	exit := err.(*exec.ExitError)
-	if exit.ExitCode() != 1 {
-		t.Fatalf("expected exit code 1.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	if exit.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
 	}
 }
 
@@ -123,7 +630,7 @@ func TestExecuteFail(t *testing.T) {
 	}
 
 	// Execute test in a subprocess
-	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteFail")
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteFail$")
 	// Reset the subprocess environment variable
 	cmd.Env = []string{
 		"BE_CRASHER=1",
@@ -147,15 +654,100 @@ func TestExecuteFail(t *testing.T) {
 	}
 
 	exit := err.(*exec.ExitError)
-	if exit.ExitCode() != 1 {
-		t.Fatalf("expected exit code 1.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	if exit.ExitCode() != 5 {
+		t.Fatalf("expected exit code 5.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+}
+
+func TestExecuteFailUsage(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "validate", "--unknown-flag")
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteFailUsage$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	_, err := cmd.Output()
+
+	exit, ok := err.(*exec.ExitError)
+	if !ok || exit.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nReturned : %v", err)
+	}
+
+	if !strings.Contains(string(exit.Stderr), "unknown flag: --unknown-flag") || !strings.Contains(string(exit.Stderr), "--help' for usage") {
+		t.Fatalf("wrong error returned.\nReturned : %s", string(exit.Stderr))
+	}
+}
+
+func TestExecuteFailVerbose(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "validate", "--file", os.Getenv("MAPPING_FILE"), "--verbose")
+		Execute()
+
+		return
+	}
+
+	file := filepath.Join(t.TempDir(), "mapping.json")
+	if err := os.WriteFile(file, []byte(`[]`), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteFailVerbose$")
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("MAPPING_FILE=%s", file),
+	}
+	_, err := cmd.Output()
+
+	exit, ok := err.(*exec.ExitError)
+	if !ok || exit.ExitCode() != 6 {
+		t.Fatalf("expected exit code 6.\nReturned : %v", err)
+	}
+
+	for _, expected := range []string{"[CRITICAL] validation error : ", "kind : validation error (exit code 6)", "stack :"} {
+		if !strings.Contains(string(exit.Stderr), expected) {
+			t.Fatalf("missing line in the output.\nExpected : %s\nReturned : %s", expected, string(exit.Stderr))
+		}
+	}
+}
+
+func TestHandleError(t *testing.T) {
+	logger := GlobalLogger
+	defer func() {
+		GlobalLogger = logger
+	}()
+
+	var buf bytes.Buffer
+	GlobalLogger = logging.NewLogger(logging.Warning)
+	GlobalLogger.SetHandler(logging.NewTextHandler(&buf))
+
+	err := fmt.Errorf("error while building the map.\nReason : %w", failure.Errorf(failure.NotFound, "no map was found with the name 'dc1'"))
+	if code := handleError(err); code != 4 {
+		t.Fatalf("wrong exit code returned.\nExpected : 4\nReturned : %d", code)
+	}
+
+	if !strings.HasPrefix(buf.String(), "[map-builder][CRITICAL] not found : error while building the map.") {
+		t.Fatalf("wrong message logged.\nReturned : %s", buf.String())
+	}
+
+	// The trace is not logged without the '--verbose' flag
+	if strings.Contains(buf.String(), "stack :") {
+		t.Fatalf("the trace should only be logged with the 'verbose' flag.\nReturned : %s", buf.String())
+	}
+
+	if code := handleError(fmt.Errorf("unexpected error")); code != 1 {
+		t.Fatalf("wrong exit code returned.\nExpected : 1\nReturned : %d", code)
 	}
 }
 
 func TestCheckRequiredFlag(t *testing.T) {
 	err := checkRequiredFlag("name", mappingFilePath)
-	if err != "" {
-		t.Fatalf("expected no error to be returned.\nError returned : %s", err)
+	if err != nil {
+		t.Fatalf("expected no error to be returned.\nError returned : %v", err)
 	}
 }
 
@@ -163,12 +755,16 @@ func TestCheckRequiredFlagMissingName(t *testing.T) {
 	expectedError := "'name' flag is required and cannot be empty"
 
 	err := checkRequiredFlag("", mappingFilePath)
-	if err == "" {
+	if err == nil {
 		t.Fatalf("expected an error to be returned (none returned).")
 	}
 
-	if err != expectedError {
-		t.Fatalf("wrong message returned.\nExpected : %s\nReturned : %s", expectedError, err)
+	if err.Error() != expectedError {
+		t.Fatalf("wrong message returned.\nExpected : %s\nReturned : %s", expectedError, err.Error())
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Config {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Config, kind)
 	}
 }
 
@@ -176,12 +772,16 @@ func TestCheckRequiredFlagMissingFile(t *testing.T) {
 	expectedError := "'file' flag is required and cannot be empty"
 
 	err := checkRequiredFlag("name", "")
-	if err == "" {
+	if err == nil {
 		t.Fatalf("expected an error to be returned (none returned).")
 	}
 
-	if err != expectedError {
-		t.Fatalf("wrong message returned.\nExpected : %s\nReturned : %s", expectedError, err)
+	if err.Error() != expectedError {
+		t.Fatalf("wrong message returned.\nExpected : %s\nReturned : %s", expectedError, err.Error())
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Config {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Config, kind)
 	}
 }
 
@@ -189,11 +789,15 @@ func TestCheckRequiredFlagUnexistingFile(t *testing.T) {
 	expectedError := "error while reading file 'file-does-not-exist'."
 
 	err := checkRequiredFlag("name", "file-does-not-exist")
-	if err == "" {
+	if err == nil {
 		t.Fatalf("expected an error to be returned (none returned).")
 	}
 
-	if err != expectedError {
-		t.Fatalf("wrong message returned.\nExpected : %s\nReturned : %s", expectedError, err)
+	if err.Error() != expectedError {
+		t.Fatalf("wrong message returned.\nExpected : %s\nReturned : %s", expectedError, err.Error())
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Config {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Config, kind)
 	}
 }
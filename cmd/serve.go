@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/spf13/cobra"
+)
+
+// tokenVariable is the environment variable holding the token used to authenticate the requests sent to the HTTP API.
+const tokenVariable = "ZABBIX_MAP_BUILDER_TOKEN"
+
+var ServeListen string
+var ServeWorkers int
+var ServeQueueSize int
+var ServeRetention time.Duration
+
+// newServeCmd is used to generate the serve command for the CLI
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Expose an HTTP API to build the maps.",
+		Long:  "Run an HTTP server building the maps from the mapping documents posted by other tools. Each request is queued as a job executed by a bounded pool of workers, the status of the job (and the preview of a dry-run job) can then be polled. The requests are authenticated using the bearer token set in the " + tokenVariable + " environment variable.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the token was set.
+			if os.Getenv(tokenVariable) == "" {
+				return failure.Errorf(failure.Config, "'%s' environment variable is required and cannot be empty", tokenVariable)
+			}
+
+			if ServeWorkers <= 0 || ServeQueueSize <= 0 {
+				return failure.Errorf(failure.Config, "'workers' and 'queue-size' flags must be greater than 0")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			setHostLookupOptions(options)
+
+			serveOptions := &app.ServeOptions{
+				Listen:    ServeListen,
+				Token:     os.Getenv(tokenVariable),
+				Workers:   ServeWorkers,
+				QueueSize: ServeQueueSize,
+				Retention: ServeRetention,
+			}
+
+			return app.RunServe(serveOptions, options, stopOnSignal(), GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVar(&ServeListen, "listen", "127.0.0.1:8080", "address on which the HTTP API listens")
+	cmd.Flags().IntVar(&ServeWorkers, "workers", 2, "number of jobs executed concurrently")
+	cmd.Flags().IntVar(&ServeQueueSize, "queue-size", 100, "number of jobs waiting for a worker, the requests are rejected once the queue is full")
+	cmd.Flags().DurationVar(&ServeRetention, "job-retention", time.Hour, "delay during which the status of a finished job can be retrieved")
+	addHostLookupFlags(cmd)
+
+	return cmd
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// freeAddress is used to retrieve a local address on which no process is listening.
+func freeAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error while searching a free port.\nReason : %v", err)
+	}
+
+	defer l.Close()
+
+	return l.Addr().String()
+}
+
+// sendServeRequest is used to send an authenticated request to the HTTP API and to decode the returned job.
+func sendServeRequest(method string, url string, body string) (int, map[string]interface{}, error) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer serve-token")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	defer res.Body.Close()
+
+	out := make(map[string]interface{}, 0)
+	err = json.NewDecoder(res.Body).Decode(&out)
+
+	return res.StatusCode, out, err
+}
+
+func TestNewServeCmd(t *testing.T) {
+	cmd := newServeCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteServeMissingToken(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "serve")
+		Execute()
+
+		return
+	}
+
+	server := newTestingServer(t)
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecuteServeMissingToken", server, "")
+	err := cmd.Run()
+
+	if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2.\nReturned : %v", err)
+	}
+}
+
+func TestExecuteServe(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "serve", "--listen", os.Getenv("SERVE_LISTEN"))
+		Execute()
+
+		return
+	}
+
+	server := newTestingServer(t)
+	address := freeAddress(t)
+
+	// Execute test in a subprocess
+	cmd := newDeleteSubprocess("TestExecuteServe", server, "")
+	cmd.Env = append(cmd.Env, fmt.Sprintf("SERVE_LISTEN=%s", address), "ZABBIX_MAP_BUILDER_TOKEN=serve-token")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("error while starting the subprocess.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(mappingFilePath)
+	if err != nil {
+		cmd.Process.Kill()
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", mappingFilePath, err)
+	}
+
+	// Wait for the server to accept the requests
+	var status int
+	var job map[string]interface{}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		status, job, err = sendServeRequest(http.MethodPost, fmt.Sprintf("http://%s/maps?name=serve-map", address), string(b))
+		if err == nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			t.Fatalf("the HTTP API was not started in time.\nReason : %v", err)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if status != http.StatusAccepted {
+		cmd.Process.Kill()
+		t.Fatalf("wrong status code returned.\nExpected : %d\nReturned : %d (%v)", http.StatusAccepted, status, job)
+	}
+
+	// Wait for the job to finish
+	for job["status"] == "queued" || job["status"] == "running" {
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			t.Fatalf("the job was not finished in time")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		if _, job, err = sendServeRequest(http.MethodGet, fmt.Sprintf("http://%s/jobs/%s", address, job["id"]), ""); err != nil {
+			cmd.Process.Kill()
+			t.Fatalf("error while retrieving the job.\nReason : %v", err)
+		}
+	}
+
+	if job["status"] != "succeeded" {
+		cmd.Process.Kill()
+		t.Fatalf("wrong job status returned.\nExpected : succeeded\nReturned : %v", job)
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("error while stopping the subprocess.\nReason : %v", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("expected exit code 0.\nError returned : %v", err)
+	}
+
+	if maps := server.Maps(); len(maps) != 1 || maps[0].Name != "serve-map" {
+		t.Fatalf("the map 'serve-map' should be created.\nReturned : %v", maps)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var SnapshotFile string
+var SnapshotOutFile string
+var SnapshotFormat string
+
+// newSnapshotCmd is used to generate the snapshot command for the CLI
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export the Zabbix objects used by a mapping file to a local snapshot.",
+		Long:  "Export the hosts, images, triggers and items referenced by the given mapping file to a local JSON file. The snapshot can then be used with the '--from-snapshot' flag to build a map without access to the Zabbix server.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the file flag was set correctly.
+			return checkFile(SnapshotFile)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := getEnvironmentVariables()
+			if err != nil {
+				return err
+			}
+
+			options.OutFile = SnapshotOutFile
+			options.Format = SnapshotFormat
+			options.IconRules = IconRules
+			setHostLookupOptions(options)
+
+			return app.RunSnapshot(SnapshotFile, options, GlobalLogger)
+		},
+	}
+
+	cmd.Flags().StringVarP(&SnapshotFile, "file", "f", "", "file containing the hosts mapping")
+	cmd.Flags().StringVar(&SnapshotFormat, "format", "", "format of the mapping file (json, yaml, csv or dot), detected from the file extension if not set")
+	cmd.Flags().StringVarP(&SnapshotOutFile, "output", "o", "", "file used to store the snapshot")
+	cmd.Flags().StringVar(&IconRules, "icon-rules", "", "file (YAML or JSON) containing the rules used to select the image of the hosts without one, the images of the rules are stored in the snapshot")
+	addHostLookupFlags(cmd)
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("output")
+
+	return cmd
+}
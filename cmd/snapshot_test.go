@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewSnapshotCmd(t *testing.T) {
+	cmd := newSnapshotCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteSnapshot(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		// Set the required arguments
+		os.Args = append(os.Args, "snapshot", "--file", mappingFilePath, "--output", os.Getenv("SNAPSHOT_FILE"))
+		Execute()
+
+		return
+	}
+
+	// Start a fake Zabbix server
+	server := newTestingServer(t)
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.json")
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteSnapshot$")
+	// Reset the subprocess environment variable
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("SNAPSHOT_FILE=%s", snapshotFile),
+	}
+	// Add the required environment variables
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_URL=%s", server.ApiUrl()))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_USER=%s", ZABBIX_USER))
+	cmd.Env = append(cmd.Env, fmt.Sprintf("ZABBIX_PWD=%s", ZABBIX_PWD))
+	cmd.Stderr = os.Stderr
+	// Run the command in the subprocess
+	err := cmd.Run()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if _, err = os.Stat(snapshotFile); err != nil {
+		t.Fatalf("the snapshot file '%s' was not created.\nReason : %v", snapshotFile, err)
+	}
+
+	if len(server.Requests("item.get")) == 0 {
+		t.Fatal("no 'item.get' request was sent to the server")
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var ValidateFile string
+var ValidateFormat string
+
+// newValidateCmd is used to generate the validate command for the CLI
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a mapping file without building the map.",
+		Long:  "Validate the given mapping file. Unknown fields, missing required fields, self-links and duplicate links are reported with the index of the mapping and its position in the file. The Zabbix server is not used.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check if the file flag was set correctly.
+			return checkFile(ValidateFile)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := app.RunValidate(ValidateFile, &app.Options{Format: ValidateFormat}, GlobalLogger)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("mapping file '%s' is valid\n", ValidateFile)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&ValidateFile, "file", "f", "", "file containing the hosts mapping")
+	cmd.Flags().StringVar(&ValidateFormat, "format", "", "format of the mapping file (json, yaml, csv or dot), detected from the file extension if not set")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
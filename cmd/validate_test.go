@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewValidateCmd(t *testing.T) {
+	cmd := newValidateCmd()
+	if cmd == nil {
+		t.Fatalf("expected a *cobra.Command.\nReturned a nil pointer")
+	}
+}
+
+func TestExecuteValidate(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "validate", "--file", mappingFilePath)
+		Execute()
+
+		return
+	}
+
+	// Execute test in a subprocess, no environment variable is required
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteValidate$")
+	cmd.Env = []string{"BE_CRASHER=1"}
+	out, err := cmd.Output()
+
+	if err != nil {
+		exit := err.(*exec.ExitError)
+		t.Fatalf("expected exit code 0.\nCode returned : %d\nError returned : %s", exit.ExitCode(), string(exit.Stderr))
+	}
+
+	if !strings.Contains(string(out), "is valid") {
+		t.Fatalf("wrong output returned.\nReturned : %s", string(out))
+	}
+}
+
+func TestExecuteValidateFail(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		os.Args = append(os.Args, "validate", "--file", os.Getenv("MAPPING_FILE"))
+		Execute()
+
+		return
+	}
+
+	file := filepath.Join(t.TempDir(), "mapping.json")
+	if err := os.WriteFile(file, []byte(`[{"local_host": "router-1", "remote_imgae": "Switch_(64)"}]`), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+	}
+
+	// Execute test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecuteValidateFail$")
+	cmd.Env = []string{
+		"BE_CRASHER=1",
+		fmt.Sprintf("MAPPING_FILE=%s", file),
+	}
+	_, err := cmd.Output()
+
+	exit, ok := err.(*exec.ExitError)
+	if !ok || exit.ExitCode() != 6 {
+		t.Fatalf("expected exit code 6.\nError returned : %v", err)
+	}
+
+	if !strings.Contains(string(exit.Stderr), "unknown field 'remote_imgae'") {
+		t.Fatalf("the unknown field was not reported.\nReturned : %s", string(exit.Stderr))
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fakeserver"
+	"github.com/spf13/cobra"
+)
+
+var datasetFile string
+
+var rootCmd = &cobra.Command{
+	Use:   "",
+	Short: "Run a fake Zabbix server.",
+	Long:  "Run a local fake Zabbix JSON-RPC server exposing the given dataset. Can be used to try the CLI without a Zabbix server.",
+	Run: func(cmd *cobra.Command, args []string) {
+		run(datasetFile)
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&datasetFile, "file", "examples/fake_dataset.json", "path of the dataset file")
+}
+
+func run(file string) {
+	dataset, err := fakeserver.LoadDataset(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(dataset.Users) == 0 {
+		log.Fatalf("no users were found in the dataset '%s'", file)
+	}
+
+	s := fakeserver.NewServer(dataset)
+	defer s.Close()
+
+	fmt.Printf("fake Zabbix server listening, use the following environment variables :\n")
+	fmt.Printf("export ZABBIX_URL=\"%s\"\n", s.ApiUrl())
+	fmt.Printf("export ZABBIX_USER=\"%s\"\n", dataset.Users[0].Username)
+	fmt.Printf("export ZABBIX_PWD=\"%s\"\n", dataset.Users[0].Password)
+
+	// Wait for an interrupt signal
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	<-c
+
+	for _, r := range s.Requests() {
+		fmt.Printf("%s %s\n", r.Method, string(r.Params))
+	}
+}
+
+func main() {
+	err := rootCmd.Execute()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
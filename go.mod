@@ -5,9 +5,12 @@ go 1.19
 require (
 	github.com/Spartan0nix/zabbix-go-sdk/v2 v2.1.2
 	github.com/spf13/cobra v1.7.0
+	golang.org/x/image v0.18.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/text v0.16.0 // indirect
 )
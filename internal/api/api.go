@@ -2,8 +2,10 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
 	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
 )
 
@@ -16,7 +18,7 @@ func initService(url string) (*zabbixgosdk.ZabbixService, error) {
 	client.Trigger.Client.Url = url
 
 	if err := client.Auth.Client.CheckConnectivity(); err != nil {
-		return nil, err
+		return nil, failure.Errorf(failure.API, "the Zabbix API '%s' is unreachable.\nReason : %v", url, err)
 	}
 
 	return client, nil
@@ -31,17 +33,17 @@ func authenticate(client *zabbixgosdk.ZabbixService, user string, password strin
 
 	res, err := client.Auth.GetCredentials(u.User, u.Pwd)
 	if err != nil {
-		return err
+		return failure.New(failure.Auth, err)
 	}
 
 	if len(res.Result) == 0 {
-		return fmt.Errorf("no token were returned during the authentification phase")
+		return failure.Errorf(failure.Auth, "no token were returned during the authentification phase")
 	}
 
 	var token string
 	err = client.Auth.Client.ConvertResponse(*res, &token)
 	if err != nil {
-		return err
+		return failure.New(failure.Auth, err)
 	}
 
 	client.Map.Client.Token = token
@@ -53,12 +55,22 @@ func authenticate(client *zabbixgosdk.ZabbixService, user string, password strin
 // InitApi is used to initialize the default Zabbix service to interact with the API.
 // A connectivity test is also run during this step.
 func InitApi(url string, user string, password string) (*zabbixgosdk.ZabbixService, error) {
+	return InitInstrumentedApi(url, user, password, nil)
+}
+
+// InitInstrumentedApi is used to initialize the API client like InitApi, the authentication call ('user.login') is recorded using the given observer.
+func InitInstrumentedApi(url string, user string, password string, observe Observer) (*zabbixgosdk.ZabbixService, error) {
 	client, err := initService(url)
 	if err != nil {
 		return nil, err
 	}
 
+	start := time.Now()
 	err = authenticate(client, user, password)
+	if observe != nil {
+		observe("user.login", time.Since(start), err)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +82,7 @@ func InitApi(url string, user string, password string) (*zabbixgosdk.ZabbixServi
 func Logout(client *zabbixgosdk.ZabbixService) error {
 	err := client.Logout()
 	if err != nil {
-		return err
+		return failure.New(failure.API, err)
 	}
 
 	return nil
@@ -79,19 +91,10 @@ func Logout(client *zabbixgosdk.ZabbixService) error {
 // GetHostsId is used to retrive the id of the given hosts.
 // The hosts name must be set as key in the map.
 // Map value for each key will be replace by the id of the host retrieve from the Zabbix server.
-func GetHostsId(client *zabbixgosdk.ZabbixService, hosts map[string]string) (map[string]string, error) {
+func GetHostsId(client ZabbixAPI, hosts map[string]string) (map[string]string, error) {
 	hostsName := utils.GetMapKey(hosts)
 
-	h, err := client.Host.Get(&zabbixgosdk.HostGetParameters{
-		Output: []string{
-			"hostid",
-			"host",
-		},
-		Filter: map[string][]string{
-			"host": hostsName,
-		},
-	})
-
+	h, err := client.GetHosts(hostsName)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +104,7 @@ func GetHostsId(client *zabbixgosdk.ZabbixService, hosts map[string]string) (map
 			return nil, fmt.Errorf("unknown key '%s'", host.Host)
 		}
 
-		hosts[host.Host] = host.HostId
+		hosts[host.Host] = host.Id
 	}
 
 	return hosts, nil
@@ -110,19 +113,10 @@ func GetHostsId(client *zabbixgosdk.ZabbixService, hosts map[string]string) (map
 // GetImagesId is used to retrive the id of the given images.
 // The images name must be set as key in the map.
 // Map value for each key will be replace by the id of the host retrieve from the Zabbix server.
-func GetImagesId(client *zabbixgosdk.ZabbixService, images map[string]string) (map[string]string, error) {
+func GetImagesId(client ZabbixAPI, images map[string]string) (map[string]string, error) {
 	imagesName := utils.GetMapKey(images)
 
-	i, err := client.Image.Get(&zabbixgosdk.ImageGetParameters{
-		Output: []string{
-			"imageid",
-			"name",
-		},
-		Filter: map[string][]string{
-			"name": imagesName,
-		},
-	})
-
+	i, err := client.GetImages(imagesName)
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +126,7 @@ func GetImagesId(client *zabbixgosdk.ZabbixService, images map[string]string) (m
 			return nil, fmt.Errorf("missing key for image '%s'", image.Name)
 		}
 
-		images[image.Name] = image.ImageId
+		images[image.Name] = image.Id
 	}
 
 	return images, nil
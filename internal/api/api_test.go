@@ -1,10 +1,9 @@
 package api
 
 import (
-	"log"
 	"testing"
 
-	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
 )
 
 const (
@@ -13,14 +12,14 @@ const (
 	ZABBIX_PWD  = "zabbix"
 )
 
-var testingClient *zabbixgosdk.ZabbixService
-
-func init() {
-	var err error
-	testingClient, err = InitApi(ZABBIX_URL, ZABBIX_USER, ZABBIX_PWD)
+// getTestingClient is used to initialize a Client for the tests requiring a Zabbix server.
+func getTestingClient(t *testing.T) *Client {
+	service, err := InitApi(ZABBIX_URL, ZABBIX_USER, ZABBIX_PWD)
 	if err != nil {
-		log.Fatalf("error while executing InitApi function.\nReason : %v", err)
+		t.Fatalf("error while executing InitApi function.\nReason : %v", err)
 	}
+
+	return NewClient(service)
 }
 
 func TestInitService(t *testing.T) {
@@ -57,6 +56,10 @@ func TestInitServiceFailConnectivity(t *testing.T) {
 	if c != nil {
 		t.Fatalf("a nil pointer should be returned when the server is unreachable instead of *zabbixgosdk.ZabbixService")
 	}
+
+	if kind := failure.KindOf(err); kind != failure.API {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.API, kind)
+	}
 }
 
 func TestAuthenticate(t *testing.T) {
@@ -163,7 +166,7 @@ func TestLogout(t *testing.T) {
 }
 
 func TestGetHostsId(t *testing.T) {
-	hosts, err := GetHostsId(testingClient, map[string]string{
+	hosts, err := GetHostsId(getTestingClient(t), map[string]string{
 		"Zabbix server": "",
 	})
 	if err != nil {
@@ -180,7 +183,7 @@ func TestGetHostsId(t *testing.T) {
 }
 
 func TestGetImagesId(t *testing.T) {
-	images, err := GetImagesId(testingClient, map[string]string{
+	images, err := GetImagesId(getTestingClient(t), map[string]string{
 		"Cloud_(24)": "",
 	})
 	if err != nil {
@@ -0,0 +1,718 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// ZabbixAPI define the Zabbix API operations used to build a map.
+// Any implementation (SDK backed client, in-memory fake, etc.) can be passed to the build functions.
+type ZabbixAPI interface {
+	// GetHosts is used to retrieve the hosts matching the given technical names.
+	GetHosts(names []string) ([]*Host, error)
+	// GetHostsById is used to retrieve the hosts matching the given ids, including their visible name, tags, templates, inventory type and host groups.
+	GetHostsById(ids []string) ([]*Host, error)
+	// GetHostsByName is used to retrieve the hosts matching the given visible names.
+	GetHostsByName(names []string) ([]*Host, error)
+	// GetHostsByTag is used to retrieve the hosts with the given tag set to one of the given values, including their tags.
+	GetHostsByTag(tag string, values []string) ([]*Host, error)
+	// GetHostInterfaces is used to retrieve the host interfaces with an IP address or a DNS name matching one of the given addresses.
+	GetHostInterfaces(addresses []string) ([]*HostInterface, error)
+	// GetImages is used to retrieve the images matching the given names.
+	GetImages(names []string) ([]*Image, error)
+	// GetImagesData is used to retrieve the images matching the given ids, including the base64 encoded content of each image.
+	GetImagesData(ids []string) ([]*Image, error)
+	// CreateImage is used to create an icon image with the given name and base64 encoded content, the id of the image is returned.
+	CreateImage(name string, data string) (string, error)
+	// UpdateImage is used to replace the base64 encoded content of the given image.
+	UpdateImage(id string, data string) error
+	// GetTriggers is used to retrieve the triggers of the given host matching the given description.
+	// If the description is empty, all the triggers of the host are returned.
+	GetTriggers(hostId string, description string) ([]*Trigger, error)
+	// GetTriggersById is used to retrieve the triggers matching the given ids, including the host of each trigger.
+	GetTriggersById(ids []string) ([]*Trigger, error)
+	// GetItems is used to retrieve the items of the given host.
+	GetItems(hostId string) ([]*Item, error)
+	// GetHostGroups is used to retrieve the host groups of the given host.
+	GetHostGroups(hostId string) ([]*HostGroup, error)
+	// GetHostGroupsByName is used to retrieve the host groups matching the given names.
+	GetHostGroupsByName(names []string) ([]*HostGroup, error)
+	// GetHostGroupsById is used to retrieve the host groups matching the given ids.
+	GetHostGroupsById(ids []string) ([]*HostGroup, error)
+	// GetMaps is used to retrieve the maps matching the given names, including their elements and links.
+	GetMaps(names []string) ([]*Map, error)
+	// GetMapsById is used to retrieve the maps matching the given ids (only the id and the name of the maps are returned).
+	GetMapsById(ids []string) ([]*Map, error)
+	// SearchMaps is used to retrieve the maps whose name starts with the given prefix (only the id and the name of the maps are returned).
+	SearchMaps(prefix string) ([]*Map, error)
+	// DeleteMaps is used to delete the maps with the given ids.
+	DeleteMaps(ids []string) error
+	// CreateMap is used to create the given map and return the ids of the created maps.
+	CreateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error)
+	// UpdateMap is used to replace the definition of the map with the id set in the given map and return the ids of the updated maps.
+	UpdateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error)
+	// GetUsers is used to retrieve the users matching the given usernames.
+	GetUsers(names []string) ([]*User, error)
+	// GetUserGroups is used to retrieve the user groups matching the given names.
+	GetUserGroups(names []string) ([]*UserGroup, error)
+	// Logout is used to release the API token.
+	Logout() error
+}
+
+// Host define the properties of an host retrieved from the Zabbix server.
+type Host struct {
+	Id   string `json:"hostid"`
+	Host string `json:"host"`
+	// Name, Tags, Templates, Inventory and HostGroups are only set when explicitly requested.
+	Name       string         `json:"name,omitempty"`
+	Tags       []*HostTag     `json:"tags,omitempty"`
+	Templates  []*Template    `json:"parentTemplates,omitempty"`
+	Inventory  *HostInventory `json:"inventory,omitempty"`
+	HostGroups []*HostGroup   `json:"hostgroups,omitempty"`
+}
+
+// Template define a template linked to an host.
+type Template struct {
+	Id   string `json:"templateid,omitempty"`
+	Name string `json:"name"`
+}
+
+// HostInventory define the inventory fields of an host used to build the map.
+type HostInventory struct {
+	Type string `json:"type,omitempty"`
+}
+
+// UnmarshalJSON is used to decode the inventory of an host.
+// The Zabbix API returns an empty list instead of an object when the inventory is disabled.
+func (i *HostInventory) UnmarshalJSON(b []byte) error {
+	list := make([]interface{}, 0)
+	if err := json.Unmarshal(b, &list); err == nil {
+		return nil
+	}
+
+	// Use an alias to prevent infinite recursion
+	type inventory HostInventory
+	return json.Unmarshal(b, (*inventory)(i))
+}
+
+// HostTag define a tag of an host.
+type HostTag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// HostInterface define the properties of an host interface retrieved from the Zabbix server.
+type HostInterface struct {
+	Id     string `json:"interfaceid,omitempty"`
+	HostId string `json:"hostid"`
+	Ip     string `json:"ip"`
+	Dns    string `json:"dns"`
+}
+
+// Image define the properties of an image retrieved from the Zabbix server.
+type Image struct {
+	Id   string `json:"imageid"`
+	Name string `json:"name"`
+	// Data contains the base64 encoded content of the image, only set when explicitly requested.
+	Data string `json:"image,omitempty"`
+}
+
+// Trigger define the properties of a trigger retrieved from the Zabbix server.
+type Trigger struct {
+	Id          string `json:"triggerid"`
+	Description string `json:"description"`
+	// Hosts are only set when explicitly requested.
+	Hosts []*Host `json:"hosts,omitempty"`
+}
+
+// Item define the properties of an item retrieved from the Zabbix server.
+type Item struct {
+	Id   string `json:"itemid"`
+	Name string `json:"name"`
+	Key  string `json:"key_"`
+}
+
+// HostGroup define the properties of an host group retrieved from the Zabbix server.
+type HostGroup struct {
+	Id   string `json:"groupid"`
+	Name string `json:"name"`
+}
+
+// User define the properties of an user retrieved from the Zabbix server.
+type User struct {
+	Id       string `json:"userid"`
+	Username string `json:"username"`
+}
+
+// UserGroup define the properties of an user group retrieved from the Zabbix server.
+type UserGroup struct {
+	Id   string `json:"usrgrpid"`
+	Name string `json:"name"`
+}
+
+// Map define a map retrieved from the Zabbix server with its elements and links.
+type Map struct {
+	zabbixgosdk.MapCreateParameters
+	Id string `json:"sysmapid"`
+}
+
+// Client is the ZabbixAPI implementation using the Zabbix SDK.
+type Client struct {
+	service *zabbixgosdk.ZabbixService
+}
+
+// NewClient is used to wrap the given ZabbixService in a new Client.
+func NewClient(service *zabbixgosdk.ZabbixService) *Client {
+	return &Client{
+		service: service,
+	}
+}
+
+// GetHosts is used to retrieve the hosts matching the given technical names.
+func (c *Client) GetHosts(names []string) ([]*Host, error) {
+	h, err := c.service.Host.Get(&zabbixgosdk.HostGetParameters{
+		Output: []string{
+			"hostid",
+			"host",
+		},
+		Filter: map[string][]string{
+			"host": names,
+		},
+	})
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	out := make([]*Host, 0)
+	for _, host := range h {
+		out = append(out, &Host{
+			Id:   host.HostId,
+			Host: host.Host,
+		})
+	}
+
+	return out, nil
+}
+
+// GetHostsById is used to retrieve the hosts matching the given ids, including their visible name, tags, templates, inventory type and host groups.
+func (c *Client) GetHostsById(ids []string) ([]*Host, error) {
+	out := make([]*Host, 0)
+
+	err := c.call("host.get", map[string]interface{}{
+		"output": []string{
+			"hostid",
+			"host",
+			"name",
+		},
+		"selectTags": []string{
+			"tag",
+			"value",
+		},
+		"selectParentTemplates": []string{
+			"templateid",
+			"name",
+		},
+		"selectInventory": []string{
+			"type",
+		},
+		"selectHostGroups": []string{
+			"groupid",
+			"name",
+		},
+		"hostids": ids,
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetHostsByName is used to retrieve the hosts matching the given visible names.
+func (c *Client) GetHostsByName(names []string) ([]*Host, error) {
+	out := make([]*Host, 0)
+
+	err := c.call("host.get", map[string]interface{}{
+		"output": []string{
+			"hostid",
+			"host",
+			"name",
+		},
+		"filter": map[string][]string{
+			"name": names,
+		},
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetHostsByTag is used to retrieve the hosts with the given tag set to one of the given values, including their tags.
+func (c *Client) GetHostsByTag(tag string, values []string) ([]*Host, error) {
+	out := make([]*Host, 0)
+
+	tags := make([]map[string]string, 0)
+	for _, value := range values {
+		tags = append(tags, map[string]string{
+			"tag":   tag,
+			"value": value,
+			// Equals
+			"operator": "1",
+		})
+	}
+
+	err := c.call("host.get", map[string]interface{}{
+		"output": []string{
+			"hostid",
+			"host",
+			"name",
+		},
+		"selectTags": []string{
+			"tag",
+			"value",
+		},
+		// Or
+		"evaltype": 2,
+		"tags":     tags,
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetHostInterfaces is used to retrieve the host interfaces with an IP address or a DNS name matching one of the given addresses.
+// The filters of the API are combined with a logical AND, the interfaces are retrieved by IP address then by DNS name and merged.
+func (c *Client) GetHostInterfaces(addresses []string) ([]*HostInterface, error) {
+	out := make([]*HostInterface, 0)
+	ids := make([]string, 0)
+
+	for _, field := range []string{"ip", "dns"} {
+		interfaces := make([]*HostInterface, 0)
+
+		err := c.call("hostinterface.get", map[string]interface{}{
+			"output": []string{
+				"interfaceid",
+				"hostid",
+				"ip",
+				"dns",
+			},
+			"filter": map[string][]string{
+				field: addresses,
+			},
+		}, &interfaces)
+
+		if err != nil {
+			return nil, failure.New(failure.API, err)
+		}
+
+		for _, i := range interfaces {
+			if !utils.Contains(ids, i.Id) {
+				ids = append(ids, i.Id)
+				out = append(out, i)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// GetImages is used to retrieve the images matching the given names.
+func (c *Client) GetImages(names []string) ([]*Image, error) {
+	i, err := c.service.Image.Get(&zabbixgosdk.ImageGetParameters{
+		Output: []string{
+			"imageid",
+			"name",
+		},
+		Filter: map[string][]string{
+			"name": names,
+		},
+	})
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	out := make([]*Image, 0)
+	for _, image := range i {
+		out = append(out, &Image{
+			Id:   image.ImageId,
+			Name: image.Name,
+		})
+	}
+
+	return out, nil
+}
+
+// GetImagesData is used to retrieve the images matching the given ids, including the base64 encoded content of each image.
+func (c *Client) GetImagesData(ids []string) ([]*Image, error) {
+	out := make([]*Image, 0)
+
+	err := c.call("image.get", map[string]interface{}{
+		"output": []string{
+			"imageid",
+			"name",
+		},
+		"imageids":     ids,
+		"select_image": true,
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// CreateImage is used to create an icon image with the given name and base64 encoded content, the id of the image is returned.
+func (c *Client) CreateImage(name string, data string) (string, error) {
+	res := struct {
+		ImageIds []string `json:"imageids"`
+	}{}
+
+	err := c.call("image.create", map[string]interface{}{
+		"name":      name,
+		"imagetype": 1,
+		"image":     data,
+	}, &res)
+
+	if err != nil {
+		return "", failure.New(failure.API, err)
+	}
+
+	if len(res.ImageIds) == 0 {
+		return "", failure.Errorf(failure.API, "an empty response was returned when creating the image '%s'", name)
+	}
+
+	return res.ImageIds[0], nil
+}
+
+// UpdateImage is used to replace the base64 encoded content of the given image.
+func (c *Client) UpdateImage(id string, data string) error {
+	res := struct {
+		ImageIds []string `json:"imageids"`
+	}{}
+
+	err := c.call("image.update", map[string]interface{}{
+		"imageid": id,
+		"image":   data,
+	}, &res)
+
+	return failure.New(failure.API, err)
+}
+
+// GetTriggers is used to retrieve the triggers of the given host matching the given description.
+// If the description is empty, all the triggers of the host are returned.
+func (c *Client) GetTriggers(hostId string, description string) ([]*Trigger, error) {
+	params := &zabbixgosdk.TriggerGetParameters{
+		Output: []string{
+			"triggerid",
+			"description",
+		},
+		HostIds: []string{
+			hostId,
+		},
+	}
+
+	if description != "" {
+		params.Filter = map[string]string{
+			"description": description,
+		}
+	}
+
+	t, err := c.service.Trigger.Get(params)
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	out := make([]*Trigger, 0)
+	for _, trigger := range t {
+		out = append(out, &Trigger{
+			Id:          trigger.Id,
+			Description: trigger.Description,
+		})
+	}
+
+	return out, nil
+}
+
+// GetTriggersById is used to retrieve the triggers matching the given ids, including the host of each trigger.
+func (c *Client) GetTriggersById(ids []string) ([]*Trigger, error) {
+	out := make([]*Trigger, 0)
+
+	err := c.call("trigger.get", map[string]interface{}{
+		"output": []string{
+			"triggerid",
+			"description",
+		},
+		"selectHosts": []string{
+			"hostid",
+			"host",
+		},
+		"triggerids": ids,
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetItems is used to retrieve the items of the given host.
+func (c *Client) GetItems(hostId string) ([]*Item, error) {
+	out := make([]*Item, 0)
+
+	err := c.call("item.get", map[string]interface{}{
+		"output": []string{
+			"itemid",
+			"name",
+			"key_",
+		},
+		"hostids": []string{
+			hostId,
+		},
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetHostGroups is used to retrieve the host groups of the given host.
+func (c *Client) GetHostGroups(hostId string) ([]*HostGroup, error) {
+	out := make([]*HostGroup, 0)
+
+	err := c.call("hostgroup.get", map[string]interface{}{
+		"output": []string{
+			"groupid",
+			"name",
+		},
+		"hostids": []string{
+			hostId,
+		},
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetHostGroupsByName is used to retrieve the host groups matching the given names.
+func (c *Client) GetHostGroupsByName(names []string) ([]*HostGroup, error) {
+	out := make([]*HostGroup, 0)
+
+	err := c.call("hostgroup.get", map[string]interface{}{
+		"output": []string{
+			"groupid",
+			"name",
+		},
+		"filter": map[string][]string{
+			"name": names,
+		},
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetHostGroupsById is used to retrieve the host groups matching the given ids.
+func (c *Client) GetHostGroupsById(ids []string) ([]*HostGroup, error) {
+	out := make([]*HostGroup, 0)
+
+	err := c.call("hostgroup.get", map[string]interface{}{
+		"output": []string{
+			"groupid",
+			"name",
+		},
+		"groupids": ids,
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetMaps is used to retrieve the maps matching the given names, including their elements and links.
+func (c *Client) GetMaps(names []string) ([]*Map, error) {
+	out := make([]*Map, 0)
+
+	err := c.call("map.get", map[string]interface{}{
+		"output":          "extend",
+		"selectSelements": "extend",
+		"selectLinks":     "extend",
+		"filter": map[string][]string{
+			"name": names,
+		},
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetMapsById is used to retrieve the maps matching the given ids (only the id and the name of the maps are returned).
+func (c *Client) GetMapsById(ids []string) ([]*Map, error) {
+	out := make([]*Map, 0)
+
+	err := c.call("map.get", map[string]interface{}{
+		"output": []string{
+			"sysmapid",
+			"name",
+		},
+		"sysmapids": ids,
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// SearchMaps is used to retrieve the maps whose name starts with the given prefix (only the id and the name of the maps are returned).
+func (c *Client) SearchMaps(prefix string) ([]*Map, error) {
+	maps := make([]*Map, 0)
+
+	err := c.call("map.get", map[string]interface{}{
+		"output": []string{
+			"sysmapid",
+			"name",
+		},
+		"search": map[string][]string{
+			"name": {prefix},
+		},
+		"startSearch": true,
+	}, &maps)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	// The search is case insensitive on some databases, only keep the exact prefix
+	out := make([]*Map, 0)
+	for _, m := range maps {
+		if strings.HasPrefix(m.Name, prefix) {
+			out = append(out, m)
+		}
+	}
+
+	return out, nil
+}
+
+// DeleteMaps is used to delete the maps with the given ids.
+func (c *Client) DeleteMaps(ids []string) error {
+	res := make(map[string][]string, 0)
+
+	return c.call("map.delete", ids, &res)
+}
+
+// CreateMap is used to create the given map and return the ids of the created maps.
+func (c *Client) CreateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error) {
+	res, err := c.service.Map.Create(m)
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	if res == nil {
+		return nil, failure.Errorf(failure.API, "an empty response was returned when creating the map")
+	}
+
+	return res.MapIds, nil
+}
+
+// UpdateMap is used to replace the definition of the map with the id set in the given map.
+func (c *Client) UpdateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error) {
+	if m.Id == "" {
+		return nil, failure.Errorf(failure.Validation, "the id of the map is required to update the map '%s'", m.Name)
+	}
+
+	res := &zabbixgosdk.MapResponse{}
+	if err := c.call("map.update", m, res); err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return res.MapIds, nil
+}
+
+// GetUsers is used to retrieve the users matching the given usernames.
+func (c *Client) GetUsers(names []string) ([]*User, error) {
+	out := make([]*User, 0)
+
+	err := c.call("user.get", map[string]interface{}{
+		"output": []string{
+			"userid",
+			"username",
+		},
+		"filter": map[string][]string{
+			"username": names,
+		},
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// GetUserGroups is used to retrieve the user groups matching the given names.
+func (c *Client) GetUserGroups(names []string) ([]*UserGroup, error) {
+	out := make([]*UserGroup, 0)
+
+	err := c.call("usergroup.get", map[string]interface{}{
+		"output": []string{
+			"usrgrpid",
+			"name",
+		},
+		"filter": map[string][]string{
+			"name": names,
+		},
+	}, &out)
+
+	if err != nil {
+		return nil, failure.New(failure.API, err)
+	}
+
+	return out, nil
+}
+
+// Logout is used to release the API token retrieve during the intialization of the API client.
+func (c *Client) Logout() error {
+	return failure.New(failure.API, c.service.Logout())
+}
+
+// call is used to execute a request for an API method not covered by the SDK services.
+// The result of the request is converted to the given interface.
+func (c *Client) call(method string, params interface{}, v interface{}) error {
+	req := c.service.Map.Client.NewRequest(method, params)
+
+	res, err := c.service.Map.Client.Post(req)
+	if err != nil {
+		return failure.New(failure.API, err)
+	}
+
+	return failure.New(failure.API, c.service.Map.Client.ConvertResponse(*res, v))
+}
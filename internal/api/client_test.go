@@ -0,0 +1,320 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+)
+
+func TestNewClient(t *testing.T) {
+	c := NewClient(zabbixgosdk.NewZabbixService())
+	if c == nil {
+		t.Fatal("a nil pointer was returned instead of *Client")
+	}
+
+	if c.service == nil {
+		t.Fatal("the ZabbixService was not set correctly")
+	}
+}
+
+func TestClientGetHosts(t *testing.T) {
+	hosts, err := getTestingClient(t).GetHosts([]string{"Zabbix server"})
+	if err != nil {
+		t.Fatalf("error while executing GetHosts function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 1 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 1\nReturned : %d", len(hosts))
+	}
+
+	if hosts[0].Id == "" {
+		t.Fatal("no hostid was returned for the 'Zabbix server' host")
+	}
+}
+
+func TestClientGetImages(t *testing.T) {
+	images, err := getTestingClient(t).GetImages([]string{"Cloud_(24)"})
+	if err != nil {
+		t.Fatalf("error while executing GetImages function.\nReason : %v", err)
+	}
+
+	if len(images) != 1 {
+		t.Fatalf("wrong number of images returned.\nExpected : 1\nReturned : %d", len(images))
+	}
+}
+
+func TestClientGetTriggers(t *testing.T) {
+	c := getTestingClient(t)
+
+	hosts, err := c.GetHosts([]string{"Zabbix server"})
+	if err != nil || len(hosts) == 0 {
+		t.Fatalf("error while retrieving host 'Zabbix server'.\nReason : %v", err)
+	}
+
+	triggers, err := c.GetTriggers(hosts[0].Id, "High CPU utilization")
+	if err != nil {
+		t.Fatalf("error while executing GetTriggers function.\nReason : %v", err)
+	}
+
+	if len(triggers) != 1 {
+		t.Fatalf("wrong number of triggers returned.\nExpected : 1\nReturned : %d", len(triggers))
+	}
+}
+
+func TestClientGetHostGroups(t *testing.T) {
+	c := getTestingClient(t)
+
+	hosts, err := c.GetHosts([]string{"Zabbix server"})
+	if err != nil || len(hosts) == 0 {
+		t.Fatalf("error while retrieving host 'Zabbix server'.\nReason : %v", err)
+	}
+
+	groups, err := c.GetHostGroups(hosts[0].Id)
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroups function.\nReason : %v", err)
+	}
+
+	if len(groups) == 0 {
+		t.Fatal("no host group was returned for the host 'Zabbix server'")
+	}
+}
+
+func TestHostInventoryUnmarshal(t *testing.T) {
+	h := &Host{}
+	if err := json.Unmarshal([]byte(`{"hostid": "1", "inventory": {"type": "switch"}}`), h); err != nil {
+		t.Fatalf("error while decoding the host.\nReason : %v", err)
+	}
+
+	if h.Inventory == nil || h.Inventory.Type != "switch" {
+		t.Fatalf("wrong inventory returned.\nExpected : switch\nReturned : %v", h.Inventory)
+	}
+
+	// The inventory is returned as an empty list when disabled
+	h = &Host{}
+	if err := json.Unmarshal([]byte(`{"hostid": "1", "inventory": []}`), h); err != nil {
+		t.Fatalf("error while decoding an host with a disabled inventory.\nReason : %v", err)
+	}
+
+	if h.Inventory == nil || h.Inventory.Type != "" {
+		t.Fatalf("an empty inventory should be returned.\nReturned : %v", h.Inventory)
+	}
+}
+
+func TestClientGetHostInterfaces(t *testing.T) {
+	// '192.168.1.2' is the IP address of an interface without DNS name, 'router-1.example.com' the DNS name of an interface
+	interfaces, err := getFakeClient(t).GetHostInterfaces([]string{"192.168.1.2", "router-1.example.com", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostInterfaces function.\nReason : %v", err)
+	}
+
+	// The interface of 'router-1' is matched by its IP address and by its DNS name, it is returned once
+	if len(interfaces) != 2 || interfaces[0].HostId != "10501" || interfaces[1].HostId != "10502" {
+		t.Fatalf("wrong interfaces returned.\nReturned : %v", interfaces)
+	}
+}
+
+func TestClientGetHostsById(t *testing.T) {
+	hosts, err := getFakeClient(t).GetHostsById([]string{"10501"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostsById function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 1 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 1\nReturned : %d", len(hosts))
+	}
+
+	if len(hosts[0].Templates) != 1 || hosts[0].Templates[0].Name != "Cisco IOS by SNMP" {
+		t.Fatalf("wrong templates returned.\nReturned : %v", hosts[0].Templates)
+	}
+
+	if len(hosts[0].Tags) != 1 || hosts[0].Tags[0].Value != "rt1.dc1" {
+		t.Fatalf("wrong tags returned.\nReturned : %v", hosts[0].Tags)
+	}
+}
+
+func TestClientCreateUpdateImage(t *testing.T) {
+	client := getFakeClient(t)
+
+	id, err := client.CreateImage("Router_(96)", "aW1hZ2U=")
+	if err != nil {
+		t.Fatalf("error while executing CreateImage function.\nReason : %v", err)
+	}
+
+	if id != "6" {
+		t.Fatalf("wrong imageid returned.\nExpected : 6\nReturned : %s", id)
+	}
+
+	if err = client.UpdateImage(id, "dXBkYXRlZA=="); err != nil {
+		t.Fatalf("error while executing UpdateImage function.\nReason : %v", err)
+	}
+
+	images, err := client.GetImagesData([]string{id})
+	if err != nil {
+		t.Fatalf("error while executing GetImagesData function.\nReason : %v", err)
+	}
+
+	if len(images) != 1 || images[0].Data != "dXBkYXRlZA==" {
+		t.Fatalf("the content of the image was not updated.\nReturned : %v", images)
+	}
+
+	if _, err = client.CreateImage("Router_(96)", "aW1hZ2U="); err == nil {
+		t.Fatal("an error should be returned when the image already exists")
+	}
+
+	if err = client.UpdateImage("999", "aW1hZ2U="); err == nil {
+		t.Fatal("an error should be returned when the image does not exist")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.API {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.API, kind)
+	}
+}
+
+func TestClientGetHostGroupsByName(t *testing.T) {
+	groups, err := getFakeClient(t).GetHostGroupsByName([]string{"Routers", "unknown"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroupsByName function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Id != "22" {
+		t.Fatalf("wrong host groups returned.\nExpected : [22]\nReturned : %v", groups)
+	}
+}
+
+func TestClientGetHostGroupsById(t *testing.T) {
+	groups, err := getFakeClient(t).GetHostGroupsById([]string{"22", "99"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroupsById function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Name != "Routers" {
+		t.Fatalf("wrong host groups returned.\nExpected : [Routers]\nReturned : %v", groups)
+	}
+}
+
+func TestClientGetTriggersById(t *testing.T) {
+	triggers, err := getFakeClient(t).GetTriggersById([]string{"30001", "30003"})
+	if err != nil {
+		t.Fatalf("error while executing GetTriggersById function.\nReason : %v", err)
+	}
+
+	if len(triggers) != 2 {
+		t.Fatalf("wrong number of triggers returned.\nExpected : 2\nReturned : %d", len(triggers))
+	}
+
+	if len(triggers[1].Hosts) != 1 || triggers[1].Hosts[0].Host != "router-2" {
+		t.Fatalf("wrong host returned for the trigger '%s'.\nExpected : router-2\nReturned : %v", triggers[1].Id, triggers[1].Hosts)
+	}
+}
+
+func TestClientGetUsers(t *testing.T) {
+	users, err := getFakeClient(t).GetUsers([]string{"noc", "unknown"})
+	if err != nil {
+		t.Fatalf("error while executing GetUsers function.\nReason : %v", err)
+	}
+
+	if len(users) != 1 || users[0].Id != "3" {
+		t.Fatalf("wrong users returned.\nExpected : [3]\nReturned : %v", users)
+	}
+}
+
+func TestClientGetUserGroups(t *testing.T) {
+	groups, err := getFakeClient(t).GetUserGroups([]string{"Network team"})
+	if err != nil {
+		t.Fatalf("error while executing GetUserGroups function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Id != "15" {
+		t.Fatalf("wrong user groups returned.\nExpected : [15]\nReturned : %v", groups)
+	}
+}
+
+func TestClientUpdateMap(t *testing.T) {
+	client := getFakeClient(t)
+
+	m := &zabbixgosdk.MapCreateParameters{}
+	m.Name = "site-paris"
+	m.Width = "800"
+	m.Height = "800"
+
+	ids, err := client.CreateMap(m)
+	if err != nil {
+		t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+	}
+
+	m.Id = ids[0]
+	m.Width = "1200"
+	if _, err = client.UpdateMap(m); err != nil {
+		t.Fatalf("error while executing UpdateMap function.\nReason : %v", err)
+	}
+
+	maps, err := client.GetMaps([]string{"site-paris"})
+	if err != nil {
+		t.Fatalf("error while executing GetMaps function.\nReason : %v", err)
+	}
+
+	if len(maps) != 1 || maps[0].Width != "1200" {
+		t.Fatalf("the map was not updated.\nReturned : %v", maps)
+	}
+
+	m.Id = ""
+	if _, err = client.UpdateMap(m); err == nil {
+		t.Fatal("an error should be returned when the id of the map is not set")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+}
+
+func TestClientSearchDeleteMaps(t *testing.T) {
+	client := getFakeClient(t)
+
+	for _, name := range []string{"site-paris", "site-lyon", "core"} {
+		m := &zabbixgosdk.MapCreateParameters{}
+		m.Name = name
+		m.Width = "800"
+		m.Height = "800"
+
+		if _, err := client.CreateMap(m); err != nil {
+			t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+		}
+	}
+
+	maps, err := client.SearchMaps("site-")
+	if err != nil {
+		t.Fatalf("error while executing SearchMaps function.\nReason : %v", err)
+	}
+
+	if len(maps) != 2 {
+		t.Fatalf("wrong number of maps returned.\nExpected : 2\nReturned : %d", len(maps))
+	}
+
+	maps, err = client.GetMapsById([]string{maps[0].Id})
+	if err != nil {
+		t.Fatalf("error while executing GetMapsById function.\nReason : %v", err)
+	}
+
+	if len(maps) != 1 || maps[0].Name != "site-paris" {
+		t.Fatalf("wrong maps returned.\nExpected : site-paris\nReturned : %v", maps)
+	}
+
+	if err = client.DeleteMaps([]string{maps[0].Id}); err != nil {
+		t.Fatalf("error while executing DeleteMaps function.\nReason : %v", err)
+	}
+
+	maps, err = client.SearchMaps("site-")
+	if err != nil {
+		t.Fatalf("error while executing SearchMaps function.\nReason : %v", err)
+	}
+
+	if len(maps) != 1 || maps[0].Name != "site-lyon" {
+		t.Fatalf("wrong maps returned after the deletion.\nExpected : site-lyon\nReturned : %v", maps)
+	}
+
+	if err = client.DeleteMaps([]string{"999"}); err == nil {
+		t.Fatal("an error should be returned when deleting an unknown map")
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+// Observer is used to record the duration and the error of a call to the Zabbix API, identified by its API method (ex: 'host.get').
+type Observer func(method string, duration time.Duration, err error)
+
+// instrumentedClient is the ZabbixAPI implementation passing each call to the wrapped client and recording it using the observer.
+type instrumentedClient struct {
+	client  ZabbixAPI
+	observe Observer
+}
+
+// Instrument is used to wrap the given client to record each call to the Zabbix API using the given observer.
+func Instrument(client ZabbixAPI, observe Observer) ZabbixAPI {
+	return &instrumentedClient{
+		client:  client,
+		observe: observe,
+	}
+}
+
+// done is used to record a call started at the given time, the error is read once the call is completed.
+func (c *instrumentedClient) done(method string, start time.Time, err *error) {
+	c.observe(method, time.Since(start), *err)
+}
+
+// GetHosts is used to retrieve the hosts matching the given technical names.
+func (c *instrumentedClient) GetHosts(names []string) (out []*Host, err error) {
+	defer c.done("host.get", time.Now(), &err)
+	return c.client.GetHosts(names)
+}
+
+// GetHostsById is used to retrieve the hosts matching the given ids, including their visible name, tags, templates, inventory type and host groups.
+func (c *instrumentedClient) GetHostsById(ids []string) (out []*Host, err error) {
+	defer c.done("host.get", time.Now(), &err)
+	return c.client.GetHostsById(ids)
+}
+
+// GetHostsByName is used to retrieve the hosts matching the given visible names.
+func (c *instrumentedClient) GetHostsByName(names []string) (out []*Host, err error) {
+	defer c.done("host.get", time.Now(), &err)
+	return c.client.GetHostsByName(names)
+}
+
+// GetHostsByTag is used to retrieve the hosts with the given tag set to one of the given values, including their tags.
+func (c *instrumentedClient) GetHostsByTag(tag string, values []string) (out []*Host, err error) {
+	defer c.done("host.get", time.Now(), &err)
+	return c.client.GetHostsByTag(tag, values)
+}
+
+// GetHostInterfaces is used to retrieve the host interfaces with an IP address or a DNS name matching one of the given addresses.
+func (c *instrumentedClient) GetHostInterfaces(addresses []string) (out []*HostInterface, err error) {
+	defer c.done("hostinterface.get", time.Now(), &err)
+	return c.client.GetHostInterfaces(addresses)
+}
+
+// GetImages is used to retrieve the images matching the given names.
+func (c *instrumentedClient) GetImages(names []string) (out []*Image, err error) {
+	defer c.done("image.get", time.Now(), &err)
+	return c.client.GetImages(names)
+}
+
+// GetImagesData is used to retrieve the images matching the given ids, including the base64 encoded content of each image.
+func (c *instrumentedClient) GetImagesData(ids []string) (out []*Image, err error) {
+	defer c.done("image.get", time.Now(), &err)
+	return c.client.GetImagesData(ids)
+}
+
+// CreateImage is used to create an icon image with the given name and base64 encoded content, the id of the image is returned.
+func (c *instrumentedClient) CreateImage(name string, data string) (out string, err error) {
+	defer c.done("image.create", time.Now(), &err)
+	return c.client.CreateImage(name, data)
+}
+
+// UpdateImage is used to replace the base64 encoded content of the given image.
+func (c *instrumentedClient) UpdateImage(id string, data string) (err error) {
+	defer c.done("image.update", time.Now(), &err)
+	return c.client.UpdateImage(id, data)
+}
+
+// GetTriggers is used to retrieve the triggers of the given host matching the given description.
+// If the description is empty, all the triggers of the host are returned.
+func (c *instrumentedClient) GetTriggers(hostId string, description string) (out []*Trigger, err error) {
+	defer c.done("trigger.get", time.Now(), &err)
+	return c.client.GetTriggers(hostId, description)
+}
+
+// GetTriggersById is used to retrieve the triggers matching the given ids, including the host of each trigger.
+func (c *instrumentedClient) GetTriggersById(ids []string) (out []*Trigger, err error) {
+	defer c.done("trigger.get", time.Now(), &err)
+	return c.client.GetTriggersById(ids)
+}
+
+// GetItems is used to retrieve the items of the given host.
+func (c *instrumentedClient) GetItems(hostId string) (out []*Item, err error) {
+	defer c.done("item.get", time.Now(), &err)
+	return c.client.GetItems(hostId)
+}
+
+// GetHostGroups is used to retrieve the host groups of the given host.
+func (c *instrumentedClient) GetHostGroups(hostId string) (out []*HostGroup, err error) {
+	defer c.done("hostgroup.get", time.Now(), &err)
+	return c.client.GetHostGroups(hostId)
+}
+
+// GetHostGroupsByName is used to retrieve the host groups matching the given names.
+func (c *instrumentedClient) GetHostGroupsByName(names []string) (out []*HostGroup, err error) {
+	defer c.done("hostgroup.get", time.Now(), &err)
+	return c.client.GetHostGroupsByName(names)
+}
+
+// GetHostGroupsById is used to retrieve the host groups matching the given ids.
+func (c *instrumentedClient) GetHostGroupsById(ids []string) (out []*HostGroup, err error) {
+	defer c.done("hostgroup.get", time.Now(), &err)
+	return c.client.GetHostGroupsById(ids)
+}
+
+// GetMaps is used to retrieve the maps matching the given names, including their elements and links.
+func (c *instrumentedClient) GetMaps(names []string) (out []*Map, err error) {
+	defer c.done("map.get", time.Now(), &err)
+	return c.client.GetMaps(names)
+}
+
+// GetMapsById is used to retrieve the maps matching the given ids (only the id and the name of the maps are returned).
+func (c *instrumentedClient) GetMapsById(ids []string) (out []*Map, err error) {
+	defer c.done("map.get", time.Now(), &err)
+	return c.client.GetMapsById(ids)
+}
+
+// SearchMaps is used to retrieve the maps whose name starts with the given prefix (only the id and the name of the maps are returned).
+func (c *instrumentedClient) SearchMaps(prefix string) (out []*Map, err error) {
+	defer c.done("map.get", time.Now(), &err)
+	return c.client.SearchMaps(prefix)
+}
+
+// DeleteMaps is used to delete the maps with the given ids.
+func (c *instrumentedClient) DeleteMaps(ids []string) (err error) {
+	defer c.done("map.delete", time.Now(), &err)
+	return c.client.DeleteMaps(ids)
+}
+
+// CreateMap is used to create the given map and return the ids of the created maps.
+func (c *instrumentedClient) CreateMap(m *zabbixgosdk.MapCreateParameters) (out []string, err error) {
+	defer c.done("map.create", time.Now(), &err)
+	return c.client.CreateMap(m)
+}
+
+// UpdateMap is used to replace the definition of the map with the id set in the given map and return the ids of the updated maps.
+func (c *instrumentedClient) UpdateMap(m *zabbixgosdk.MapCreateParameters) (out []string, err error) {
+	defer c.done("map.update", time.Now(), &err)
+	return c.client.UpdateMap(m)
+}
+
+// GetUsers is used to retrieve the users matching the given usernames.
+func (c *instrumentedClient) GetUsers(names []string) (out []*User, err error) {
+	defer c.done("user.get", time.Now(), &err)
+	return c.client.GetUsers(names)
+}
+
+// GetUserGroups is used to retrieve the user groups matching the given names.
+func (c *instrumentedClient) GetUserGroups(names []string) (out []*UserGroup, err error) {
+	defer c.done("usergroup.get", time.Now(), &err)
+	return c.client.GetUserGroups(names)
+}
+
+// Logout is used to release the API token.
+func (c *instrumentedClient) Logout() (err error) {
+	defer c.done("user.logout", time.Now(), &err)
+	return c.client.Logout()
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fakeserver"
+)
+
+func TestInstrument(t *testing.T) {
+	calls := make(map[string]int, 0)
+	failed := make(map[string]int, 0)
+
+	client := Instrument(getFakeClient(t), func(method string, duration time.Duration, err error) {
+		calls[method]++
+		if err != nil {
+			failed[method]++
+		}
+	})
+
+	hosts, err := client.GetHosts([]string{"router-1", "router-2"})
+	if err != nil {
+		t.Fatalf("error while executing GetHosts function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 2 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 2\nReturned : %d", len(hosts))
+	}
+
+	if _, err = client.GetHostsByName([]string{"Core router 3"}); err != nil {
+		t.Fatalf("error while executing GetHostsByName function.\nReason : %v", err)
+	}
+
+	if _, err = client.UpdateMap(&zabbixgosdk.MapCreateParameters{}); err == nil {
+		t.Fatalf("an error should be returned when updating a map without id")
+	}
+
+	if calls["host.get"] != 2 || failed["host.get"] != 0 {
+		t.Fatalf("wrong calls recorded for the method 'host.get'.\nExpected : 2 calls, 0 failed\nReturned : %d calls, %d failed", calls["host.get"], failed["host.get"])
+	}
+
+	if calls["map.update"] != 1 || failed["map.update"] != 1 {
+		t.Fatalf("wrong calls recorded for the method 'map.update'.\nExpected : 1 call, 1 failed\nReturned : %d calls, %d failed", calls["map.update"], failed["map.update"])
+	}
+}
+
+func TestInitInstrumentedApi(t *testing.T) {
+	pwd, _ := os.Getwd()
+	dataset, err := fakeserver.LoadDataset(filepath.Join(pwd, "..", "..", "examples", "fake_dataset.json"))
+	if err != nil {
+		t.Fatalf("error while loading the fake server dataset.\nReason : %v", err)
+	}
+
+	s := fakeserver.NewServer(dataset)
+	t.Cleanup(s.Close)
+
+	calls := make(map[string]int, 0)
+	failed := make(map[string]int, 0)
+	observe := func(method string, duration time.Duration, err error) {
+		calls[method]++
+		if err != nil {
+			failed[method]++
+		}
+	}
+
+	if _, err = InitInstrumentedApi(s.ApiUrl(), ZABBIX_USER, ZABBIX_PWD, observe); err != nil {
+		t.Fatalf("error while executing InitInstrumentedApi function.\nReason : %v", err)
+	}
+
+	if _, err = InitInstrumentedApi(s.ApiUrl(), "random-user", "random-password", observe); err == nil {
+		t.Fatalf("an error should be returned when using wrong credentials")
+	}
+
+	if calls["user.login"] != 2 || failed["user.login"] != 1 {
+		t.Fatalf("wrong calls recorded for the method 'user.login'.\nExpected : 2 calls, 1 failed\nReturned : %d calls, %d failed", calls["user.login"], failed["user.login"])
+	}
+}
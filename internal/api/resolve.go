@@ -0,0 +1,221 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+const (
+	// StrategyHost is used to resolve an host using its technical name.
+	StrategyHost = "host"
+	// StrategyName is used to resolve an host using its visible name.
+	StrategyName = "name"
+	// StrategyInterface is used to resolve an host using the IP address or the DNS name of one of its interfaces.
+	StrategyInterface = "interface"
+	// StrategyTag is used to resolve an host using the value of a tag (sysName for example).
+	StrategyTag = "tag"
+)
+
+// Strategies define the supported host lookup strategies.
+var Strategies = []string{
+	StrategyHost,
+	StrategyName,
+	StrategyInterface,
+	StrategyTag,
+}
+
+// ResolveOptions define how the hosts referenced in the mappings are resolved.
+type ResolveOptions struct {
+	// Strategies are tried in the given order for each host not resolved yet, only the technical name is used if empty.
+	Strategies []string
+	// Tag is the name of the tag used by the 'tag' strategy.
+	Tag string
+	// Rename associate the name used in the mappings to the value used to search the host.
+	Rename map[string]string
+}
+
+// Resolution define how an host referenced in the mappings was resolved.
+type Resolution struct {
+	// Name is the name used in the mappings, Lookup the value used to search the host (after renaming).
+	Name   string `json:"name"`
+	Lookup string `json:"lookup"`
+	// Id and Host are the id and the technical name of the matching host, empty if the host was not resolved.
+	Id       string `json:"hostid"`
+	Host     string `json:"host"`
+	Strategy string `json:"strategy"`
+}
+
+// Validate is used to validate the resolve options.
+func (o *ResolveOptions) Validate() error {
+	for _, s := range o.Strategies {
+		if !utils.Contains(Strategies, s) {
+			return fmt.Errorf("unsupported host lookup strategy '%s', supported strategies are %v", s, Strategies)
+		}
+	}
+
+	if utils.Contains(o.Strategies, StrategyTag) && o.Tag == "" {
+		return fmt.Errorf("a tag name is required when using the '%s' host lookup strategy", StrategyTag)
+	}
+
+	return nil
+}
+
+// lookupHosts is used to retrieve the hosts matching the given values using the given strategy.
+// The returned map associate each value to the list of matching hosts.
+func lookupHosts(client ZabbixAPI, strategy string, tag string, values []string) (map[string][]*Host, error) {
+	out := make(map[string][]*Host, 0)
+	add := func(value string, host *Host) {
+		for _, h := range out[value] {
+			if h.Id == host.Id {
+				return
+			}
+		}
+
+		out[value] = append(out[value], host)
+	}
+
+	switch strategy {
+	case StrategyHost:
+		hosts, err := client.GetHosts(values)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range hosts {
+			add(h.Host, h)
+		}
+	case StrategyName:
+		hosts, err := client.GetHostsByName(values)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range hosts {
+			add(h.Name, h)
+		}
+	case StrategyTag:
+		hosts, err := client.GetHostsByTag(tag, values)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range hosts {
+			for _, t := range h.Tags {
+				if t.Tag == tag && utils.Contains(values, t.Value) {
+					add(t.Value, h)
+				}
+			}
+		}
+	case StrategyInterface:
+		interfaces, err := client.GetHostInterfaces(values)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, 0)
+		for _, i := range interfaces {
+			if !utils.Contains(ids, i.HostId) {
+				ids = append(ids, i.HostId)
+			}
+		}
+
+		if len(ids) == 0 {
+			return out, nil
+		}
+
+		hosts, err := client.GetHostsById(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range interfaces {
+			for _, h := range hosts {
+				if h.Id != i.HostId {
+					continue
+				}
+
+				if utils.Contains(values, i.Ip) {
+					add(i.Ip, h)
+				}
+
+				if utils.Contains(values, i.Dns) {
+					add(i.Dns, h)
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported host lookup strategy '%s', supported strategies are %v", strategy, Strategies)
+	}
+
+	return out, nil
+}
+
+// ResolveHosts is used to retrieve the host matching each of the given names.
+// The strategies are tried in order, each strategy is only used for the hosts not resolved by the previous ones.
+// An error is returned if a value matches multiple hosts. Hosts not resolved are returned with an empty id.
+func ResolveHosts(client ZabbixAPI, names []string, options *ResolveOptions) (map[string]*Resolution, error) {
+	if options == nil {
+		options = &ResolveOptions{}
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	strategies := options.Strategies
+	if len(strategies) == 0 {
+		strategies = []string{StrategyHost}
+	}
+
+	out := make(map[string]*Resolution, 0)
+	for _, name := range names {
+		lookup := name
+		if value, exist := options.Rename[name]; exist && value != "" {
+			lookup = value
+		}
+
+		out[name] = &Resolution{
+			Name:   name,
+			Lookup: lookup,
+		}
+	}
+
+	for _, strategy := range strategies {
+		values := make([]string, 0)
+		for _, r := range out {
+			if r.Id == "" && !utils.Contains(values, r.Lookup) {
+				values = append(values, r.Lookup)
+			}
+		}
+
+		if len(values) == 0 {
+			break
+		}
+
+		sort.Strings(values)
+		matches, err := lookupHosts(client, strategy, options.Tag, values)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range out {
+			hosts := matches[r.Lookup]
+			if r.Id != "" || len(hosts) == 0 {
+				continue
+			}
+
+			if len(hosts) > 1 {
+				return nil, failure.Errorf(failure.Validation, "the host '%s' is ambiguous, %d hosts match the value '%s' using the '%s' strategy", r.Name, len(hosts), r.Lookup, strategy)
+			}
+
+			r.Id = hosts[0].Id
+			r.Host = hosts[0].Host
+			r.Strategy = strategy
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fakeserver"
+)
+
+// getFakeClient is used to initialize a Client connected to a fake server using the examples dataset.
+func getFakeClient(t *testing.T) *Client {
+	pwd, _ := os.Getwd()
+	dataset, err := fakeserver.LoadDataset(filepath.Join(pwd, "..", "..", "examples", "fake_dataset.json"))
+	if err != nil {
+		t.Fatalf("error while loading the fake server dataset.\nReason : %v", err)
+	}
+
+	s := fakeserver.NewServer(dataset)
+	t.Cleanup(s.Close)
+
+	service, err := InitApi(s.ApiUrl(), ZABBIX_USER, ZABBIX_PWD)
+	if err != nil {
+		t.Fatalf("error while executing InitApi function.\nReason : %v", err)
+	}
+
+	return NewClient(service)
+}
+
+func TestResolveHosts(t *testing.T) {
+	options := &ResolveOptions{
+		Strategies: Strategies,
+		Tag:        "sysName",
+		Rename: map[string]string{
+			"core-3": "Core router 3",
+		},
+	}
+
+	names := []string{"Zabbix server", "core-3", "192.168.1.2", "router-1.example.com", "rt1.dc1", "unknown"}
+	resolutions, err := ResolveHosts(getFakeClient(t), names, options)
+	if err != nil {
+		t.Fatalf("error while executing ResolveHosts function.\nReason : %v", err)
+	}
+
+	expected := map[string]*Resolution{
+		"Zabbix server":        {Name: "Zabbix server", Lookup: "Zabbix server", Id: "10084", Host: "Zabbix server", Strategy: StrategyHost},
+		"core-3":               {Name: "core-3", Lookup: "Core router 3", Id: "10503", Host: "router-3", Strategy: StrategyName},
+		"192.168.1.2":          {Name: "192.168.1.2", Lookup: "192.168.1.2", Id: "10502", Host: "router-2", Strategy: StrategyInterface},
+		"router-1.example.com": {Name: "router-1.example.com", Lookup: "router-1.example.com", Id: "10501", Host: "router-1", Strategy: StrategyInterface},
+		"rt1.dc1":              {Name: "rt1.dc1", Lookup: "rt1.dc1", Id: "10501", Host: "router-1", Strategy: StrategyTag},
+		"unknown":              {Name: "unknown", Lookup: "unknown"},
+	}
+
+	for name, e := range expected {
+		r, exist := resolutions[name]
+		if !exist {
+			t.Fatalf("no resolution returned for the host '%s'", name)
+		}
+
+		if *r != *e {
+			t.Fatalf("wrong resolution returned for the host '%s'.\nExpected : %+v\nReturned : %+v", name, e, r)
+		}
+	}
+}
+
+func TestResolveHostsDefault(t *testing.T) {
+	resolutions, err := ResolveHosts(getFakeClient(t), []string{"router-2", "Core router 3"}, nil)
+	if err != nil {
+		t.Fatalf("error while executing ResolveHosts function.\nReason : %v", err)
+	}
+
+	if resolutions["router-2"].Id != "10502" {
+		t.Fatalf("wrong id returned for the host 'router-2'.\nExpected : 10502\nReturned : %s", resolutions["router-2"].Id)
+	}
+
+	// Only the technical name is used by default
+	if resolutions["Core router 3"].Id != "" {
+		t.Fatalf("the visible name should not be used by default.\nReturned : %+v", resolutions["Core router 3"])
+	}
+}
+
+func TestResolveOptionsValidate(t *testing.T) {
+	if err := (&ResolveOptions{Strategies: []string{"mac"}}).Validate(); err == nil {
+		t.Fatal("an error should be returned when using an unsupported strategy")
+	}
+
+	if err := (&ResolveOptions{Strategies: []string{StrategyTag}}).Validate(); err == nil {
+		t.Fatal("an error should be returned when using the tag strategy without a tag name")
+	}
+}
+
+func TestClientGetHostsByName(t *testing.T) {
+	hosts, err := getFakeClient(t).GetHostsByName([]string{"Core router 3"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostsByName function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 1 || hosts[0].Host != "router-3" {
+		t.Fatalf("wrong hosts returned.\nReturned : %v", hosts)
+	}
+}
+
+func TestResolveHostsInterfaceWithoutDns(t *testing.T) {
+	options := &ResolveOptions{
+		Strategies: []string{StrategyInterface},
+	}
+
+	resolutions, err := ResolveHosts(getFakeClient(t), []string{"192.168.1.2"}, options)
+	if err != nil {
+		t.Fatalf("error while executing ResolveHosts function.\nReason : %v", err)
+	}
+
+	if r := resolutions["192.168.1.2"]; r == nil || r.Id != "10502" || r.Strategy != StrategyInterface {
+		t.Fatalf("the host should be resolved using the IP address of its interface.\nReturned : %+v", r)
+	}
+}
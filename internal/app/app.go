@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
 	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
 	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
 	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/snapshot"
 )
 
 func outputToFile(file string, m *zabbixgosdk.MapCreateParameters) error {
@@ -36,67 +39,175 @@ func outputToFile(file string, m *zabbixgosdk.MapCreateParameters) error {
 	return nil
 }
 
-// RunApp is used to run the main logic of the application.
-func RunApp(file string, options *Options, logger *logging.Logger) error {
-	if logger == nil {
-		logger = logging.NewLogger(logging.Warning)
+// initClient is used to initialize the client used to interact with the Zabbix API.
+// If a snapshot file is set in the options, the snapshot is used instead of the Zabbix server.
+func initClient(options *Options, logger *logging.Logger) (api.ZabbixAPI, error) {
+	if options.Snapshot != "" {
+		logger.Debug(fmt.Sprintf("loading snapshot '%s'", options.Snapshot))
+		return snapshot.Load(options.Snapshot)
 	}
 
-	// Retrieve the list of hosts mappings for the input file
-	logger.Debug(fmt.Sprintf("reading input file '%s'", file))
-	mappings, err := ReadInput(file)
-	if err != nil {
-		return err
-	}
+	return initServerClient(options, logger)
+}
 
-	// Initialize an api client.
+// initServerClient is used to initialize a client for the commands requiring a Zabbix server.
+// Each call to the API is recorded in the metrics and in the debug logs.
+func initServerClient(options *Options, logger *logging.Logger) (api.ZabbixAPI, error) {
 	logger.Debug("initializing the API client")
-	client, err := api.InitApi(options.ZabbixUrl, options.ZabbixUser, options.ZabbixPwd)
+	observe := apiObserver(options, logger)
+	service, err := api.InitInstrumentedApi(options.ZabbixUrl, options.ZabbixUser, options.ZabbixPwd, observe)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return api.Instrument(api.NewClient(service), observe), nil
+}
+
+// logout is used to release the API token of the given client once the command is completed.
+// The logout error is stored in the given error (the named result of the command), unless the command already failed.
+func logout(client api.ZabbixAPI, err *error) {
+	if logoutErr := client.Logout(); logoutErr != nil && *err == nil {
+		*err = logoutErr
 	}
+}
 
-	// Catch logout error
-	defer func() {
-		err = client.Logout()
-	}()
+// apiObserver is used to record each call to the API in the metrics and in the debug logs.
+func apiObserver(options *Options, logger *logging.Logger) api.Observer {
+	return func(method string, duration time.Duration, err error) {
+		options.Metrics.ObserveAPICall(method, duration, err)
 
+		if err != nil {
+			logger.With("method", method).Debug(fmt.Sprintf("call to '%s' failed after %s", method, duration), err)
+			return
+		}
+
+		logger.With("method", method).Debug(fmt.Sprintf("call to '%s' completed in %s", method, duration))
+	}
+}
+
+// buildMap is used to build the map create request from the given mappings.
+// Hosts and images referenced in the mappings are resolved using the given client.
+func buildMap(client api.ZabbixAPI, mappings []*zbxmap.Mapping, options *Options, logger *logging.Logger) (*zabbixgosdk.MapCreateParameters, error) {
 	// Remove duplicate from the hosts mappings and associate 'host' -> 'hostid'
 	// Make it easier to retrieve id of each hosts
 	logger.Debug("retrieving hosts information from the server")
-	hosts, err := getUniqueHosts(client, mappings)
+	hosts, err := getUniqueHosts(client, mappings, options, logger)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	unresolved := 0
+	for _, id := range hosts {
+		if id == "" {
+			unresolved++
+		}
+	}
+
+	options.Metrics.SetHosts(options.Name, len(hosts)-unresolved, unresolved)
+
+	// Skip the links referencing an host not found, instead of building a map with missing elements
+	mappings, skipped := skipUnresolvedMappings(mappings, hosts, logger)
+	if len(mappings) == 0 {
+		return nil, failure.Errorf(failure.NotFound, "no link can be added to the map '%s', the hosts of every link were not found", options.Name)
+	}
+
+	if skipped > 0 {
+		logger.Warning(fmt.Sprintf("%d link(s) skipped, an host was not found", skipped))
+	}
+
+	// Associate the host groups and the maps used as elements to their id
+	hostGroups, err := getUniqueHostGroups(client, mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	maps, err := getUniqueMaps(client, mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	// Select the image of the hosts without one using the icon rules
+	rules, err := options.iconRules()
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, icons, err := applyIconRules(client, mappings, hosts, rules, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	// Remove duplicate from the hosts mappings and associate 'image' -> 'imageid'
 	// Make it easier to retrieve id of each hosts
 	logger.Debug("retrieving images information from the server")
-	images, err := getUniqueImages(client, mappings)
+	images, err := getUniqueImages(client, mappings, iconImages(icons)...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Upload the missing images and update the images whose content changed
+	if options.ImagesDir != "" {
+		if err = uploadMapImages(client, images, options, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	urls, err := options.urlTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	sharing, err := options.sharing()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the position of the elements and the size of the map set in the layout
+	var layout *zbxmap.Layout
+	width := options.Width
+	height := options.Height
+	if options.Layout != "" {
+		logger.Debug(fmt.Sprintf("loading the layout '%s'", options.Layout))
+		layout, err = zbxmap.LoadLayout(options.Layout)
+		if err != nil {
+			return nil, failure.New(failure.Config, err)
+		}
+
+		if layout.Width != "" {
+			width = layout.Width
+		}
+
+		if layout.Height != "" {
+			height = layout.Height
+		}
 	}
 
 	// Construct map options
-	// fmt.Println("building map options")
 	mapOptions := zbxmap.MapOptions{
-		Name:         options.Name,
-		Color:        options.Color,
-		TriggerColor: options.TriggerColor,
-		Height:       options.Height,
-		Width:        options.Width,
-		Spacer:       options.Spacer,
-		StackHosts:   options.StackHosts,
-		Mappings:     mappings,
-		Hosts:        hosts,
-		Images:       images,
+		Name:          options.Name,
+		Color:         options.Color,
+		TriggerColor:  options.TriggerColor,
+		Height:        height,
+		Width:         width,
+		Spacer:        options.Spacer,
+		StackHosts:    options.StackHosts,
+		Mappings:      mappings,
+		Hosts:         hosts,
+		Images:        images,
+		HostIcons:     icons,
+		HostGroups:    hostGroups,
+		Maps:          maps,
+		LabelTemplate: options.LabelTemplate,
+		LabelLocation: options.LabelLocation,
+		Urls:          urls,
+		Layout:        layout,
 	}
 
 	// Validate the options
 	logger.Debug("validating the map configuration options")
 	err = mapOptions.Validate()
 	if err != nil {
-		return err
+		return nil, failure.New(failure.Validation, err)
 	}
 
 	if logger.Level >= logging.Debug {
@@ -108,22 +219,112 @@ func RunApp(file string, options *Options, logger *logging.Logger) error {
 	logger.Debug("building the map")
 	m, err := zbxmap.BuildMap(client, &mapOptions)
 	if err != nil {
+		return nil, err
+	}
+
+	if !sharing.IsEmpty() {
+		logger.Debug("resolving the owner, users and user groups the map is shared with")
+		if err = zbxmap.ApplySharing(client, m, sharing); err != nil {
+			return nil, err
+		}
+	}
+
+	options.Metrics.SetMapStats(options.Name, getMapStats(m, skipped))
+
+	return m, nil
+}
+
+// saveMap is used to create the given map on the server.
+// If the 'Update' option is set and a map with the same name already exists, the existing map is updated instead.
+func saveMap(client api.ZabbixAPI, m *zabbixgosdk.MapCreateParameters, options *Options, logger *logging.Logger) error {
+	if options.Update {
+		maps, err := client.GetMaps([]string{m.Name})
+		if err != nil {
+			return err
+		}
+
+		if len(maps) > 0 {
+			logger.Debug(fmt.Sprintf("updating the map '%s' (sysmapid %s) on the server", m.Name, maps[0].Id))
+			m.Id = maps[0].Id
+			if err = zbxmap.UpdateMap(client, m); err != nil {
+				return err
+			}
+
+			options.Metrics.SetLastSuccess(m.Name, time.Now())
+			return nil
+		}
+
+		logger.Debug(fmt.Sprintf("no map named '%s' exists on the server", m.Name))
+	}
+
+	logger.Debug("creating the map on the server")
+	if err := zbxmap.CreateMap(client, m); err != nil {
 		return err
 	}
 
+	options.Metrics.SetLastSuccess(m.Name, time.Now())
+	return nil
+}
+
+// readMappings is used to read and normalize the mappings of the given file.
+func readMappings(file string, options *Options, logger *logging.Logger) ([]*zbxmap.Mapping, error) {
+	// Retrieve the list of hosts mappings for the input file
+	logger.Debug(fmt.Sprintf("reading input file '%s'", file))
+	mappings, err := ReadInputFormat(file, options.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalizeMappings(mappings, logger), nil
+}
+
+// prepareMap is used to build the map from the mappings matching the filter set in the options.
+// The create request is stored to the output file set in the options.
+func prepareMap(client api.ZabbixAPI, mappings []*zbxmap.Mapping, options *Options, logger *logging.Logger) (*zabbixgosdk.MapCreateParameters, error) {
+	start := time.Now()
+
+	selected, err := selectMappings(client, mappings, options, logger)
+	if err != nil {
+		options.Metrics.ObserveBuild(options.Name, time.Since(start), err)
+		return nil, err
+	}
+
+	m, err := buildMap(client, selected, options, logger)
+	options.Metrics.ObserveBuild(options.Name, time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+
 	// Store the create request if asked before executing it on the server
 	if options.OutFile != "" {
 		logger.Debug(fmt.Sprintf("outputting the create request to '%s'", options.OutFile))
 		err = outputToFile(options.OutFile, m)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		logger.Debug("'--ouptput' flag not used, skipping step.")
 	}
 
+	return m, nil
+}
+
+// outputOnly is used to check if the map definition must be output instead of being created on the server.
+// When using a snapshot without an output file, the map definition is also output.
+func outputOnly(options *Options) bool {
+	return options.DryRun || (options.Snapshot != "" && options.OutFile == "")
+}
+
+// applyMap is used to build the map from the mappings matching the filter set in the options and to create (or update) it on the server.
+// The map definition is output to the shell instead when using the 'DryRun' option or a snapshot.
+func applyMap(client api.ZabbixAPI, mappings []*zbxmap.Mapping, options *Options, logger *logging.Logger) error {
+	m, err := prepareMap(client, mappings, options, logger)
+	if err != nil {
+		return err
+	}
+
 	// If dry-run was set to true, output the map definition to the shell
-	if options.DryRun {
+	if outputOnly(options) {
 		// Convert the request parameters to a slice of byte before output the content as a string to the shell
 		logger.Debug("outputting map to the shell")
 		b, err := json.Marshal(m)
@@ -138,14 +339,49 @@ func RunApp(file string, options *Options, logger *logging.Logger) error {
 		logger.Debug("'--dry-run' flag not used, skipping step.")
 	}
 
-	// Create the map using the previously build request
-	logger.Debug("creating the map on the server")
-	err = zbxmap.CreateMap(client, m)
+	// A map cannot be created when using a snapshot
+	if options.Snapshot != "" {
+		logger.Debug("a snapshot was used to build the map, skipping the map creation.")
+		return nil
+	}
+
+	// Create or update the map using the previously build request
+	return saveMap(client, m, options, logger)
+}
+
+// RunApp is used to run the main logic of the application.
+func RunApp(file string, options *Options, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	enableMetrics(options)
+	logger = logger.With("map", options.Name)
+
+	mappings, err := readMappings(file, options, logger)
+	if err != nil {
+		return err
+	}
+
+	// Initialize an api client.
+	client, err := initClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	err = applyMap(client, mappings, options, logger)
+
+	// The metrics are also written when the build failed
+	if metricsErr := writeMetricsFile(options, logger); err == nil {
+		err = metricsErr
+	}
+
 	if err != nil {
 		return err
 	}
 
-	// Allow to return errors from the defer function (API logout)
 	logger.Debug("all steps have been passed already, starting the exit process.")
-	return err
+	return nil
 }
@@ -11,6 +11,10 @@ import (
 	"time"
 
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
 )
 
 // generateMapName is used to generate a random name for each map created during test.
@@ -61,6 +65,138 @@ func TestOutputToFileEmptyName(t *testing.T) {
 	}
 }
 
+func TestBuildMap(t *testing.T) {
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	opts := Options{
+		Name:         "test-map-builder",
+		Color:        "7AC2E1",
+		TriggerColor: "EE445B",
+		Width:        "400",
+		Height:       "400",
+		Spacer:       50,
+		StackHosts:   true,
+	}
+
+	m, err := buildMap(newFakeClient(), mappings, &opts, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if len(m.Elements) != 3 {
+		t.Fatalf("wrong number of elements set.\nExpected : 3\nReturned : %d", len(m.Elements))
+	}
+
+	if len(m.Links) != 2 {
+		t.Fatalf("wrong number of links set.\nExpected : 2\nReturned : %d", len(m.Links))
+	}
+
+	if m.Links[1].LinkTriggers[1].TriggerId != "24" {
+		t.Fatalf("wrong trigger id set for the remote host of the second link.\nExpected : '24'\nReturned : %s", m.Links[1].LinkTriggers[1].TriggerId)
+	}
+}
+
+func TestBuildMapUnresolvedHost(t *testing.T) {
+	mappings := []*zbxmap.Mapping{
+		{
+			LocalHost:            "router-1",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Firewall_(64)",
+			RemoteHost:           "router-2",
+			RemoteTriggerPattern: "Interface eth0(): Link down",
+			RemoteImage:          "Switch_(64)",
+		},
+		{
+			LocalHost:            "router-1",
+			LocalTriggerPattern:  "Interface eth1(): Link down",
+			LocalImage:           "Firewall_(64)",
+			RemoteHost:           "router-unknown",
+			RemoteTriggerPattern: "Interface eth0(): Link down",
+			RemoteImage:          "Switch_(64)",
+		},
+	}
+
+	opts := Options{
+		Name:   "test-map-builder",
+		Width:  "400",
+		Height: "400",
+		Spacer: 50,
+	}
+
+	// The link referencing the unknown host is skipped
+	m, err := buildMap(newFakeClient(), mappings, &opts, logging.NewLogger(logging.Error))
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if len(m.Links) != 1 {
+		t.Fatalf("wrong number of links set.\nExpected : 1\nReturned : %d", len(m.Links))
+	}
+
+	if len(m.Elements) != 2 {
+		t.Fatalf("wrong number of elements set.\nExpected : 2\nReturned : %d", len(m.Elements))
+	}
+
+	// A map without any link is not built
+	if _, err = buildMap(newFakeClient(), mappings[1:], &opts, logging.NewLogger(logging.Error)); err == nil {
+		t.Fatal("an error should be returned when the hosts of every link are not found")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.NotFound {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.NotFound, kind)
+	}
+}
+
+func TestBuildMapElementTypes(t *testing.T) {
+	mappings := []*zbxmap.Mapping{
+		{
+			LocalHost:           "router-1",
+			LocalTriggerPattern: "Interface eth0(): Link down",
+			LocalImage:          "Firewall_(64)",
+			RemoteHost:          "Routers",
+			RemoteType:          zbxmap.ElementHostGroup,
+			RemoteImage:         "Switch_(64)",
+		},
+		{
+			LocalHost:   "Routers",
+			LocalType:   zbxmap.ElementHostGroup,
+			LocalImage:  "Switch_(64)",
+			RemoteHost:  "Internet",
+			RemoteType:  zbxmap.ElementImage,
+			RemoteImage: "Firewall_(64)",
+		},
+	}
+
+	client := newFakeClient().AddHostGroup("1", "41", "Routers")
+	opts := Options{
+		Name:       "test-map-builder",
+		Width:      "400",
+		Height:     "400",
+		Spacer:     50,
+		StackHosts: true,
+	}
+
+	m, err := buildMap(client, mappings, &opts, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if len(m.Elements) != 3 {
+		t.Fatalf("wrong number of elements set.\nExpected : 3\nReturned : %d", len(m.Elements))
+	}
+
+	if m.Elements[1].ElementType != zabbixgosdk.MapHostGroup || m.Elements[2].ElementType != zabbixgosdk.MapImage {
+		t.Fatalf("wrong element types set.\nExpected : %s / %s\nReturned : %s / %s", zabbixgosdk.MapHostGroup, zabbixgosdk.MapImage, m.Elements[1].ElementType, m.Elements[2].ElementType)
+	}
+
+	if len(m.Links[1].LinkTriggers) != 0 {
+		t.Fatalf("no trigger should be attached to a link between an host group and an image.\nReturned : %v", m.Links[1].LinkTriggers)
+	}
+}
+
 func TestRunApp(t *testing.T) {
 	opts := Options{
 		ZabbixUrl:    ZABBIX_URL,
@@ -188,3 +324,118 @@ func TestRunAppFailInitApi(t *testing.T) {
 		t.Fatalf("an error should be returned when the Zabbix API is unreachable")
 	}
 }
+
+func TestSaveMap(t *testing.T) {
+	logger := logging.NewLogger(logging.Warning)
+	client := newFakeClientWithMaps(t, "site-paris")
+
+	m := &zabbixgosdk.MapCreateParameters{}
+	m.Name = "site-paris"
+	m.Width = "1200"
+
+	// A new map is created without the 'Update' option
+	if err := saveMap(client, m, &Options{}, logger); err != nil {
+		t.Fatalf("error while executing saveMap function.\nReason : %v", err)
+	}
+
+	if len(client.Maps) != 2 {
+		t.Fatalf("a new map should be created.\nExpected : 2\nReturned : %d", len(client.Maps))
+	}
+
+	// The existing map is updated with the 'Update' option
+	m = &zabbixgosdk.MapCreateParameters{}
+	m.Name = "site-paris"
+	m.Width = "1600"
+	client = newFakeClientWithMaps(t, "site-paris")
+	if err := saveMap(client, m, &Options{Update: true}, logger); err != nil {
+		t.Fatalf("error while executing saveMap function.\nReason : %v", err)
+	}
+
+	if len(client.Maps) != 1 || client.Maps[0].Width != "1600" {
+		t.Fatalf("the existing map should be updated.\nReturned : %+v", client.Maps)
+	}
+
+	// A new map is created if no map uses the name
+	m = &zabbixgosdk.MapCreateParameters{}
+	m.Name = "site-lyon"
+	if err := saveMap(client, m, &Options{Update: true}, logger); err != nil {
+		t.Fatalf("error while executing saveMap function.\nReason : %v", err)
+	}
+
+	if len(client.Maps) != 2 || client.Maps[1].Name != "site-lyon" {
+		t.Fatalf("a new map should be created.\nReturned : %+v", client.Maps)
+	}
+}
+
+func TestBuildMapSharing(t *testing.T) {
+	client := newFakeClient().AddUserGroup("15", "Network team")
+	options := &Options{
+		Name:        "test-map",
+		Width:       "400",
+		Height:      "400",
+		Spacer:      50,
+		StackHosts:  true,
+		Public:      true,
+		ShareGroups: []string{"Network team:read"},
+	}
+
+	mappings := []*zbxmap.Mapping{
+		{
+			LocalHost:            "router-1",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Firewall_(64)",
+			RemoteHost:           "router-2",
+			RemoteTriggerPattern: "Interface eth0(): Link down",
+			RemoteImage:          "Switch_(64)",
+		},
+	}
+
+	m, err := buildMap(client, mappings, options, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if m.Private != "0" || len(m.UserGroups) != 1 || m.UserGroups[0].UserGroupId != "15" {
+		t.Fatalf("wrong sharing options set.\nReturned : %+v", m.Map)
+	}
+
+	options.ShareGroups = []string{"Network team"}
+	if _, err = buildMap(client, mappings, options, logging.NewLogger(logging.Warning)); err == nil {
+		t.Fatal("an error should be returned when a share is not written as 'name:permission'")
+	}
+}
+
+// failingLogoutClient is used to simulate an error when releasing the API token.
+type failingLogoutClient struct {
+	*fake.Client
+}
+
+// Logout is used to return an error.
+func (c *failingLogoutClient) Logout() error {
+	return fmt.Errorf("the session has already been terminated")
+}
+
+func TestLogoutError(t *testing.T) {
+	client := &failingLogoutClient{Client: fake.NewClient()}
+
+	// The logout error is returned if the command succeeded
+	var err error
+	logout(client, &err)
+	if err == nil || err.Error() != "the session has already been terminated" {
+		t.Fatalf("the logout error should be returned.\nReturned : %v", err)
+	}
+
+	// The error of the command is kept
+	err = fmt.Errorf("no host found for 'router-1'")
+	logout(client, &err)
+	if err.Error() != "no host found for 'router-1'" {
+		t.Fatalf("the error of the command should be kept.\nReturned : %v", err)
+	}
+
+	// No error is returned if the logout succeeded
+	err = nil
+	logout(fake.NewClient(), &err)
+	if err != nil {
+		t.Fatalf("no error should be returned.\nReturned : %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// BuildResult define the outcome of the build of a map of a manifest.
+type BuildResult struct {
+	Name     string
+	Duration time.Duration
+	// Err is the error returned while building the map, nil if the map was built.
+	Err error
+	// Request is the definition of the map when it is only output (dry-run), nil otherwise.
+	Request *zabbixgosdk.MapCreateParameters
+}
+
+// mappingsCache is used to read each mapping file only once when it is shared by multiple maps of a manifest.
+type mappingsCache struct {
+	mutex sync.Mutex
+	files map[string]*cachedMappings
+}
+
+// cachedMappings define the mappings read from a file, or the error returned while reading it.
+type cachedMappings struct {
+	once     sync.Once
+	mappings []*zbxmap.Mapping
+	err      error
+}
+
+// read is used to retrieve the mappings of the given file using the given format.
+// The file is read on the first call, the following calls return the same mappings (the mappings are not modified while building a map).
+func (c *mappingsCache) read(file string, format string, logger *logging.Logger) ([]*zbxmap.Mapping, error) {
+	key := format + ":" + file
+
+	c.mutex.Lock()
+	entry, exist := c.files[key]
+	if !exist {
+		entry = &cachedMappings{}
+		c.files[key] = entry
+	}
+	c.mutex.Unlock()
+
+	entry.once.Do(func() {
+		entry.mappings, entry.err = readMappings(file, &Options{Format: format}, logger)
+	})
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	return entry.mappings, nil
+}
+
+// buildManifestMap is used to build the given map of a manifest and to create (or update) it on the server.
+func buildManifestMap(client api.ZabbixAPI, entry *ManifestMap, base *Options, cache *mappingsCache, logger *logging.Logger) *BuildResult {
+	start := time.Now()
+	result := &BuildResult{
+		Name: entry.Name,
+	}
+
+	options := entry.options(base)
+	logger = logger.With("map", entry.Name)
+
+	mappings, err := cache.read(entry.File, entry.Format, logger)
+	if err == nil {
+		logger.Debug(fmt.Sprintf("building the map '%s'", entry.Name))
+
+		// The definitions are output once all the maps are built to avoid interleaving the output of the workers
+		if outputOnly(options) {
+			result.Request, err = prepareMap(client, mappings, options, logger)
+		} else {
+			err = applyMap(client, mappings, options, logger)
+		}
+	}
+
+	result.Err = err
+	result.Duration = time.Since(start)
+
+	return result
+}
+
+// buildAll is used to build the maps of the given manifest using the given client.
+// The maps are built concurrently by the given number of workers, the results are returned in the order of the manifest.
+func buildAll(client api.ZabbixAPI, manifest *Manifest, base *Options, workers int, logger *logging.Logger) []*BuildResult {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	cache := &mappingsCache{
+		files: make(map[string]*cachedMappings, 0),
+	}
+
+	results := make([]*BuildResult, len(manifest.Maps))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = buildManifestMap(client, manifest.Maps[i], base, cache, logger)
+			}
+		}()
+	}
+
+	for i := range manifest.Maps {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// writeRequests is used to write the definition of the maps only output (dry-run) to the given writer.
+// The definitions are written as a single JSON object using the name of the maps as keys.
+func writeRequests(out io.Writer, results []*BuildResult) error {
+	requests := make(map[string]*zabbixgosdk.MapCreateParameters, 0)
+	for _, r := range results {
+		if r.Request != nil {
+			requests[r.Name] = r.Request
+		}
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, string(b))
+	return err
+}
+
+// writeSummary is used to write the status of each map to the given writer.
+// An error is returned if the build of at least one map failed.
+func writeSummary(out io.Writer, results []*BuildResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "MAP\tSTATUS\tDURATION\tERROR")
+
+	failed := 0
+	for _, r := range results {
+		status := "ok"
+		reason := ""
+		if r.Err != nil {
+			failed++
+			status = "failed"
+			reason = strings.Join(strings.Fields(r.Err.Error()), " ")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Name, status, r.Duration.Round(time.Millisecond), reason)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%d map(s) built, %d failed\n", len(results)-failed, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d map(s) could not be built", failed, len(results))
+	}
+
+	return nil
+}
+
+// RunBuildAll is used to build the maps of the given manifest using a single session on the server.
+// The maps are built concurrently by the given number of workers (the value of the manifest is used if 0).
+// The definition of the maps is written to the given writer when using the 'DryRun' option.
+// A summary is written to the given writer and an error is returned if the build of at least one map failed.
+func RunBuildAll(manifestFile string, workers int, options *Options, out io.Writer, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	logger.Debug(fmt.Sprintf("reading the manifest '%s'", manifestFile))
+	manifest, err := LoadManifest(manifestFile)
+	if err != nil {
+		return err
+	}
+
+	if workers == 0 {
+		workers = manifest.Workers
+	}
+
+	enableMetrics(options)
+
+	client, err := initClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	logger.Debug(fmt.Sprintf("building %d map(s)", len(manifest.Maps)))
+	results := buildAll(client, manifest, options, workers, logger)
+
+	if err = writeRequests(out, results); err != nil {
+		return err
+	}
+
+	if err = writeMetricsFile(options, logger); err != nil {
+		return err
+	}
+
+	return writeSummary(out, results)
+}
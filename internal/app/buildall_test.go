@@ -0,0 +1,180 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// newTestingManifest is used to generate a manifest building maps from the example mapping file.
+// The map 'missing' cannot be built since no host matches its filter.
+func newTestingManifest() *Manifest {
+	style := &ManifestMap{
+		File:   mappingFilePath,
+		Width:  "400",
+		Height: "400",
+		Spacer: 50,
+	}
+
+	maps := make([]*ManifestMap, 0)
+	for name, f := range map[string]*filter.Filter{
+		"all":     nil,
+		"r1-r2":   {IncludeHosts: []string{"router-1", "router-2"}},
+		"missing": {IncludeHosts: []string{"switch-*"}},
+	} {
+		entry := &ManifestMap{Name: name, Filter: f}
+		entry.applyDefaults(style)
+		maps = append(maps, entry)
+	}
+
+	return &Manifest{
+		Maps: maps,
+	}
+}
+
+func TestBuildAll(t *testing.T) {
+	client := newFakeClient()
+	manifest := newTestingManifest()
+
+	results := buildAll(client, manifest, &Options{}, 2, logging.NewLogger(logging.Warning))
+	if len(results) != 3 {
+		t.Fatalf("wrong number of results returned.\nExpected : 3\nReturned : %d", len(results))
+	}
+
+	for i, r := range results {
+		if r.Name != manifest.Maps[i].Name {
+			t.Fatalf("the results should be returned in the order of the manifest.\nExpected : %s\nReturned : %s", manifest.Maps[i].Name, r.Name)
+		}
+
+		if r.Name == "missing" && r.Err == nil {
+			t.Fatalf("an error should be returned for the map 'missing'")
+		}
+
+		if r.Name != "missing" && r.Err != nil {
+			t.Fatalf("error while building the map '%s'.\nReason : %v", r.Name, r.Err)
+		}
+	}
+
+	if len(client.Maps) != 2 {
+		t.Fatalf("wrong number of maps created.\nExpected : 2\nReturned : %d", len(client.Maps))
+	}
+
+	for _, m := range client.Maps {
+		if m.Name == "r1-r2" && len(m.Links) != 1 {
+			t.Fatalf("wrong number of links for the map 'r1-r2'.\nExpected : 1\nReturned : %d", len(m.Links))
+		}
+
+		if m.Name == "all" && len(m.Links) != 2 {
+			t.Fatalf("wrong number of links for the map 'all'.\nExpected : 2\nReturned : %d", len(m.Links))
+		}
+	}
+}
+
+func TestBuildAllUpdate(t *testing.T) {
+	client := newFakeClient()
+	existing := &zabbixgosdk.MapCreateParameters{}
+	existing.Name = "all"
+	if _, err := client.CreateMap(existing); err != nil {
+		t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+	}
+
+	manifest := &Manifest{}
+	for _, entry := range newTestingManifest().Maps {
+		if entry.Name == "all" {
+			manifest.Maps = append(manifest.Maps, entry)
+		}
+	}
+
+	results := buildAll(client, manifest, &Options{Update: true}, 0, logging.NewLogger(logging.Warning))
+	if results[0].Err != nil {
+		t.Fatalf("error while building the map 'all'.\nReason : %v", results[0].Err)
+	}
+
+	if len(client.Maps) != 1 || len(client.Maps[0].Links) != 2 {
+		t.Fatalf("the existing map should be updated.\nReturned : %+v", client.Maps)
+	}
+}
+
+func TestBuildAllDryRun(t *testing.T) {
+	client := newFakeClient()
+
+	results := buildAll(client, newTestingManifest(), &Options{DryRun: true}, 2, logging.NewLogger(logging.Warning))
+	if len(client.Maps) != 0 {
+		t.Fatalf("no map should be created in dry-run mode.\nReturned : %d", len(client.Maps))
+	}
+
+	var out bytes.Buffer
+	if err := writeRequests(&out, results); err != nil {
+		t.Fatalf("error while executing writeRequests function.\nReason : %v", err)
+	}
+
+	requests := make(map[string]*zabbixgosdk.MapCreateParameters, 0)
+	if err := json.Unmarshal(out.Bytes(), &requests); err != nil {
+		t.Fatalf("the definitions should be output as a single JSON object.\nReason : %v\nReturned : %s", err, out.String())
+	}
+
+	if len(requests) != 2 || requests["all"].Name != "all" || requests["r1-r2"].Name != "r1-r2" {
+		t.Fatalf("the definitions should be keyed by map name.\nReturned : %s", out.String())
+	}
+}
+
+func TestMappingsCacheRead(t *testing.T) {
+	cache := &mappingsCache{
+		files: make(map[string]*cachedMappings, 0),
+	}
+	logger := logging.NewLogger(logging.Warning)
+
+	first, err := cache.read(mappingFilePath, "", logger)
+	if err != nil {
+		t.Fatalf("error while executing read function.\nReason : %v", err)
+	}
+
+	second, err := cache.read(mappingFilePath, "", logger)
+	if err != nil {
+		t.Fatalf("error while executing read function.\nReason : %v", err)
+	}
+
+	if len(second) != len(first) || second[0] != first[0] {
+		t.Fatalf("the mapping file should only be read once.\nExpected : %v\nReturned : %v", first, second)
+	}
+
+	if _, err = cache.read("missing.json", "", logger); err == nil {
+		t.Fatalf("an error should be returned when the mapping file does not exist")
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	results := buildAll(newFakeClient(), newTestingManifest(), &Options{}, 1, logging.NewLogger(logging.Warning))
+
+	var out bytes.Buffer
+	err := writeSummary(&out, results)
+	if err == nil {
+		t.Fatalf("an error should be returned when the build of a map failed")
+	}
+
+	if !strings.Contains(out.String(), "2 map(s) built, 1 failed") {
+		t.Fatalf("wrong summary returned.\nReturned : %s", out.String())
+	}
+
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(line, "missing") && !strings.Contains(line, "failed") {
+			t.Fatalf("the map 'missing' should be reported as failed.\nReturned : %s", line)
+		}
+
+		if strings.HasPrefix(line, "all") && !strings.Contains(line, "ok") {
+			t.Fatalf("the map 'all' should be reported as built.\nReturned : %s", line)
+		}
+	}
+}
+
+func TestRunBuildAllFailManifest(t *testing.T) {
+	var out bytes.Buffer
+	if err := RunBuildAll("missing.yaml", 0, &Options{}, &out, nil); err == nil {
+		t.Fatalf("an error should be returned when the manifest does not exist")
+	}
+}
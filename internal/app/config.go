@@ -1,11 +1,15 @@
 package app
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
 
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/input"
 	zbxMap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/metrics"
+	"gopkg.in/yaml.v3"
 )
 
 type Options struct {
@@ -21,6 +25,141 @@ type Options struct {
 	Spacer       int64
 	StackHosts   bool
 	DryRun       bool
+	Snapshot     string
+	// Format is the format of the mapping file (json, yaml, csv or dot), detected from the file extension if empty.
+	Format string
+	// HostLookup is the list of strategies used to resolve the hosts (host, name, interface, tag), only the technical name is used if empty.
+	HostLookup []string
+	// HostTag is the name of the tag used by the 'tag' lookup strategy.
+	HostTag string
+	// HostRename is a file associating the names used in the mappings to the values used to search the hosts.
+	HostRename string
+	// HostReport is the file used to store how each host was resolved.
+	HostReport string
+	// IconRules is a file containing the rules used to select the image of the hosts without one.
+	IconRules string
+	// ImagesDir is a directory containing the images to upload to the server before building the map.
+	ImagesDir string
+	// LabelTemplate is the label of the elements without a label set in the mappings.
+	LabelTemplate string
+	// LabelLocation is the location of the labels of the elements (default, bottom, left, right or top).
+	LabelLocation string
+	// Urls is the list of URLs added to the elements, written as 'name=url'.
+	Urls []string
+	// Layout is a file containing the position of the elements and the size of the map (optional).
+	Layout string
+	// Public is used to create a public map instead of a private map.
+	Public bool
+	// Owner is the username of the owner of the map, the API user is used if empty.
+	Owner string
+	// ShareUsers and ShareGroups are the users and the user groups the map is shared with, written as 'name:permission'.
+	ShareUsers  []string
+	ShareGroups []string
+	// SharingFile is a file (YAML or JSON) containing the sharing options, completed by the options above.
+	SharingFile string
+	// Update is used to update the map with the same name if it already exists instead of creating a new map.
+	Update bool
+	// Filter is used to select the part of the mappings to draw (optional).
+	Filter *filter.Filter
+	// Yes is used to skip the confirmation of the destructive actions (deletion of maps).
+	Yes bool
+	// Metrics is used to collect the metrics of the API calls and of the builds (optional).
+	Metrics *metrics.Registry
+	// MetricsFile is the file the metrics are written to once the maps are built, for the textfile collector of the node exporter.
+	MetricsFile string
+}
+
+// urlTemplates is used to read the URLs added to the elements.
+func (o *Options) urlTemplates() ([]*zbxMap.UrlTemplate, error) {
+	out := make([]*zbxMap.UrlTemplate, 0)
+
+	for _, value := range o.Urls {
+		u, err := zbxMap.ParseUrlTemplate(value)
+		if err != nil {
+			return nil, failure.New(failure.Config, err)
+		}
+
+		out = append(out, u)
+	}
+
+	return out, nil
+}
+
+// sharing is used to read the sharing options of the map from the sharing file, completed by the sharing flags.
+func (o *Options) sharing() (*zbxMap.Sharing, error) {
+	sharing := &zbxMap.Sharing{}
+
+	if o.SharingFile != "" {
+		s, err := zbxMap.LoadSharing(o.SharingFile)
+		if err != nil {
+			return nil, failure.New(failure.Config, err)
+		}
+
+		sharing = s
+	}
+
+	if o.Public {
+		private := false
+		sharing.Private = &private
+	}
+
+	if o.Owner != "" {
+		sharing.Owner = o.Owner
+	}
+
+	for _, value := range o.ShareUsers {
+		s, err := zbxMap.ParseShare(value)
+		if err != nil {
+			return nil, failure.New(failure.Config, err)
+		}
+
+		sharing.Users = append(sharing.Users, s)
+	}
+
+	for _, value := range o.ShareGroups {
+		s, err := zbxMap.ParseShare(value)
+		if err != nil {
+			return nil, failure.New(failure.Config, err)
+		}
+
+		sharing.UserGroups = append(sharing.UserGroups, s)
+	}
+
+	return sharing, nil
+}
+
+// resolveOptions is used to retrieve the options used to resolve the hosts referenced in the mappings.
+func (o *Options) resolveOptions() (*api.ResolveOptions, error) {
+	options := &api.ResolveOptions{
+		Strategies: o.HostLookup,
+		Tag:        o.HostTag,
+	}
+
+	if o.HostRename != "" {
+		rename, err := readRenameFile(o.HostRename)
+		if err != nil {
+			return nil, err
+		}
+
+		options.Rename = rename
+	}
+
+	return options, failure.New(failure.Config, options.Validate())
+}
+
+// readRenameFile is used to read a file (JSON or YAML) associating the names used in the mappings to the values used to search the hosts.
+func readRenameFile(file string) (map[string]string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, failure.New(failure.Config, err)
+	}
+
+	out := make(map[string]string, 0)
+	if err = yaml.Unmarshal(b, &out); err != nil {
+		return nil, failure.Errorf(failure.Config, "error while reading the rename file '%s'.\nReason : %v", file, err)
+	}
+
+	return out, nil
 }
 
 // GetEnvironmentVariables is used to retrive the required environment variables for the Zabbix API.
@@ -28,36 +167,71 @@ func GetEnvironmentVariables() (*Options, error) {
 	vars := Options{}
 
 	if vars.ZabbixUrl = os.Getenv("ZABBIX_URL"); vars.ZabbixUrl == "" {
-		return nil, fmt.Errorf("required environment variable 'ZABBIX_URL' is not set")
+		return nil, failure.Errorf(failure.Config, "required environment variable 'ZABBIX_URL' is not set")
 	}
 
 	if vars.ZabbixUser = os.Getenv("ZABBIX_USER"); vars.ZabbixUser == "" {
-		return nil, fmt.Errorf("required environment variable 'ZABBIX_USER' is not set")
+		return nil, failure.Errorf(failure.Config, "required environment variable 'ZABBIX_USER' is not set")
 	}
 
 	if vars.ZabbixPwd = os.Getenv("ZABBIX_PWD"); vars.ZabbixPwd == "" {
-		return nil, fmt.Errorf("required environment variable 'ZABBIX_PWD' is not set")
+		return nil, failure.Errorf(failure.Config, "required environment variable 'ZABBIX_PWD' is not set")
 	}
 
 	return &vars, nil
 }
 
 // readInput is used to read data from the given file and return a list of Host.
+// The format of the file is detected from its extension.
 func ReadInput(file string) ([]*zbxMap.Mapping, error) {
+	return ReadInputFormat(file, "")
+}
+
+// ReadInputFormat is used to read data from the given file using the given format (json, yaml, csv or dot) and return a list of Host.
+// If the format is empty, it is detected from the extension of the file.
+// The file is validated before being read, unknown fields, missing required fields, self-links and duplicate links are rejected.
+// The file is read once, the same content is validated and read even if the file is modified in the meantime (see the watch command).
+func ReadInputFormat(file string, format string) ([]*zbxMap.Mapping, error) {
+	if format == "" {
+		format = input.DetectFormat(file)
+	}
+
 	b, err := os.ReadFile(file)
 	if err != nil {
-		return nil, err
+		return nil, failure.New(failure.Validation, err)
 	}
 
-	entries := make([]*zbxMap.Mapping, 0)
-	err = json.Unmarshal(b, &entries)
+	if err = input.ValidateBytes(file, b, format); err != nil {
+		return nil, failure.New(failure.Validation, err)
+	}
+
+	entries, err := input.ReadBytes(b, format)
 	if err != nil {
-		return nil, err
+		return nil, failure.New(failure.Validation, err)
 	}
 
 	if len(entries) == 0 {
-		return nil, fmt.Errorf("no mapping were found in '%s'", file)
+		return nil, failure.Errorf(failure.Validation, "no mapping were found in '%s'", file)
 	}
 
 	return entries, nil
 }
+
+// RenderOptions define the options used to render a map to an image.
+type RenderOptions struct {
+	// Input is the file containing a map create request (see the 'output' flag).
+	Input string
+	// Map is the name of an existing map to retrieve from the Zabbix server.
+	Map     string
+	SvgFile string
+	PngFile string
+}
+
+// GraphOptions define the options used to export a mapping file to a graph format.
+type GraphOptions struct {
+	Name string
+	// Format is one of the formats supported by the export package (dot, mermaid, graphml).
+	Format string
+	// OutFile is the file used to store the graph, the graph is output to the shell if empty.
+	OutFile string
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
 )
 
 const (
@@ -68,6 +70,10 @@ func TestGetEnvironmentVariablesMissingUrl(t *testing.T) {
 	if opts != nil {
 		t.Fatalf("a nil pointer should be returned instead of *Options when an environment variable is missing")
 	}
+
+	if kind := failure.KindOf(err); kind != failure.Config {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Config, kind)
+	}
 }
 
 func TestGetEnvironmentVariablesMissingUser(t *testing.T) {
@@ -184,8 +190,65 @@ func TestReadInputNoData(t *testing.T) {
 		t.Fatal("a nil pointer should be returned instead of *[]zbxMap.Mapping when the processing fails")
 	}
 
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+
 	err = os.Remove(testFile)
 	if err != nil {
 		t.Fatalf("error while removing file '%s'.\nReason : %v", testFile, err)
 	}
 }
+
+func TestReadInputFormat(t *testing.T) {
+	file := filepath.Join(filepath.Dir(mappingFilePath), "mapping.csv")
+
+	m, err := ReadInputFormat(file, "csv")
+	if err != nil {
+		t.Fatalf("error while executing ReadInputFormat function.\nReason : %v", err)
+	}
+
+	if len(m) != 2 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 2\nReturned : %d", len(m))
+	}
+}
+
+func TestReadInputFormatUnsupported(t *testing.T) {
+	m, err := ReadInputFormat(mappingFilePath, "xml")
+	if err == nil {
+		t.Fatalf("an error should be returned when the format is not supported")
+	}
+
+	if m != nil {
+		t.Fatal("a nil pointer should be returned instead of *[]zbxMap.Mapping when the processing fails")
+	}
+}
+
+func TestOptionsSharing(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sharing.yaml")
+	if err := os.WriteFile(file, []byte("owner: Admin\nusers:\n  - name: noc\n    permission: read\n"), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+	}
+
+	options := &Options{
+		SharingFile: file,
+		Public:      true,
+		Owner:       "noc",
+		ShareUsers:  []string{"ops:read-write"},
+	}
+
+	sharing, err := options.sharing()
+	if err != nil {
+		t.Fatalf("error while executing sharing function.\nReason : %v", err)
+	}
+
+	// The flags override the owner of the file and complete its users
+	if sharing.Owner != "noc" || sharing.Private == nil || *sharing.Private || len(sharing.Users) != 2 || sharing.Users[1].Name != "ops" {
+		t.Fatalf("wrong sharing options returned.\nReturned : %+v", sharing)
+	}
+
+	sharing, err = (&Options{}).sharing()
+	if err != nil || !sharing.IsEmpty() {
+		t.Fatalf("no sharing option should be returned.\nReturned : %+v", sharing)
+	}
+}
@@ -0,0 +1,489 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/metrics"
+	"gopkg.in/yaml.v3"
+)
+
+// DaemonConfig define the schedule, the discovery commands and the maps reconciled by the daemon command.
+// Relative paths are resolved from the directory of the configuration file.
+type DaemonConfig struct {
+	// Interval is the delay between two runs (ex: '15m'), each run executes the discovery then reconciles the maps.
+	Interval string `yaml:"interval"`
+	// Discovery contains the commands regenerating the mapping files, executed in order before reconciling the maps.
+	Discovery []*DiscoveryCommand `yaml:"discovery,omitempty"`
+	// Manifest is the manifest listing the maps to reconcile (see the build-all command).
+	Manifest string `yaml:"manifest"`
+	// Workers is the number of maps built concurrently, the value of the manifest is used if not set.
+	Workers int `yaml:"workers,omitempty"`
+	// StateFile is the file recording the last run and the last successful sync of each map.
+	StateFile string `yaml:"state_file"`
+	// LockFile is the file preventing overlapping runs, the lock is released by the system if the process holding it exits.
+	LockFile string `yaml:"lock_file"`
+	// MetricsListen is the address on which the metrics are exposed (ex: ':9101'), the metrics are not exposed if empty.
+	MetricsListen string `yaml:"metrics_listen,omitempty"`
+}
+
+// DiscoveryCommand define an external command (SNMP, CDP or LLDP collector for example) generating a mapping file.
+type DiscoveryCommand struct {
+	// Command is the executable and its arguments.
+	Command []string `yaml:"command"`
+	// Output is the mapping file written with the standard output of the command, the command writes the file itself if empty.
+	Output string `yaml:"output,omitempty"`
+	// Timeout is the maximum duration of the command (ex: '5m'), 10 minutes are used if not set.
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+const defaultDiscoveryTimeout = 10 * time.Minute
+
+// LoadDaemonConfig is used to read the configuration of the daemon from the given file (YAML or JSON).
+func LoadDaemonConfig(file string) (*DaemonConfig, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, failure.New(failure.Config, err)
+	}
+
+	c := &DaemonConfig{}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(c); err != nil {
+		return nil, failure.Errorf(failure.Config, "error while reading the daemon configuration '%s'.\nReason : %v", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	paths := []*string{&c.Manifest, &c.StateFile, &c.LockFile}
+	for _, d := range c.Discovery {
+		paths = append(paths, &d.Output)
+	}
+
+	for _, p := range paths {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+
+	if err = c.Validate(); err != nil {
+		return nil, failure.Errorf(failure.Config, "error while reading the daemon configuration '%s'.\nReason : %v", file, err)
+	}
+
+	return c, nil
+}
+
+// parseDuration is used to read the given duration, the default value is returned if empty.
+func parseDuration(name string, value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s'.\nReason : %v", name, value, err)
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("the %s must be greater than 0", name)
+	}
+
+	return d, nil
+}
+
+// Validate is used to validate the configuration of the daemon.
+func (c *DaemonConfig) Validate() error {
+	if c.Interval == "" {
+		return fmt.Errorf("an interval is required")
+	}
+
+	if _, err := parseDuration("interval", c.Interval, 0); err != nil {
+		return err
+	}
+
+	if c.Manifest == "" {
+		return fmt.Errorf("a manifest is required")
+	}
+
+	if c.StateFile == "" {
+		return fmt.Errorf("a state file is required")
+	}
+
+	if c.LockFile == "" {
+		return fmt.Errorf("a lock file is required")
+	}
+
+	if c.Workers < 0 {
+		return fmt.Errorf("the number of workers cannot be negative")
+	}
+
+	for i, d := range c.Discovery {
+		if len(d.Command) == 0 || d.Command[0] == "" {
+			return fmt.Errorf("a command is required for the discovery at the position %d", i+1)
+		}
+
+		if _, err := parseDuration("discovery timeout", d.Timeout, defaultDiscoveryTimeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// errLocked is returned when the lock is held by another run.
+var errLocked = errors.New("another run is in progress")
+
+// acquireLock is used to lock the given lock file and to write the pid of the process to it.
+// The lock is held until releaseLock is called, errLocked is returned if the lock is held by another run.
+func acquireLock(file string) (*os.File, error) {
+	f, err := openLock(file)
+	if errors.Is(err, errLocked) {
+		pid, _ := os.ReadFile(file)
+		return nil, fmt.Errorf("%w (lock '%s' held by the pid %s)", errLocked, file, strings.TrimSpace(string(pid)))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.Truncate(0); err == nil {
+		_, err = f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// releaseLock is used to release the lock held on the given file.
+// The file is kept, removing it would allow another run to lock a new file while a run still holds the removed one.
+func releaseLock(f *os.File) error {
+	return f.Close()
+}
+
+// DaemonState define the outcome of the last run and the last successful sync of each map.
+type DaemonState struct {
+	LastRun *time.Time `json:"last_run,omitempty"`
+	// LastError is the error of the last run (lock, discovery or manifest error), empty if the maps were reconciled.
+	LastError string               `json:"last_error,omitempty"`
+	Maps      map[string]*MapState `json:"maps"`
+}
+
+// MapState define the last sync of a map.
+type MapState struct {
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	// LastError is the error of the last attempt, empty if the map was synced.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// LoadDaemonState is used to read the state of the daemon from the given file, an empty state is returned if the file does not exist.
+func LoadDaemonState(file string) (*DaemonState, error) {
+	state := &DaemonState{
+		Maps: make(map[string]*MapState, 0),
+	}
+
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(b, state); err != nil {
+		return nil, fmt.Errorf("error while reading the state file '%s'.\nReason : %v", file, err)
+	}
+
+	if state.Maps == nil {
+		state.Maps = make(map[string]*MapState, 0)
+	}
+
+	return state, nil
+}
+
+// Write is used to write the state to the given file.
+// The state is written to a temporary file renamed once complete, the previous state is kept if the write fails.
+func (s *DaemonState) Write(file string) error {
+	b, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	tmp := file + ".tmp"
+	if err = os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, file)
+}
+
+// record is used to update the state of the maps using the given results.
+func (s *DaemonState) record(results []*BuildResult, at time.Time) {
+	for _, r := range results {
+		m, exist := s.Maps[r.Name]
+		if !exist {
+			m = &MapState{}
+			s.Maps[r.Name] = m
+		}
+
+		attempt := at
+		m.LastAttempt = &attempt
+		m.LastError = ""
+
+		if r.Err != nil {
+			m.LastError = strings.Join(strings.Fields(r.Err.Error()), " ")
+			continue
+		}
+
+		m.LastSuccess = &attempt
+	}
+}
+
+// runDiscovery is used to execute the given discovery command, the standard output is written to the output file if set.
+// The command is stopped when the given context is cancelled or when the timeout is reached.
+func runDiscovery(ctx context.Context, d *DiscoveryCommand, logger *logging.Logger) error {
+	timeout, err := parseDuration("discovery timeout", d.Timeout, defaultDiscoveryTimeout)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logger.Debug(fmt.Sprintf("running the discovery command %v", d.Command))
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, d.Command[0], d.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err = cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+
+		return fmt.Errorf("error while running the discovery command %v.\nReason : %v\n%s", d.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	if d.Output == "" {
+		return nil
+	}
+
+	// The mapping file is only replaced once the command succeeded
+	tmp := d.Output + ".tmp"
+	if err = os.WriteFile(tmp, stdout.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, d.Output)
+}
+
+// daemon define the reconciliation loop of the daemon command.
+type daemon struct {
+	config  *DaemonConfig
+	options *Options
+	logger  *logging.Logger
+	// newClient is used to open a new session on the server for each run.
+	newClient func() (api.ZabbixAPI, error)
+}
+
+// reconcile is used to run the discovery commands and to create or update the maps of the manifest.
+// The results of the maps are returned, an error is returned if the maps could not be reconciled (lock, discovery or manifest error).
+func (d *daemon) reconcile(ctx context.Context) ([]*BuildResult, error) {
+	lock, err := acquireLock(d.config.LockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := releaseLock(lock); err != nil {
+			d.logger.Error("error while releasing the lock", err)
+		}
+	}()
+
+	for _, discovery := range d.config.Discovery {
+		if err = runDiscovery(ctx, discovery, d.logger); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := LoadManifest(d.config.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := d.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := client.Logout(); err != nil {
+			d.logger.Error("error while logging out", err)
+		}
+	}()
+
+	workers := d.config.Workers
+	if workers == 0 {
+		workers = manifest.Workers
+	}
+
+	return buildAll(client, manifest, d.options, workers, d.logger), nil
+}
+
+// run is used to execute a single reconciliation and to record its outcome in the state file.
+// The returned error is the error of the run, or the error of the first map that could not be built.
+func (d *daemon) run(ctx context.Context) error {
+	start := time.Now()
+	results, runErr := d.reconcile(ctx)
+
+	if errors.Is(runErr, errLocked) {
+		// The state is owned by the run holding the lock
+		return runErr
+	}
+
+	state, err := LoadDaemonState(d.config.StateFile)
+	if err != nil {
+		return err
+	}
+
+	state.LastRun = &start
+	state.LastError = ""
+	if runErr != nil {
+		state.LastError = strings.Join(strings.Fields(runErr.Error()), " ")
+	}
+
+	state.record(results, start)
+
+	if err = state.Write(d.config.StateFile); err != nil {
+		return err
+	}
+
+	if err = writeMetricsFile(d.options, d.logger); err != nil {
+		return err
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			d.logger.With("map", r.Name).Error(fmt.Sprintf("error while reconciling the map '%s'", r.Name), r.Err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d map(s) could not be reconciled", failed, len(results))
+	}
+
+	d.logger.Info(fmt.Sprintf("%d map(s) reconciled in %s", len(results), time.Since(start).Round(time.Millisecond)))
+	return nil
+}
+
+// loop is used to run a reconciliation immediately, then on each interval until the given channel is closed.
+// A run in progress is completed before stopping, except the discovery commands which are cancelled.
+func (d *daemon) loop(interval time.Duration, stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.run(ctx); err != nil {
+			d.logger.Error("error during the reconciliation", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDaemon is used to reconcile the maps of the given configuration on a schedule, until the given channel is closed.
+// If once is set, a single reconciliation is executed and its error is returned.
+func RunDaemon(configFile string, once bool, options *Options, stop <-chan struct{}, logger *logging.Logger) error {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	config, err := LoadDaemonConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	interval, err := parseDuration("interval", config.Interval, 0)
+	if err != nil {
+		return err
+	}
+
+	// The maps created by a previous run are updated
+	options.Update = true
+
+	enableMetrics(options)
+	if config.MetricsListen != "" && options.Metrics == nil {
+		options.Metrics = metrics.NewRegistry()
+	}
+
+	d := &daemon{
+		config:  config,
+		options: options,
+		logger:  logger,
+		newClient: func() (api.ZabbixAPI, error) {
+			return initClient(options, logger)
+		},
+	}
+
+	if once {
+		return d.run(context.Background())
+	}
+
+	var metricsServer *http.Server
+	if config.MetricsListen != "" {
+		metricsServer, err = serveMetrics(config.MetricsListen, options.Metrics, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Debug(fmt.Sprintf("starting the reconciliation loop every %s", interval))
+	d.loop(interval, stop)
+
+	logger.Debug("daemon stopped, starting the exit process.")
+	if metricsServer != nil {
+		return metricsServer.Close()
+	}
+
+	return nil
+}
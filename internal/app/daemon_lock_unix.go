@@ -0,0 +1,30 @@
+//go:build !windows
+
+package app
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openLock is used to open the given lock file and to hold an exclusive lock on it (flock).
+// The lock is released when the file is closed or when the process exits, errLocked is returned if the lock is held by another run.
+func openLock(file string) (*os.File, error) {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errLocked
+		}
+
+		return nil, err
+	}
+
+	return f, nil
+}
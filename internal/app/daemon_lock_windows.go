@@ -0,0 +1,31 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// errorSharingViolation is the error returned by Windows when a file is opened by another process without sharing it.
+const errorSharingViolation syscall.Errno = 32
+
+// openLock is used to open the given lock file without sharing the write access, preventing other runs from opening it.
+// The lock is released when the file is closed or when the process exits, errLocked is returned if the lock is held by another run.
+func openLock(file string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// The read access is shared to allow other runs to read the pid of the process holding the lock
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, syscall.FILE_SHARE_READ, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if errors.Is(err, errorSharingViolation) {
+			return nil, errLocked
+		}
+
+		return nil, err
+	}
+
+	return os.NewFile(uintptr(h), file), nil
+}
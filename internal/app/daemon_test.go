@@ -0,0 +1,376 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// writeDaemonConfig is used to write a daemon configuration and a manifest to a temporary directory.
+// The discovery command copies the example mapping file to the mapping file of the manifest.
+func writeDaemonConfig(t *testing.T) string {
+	dir := t.TempDir()
+
+	manifest := "maps:\n  - name: daemon-map\n    file: mapping.json\n    width: \"400\"\n    height: \"400\"\n    spacer: 50\n"
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("error while writing the manifest.\nReason : %v", err)
+	}
+
+	config := fmt.Sprintf(`
+interval: 20ms
+discovery:
+  - command: ["cat", "%s"]
+    output: mapping.json
+manifest: manifest.yaml
+state_file: state.json
+lock_file: daemon.lock
+`, mappingFilePath)
+
+	file := filepath.Join(dir, "daemon.yaml")
+	if err := os.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatalf("error while writing the daemon configuration.\nReason : %v", err)
+	}
+
+	return file
+}
+
+// newTestingDaemon is used to create a daemon using the configuration of the given file and the given client.
+func newTestingDaemon(t *testing.T, file string, client api.ZabbixAPI) *daemon {
+	config, err := LoadDaemonConfig(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadDaemonConfig function.\nReason : %v", err)
+	}
+
+	return &daemon{
+		config:  config,
+		options: &Options{Update: true},
+		logger:  logging.NewLogger(logging.Critical),
+		newClient: func() (api.ZabbixAPI, error) {
+			return client, nil
+		},
+	}
+}
+
+func TestLoadDaemonConfig(t *testing.T) {
+	file := writeDaemonConfig(t)
+	dir := filepath.Dir(file)
+
+	c, err := LoadDaemonConfig(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadDaemonConfig function.\nReason : %v", err)
+	}
+
+	if c.Manifest != filepath.Join(dir, "manifest.yaml") || c.StateFile != filepath.Join(dir, "state.json") || c.LockFile != filepath.Join(dir, "daemon.lock") {
+		t.Fatalf("the relative paths should be resolved from the directory of the configuration.\nReturned : %+v", c)
+	}
+
+	if len(c.Discovery) != 1 || c.Discovery[0].Output != filepath.Join(dir, "mapping.json") {
+		t.Fatalf("wrong discovery commands returned.\nReturned : %+v", c.Discovery)
+	}
+}
+
+func TestLoadDaemonConfigFail(t *testing.T) {
+	base := "interval: 5m\nmanifest: m.yaml\nstate_file: s.json\nlock_file: d.lock\n"
+	tests := map[string]string{
+		"missing interval":  "manifest: m.yaml\nstate_file: s.json\nlock_file: d.lock\n",
+		"invalid interval":  strings.Replace(base, "5m", "5 minutes", 1),
+		"negative interval": strings.Replace(base, "5m", "-5m", 1),
+		"missing manifest":  strings.Replace(base, "manifest: m.yaml\n", "", 1),
+		"missing state":     strings.Replace(base, "state_file: s.json\n", "", 1),
+		"missing lock":      strings.Replace(base, "lock_file: d.lock\n", "", 1),
+		"empty command":     base + "discovery:\n  - output: mapping.json\n",
+		"command timeout":   base + "discovery:\n  - command: [true]\n    timeout: 0s\n",
+		"unknown field":     base + "schedule: daily\n",
+	}
+
+	for name, content := range tests {
+		file := filepath.Join(t.TempDir(), "daemon.yaml")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("error while writing the daemon configuration.\nReason : %v", err)
+		}
+
+		if _, err := LoadDaemonConfig(file); err == nil {
+			t.Fatalf("an error should be returned for the test '%s'", name)
+		}
+	}
+}
+
+func TestAcquireLock(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "daemon.lock")
+
+	lock, err := acquireLock(file)
+	if err != nil {
+		t.Fatalf("error while executing acquireLock function.\nReason : %v", err)
+	}
+
+	pid := strconv.Itoa(os.Getpid())
+	if _, err = acquireLock(file); !errors.Is(err, errLocked) || !strings.Contains(err.Error(), pid) {
+		t.Fatalf("the lock should be held by the pid %s.\nReturned : %v", pid, err)
+	}
+
+	if err = releaseLock(lock); err != nil {
+		t.Fatalf("error while executing releaseLock function.\nReason : %v", err)
+	}
+
+	// The lock file is kept and can be locked again
+	lock, err = acquireLock(file)
+	if err != nil {
+		t.Fatalf("the lock should be acquired once released.\nReason : %v", err)
+	}
+
+	if err = releaseLock(lock); err != nil {
+		t.Fatalf("error while executing releaseLock function.\nReason : %v", err)
+	}
+}
+
+func TestAcquireLockConcurrent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "daemon.lock")
+
+	var wg sync.WaitGroup
+	locks := make(chan *os.File, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if lock, err := acquireLock(file); err == nil {
+				locks <- lock
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(locks)
+
+	if len(locks) != 1 {
+		t.Fatalf("the lock should be acquired by a single run.\nExpected : 1\nReturned : %d", len(locks))
+	}
+
+	for lock := range locks {
+		releaseLock(lock)
+	}
+}
+
+func TestDaemonState(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := LoadDaemonState(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadDaemonState function.\nReason : %v", err)
+	}
+
+	first := time.Now().Add(-time.Hour)
+	state.record([]*BuildResult{{Name: "dc1"}, {Name: "dc2"}}, first)
+
+	second := time.Now()
+	state.record([]*BuildResult{{Name: "dc1"}, {Name: "dc2", Err: fmt.Errorf("no host found\nfor 'router-4'")}}, second)
+
+	if err = state.Write(file); err != nil {
+		t.Fatalf("error while executing Write function.\nReason : %v", err)
+	}
+
+	state, err = LoadDaemonState(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadDaemonState function.\nReason : %v", err)
+	}
+
+	if !state.Maps["dc1"].LastSuccess.Equal(second) {
+		t.Fatalf("wrong last success for the map 'dc1'.\nExpected : %s\nReturned : %s", second, state.Maps["dc1"].LastSuccess)
+	}
+
+	dc2 := state.Maps["dc2"]
+	if !dc2.LastSuccess.Equal(first) || !dc2.LastAttempt.Equal(second) || dc2.LastError != "no host found for 'router-4'" {
+		t.Fatalf("the last success of the map 'dc2' should be kept when the sync failed.\nReturned : %+v", dc2)
+	}
+}
+
+func TestRunDiscovery(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "mapping.json")
+	logger := logging.NewLogger(logging.Critical)
+
+	err := runDiscovery(context.Background(), &DiscoveryCommand{Command: []string{"echo", "[]"}, Output: output}, logger)
+	if err != nil {
+		t.Fatalf("error while executing runDiscovery function.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(output)
+	if err != nil || string(b) != "[]\n" {
+		t.Fatalf("the standard output of the command should be written to '%s'.\nReturned : %s", output, string(b))
+	}
+
+	// The mapping file is kept if the command fails
+	err = runDiscovery(context.Background(), &DiscoveryCommand{Command: []string{"sh", "-c", "echo partial; exit 3"}, Output: output}, logger)
+	if err == nil {
+		t.Fatalf("an error should be returned when the command fails")
+	}
+
+	if b, _ = os.ReadFile(output); string(b) != "[]\n" {
+		t.Fatalf("the mapping file should not be modified when the command fails.\nReturned : %s", string(b))
+	}
+
+	err = runDiscovery(context.Background(), &DiscoveryCommand{Command: []string{"sleep", "5"}, Timeout: "50ms"}, logger)
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Fatalf("an error should be returned when the timeout is reached.\nReturned : %v", err)
+	}
+}
+
+func TestDaemonRun(t *testing.T) {
+	client := newFakeClient()
+	d := newTestingDaemon(t, writeDaemonConfig(t), client)
+
+	for i := 0; i < 2; i++ {
+		if err := d.run(context.Background()); err != nil {
+			t.Fatalf("error while executing run function.\nReason : %v", err)
+		}
+	}
+
+	// The map created by the first run is updated by the second run
+	if len(client.Maps) != 1 || client.Maps[0].Name != "daemon-map" {
+		t.Fatalf("wrong maps created.\nReturned : %+v", client.Maps)
+	}
+
+	state, err := LoadDaemonState(d.config.StateFile)
+	if err != nil {
+		t.Fatalf("error while executing LoadDaemonState function.\nReason : %v", err)
+	}
+
+	if state.LastRun == nil || state.LastError != "" || state.Maps["daemon-map"] == nil || state.Maps["daemon-map"].LastSuccess == nil {
+		t.Fatalf("the sync of the map should be recorded.\nReturned : %+v", state)
+	}
+
+	lock, err := acquireLock(d.config.LockFile)
+	if err != nil {
+		t.Fatalf("the lock should be released after the run.\nReason : %v", err)
+	}
+
+	releaseLock(lock)
+}
+
+func TestDaemonRunLocked(t *testing.T) {
+	client := newFakeClient()
+	d := newTestingDaemon(t, writeDaemonConfig(t), client)
+
+	lock, err := acquireLock(d.config.LockFile)
+	if err != nil {
+		t.Fatalf("error while executing acquireLock function.\nReason : %v", err)
+	}
+
+	defer releaseLock(lock)
+
+	if err = d.run(context.Background()); !errors.Is(err, errLocked) {
+		t.Fatalf("the run should be skipped when the lock is held.\nExpected : %v\nReturned : %v", errLocked, err)
+	}
+
+	if len(client.Maps) != 0 {
+		t.Fatalf("no map should be created when the lock is held.\nReturned : %d", len(client.Maps))
+	}
+
+	if _, err = os.Stat(d.config.StateFile); !os.IsNotExist(err) {
+		t.Fatalf("the state should not be written when the lock is held")
+	}
+}
+
+func TestDaemonRunDiscoveryError(t *testing.T) {
+	client := newFakeClient()
+	d := newTestingDaemon(t, writeDaemonConfig(t), client)
+	d.config.Discovery[0].Command = []string{"false"}
+
+	if err := d.run(context.Background()); err == nil {
+		t.Fatalf("an error should be returned when the discovery fails")
+	}
+
+	if len(client.Maps) != 0 {
+		t.Fatalf("the maps should not be reconciled when the discovery fails.\nReturned : %d", len(client.Maps))
+	}
+
+	state, err := LoadDaemonState(d.config.StateFile)
+	if err != nil {
+		t.Fatalf("error while executing LoadDaemonState function.\nReason : %v", err)
+	}
+
+	if state.LastError == "" {
+		t.Fatalf("the error of the run should be recorded.\nReturned : %+v", state)
+	}
+}
+
+func TestDaemonLoop(t *testing.T) {
+	client := newFakeClient()
+	d := newTestingDaemon(t, writeDaemonConfig(t), client)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		d.loop(20*time.Millisecond, stop)
+		close(done)
+	}()
+
+	// Wait for a few runs
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		maps, _ := client.GetMaps([]string{"daemon-map"})
+		if len(maps) == 1 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("the map was not reconciled in time")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	close(stop)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the loop should stop once the channel is closed")
+	}
+
+	if len(client.Maps) != 1 {
+		t.Fatalf("each run should update the same map.\nExpected : 1\nReturned : %d", len(client.Maps))
+	}
+}
+
+func TestLoadDaemonConfigExample(t *testing.T) {
+	file := filepath.Join(filepath.Dir(mappingFilePath), "daemon.yaml")
+
+	c, err := LoadDaemonConfig(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadDaemonConfig function.\nReason : %v", err)
+	}
+
+	if _, err = LoadManifest(c.Manifest); err != nil {
+		t.Fatalf("the manifest of the example configuration should be valid.\nReason : %v", err)
+	}
+}
+
+func TestDaemonRunMetrics(t *testing.T) {
+	d := newTestingDaemon(t, writeDaemonConfig(t), newFakeClient())
+	d.options.MetricsFile = filepath.Join(filepath.Dir(d.config.StateFile), "zabbix_map_builder.prom")
+	enableMetrics(d.options)
+
+	if err := d.run(context.Background()); err != nil {
+		t.Fatalf("error while executing run function.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(d.options.MetricsFile)
+	if err != nil {
+		t.Fatalf("error while reading the metrics file.\nReason : %v", err)
+	}
+
+	if !strings.Contains(string(b), `zabbix_map_builder_last_success_timestamp_seconds{map="daemon-map"}`) {
+		t.Fatalf("the last successful sync of the map should be written.\nReturned : %s", string(b))
+	}
+}
@@ -0,0 +1,251 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// isId is used to check if the given value can be used as a map id.
+func isId(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findMaps is used to retrieve the maps matching the given names or ids.
+// Each value is first searched as a name, then as an id if it only contains digits. An error is returned if a value matches no map.
+func findMaps(client api.ZabbixAPI, identifiers []string) ([]*api.Map, error) {
+	byName, err := client.GetMaps(identifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+	for _, value := range identifiers {
+		if isId(value) {
+			ids = append(ids, value)
+		}
+	}
+
+	byId := make([]*api.Map, 0)
+	if len(ids) > 0 {
+		byId, err = client.GetMapsById(ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]*api.Map, 0)
+	found := make([]string, 0)
+	add := func(m *api.Map) {
+		if !utils.Contains(found, m.Id) {
+			found = append(found, m.Id)
+			out = append(out, m)
+		}
+	}
+
+	for _, value := range identifiers {
+		var match *api.Map
+		for _, m := range byName {
+			if m.Name == value {
+				match = m
+				break
+			}
+		}
+
+		if match == nil {
+			for _, m := range byId {
+				if m.Id == value {
+					match = m
+					break
+				}
+			}
+		}
+
+		if match == nil {
+			return nil, failure.Errorf(failure.NotFound, "no map was found with the name or the id '%s'", value)
+		}
+
+		add(match)
+	}
+
+	return out, nil
+}
+
+// findStaleMaps is used to retrieve the maps whose name starts with the given prefix and that are not part of the maps to keep.
+// The maps are sorted by name.
+func findStaleMaps(client api.ZabbixAPI, prefix string, keep []string) ([]*api.Map, error) {
+	if prefix == "" {
+		return nil, failure.Errorf(failure.Config, "a prefix is required to find the maps managed by the tool")
+	}
+
+	maps, err := client.SearchMaps(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*api.Map, 0)
+	for _, m := range maps {
+		if !utils.Contains(keep, m.Name) {
+			out = append(out, m)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+// readKeepFile is used to read the names of the maps to keep from the given file (one name per line, lines starting with '#' are ignored).
+func readKeepFile(file string) ([]string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0)
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			out = append(out, line)
+		}
+	}
+
+	return out, nil
+}
+
+// confirm is used to ask the user to confirm an action, only 'y' and 'yes' are accepted as confirmation.
+func confirm(in io.Reader, out io.Writer, message string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", message)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// deleteMaps is used to delete the given maps after the confirmation of the user.
+// The confirmation is skipped if the 'Yes' option is set, nothing is deleted if the 'DryRun' option is set.
+func deleteMaps(client api.ZabbixAPI, maps []*api.Map, options *Options, in io.Reader, out io.Writer, logger *logging.Logger) error {
+	if len(maps) == 0 {
+		fmt.Fprintln(out, "no map to delete")
+		return nil
+	}
+
+	ids := make([]string, 0)
+	for _, m := range maps {
+		ids = append(ids, m.Id)
+		fmt.Fprintf(out, "%s (sysmapid %s)\n", m.Name, m.Id)
+	}
+
+	if options.DryRun {
+		logger.Debug("'--dry-run' flag used, skipping the deletion.")
+		return nil
+	}
+
+	if !options.Yes && !confirm(in, out, fmt.Sprintf("Delete %d map(s) ?", len(maps))) {
+		return fmt.Errorf("the deletion was not confirmed, no map was deleted")
+	}
+
+	logger.Debug(fmt.Sprintf("deleting the maps %v", ids))
+	if err := client.DeleteMaps(ids); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%d map(s) deleted\n", len(maps))
+
+	return nil
+}
+
+// RunDelete is used to delete the maps matching the given names or ids.
+// The user is asked to confirm the deletion using the given input, unless the 'Yes' option is set.
+func RunDelete(identifiers []string, options *Options, in io.Reader, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	client, err := initServerClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	maps, err := findMaps(client, identifiers)
+	if err != nil {
+		return err
+	}
+
+	return deleteMaps(client, maps, options, in, os.Stdout, logger)
+}
+
+// RunPrune is used to delete the maps whose name starts with the given prefix and that are not part of the maps to keep.
+// The maps to keep are the given names, the names read from the keep file (optional) and the names of the maps of the manifest (optional).
+// The user is asked to confirm the deletion using the given input, unless the 'Yes' option is set.
+func RunPrune(prefix string, keep []string, keepFile string, manifestFile string, options *Options, in io.Reader, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	names := make([]string, 0)
+	names = append(names, keep...)
+
+	if keepFile != "" {
+		logger.Debug(fmt.Sprintf("reading the maps to keep from '%s'", keepFile))
+		values, err := readKeepFile(keepFile)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, values...)
+	}
+
+	if manifestFile != "" {
+		logger.Debug(fmt.Sprintf("reading the maps to keep from the manifest '%s'", manifestFile))
+		manifest, err := LoadManifest(manifestFile)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range manifest.Maps {
+			names = append(names, m.Name)
+		}
+	}
+
+	client, err := initServerClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	maps, err := findStaleMaps(client, prefix, names)
+	if err != nil {
+		return err
+	}
+
+	return deleteMaps(client, maps, options, in, os.Stdout, logger)
+}
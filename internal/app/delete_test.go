@@ -0,0 +1,165 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// newFakeClientWithMaps is used to initialize a fake client with the given maps (the id of each map is its position starting at 1).
+func newFakeClientWithMaps(t *testing.T, names ...string) *fake.Client {
+	client := fake.NewClient()
+
+	for _, name := range names {
+		m := &zabbixgosdk.MapCreateParameters{}
+		m.Name = name
+
+		if _, err := client.CreateMap(m); err != nil {
+			t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+		}
+	}
+
+	return client
+}
+
+func TestFindMaps(t *testing.T) {
+	client := newFakeClientWithMaps(t, "site-paris", "42", "core")
+
+	// '42' is used as name, '3' as id
+	maps, err := findMaps(client, []string{"42", "3", "core"})
+	if err != nil {
+		t.Fatalf("error while executing findMaps function.\nReason : %v", err)
+	}
+
+	if len(maps) != 2 || maps[0].Id != "2" || maps[1].Id != "3" {
+		t.Fatalf("wrong maps returned.\nReturned : %v", maps)
+	}
+
+	if _, err = findMaps(client, []string{"unknown"}); err == nil {
+		t.Fatal("an error should be returned when a map is not found")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.NotFound {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.NotFound, kind)
+	}
+}
+
+func TestFindStaleMaps(t *testing.T) {
+	client := newFakeClientWithMaps(t, "site-paris", "site-lyon", "site-nantes", "core")
+
+	maps, err := findStaleMaps(client, "site-", []string{"site-paris"})
+	if err != nil {
+		t.Fatalf("error while executing findStaleMaps function.\nReason : %v", err)
+	}
+
+	if len(maps) != 2 || maps[0].Name != "site-lyon" || maps[1].Name != "site-nantes" {
+		t.Fatalf("wrong maps returned.\nExpected : [site-lyon site-nantes]\nReturned : %v", maps)
+	}
+
+	if _, err = findStaleMaps(client, "", nil); err == nil {
+		t.Fatal("an error should be returned when no prefix is set")
+	}
+}
+
+func TestReadKeepFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keep.txt")
+	if err := os.WriteFile(file, []byte("# sites\nsite-paris\n\n  site-lyon  \n"), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+	}
+
+	names, err := readKeepFile(file)
+	if err != nil {
+		t.Fatalf("error while executing readKeepFile function.\nReason : %v", err)
+	}
+
+	if len(names) != 2 || names[0] != "site-paris" || names[1] != "site-lyon" {
+		t.Fatalf("wrong names returned.\nExpected : [site-paris site-lyon]\nReturned : %v", names)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := map[string]bool{
+		"y\n":   true,
+		"YES\n": true,
+		"yes":   true,
+		"n\n":   false,
+		"\n":    false,
+		"":      false,
+	}
+
+	for answer, expected := range tests {
+		out := &bytes.Buffer{}
+		if confirm(strings.NewReader(answer), out, "Delete ?") != expected {
+			t.Fatalf("wrong confirmation returned for the answer '%s'.\nExpected : %t", answer, expected)
+		}
+
+		if out.String() != "Delete ? [y/N] " {
+			t.Fatalf("wrong prompt returned.\nExpected : Delete ? [y/N]\nReturned : %s", out.String())
+		}
+	}
+}
+
+func TestDeleteMaps(t *testing.T) {
+	logger := logging.NewLogger(logging.Warning)
+	client := newFakeClientWithMaps(t, "site-paris", "site-lyon")
+
+	maps, err := findMaps(client, []string{"site-paris"})
+	if err != nil {
+		t.Fatalf("error while executing findMaps function.\nReason : %v", err)
+	}
+
+	// The deletion is refused
+	out := &bytes.Buffer{}
+	if err = deleteMaps(client, maps, &Options{}, strings.NewReader("n\n"), out, logger); err == nil {
+		t.Fatal("an error should be returned when the deletion is not confirmed")
+	}
+
+	// Nothing is deleted in dry-run mode
+	out.Reset()
+	if err = deleteMaps(client, maps, &Options{DryRun: true}, strings.NewReader(""), out, logger); err != nil {
+		t.Fatalf("error while executing deleteMaps function.\nReason : %v", err)
+	}
+
+	if client.Maps[0] == nil || out.String() != "site-paris (sysmapid 1)\n" {
+		t.Fatalf("no map should be deleted in dry-run mode.\nReturned : %s", out.String())
+	}
+
+	// The deletion is confirmed
+	out.Reset()
+	if err = deleteMaps(client, maps, &Options{}, strings.NewReader("y\n"), out, logger); err != nil {
+		t.Fatalf("error while executing deleteMaps function.\nReason : %v", err)
+	}
+
+	if client.Maps[0] != nil || client.Maps[1] == nil {
+		t.Fatal("only the map 'site-paris' should be deleted")
+	}
+
+	if !strings.HasSuffix(out.String(), "1 map(s) deleted\n") {
+		t.Fatalf("wrong output returned.\nReturned : %s", out.String())
+	}
+}
+
+func TestDeleteMapsYes(t *testing.T) {
+	client := newFakeClientWithMaps(t, "site-paris")
+
+	maps, err := findMaps(client, []string{"1"})
+	if err != nil {
+		t.Fatalf("error while executing findMaps function.\nReason : %v", err)
+	}
+
+	// No confirmation is asked
+	if err = deleteMaps(client, maps, &Options{Yes: true}, strings.NewReader(""), &bytes.Buffer{}, logging.NewLogger(logging.Warning)); err != nil {
+		t.Fatalf("error while executing deleteMaps function.\nReason : %v", err)
+	}
+
+	if client.Maps[0] != nil {
+		t.Fatal("the map 'site-paris' should be deleted")
+	}
+}
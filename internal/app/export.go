@@ -0,0 +1,84 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/export"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// exportMap is used to write the mapping file and the layout file reproducing the map matching the given name or id.
+// The parts of the map that could not be exported are logged as warnings.
+func exportMap(client api.ZabbixAPI, identifier string, mappingFile string, layoutFile string, logger *logging.Logger) error {
+	maps, err := findMaps(client, []string{identifier})
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the elements and the links of the map
+	logger.Debug(fmt.Sprintf("retrieving the definition of the map '%s'", maps[0].Name))
+	definitions, err := client.GetMaps([]string{maps[0].Name})
+	if err != nil {
+		return err
+	}
+
+	if len(definitions) == 0 {
+		return failure.Errorf(failure.NotFound, "no map named '%s' was found on the server", maps[0].Name)
+	}
+
+	logger.Debug("converting the elements and the links of the map to mappings")
+	out, err := export.FromMap(client, definitions[0])
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range out.Warnings {
+		logger.Warning(warning)
+	}
+
+	if !out.Stacked {
+		logger.Warning("an host is used by more than one element, use the '--stack-hosts=false' flag to reproduce the map")
+	}
+
+	b, err := json.MarshalIndent(out.Mappings, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("writing %d mapping(s) to '%s'", len(out.Mappings), mappingFile))
+	if err = os.WriteFile(mappingFile, b, 0644); err != nil {
+		return err
+	}
+
+	if layoutFile == "" {
+		logger.Debug("'--layout' flag not used, skipping step.")
+		return nil
+	}
+
+	logger.Debug(fmt.Sprintf("writing the layout to '%s'", layoutFile))
+	return out.Layout.Write(layoutFile)
+}
+
+// RunExport is used to build a mapping file (and optionally a layout file) from an existing map matching the given name or id.
+func RunExport(identifier string, mappingFile string, layoutFile string, options *Options, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	if mappingFile == "" {
+		return failure.Errorf(failure.Config, "an output file is required to store the mappings")
+	}
+
+	client, err := initServerClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	return exportMap(client, identifier, mappingFile, layoutFile, logger)
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+func TestExportMap(t *testing.T) {
+	logger := logging.NewLogger(logging.Warning)
+	client := newFakeClient()
+	options := &Options{
+		Name:       "dc1",
+		Width:      "400",
+		Height:     "400",
+		Spacer:     50,
+		StackHosts: true,
+	}
+
+	mappings := []*zbxmap.Mapping{
+		{
+			LocalHost:            "router-1",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Firewall_(64)",
+			RemoteHost:           "router-2",
+			RemoteTriggerPattern: "Interface eth0(): Link down",
+			RemoteImage:          "Switch_(64)",
+		},
+		{
+			LocalHost:            "router-1",
+			LocalTriggerPattern:  "Interface eth1(): Link down",
+			LocalImage:           "Firewall_(64)",
+			RemoteHost:           "router-3",
+			RemoteTriggerPattern: "Interface eth1(): Link down",
+			RemoteImage:          "Switch_(64)",
+		},
+	}
+
+	m, err := buildMap(client, mappings, options, logger)
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if _, err = client.CreateMap(m); err != nil {
+		t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+	}
+
+	dir := t.TempDir()
+	mappingFile := filepath.Join(dir, "mapping.json")
+	layoutFile := filepath.Join(dir, "layout.json")
+
+	// The map is exported using its id
+	if err = exportMap(client, "1", mappingFile, layoutFile, logger); err != nil {
+		t.Fatalf("error while executing exportMap function.\nReason : %v", err)
+	}
+
+	exported, err := ReadInput(mappingFile)
+	if err != nil {
+		t.Fatalf("error while reading the exported mapping file.\nReason : %v", err)
+	}
+
+	if len(exported) != 2 || exported[1].RemoteHost != "router-3" || exported[1].LocalTriggerPattern != "Interface eth1(): Link down" || exported[1].LocalInterface != "eth1" {
+		t.Fatalf("wrong mappings exported.\nReturned : %+v", exported)
+	}
+
+	// Building the map with the exported files reproduces the original map
+	options.Name = "dc1-copy"
+	options.Layout = layoutFile
+	options.Width = "800"
+	rebuilt, err := buildMap(client, exported, options, logger)
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if rebuilt.Width != "400" || len(rebuilt.Elements) != len(m.Elements) {
+		t.Fatalf("wrong map built from the exported files.\nReturned : %+v", rebuilt)
+	}
+
+	for i, e := range rebuilt.Elements {
+		if e.X != m.Elements[i].X || e.Y != m.Elements[i].Y || e.IconIdOff != m.Elements[i].IconIdOff {
+			t.Fatalf("wrong element built from the exported files.\nExpected : %+v\nReturned : %+v", m.Elements[i], e)
+		}
+	}
+}
+
+func TestExportMapWithoutLayout(t *testing.T) {
+	client := newFakeClientWithMaps(t, "dc1")
+	file := filepath.Join(t.TempDir(), "mapping.json")
+
+	if err := exportMap(client, "dc1", file, "", logging.NewLogger(logging.Warning)); err != nil {
+		t.Fatalf("error while executing exportMap function.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", file, err)
+	}
+
+	mappings := make([]*zbxmap.Mapping, 0)
+	if err = json.Unmarshal(b, &mappings); err != nil || len(mappings) != 0 {
+		t.Fatalf("no mapping should be exported for a map without links.\nReturned : %s", string(b))
+	}
+}
+
+func TestExportMapFail(t *testing.T) {
+	client := newFakeClientWithMaps(t, "dc1")
+	file := filepath.Join(t.TempDir(), "mapping.json")
+
+	if err := exportMap(client, "unknown", file, "", logging.NewLogger(logging.Warning)); err == nil {
+		t.Fatal("an error should be returned when the map is not found")
+	}
+
+	if err := RunExport("dc1", "", "", &Options{}, nil); err == nil {
+		t.Fatal("an error should be returned when no output file is set")
+	}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxMap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// getFilterHosts is used to retrieve the host groups and the tags of the hosts referenced in the mappings, used by the filter expressions.
+// Hosts that cannot be resolved are ignored, they can only be selected using their name.
+func getFilterHosts(client api.ZabbixAPI, mappings []*zbxMap.Mapping, options *Options) (map[string]*filter.Host, error) {
+	names := make([]string, 0)
+	for _, m := range mappings {
+		for _, name := range elementNames(m, zbxMap.ElementHost, zbxMap.ElementTrigger) {
+			if !utils.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	resolveOptions, err := options.resolveOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	resolutions, err := api.ResolveHosts(client, names, resolveOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string, 0)
+	for _, name := range names {
+		ids[name] = resolutions[name].Id
+	}
+
+	metadata, err := getIconHosts(client, mappings, names, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*filter.Host, 0)
+	for name, h := range metadata {
+		host := &filter.Host{
+			Groups: h.Groups,
+			Tags:   make([]*filter.Tag, 0),
+		}
+
+		for _, t := range h.Tags {
+			host.Tags = append(host.Tags, &filter.Tag{Name: t.Name, Value: t.Value})
+		}
+
+		out[name] = host
+	}
+
+	return out, nil
+}
+
+// selectMappings is used to select the mappings matching the filter set in the options.
+// The host groups and the tags of the hosts are retrieved using the given client when used by the filter.
+func selectMappings(client api.ZabbixAPI, mappings []*zbxMap.Mapping, options *Options, logger *logging.Logger) ([]*zbxMap.Mapping, error) {
+	if options.Filter.IsEmpty() {
+		return mappings, nil
+	}
+
+	if err := options.Filter.Validate(); err != nil {
+		return nil, failure.New(failure.Config, err)
+	}
+
+	var hosts map[string]*filter.Host
+	if options.Filter.NeedsHosts() {
+		logger.Debug("retrieving the host groups and the tags of the hosts used by the filter")
+
+		var err error
+		hosts, err = getFilterHosts(client, mappings, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// An empty selection or a stub without image is an error of the mapping file and the filter combined
+	out, err := options.Filter.Apply(mappings, hosts)
+	if err != nil {
+		return nil, failure.New(failure.Validation, err)
+	}
+
+	logger.Debug(fmt.Sprintf("%d of %d mapping(s) selected by the filter", len(out), len(mappings)))
+
+	return out, nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+func TestSelectMappings(t *testing.T) {
+	client := newFakeClient().
+		AddHostGroup("1", "31", "DC1").
+		AddHostGroup("2", "31", "DC1").
+		AddHostTag("3", "site", "lyon")
+	logger := logging.NewLogger(logging.Warning)
+
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	out, err := selectMappings(client, mappings, &Options{}, logger)
+	if err != nil {
+		t.Fatalf("error while executing selectMappings function.\nReason : %v", err)
+	}
+
+	if len(out) != len(mappings) {
+		t.Fatalf("all the mappings should be returned without filter.\nExpected : %d\nReturned : %d", len(mappings), len(out))
+	}
+
+	out, err = selectMappings(client, mappings, &Options{Filter: &filter.Filter{IncludeGroups: []string{"DC1"}}}, logger)
+	if err != nil {
+		t.Fatalf("error while executing selectMappings function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 || out[0].RemoteHost != "router-2" {
+		t.Fatalf("wrong mappings returned for the group 'DC1'.\nReturned : %+v", out)
+	}
+
+	out, err = selectMappings(client, mappings, &Options{Filter: &filter.Filter{Tags: []string{"site=lyon"}, Hops: 1}}, logger)
+	if err != nil {
+		t.Fatalf("error while executing selectMappings function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 || out[0].RemoteHost != "router-3" {
+		t.Fatalf("wrong mappings returned for the tag 'site=lyon'.\nReturned : %+v", out)
+	}
+}
+
+// hostGroupsClient is used to make sure the host groups are retrieved with the hosts instead of one call per host.
+type hostGroupsClient struct {
+	*fake.Client
+}
+
+// GetHostGroups is used to fail when the host groups of an host are retrieved individually.
+func (c *hostGroupsClient) GetHostGroups(hostId string) ([]*api.HostGroup, error) {
+	return nil, fmt.Errorf("host groups of host '%s' retrieved individually", hostId)
+}
+
+func TestSelectMappingsHostGroups(t *testing.T) {
+	client := &hostGroupsClient{
+		Client: newFakeClient().AddHostGroup("1", "31", "DC1").AddHostGroup("2", "31", "DC1"),
+	}
+
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	out, err := selectMappings(client, mappings, &Options{Filter: &filter.Filter{IncludeGroups: []string{"DC1"}}}, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing selectMappings function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 || out[0].RemoteHost != "router-2" {
+		t.Fatalf("wrong mappings returned for the group 'DC1'.\nReturned : %+v", out)
+	}
+}
+
+func TestSelectMappingsFail(t *testing.T) {
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	options := &Options{
+		Filter: &filter.Filter{IncludeGroups: []string{"DC2"}},
+	}
+
+	if _, err = selectMappings(newFakeClient(), mappings, options, logging.NewLogger(logging.Warning)); err == nil {
+		t.Fatalf("an error should be returned when no mapping matches the filter")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+
+	// An invalid filter is a configuration error
+	options.Filter = &filter.Filter{IncludeHosts: []string{"[ab"}}
+	if _, err = selectMappings(newFakeClient(), mappings, options, logging.NewLogger(logging.Warning)); err == nil {
+		t.Fatalf("an error should be returned when a pattern of the filter is invalid")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Config {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Config, kind)
+	}
+}
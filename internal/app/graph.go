@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/export"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// RunGraph is used to export the topology described in the given mapping file to a graph format (DOT, Mermaid, GraphML).
+func RunGraph(file string, options *Options, graphOptions *GraphOptions, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	// Retrieve the list of hosts mappings for the input file
+	logger.Debug(fmt.Sprintf("reading input file '%s'", file))
+	mappings, err := ReadInputFormat(file, options.Format)
+	if err != nil {
+		return err
+	}
+
+	mappings = normalizeMappings(mappings, logger)
+
+	// Initialize an api client.
+	client, err := initClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	resolveOptions, err := options.resolveOptions()
+	if err != nil {
+		return err
+	}
+
+	return writeGraph(client, mappings, resolveOptions, graphOptions, logger)
+}
+
+// writeGraph is used to resolve the topology described by the given mappings and write it in the requested format.
+func writeGraph(client api.ZabbixAPI, mappings []*zbxmap.Mapping, resolveOptions *api.ResolveOptions, options *GraphOptions, logger *logging.Logger) error {
+	logger.Debug("retrieving hosts, host groups and triggers information from the server")
+	topology, err := export.NewTopology(client, options.Name, mappings, resolveOptions)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("converting the topology to the '%s' format", options.Format))
+	b, err := export.Format(topology, options.Format)
+	if err != nil {
+		return err
+	}
+
+	if options.OutFile == "" {
+		logger.Debug("outputting the graph to the shell")
+		fmt.Print(string(b))
+
+		return nil
+	}
+
+	logger.Debug(fmt.Sprintf("outputting the graph to '%s'", options.OutFile))
+	return os.WriteFile(options.OutFile, b, 0644)
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+func TestWriteGraph(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "topology.dot")
+
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	client := newFakeClient().AddHostGroup("1", "41", "Routers")
+
+	err = writeGraph(client, mappings, nil, &GraphOptions{
+		Name:    "test-topology",
+		Format:  "dot",
+		OutFile: file,
+	}, logging.NewLogger(logging.Warning))
+
+	if err != nil {
+		t.Fatalf("error while executing writeGraph function.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", file, err)
+	}
+
+	if !strings.Contains(string(b), "label=\"Routers\";") {
+		t.Fatalf("the host group was not drawn as a cluster.\nReturned : %s", string(b))
+	}
+}
+
+func TestWriteGraphUnsupportedFormat(t *testing.T) {
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	err = writeGraph(newFakeClient(), mappings, nil, &GraphOptions{
+		Format: "svg",
+	}, logging.NewLogger(logging.Warning))
+
+	if err == nil {
+		t.Fatal("an error should be returned when the format is not supported")
+	}
+}
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/icon"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxMap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// iconRules is used to load the icon rules set in the options, nil is returned if no rules file was set.
+func (o *Options) iconRules() (*icon.Rules, error) {
+	if o.IconRules == "" {
+		return nil, nil
+	}
+
+	rules, err := icon.Load(o.IconRules)
+	if err != nil {
+		return nil, failure.New(failure.Config, err)
+	}
+
+	return rules, nil
+}
+
+// hostsWithoutImage is used to retrieve the hosts for which no image was set in at least one mapping.
+func hostsWithoutImage(mappings []*zbxMap.Mapping) []string {
+	out := make([]string, 0)
+
+	for _, m := range mappings {
+		if m.LocalImage == "" && !utils.Contains(out, m.LocalHost) {
+			out = append(out, m.LocalHost)
+		}
+
+		if m.RemoteImage == "" && !utils.Contains(out, m.RemoteHost) {
+			out = append(out, m.RemoteHost)
+		}
+	}
+
+	return out
+}
+
+// hostCapabilities is used to retrieve the CDP capabilities of the given host from the first mapping setting them.
+func hostCapabilities(mappings []*zbxMap.Mapping, host string) ([]string, error) {
+	for _, m := range mappings {
+		value := ""
+		if m.LocalHost == host && m.LocalCapabilities != "" {
+			value = m.LocalCapabilities
+		} else if m.RemoteHost == host && m.RemoteCapabilities != "" {
+			value = m.RemoteCapabilities
+		}
+
+		if value != "" {
+			c, err := icon.ParseCapabilities(value)
+			if err != nil {
+				return nil, failure.Errorf(failure.Validation, "host '%s' : %v", host, err)
+			}
+
+			return c, nil
+		}
+	}
+
+	return make([]string, 0), nil
+}
+
+// getIconHosts is used to retrieve the metadata (templates, groups, inventory type, tags and CDP capabilities) of the given hosts.
+// The hosts map associate each name used in the mappings to its hostid, hosts without id only use the capabilities of the mappings.
+func getIconHosts(client api.ZabbixAPI, mappings []*zbxMap.Mapping, names []string, hosts map[string]string) (map[string]*icon.Host, error) {
+	ids := make([]string, 0)
+	for _, name := range names {
+		if id := hosts[name]; id != "" && !utils.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	metadata := make(map[string]*api.Host, 0)
+	if len(ids) > 0 {
+		h, err := client.GetHostsById(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, host := range h {
+			metadata[host.Id] = host
+		}
+	}
+
+	out := make(map[string]*icon.Host, 0)
+	for _, name := range names {
+		capabilities, err := hostCapabilities(mappings, name)
+		if err != nil {
+			return nil, err
+		}
+
+		h := &icon.Host{
+			Name:         name,
+			Templates:    make([]string, 0),
+			Groups:       make([]string, 0),
+			Tags:         make([]*icon.Tag, 0),
+			Capabilities: capabilities,
+		}
+
+		if m, exist := metadata[hosts[name]]; exist {
+			for _, t := range m.Templates {
+				h.Templates = append(h.Templates, t.Name)
+			}
+
+			for _, t := range m.Tags {
+				h.Tags = append(h.Tags, &icon.Tag{Name: t.Tag, Value: t.Value})
+			}
+
+			if m.Inventory != nil {
+				h.InventoryType = m.Inventory.Type
+			}
+
+			for _, g := range m.HostGroups {
+				h.Groups = append(h.Groups, g.Name)
+			}
+		}
+
+		out[name] = h
+	}
+
+	return out, nil
+}
+
+// applyIconRules is used to set the image of the hosts for which no image was set in the mappings using the given icon rules.
+// The given mappings are not modified, the mappings returned are copies using the selected images.
+// The returned map associate each host to the images used for each state of its element.
+func applyIconRules(client api.ZabbixAPI, mappings []*zbxMap.Mapping, hosts map[string]string, rules *icon.Rules, logger *logging.Logger) ([]*zbxMap.Mapping, map[string]*zbxMap.ElementIcons, error) {
+	out := make(map[string]*zbxMap.ElementIcons, 0)
+
+	names := hostsWithoutImage(mappings)
+	if len(names) == 0 {
+		return mappings, out, nil
+	}
+
+	if rules == nil {
+		return nil, nil, failure.Errorf(failure.Validation, "no image was set for the host '%s', set the 'local_image' or 'remote_image' field or use icon rules ('--icon-rules' flag)", names[0])
+	}
+
+	metadata, err := getIconHosts(client, mappings, names, hosts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range names {
+		rule := rules.Match(metadata[name])
+		if rule == nil {
+			return nil, nil, failure.Errorf(failure.Validation, "no image was set for the host '%s' and no icon rule match the host", name)
+		}
+
+		logger.Info(fmt.Sprintf("image '%s' selected for the host '%s' using the icon rules", rule.Image, name))
+
+		out[name] = &zbxMap.ElementIcons{
+			Image:       rule.Image,
+			Problem:     rule.ImageProblem,
+			Maintenance: rule.ImageMaintenance,
+			Disabled:    rule.ImageDisabled,
+		}
+	}
+
+	selected := make([]*zbxMap.Mapping, 0, len(mappings))
+	for _, m := range mappings {
+		mapping := *m
+		if mapping.LocalImage == "" {
+			mapping.LocalImage = out[mapping.LocalHost].Image
+		}
+
+		if mapping.RemoteImage == "" {
+			mapping.RemoteImage = out[mapping.RemoteHost].Image
+		}
+
+		selected = append(selected, &mapping)
+	}
+
+	return selected, out, nil
+}
+
+// iconImages is used to retrieve the names of the images used for the problem, maintenance and disabled states of the elements.
+func iconImages(icons map[string]*zbxMap.ElementIcons) []string {
+	out := make([]string, 0)
+
+	for _, i := range icons {
+		for _, image := range []string{i.Problem, i.Maintenance, i.Disabled} {
+			if image != "" && !utils.Contains(out, image) {
+				out = append(out, image)
+			}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/icon"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxMap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// newIconRules is used to initialize the rules used during the tests.
+func newIconRules() *icon.Rules {
+	return &icon.Rules{
+		Rules: []*icon.Rule{
+			{Template: "Cisco IOS*", Image: "Firewall_(64)", ImageProblem: "Firewall_(64)_problem"},
+			{Group: "Switches", Image: "Switch_(64)"},
+			{Capabilities: "S", Image: "Switch_(64)"},
+		},
+	}
+}
+
+func TestApplyIconRules(t *testing.T) {
+	client := newFakeClient().
+		AddHostTemplate("1", "Cisco IOS by SNMP").
+		AddHostGroup("2", "31", "Switches").
+		AddImage("13", "Firewall_(64)_problem")
+
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "router-1",
+			RemoteHost: "router-2",
+		},
+		{
+			LocalHost:          "router-1",
+			RemoteHost:         "router-3",
+			RemoteImage:        "",
+			RemoteCapabilities: "00 00 00 28",
+		},
+	}
+
+	hosts := map[string]string{"router-1": "1", "router-2": "2", "router-3": "3"}
+
+	out, icons, err := applyIconRules(client, m, hosts, newIconRules(), logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing applyIconRules function.\nReason : %v", err)
+	}
+
+	if out[0].LocalImage != "Firewall_(64)" || out[0].RemoteImage != "Switch_(64)" || out[1].RemoteImage != "Switch_(64)" {
+		t.Fatalf("wrong images set.\nExpected : Firewall_(64), Switch_(64), Switch_(64)\nReturned : %s, %s, %s", out[0].LocalImage, out[0].RemoteImage, out[1].RemoteImage)
+	}
+
+	if m[0].LocalImage != "" || m[0].RemoteImage != "" || m[1].RemoteImage != "" {
+		t.Fatalf("the given mappings should not be modified.\nReturned : %s, %s, %s", m[0].LocalImage, m[0].RemoteImage, m[1].RemoteImage)
+	}
+
+	if icons["router-1"].Problem != "Firewall_(64)_problem" {
+		t.Fatalf("wrong problem image returned.\nExpected : Firewall_(64)_problem\nReturned : %s", icons["router-1"].Problem)
+	}
+
+	images := iconImages(icons)
+	if len(images) != 1 || images[0] != "Firewall_(64)_problem" {
+		t.Fatalf("wrong extra images returned.\nExpected : [Firewall_(64)_problem]\nReturned : %v", images)
+	}
+}
+
+func TestApplyIconRulesKeepImage(t *testing.T) {
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:   "router-1",
+			LocalImage:  "Switch_(64)",
+			RemoteHost:  "router-2",
+			RemoteImage: "Switch_(64)",
+		},
+	}
+
+	out, icons, err := applyIconRules(newFakeClient(), m, map[string]string{}, nil, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing applyIconRules function.\nReason : %v", err)
+	}
+
+	if len(icons) != 0 || out[0].LocalImage != "Switch_(64)" {
+		t.Fatalf("the images set in the mappings should be kept.\nReturned : %v, %s", icons, out[0].LocalImage)
+	}
+}
+
+func TestApplyIconRulesFail(t *testing.T) {
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:   "router-1",
+			LocalImage:  "Switch_(64)",
+			RemoteHost:  "router-3",
+			RemoteImage: "",
+		},
+	}
+
+	hosts := map[string]string{"router-1": "1", "router-3": "3"}
+
+	_, _, err := applyIconRules(newFakeClient(), m, hosts, nil, logging.NewLogger(logging.Warning))
+	if err == nil {
+		t.Fatalf("an error should be returned when an image is missing and no rules are set")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+
+	_, _, err = applyIconRules(newFakeClient(), m, hosts, newIconRules(), logging.NewLogger(logging.Warning))
+	if err == nil {
+		t.Fatalf("an error should be returned when no rule match an host without image")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+}
+
+func TestBuildMapIconRules(t *testing.T) {
+	rules := filepath.Join(t.TempDir(), "icons.yaml")
+	content := "rules:\n  - template: \"Cisco IOS*\"\n    image: Firewall_(64)\n    image_problem: Firewall_(64)_problem\n  - image: Switch_(64)\n"
+	if err := os.WriteFile(rules, []byte(content), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", rules, err)
+	}
+
+	client := newFakeClient().
+		AddHostTemplate("1", "Cisco IOS by SNMP").
+		AddImage("13", "Firewall_(64)_problem")
+
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:            "router-1",
+			LocalInterface:       "eth0",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			RemoteHost:           "router-2",
+			RemoteInterface:      "eth0",
+			RemoteTriggerPattern: "Interface eth0(): Link down",
+		},
+	}
+
+	opts := &Options{
+		Name:      "test-map-builder",
+		Width:     "400",
+		Height:    "400",
+		IconRules: rules,
+	}
+
+	out, err := buildMap(client, m, opts, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if out.Elements[0].IconIdOff != "11" || out.Elements[0].IconIdOn != "13" {
+		t.Fatalf("wrong images set for the first element.\nExpected : '11' / '13'\nReturned : '%s' / '%s'", out.Elements[0].IconIdOff, out.Elements[0].IconIdOn)
+	}
+
+	if out.Elements[1].IconIdOff != "12" || out.Elements[1].IconIdOn != "" {
+		t.Fatalf("wrong images set for the second element.\nExpected : '12' / ''\nReturned : '%s' / '%s'", out.Elements[1].IconIdOff, out.Elements[1].IconIdOn)
+	}
+}
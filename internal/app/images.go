@@ -0,0 +1,101 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/icon"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// syncImages is used to upload the images of the given directory to the server.
+// When names are given, only the images matching one of the names are synchronized.
+func syncImages(client api.ZabbixAPI, dir string, names []string, dryRun bool, logger *logging.Logger) ([]*icon.SyncResult, error) {
+	logger.Debug(fmt.Sprintf("reading images from the directory '%s'", dir))
+	files, err := icon.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]*icon.LocalImage, 0)
+	for _, f := range files {
+		if len(names) == 0 || utils.Contains(names, f.Name) {
+			images = append(images, f)
+		}
+	}
+
+	results, err := icon.Sync(client, images, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range results {
+		if r.Action != icon.ActionNone {
+			logger.Info(fmt.Sprintf("image '%s' %s", r.Name, r.Action))
+		}
+	}
+
+	return results, nil
+}
+
+// uploadMapImages is used to upload the images referenced by the map from the directory set in the options.
+// The ids of the images created or updated are set in the given map ('image' -> 'imageid').
+func uploadMapImages(client api.ZabbixAPI, images map[string]string, options *Options, logger *logging.Logger) error {
+	if options.Snapshot != "" {
+		logger.Warning("images cannot be uploaded when using a snapshot, the '--images-dir' flag is ignored")
+		return nil
+	}
+
+	names := make([]string, 0)
+	for name := range images {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	results, err := syncImages(client, options.ImagesDir, names, options.DryRun, logger)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Id != "" {
+			images[r.Name] = r.Id
+		}
+	}
+
+	return nil
+}
+
+// RunImagesSync is used to upload the images of the given directory to the server.
+// Missing images are created and images whose content changed are updated, the action executed for each image is output to the shell.
+func RunImagesSync(dir string, options *Options, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	// Initialize an api client.
+	client, err := initServerClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	results, err := syncImages(client, dir, nil, options.DryRun, logger)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Id == "" {
+			fmt.Printf("%s : %s\n", r.Name, r.Action)
+			continue
+		}
+
+		fmt.Printf("%s : %s (imageid %s)\n", r.Name, r.Action, r.Id)
+	}
+
+	return err
+}
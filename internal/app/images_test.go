@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// writeTestingImages is used to write the given images to a temporary directory.
+func writeTestingImages(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+		}
+	}
+
+	return dir
+}
+
+func TestUploadMapImages(t *testing.T) {
+	dir := writeTestingImages(t, map[string]string{
+		"Router_(64).png": "router",
+		"Switch_(64).png": "switch",
+		"Cloud_(24).png":  "cloud",
+	})
+
+	client := newFakeClient()
+	images := map[string]string{
+		"Router_(64)": "",
+		"Switch_(64)": "12",
+	}
+
+	err := uploadMapImages(client, images, &Options{ImagesDir: dir}, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing uploadMapImages function.\nReason : %v", err)
+	}
+
+	if images["Router_(64)"] != "13" || images["Switch_(64)"] != "12" {
+		t.Fatalf("wrong imageid returned.\nExpected : map[Router_(64):13 Switch_(64):12]\nReturned : %v", images)
+	}
+
+	// Only the images referenced by the map are uploaded
+	if len(client.Images) != 3 {
+		t.Fatalf("wrong number of images stored.\nExpected : 3\nReturned : %d", len(client.Images))
+	}
+
+	if client.Images[1].Data != base64.StdEncoding.EncodeToString([]byte("switch")) {
+		t.Fatalf("the content of the image 'Switch_(64)' was not updated.\nReturned : %s", client.Images[1].Data)
+	}
+}
+
+func TestUploadMapImagesSnapshot(t *testing.T) {
+	client := newFakeClient()
+	images := map[string]string{
+		"Router_(64)": "",
+	}
+
+	err := uploadMapImages(client, images, &Options{ImagesDir: "missing", Snapshot: "snapshot.json"}, logging.NewLogger(logging.Error))
+	if err != nil {
+		t.Fatalf("error while executing uploadMapImages function.\nReason : %v", err)
+	}
+
+	if len(client.Images) != 2 {
+		t.Fatalf("no image should be uploaded when using a snapshot.\nReturned : %d", len(client.Images))
+	}
+}
+
+func TestBuildMapImagesDir(t *testing.T) {
+	dir := writeTestingImages(t, map[string]string{
+		"Router_(64).png": "router",
+	})
+
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	for _, m := range mappings {
+		m.RemoteImage = "Router_(64)"
+	}
+
+	opts := &Options{
+		Name:      "test-map-builder",
+		Width:     "400",
+		Height:    "400",
+		ImagesDir: dir,
+	}
+
+	m, err := buildMap(newFakeClient(), mappings, opts, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	if m.Elements[1].IconIdOff != "13" {
+		t.Fatalf("the id of the uploaded image was not used.\nExpected : 13\nReturned : %s", m.Elements[1].IconIdOff)
+	}
+}
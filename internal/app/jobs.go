@@ -0,0 +1,206 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+const (
+	// JobQueued is the status of a job waiting for a worker.
+	JobQueued = "queued"
+	// JobRunning is the status of a job being executed.
+	JobRunning = "running"
+	// JobSucceeded is the status of a job whose map was built.
+	JobSucceeded = "succeeded"
+	// JobFailed is the status of a job whose map could not be built.
+	JobFailed = "failed"
+)
+
+// Job define a build request submitted to the HTTP API.
+type Job struct {
+	Id         string     `json:"id"`
+	Map        string     `json:"map"`
+	DryRun     bool       `json:"dry_run"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// file is the mapping document of the request, options the options used to build the map.
+	file    string
+	options *Options
+	preview *Preview
+}
+
+// Preview define the map built by a dry-run job, as a create request and as a SVG image.
+type Preview struct {
+	Map *zabbixgosdk.MapCreateParameters
+	SVG []byte
+}
+
+// errQueueFull is returned when a job is submitted while the queue is full.
+var errQueueFull = errors.New("the job queue is full, retry later")
+
+// errQueueClosed is returned when a job is submitted once the queue is closed (the server is shutting down).
+var errQueueClosed = errors.New("the job queue is closed, the server is shutting down")
+
+// jobQueue is used to execute the jobs with a bounded number of workers.
+// The finished jobs are kept for the retention delay to allow their status to be polled.
+type jobQueue struct {
+	mutex     sync.Mutex
+	jobs      map[string]*Job
+	pending   chan *Job
+	retention time.Duration
+	// closed is set once the queue stops accepting jobs, the pending channel must not be used anymore.
+	closed bool
+	// run is used to execute a job, the preview is kept for the dry-run jobs.
+	run func(j *Job) (*Preview, error)
+	wg  sync.WaitGroup
+}
+
+// newJobQueue is used to create a queue holding up to the given number of pending jobs, executed by the given number of workers.
+func newJobQueue(size int, workers int, retention time.Duration, run func(j *Job) (*Preview, error)) *jobQueue {
+	q := &jobQueue{
+		jobs:      make(map[string]*Job, 0),
+		pending:   make(chan *Job, size),
+		retention: retention,
+		run:       run,
+	}
+
+	for w := 0; w < workers; w++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+// newJobId is used to generate a random job id.
+func newJobId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// submit is used to add the given job to the queue, errQueueFull is returned if the queue is full and errQueueClosed if the queue is closed.
+func (q *jobQueue) submit(j *Job) error {
+	id, err := newJobId()
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return errQueueClosed
+	}
+
+	q.purge()
+
+	j.Id = id
+	j.Status = JobQueued
+	j.CreatedAt = time.Now()
+
+	select {
+	case q.pending <- j:
+		q.jobs[j.Id] = j
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+// purge is used to remove the finished jobs older than the retention delay, the mutex must be held by the caller.
+// The jobs are purged each time the queue is used, no job older than the retention delay can be returned.
+func (q *jobQueue) purge() {
+	for key, job := range q.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > q.retention {
+			delete(q.jobs, key)
+		}
+	}
+}
+
+// get is used to retrieve a copy of the job with the given id.
+func (q *jobQueue) get(id string) (*Job, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.purge()
+
+	j, exist := q.jobs[id]
+	if !exist {
+		return nil, false
+	}
+
+	out := *j
+	return &out, true
+}
+
+// counts is used to retrieve the number of jobs of each status.
+func (q *jobQueue) counts() map[string]int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.purge()
+
+	out := map[string]int{
+		JobQueued:    0,
+		JobRunning:   0,
+		JobSucceeded: 0,
+		JobFailed:    0,
+	}
+
+	for _, j := range q.jobs {
+		out[j.Status]++
+	}
+
+	return out
+}
+
+// work is used to execute the pending jobs until the queue is closed.
+func (q *jobQueue) work() {
+	defer q.wg.Done()
+
+	for j := range q.pending {
+		q.mutex.Lock()
+		start := time.Now()
+		j.Status = JobRunning
+		j.StartedAt = &start
+		q.mutex.Unlock()
+
+		preview, err := q.run(j)
+
+		q.mutex.Lock()
+		end := time.Now()
+		j.FinishedAt = &end
+		j.Status = JobSucceeded
+		j.preview = preview
+		if err != nil {
+			j.Status = JobFailed
+			j.Error = err.Error()
+		}
+		q.mutex.Unlock()
+	}
+}
+
+// close is used to stop accepting jobs and to wait for the pending jobs to be executed.
+// The channel is closed while holding the mutex, a job submitted concurrently is rejected instead of being sent to a closed channel.
+func (q *jobQueue) close() {
+	q.mutex.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.pending)
+	}
+	q.mutex.Unlock()
+
+	q.wg.Wait()
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// waitForJob is used to wait until the job with the given id is finished.
+func waitForJob(t *testing.T, q *jobQueue, id string) *Job {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		j, exist := q.get(id)
+		if !exist {
+			t.Fatalf("no job found with the id '%s'", id)
+		}
+
+		if j.FinishedAt != nil {
+			return j
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("the job '%s' was not finished in time", id)
+	return nil
+}
+
+func TestJobQueue(t *testing.T) {
+	q := newJobQueue(10, 2, time.Hour, func(j *Job) (*Preview, error) {
+		if j.Map == "fail" {
+			return nil, fmt.Errorf("no host found for 'router-4'")
+		}
+
+		return nil, nil
+	})
+
+	ok := &Job{Map: "ok"}
+	fail := &Job{Map: "fail"}
+	for _, j := range []*Job{ok, fail} {
+		if err := q.submit(j); err != nil {
+			t.Fatalf("error while executing submit function.\nReason : %v", err)
+		}
+	}
+
+	if ok.Id == "" || ok.Id == fail.Id {
+		t.Fatalf("a unique id should be set for each job.\nReturned : '%s' and '%s'", ok.Id, fail.Id)
+	}
+
+	if j := waitForJob(t, q, ok.Id); j.Status != JobSucceeded || j.StartedAt == nil {
+		t.Fatalf("wrong status returned for the job 'ok'.\nExpected : %s\nReturned : %s", JobSucceeded, j.Status)
+	}
+
+	if j := waitForJob(t, q, fail.Id); j.Status != JobFailed || j.Error != "no host found for 'router-4'" {
+		t.Fatalf("wrong status returned for the job 'fail'.\nExpected : %s\nReturned : %s (%s)", JobFailed, j.Status, j.Error)
+	}
+
+	if counts := q.counts(); counts[JobSucceeded] != 1 || counts[JobFailed] != 1 {
+		t.Fatalf("wrong counts returned.\nReturned : %v", counts)
+	}
+
+	if _, exist := q.get("unknown"); exist {
+		t.Fatalf("no job should be returned for an unknown id")
+	}
+
+	q.close()
+}
+
+func TestJobQueueFull(t *testing.T) {
+	release := make(chan struct{})
+	q := newJobQueue(1, 1, time.Hour, func(j *Job) (*Preview, error) {
+		<-release
+		return nil, nil
+	})
+
+	// The first job is executed by the worker, the second job fills the queue
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = q.submit(&Job{Map: fmt.Sprintf("map-%d", i)})
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if !errors.Is(err, errQueueFull) {
+		t.Fatalf("an error should be returned when the queue is full.\nExpected : %v\nReturned : %v", errQueueFull, err)
+	}
+
+	close(release)
+	q.close()
+}
+
+func TestJobQueueRetention(t *testing.T) {
+	q := newJobQueue(10, 1, time.Minute, func(j *Job) (*Preview, error) {
+		return nil, nil
+	})
+
+	// addExpiredJob is used to add a job finished before the retention delay
+	addExpiredJob := func(id string) {
+		finished := time.Now().Add(-time.Hour)
+		q.mutex.Lock()
+		q.jobs[id] = &Job{Id: id, Status: JobSucceeded, FinishedAt: &finished}
+		q.mutex.Unlock()
+	}
+
+	addExpiredJob("first")
+	if _, exist := q.get("first"); exist {
+		t.Fatalf("the finished jobs older than the retention delay should be removed when retrieving a job")
+	}
+
+	addExpiredJob("second")
+	if counts := q.counts(); counts[JobSucceeded] != 0 {
+		t.Fatalf("the finished jobs older than the retention delay should be removed when counting the jobs.\nReturned : %v", counts)
+	}
+
+	addExpiredJob("third")
+	if err := q.submit(&Job{Map: "fourth"}); err != nil {
+		t.Fatalf("error while executing submit function.\nReason : %v", err)
+	}
+
+	q.mutex.Lock()
+	_, exist := q.jobs["third"]
+	q.mutex.Unlock()
+
+	if exist {
+		t.Fatalf("the finished jobs older than the retention delay should be removed when submitting a job")
+	}
+
+	q.close()
+}
+
+func TestJobQueueClosed(t *testing.T) {
+	q := newJobQueue(10, 1, time.Hour, func(j *Job) (*Preview, error) {
+		return nil, nil
+	})
+
+	q.close()
+
+	if err := q.submit(&Job{Map: "late"}); !errors.Is(err, errQueueClosed) {
+		t.Fatalf("an error should be returned when the queue is closed.\nExpected : %v\nReturned : %v", errQueueClosed, err)
+	}
+
+	// Closing the queue again has no effect
+	q.close()
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest define the maps built by the build-all command.
+type Manifest struct {
+	// Workers is the number of maps built concurrently, 4 is used if not set.
+	Workers int `yaml:"workers,omitempty"`
+	// Defaults contains the options used by the maps that do not set them.
+	Defaults *ManifestMap   `yaml:"defaults,omitempty"`
+	Maps     []*ManifestMap `yaml:"maps"`
+}
+
+// ManifestMap define the mapping file, the filter, the layout and the style of a map built by the build-all command.
+// Relative paths are resolved from the directory of the manifest.
+type ManifestMap struct {
+	Name   string         `yaml:"name,omitempty"`
+	File   string         `yaml:"file,omitempty"`
+	Format string         `yaml:"format,omitempty"`
+	Filter *filter.Filter `yaml:"filter,omitempty"`
+	Layout string         `yaml:"layout,omitempty"`
+	// Output is a file used to store the parameters used to create the map.
+	Output        string   `yaml:"output,omitempty"`
+	Color         string   `yaml:"color,omitempty"`
+	TriggerColor  string   `yaml:"trigger_color,omitempty"`
+	Width         string   `yaml:"width,omitempty"`
+	Height        string   `yaml:"height,omitempty"`
+	Spacer        int64    `yaml:"spacer,omitempty"`
+	StackHosts    *bool    `yaml:"stack_hosts,omitempty"`
+	Label         string   `yaml:"label,omitempty"`
+	LabelLocation string   `yaml:"label_location,omitempty"`
+	Urls          []string `yaml:"urls,omitempty"`
+	IconRules     string   `yaml:"icon_rules,omitempty"`
+	Sharing       string   `yaml:"sharing,omitempty"`
+}
+
+// defaultWorkers is the number of maps built concurrently if not set in the manifest or with the '--workers' flag.
+const defaultWorkers = 4
+
+// LoadManifest is used to read a manifest from the given file (YAML or JSON).
+// The defaults are applied to each map and the relative paths are resolved from the directory of the manifest.
+func LoadManifest(file string) (*Manifest, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, failure.New(failure.Config, err)
+	}
+
+	m := &Manifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(m); err != nil {
+		return nil, failure.Errorf(failure.Config, "error while reading the manifest '%s'.\nReason : %v", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	for _, entry := range m.Maps {
+		entry.applyDefaults(m.Defaults)
+		entry.resolvePaths(dir)
+	}
+
+	if err = m.Validate(); err != nil {
+		return nil, failure.Errorf(failure.Config, "error while reading the manifest '%s'.\nReason : %v", file, err)
+	}
+
+	return m, nil
+}
+
+// Validate is used to validate the maps of the manifest.
+func (m *Manifest) Validate() error {
+	if len(m.Maps) == 0 {
+		return fmt.Errorf("no maps were found in the manifest")
+	}
+
+	if m.Workers < 0 {
+		return fmt.Errorf("the number of workers cannot be negative")
+	}
+
+	names := make(map[string]bool, 0)
+	for i, entry := range m.Maps {
+		if entry.Name == "" {
+			return fmt.Errorf("a name is required for the map at the position %d", i+1)
+		}
+
+		if names[entry.Name] {
+			return fmt.Errorf("the map '%s' is defined more than once", entry.Name)
+		}
+
+		names[entry.Name] = true
+
+		if entry.File == "" {
+			return fmt.Errorf("a mapping file is required for the map '%s'", entry.Name)
+		}
+
+		if err := entry.Filter.Validate(); err != nil {
+			return fmt.Errorf("invalid filter for the map '%s'.\nReason : %v", entry.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyDefaults is used to set the options not set for the map using the given defaults.
+func (e *ManifestMap) applyDefaults(d *ManifestMap) {
+	if d == nil {
+		return
+	}
+
+	fields := []struct {
+		value    *string
+		fallback string
+	}{
+		{&e.File, d.File},
+		{&e.Format, d.Format},
+		{&e.Layout, d.Layout},
+		{&e.Color, d.Color},
+		{&e.TriggerColor, d.TriggerColor},
+		{&e.Width, d.Width},
+		{&e.Height, d.Height},
+		{&e.Label, d.Label},
+		{&e.LabelLocation, d.LabelLocation},
+		{&e.IconRules, d.IconRules},
+		{&e.Sharing, d.Sharing},
+	}
+
+	for _, f := range fields {
+		if *f.value == "" {
+			*f.value = f.fallback
+		}
+	}
+
+	if e.Filter == nil {
+		e.Filter = d.Filter
+	}
+
+	if e.Spacer == 0 {
+		e.Spacer = d.Spacer
+	}
+
+	if e.StackHosts == nil {
+		e.StackHosts = d.StackHosts
+	}
+
+	if e.Urls == nil {
+		e.Urls = d.Urls
+	}
+}
+
+// resolvePaths is used to resolve the relative paths of the map from the given directory.
+func (e *ManifestMap) resolvePaths(dir string) {
+	for _, p := range []*string{&e.File, &e.Layout, &e.Output, &e.IconRules, &e.Sharing} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+}
+
+// options is used to build the options of the map from the given base options (Zabbix server, host lookup, dry-run, etc.).
+// The default style of the CLI is used for the options not set in the manifest.
+func (e *ManifestMap) options(base *Options) *Options {
+	o := *base
+	o.Name = e.Name
+	o.Format = e.Format
+	o.Filter = e.Filter
+	o.Layout = e.Layout
+	o.OutFile = e.Output
+	o.Color = valueOrDefault(e.Color, "000000")
+	o.TriggerColor = valueOrDefault(e.TriggerColor, "DD0000")
+	o.Width = valueOrDefault(e.Width, "800")
+	o.Height = valueOrDefault(e.Height, "800")
+	o.Spacer = e.Spacer
+	o.LabelTemplate = e.Label
+	o.LabelLocation = e.LabelLocation
+	o.Urls = e.Urls
+	o.IconRules = e.IconRules
+	o.SharingFile = e.Sharing
+
+	if o.Spacer == 0 {
+		o.Spacer = 100
+	}
+
+	o.StackHosts = true
+	if e.StackHosts != nil {
+		o.StackHosts = *e.StackHosts
+	}
+
+	return &o
+}
+
+// valueOrDefault is used to retrieve the given value, or the default value if empty.
+func valueOrDefault(value string, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+)
+
+// writeManifest is used to write the given content to a manifest file in a temporary directory.
+func writeManifest(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	return file
+}
+
+func TestLoadManifest(t *testing.T) {
+	file := writeManifest(t, `
+workers: 2
+defaults:
+  file: mapping.json
+  color: 00AA00
+  stack_hosts: false
+maps:
+  - name: dc1
+    filter:
+      include_hosts: ["dc1-*"]
+    layout: layouts/dc1.json
+  - name: dc2
+    file: /data/dc2.yaml
+    color: 0000AA
+`)
+
+	m, err := LoadManifest(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadManifest function.\nReason : %v", err)
+	}
+
+	if m.Workers != 2 || len(m.Maps) != 2 {
+		t.Fatalf("wrong manifest returned.\nReturned : %+v", m)
+	}
+
+	dir := filepath.Dir(file)
+	dc1 := m.Maps[0]
+	if dc1.File != filepath.Join(dir, "mapping.json") {
+		t.Fatalf("wrong mapping file returned for the map 'dc1'.\nExpected : %s\nReturned : %s", filepath.Join(dir, "mapping.json"), dc1.File)
+	}
+
+	if dc1.Layout != filepath.Join(dir, "layouts", "dc1.json") {
+		t.Fatalf("wrong layout returned for the map 'dc1'.\nExpected : %s\nReturned : %s", filepath.Join(dir, "layouts", "dc1.json"), dc1.Layout)
+	}
+
+	if dc1.Color != "00AA00" || dc1.StackHosts == nil || *dc1.StackHosts {
+		t.Fatalf("the defaults should be applied to the map 'dc1'.\nReturned : %+v", dc1)
+	}
+
+	if dc1.Filter.IsEmpty() || dc1.Filter.IncludeHosts[0] != "dc1-*" {
+		t.Fatalf("wrong filter returned for the map 'dc1'.\nReturned : %+v", dc1.Filter)
+	}
+
+	dc2 := m.Maps[1]
+	if dc2.File != "/data/dc2.yaml" || dc2.Color != "0000AA" {
+		t.Fatalf("the options set for the map 'dc2' should not be overridden.\nReturned : %+v", dc2)
+	}
+}
+
+func TestLoadManifestFail(t *testing.T) {
+	tests := map[string]string{
+		"no maps":        "maps: []\n",
+		"missing name":   "maps:\n  - file: mapping.json\n",
+		"missing file":   "maps:\n  - name: dc1\n",
+		"duplicate name": "maps:\n  - name: dc1\n    file: a.json\n  - name: dc1\n    file: b.json\n",
+		"invalid filter": "maps:\n  - name: dc1\n    file: a.json\n    filter:\n      include_hosts: ['dc1-[']\n",
+		"unknown field":  "maps:\n  - name: dc1\n    file: a.json\n    colour: 000000\n",
+		"workers":        "workers: -1\nmaps:\n  - name: dc1\n    file: a.json\n",
+	}
+
+	for name, content := range tests {
+		_, err := LoadManifest(writeManifest(t, content))
+		if err == nil {
+			t.Fatalf("an error should be returned for the test '%s'", name)
+		}
+
+		if kind := failure.KindOf(err); kind != failure.Config {
+			t.Fatalf("wrong kind of error returned for the test '%s'.\nExpected : %s\nReturned : %s", name, failure.Config, kind)
+		}
+	}
+}
+
+func TestLoadManifestMissingFile(t *testing.T) {
+	if _, err := LoadManifest(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatalf("an error should be returned when the manifest does not exist")
+	}
+}
+
+func TestManifestMapOptions(t *testing.T) {
+	base := &Options{
+		ZabbixUrl: "http://localhost:4444/api_jsonrpc.php",
+		DryRun:    true,
+		Name:      "base",
+	}
+
+	stack := false
+	entry := &ManifestMap{
+		Name:       "dc1",
+		Filter:     &filter.Filter{IncludeHosts: []string{"dc1-*"}},
+		Width:      "1200",
+		StackHosts: &stack,
+		Output:     "dc1.json",
+	}
+
+	o := entry.options(base)
+	if o.Name != "dc1" || o.ZabbixUrl != base.ZabbixUrl || !o.DryRun {
+		t.Fatalf("the options of the map should be based on the given options.\nReturned : %+v", o)
+	}
+
+	if o.Color != "000000" || o.TriggerColor != "DD0000" || o.Height != "800" || o.Spacer != 100 {
+		t.Fatalf("the default style should be used for the options not set.\nReturned : %+v", o)
+	}
+
+	if o.Width != "1200" || o.StackHosts || o.OutFile != "dc1.json" || o.Filter != entry.Filter {
+		t.Fatalf("the options set for the map should be used.\nReturned : %+v", o)
+	}
+
+	if base.Name != "base" {
+		t.Fatalf("the given options should not be modified.\nReturned : %+v", base)
+	}
+}
+
+func TestLoadManifestExample(t *testing.T) {
+	file := filepath.Join(filepath.Dir(mappingFilePath), "manifest.yaml")
+
+	m, err := LoadManifest(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadManifest function.\nReason : %v", err)
+	}
+
+	if len(m.Maps) != 4 {
+		t.Fatalf("wrong number of maps returned.\nExpected : 4\nReturned : %d", len(m.Maps))
+	}
+
+	for _, entry := range m.Maps {
+		if _, err = os.Stat(entry.File); err != nil {
+			t.Fatalf("the mapping file of the map '%s' should exist.\nReason : %v", entry.Name, err)
+		}
+	}
+}
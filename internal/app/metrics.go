@@ -0,0 +1,76 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/metrics"
+)
+
+// enableMetrics is used to create the registry collecting the metrics if a metrics file is set in the options.
+func enableMetrics(options *Options) {
+	if options.MetricsFile != "" && options.Metrics == nil {
+		options.Metrics = metrics.NewRegistry()
+	}
+}
+
+// writeMetricsFile is used to write the collected metrics to the metrics file set in the options, if any.
+func writeMetricsFile(options *Options, logger *logging.Logger) error {
+	if options.MetricsFile == "" {
+		return nil
+	}
+
+	logger.Debug(fmt.Sprintf("writing the metrics to '%s'", options.MetricsFile))
+	if err := options.Metrics.WriteFile(options.MetricsFile); err != nil {
+		return fmt.Errorf("error while writing the metrics to '%s'.\nReason : %v", options.MetricsFile, err)
+	}
+
+	return nil
+}
+
+// getMapStats is used to count the links and the triggers of the given map, the given number of links were skipped while building it.
+func getMapStats(m *zabbixgosdk.MapCreateParameters, skipped int) *metrics.MapStats {
+	triggers := make(map[string]bool, 0)
+	for _, l := range m.Links {
+		for _, t := range l.LinkTriggers {
+			triggers[t.TriggerId] = true
+		}
+	}
+
+	return &metrics.MapStats{
+		Links:        len(m.Links),
+		SkippedLinks: skipped,
+		Triggers:     len(triggers),
+	}
+}
+
+// serveMetrics is used to expose the metrics of the given registry on the given address, under the '/metrics' path.
+// The listener is opened before returning to report the address errors, the requests are served in the background.
+func serveMetrics(address string, registry *metrics.Registry, logger *logging.Logger) (*http.Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("error while listening on '%s' to expose the metrics.\nReason : %v", address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		logger.Info(fmt.Sprintf("exposing the metrics on '%s'", listener.Addr()))
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("error while exposing the metrics", err)
+		}
+	}()
+
+	return server, nil
+}
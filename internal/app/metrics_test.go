@@ -0,0 +1,183 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/metrics"
+)
+
+// checkMetrics is used to check that each of the given lines is part of the metrics of the given registry.
+func checkMetrics(t *testing.T, registry *metrics.Registry, lines ...string) {
+	var buf bytes.Buffer
+	if err := registry.Write(&buf); err != nil {
+		t.Fatalf("error while writing the metrics.\nReason : %v", err)
+	}
+
+	for _, line := range lines {
+		if !strings.Contains(buf.String(), line) {
+			t.Fatalf("missing metric.\nExpected : %s\nReturned :\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestGetMapStats(t *testing.T) {
+	m := &zabbixgosdk.MapCreateParameters{
+		Links: []*zabbixgosdk.MapLink{
+			{LinkTriggers: []*zabbixgosdk.MapLinkTrigger{{TriggerId: "1"}, {TriggerId: "2"}}},
+			{LinkTriggers: []*zabbixgosdk.MapLinkTrigger{{TriggerId: "2"}}},
+		},
+	}
+
+	stats := getMapStats(m, 1)
+	if stats.Links != 2 || stats.SkippedLinks != 1 || stats.Triggers != 2 {
+		t.Fatalf("wrong stats returned.\nExpected : {Links:2 SkippedLinks:1 Triggers:2}\nReturned : %+v", *stats)
+	}
+}
+
+func TestApplyMapMetrics(t *testing.T) {
+	logger := logging.NewLogger(logging.Critical)
+	mappings, err := readMappings(mappingFilePath, &Options{}, logger)
+	if err != nil {
+		t.Fatalf("error while executing readMappings function.\nReason : %v", err)
+	}
+
+	options := &Options{
+		Name:    "metrics-map",
+		Width:   "400",
+		Height:  "400",
+		Spacer:  50,
+		Filter:  &filter.Filter{IncludeHosts: []string{"router-1", "router-2"}},
+		Metrics: metrics.NewRegistry(),
+	}
+
+	if err = applyMap(newFakeClient(), mappings, options, logger); err != nil {
+		t.Fatalf("error while executing applyMap function.\nReason : %v", err)
+	}
+
+	checkMetrics(t, options.Metrics,
+		`zabbix_map_builder_builds_total{map="metrics-map",outcome="success"} 1`,
+		`zabbix_map_builder_build_duration_seconds_count{map="metrics-map"} 1`,
+		`zabbix_map_builder_resolved_hosts{map="metrics-map"} 2`,
+		`zabbix_map_builder_unresolved_hosts{map="metrics-map"} 0`,
+		`zabbix_map_builder_links{map="metrics-map"} 1`,
+		`zabbix_map_builder_skipped_links{map="metrics-map"} 0`,
+		`zabbix_map_builder_last_success_timestamp_seconds{map="metrics-map"}`,
+	)
+
+	// The links referencing an host not found are skipped, the mappings excluded by the filter are not counted
+	options.Filter = nil
+	unresolved := append(mappings, &zbxmap.Mapping{
+		LocalHost:            "router-1",
+		LocalTriggerPattern:  "Interface eth1(): Link down",
+		LocalImage:           "Firewall_(64)",
+		RemoteHost:           "router-unknown",
+		RemoteTriggerPattern: "Interface eth0(): Link down",
+		RemoteImage:          "Switch_(64)",
+	})
+
+	if err = applyMap(newFakeClient(), unresolved, options, logger); err != nil {
+		t.Fatalf("error while executing applyMap function.\nReason : %v", err)
+	}
+
+	checkMetrics(t, options.Metrics,
+		`zabbix_map_builder_resolved_hosts{map="metrics-map"} 3`,
+		`zabbix_map_builder_unresolved_hosts{map="metrics-map"} 1`,
+		`zabbix_map_builder_links{map="metrics-map"} 2`,
+		`zabbix_map_builder_skipped_links{map="metrics-map"} 1`,
+	)
+
+	// The failed builds are recorded
+	options.Filter = &filter.Filter{IncludeHosts: []string{"switch-*"}}
+	if err = applyMap(newFakeClient(), mappings, options, logger); err == nil {
+		t.Fatalf("an error should be returned when no mapping matches the filter")
+	}
+
+	checkMetrics(t, options.Metrics, `zabbix_map_builder_builds_total{map="metrics-map",outcome="error"} 1`)
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	logger := logging.NewLogger(logging.Critical)
+
+	// Nothing is written without a metrics file
+	options := &Options{}
+	enableMetrics(options)
+	if options.Metrics != nil {
+		t.Fatalf("the metrics should not be collected without a metrics file")
+	}
+
+	if err := writeMetricsFile(options, logger); err != nil {
+		t.Fatalf("error while executing writeMetricsFile function.\nReason : %v", err)
+	}
+
+	options.MetricsFile = filepath.Join(t.TempDir(), "zabbix_map_builder.prom")
+	enableMetrics(options)
+	options.Metrics.ObserveBuild("metrics-map", time.Second, nil)
+
+	if err := writeMetricsFile(options, logger); err != nil {
+		t.Fatalf("error while executing writeMetricsFile function.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(options.MetricsFile)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", options.MetricsFile, err)
+	}
+
+	if !strings.Contains(string(b), `zabbix_map_builder_builds_total{map="metrics-map",outcome="success"} 1`) {
+		t.Fatalf("the metrics should be written to the file.\nReturned : %s", string(b))
+	}
+
+	options.MetricsFile = filepath.Join(t.TempDir(), "missing", "zabbix_map_builder.prom")
+	if err := writeMetricsFile(options, logger); err == nil {
+		t.Fatalf("an error should be returned when the directory does not exist")
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error while searching a free port.\nReason : %v", err)
+	}
+
+	address := l.Addr().String()
+	l.Close()
+
+	registry := metrics.NewRegistry()
+	registry.ObserveBuild("metrics-map", time.Second, nil)
+
+	server, err := serveMetrics(address, registry, logging.NewLogger(logging.Critical))
+	if err != nil {
+		t.Fatalf("error while executing serveMetrics function.\nReason : %v", err)
+	}
+
+	defer server.Shutdown(context.Background())
+
+	res, err := http.Get("http://" + address + "/metrics")
+	if err != nil {
+		t.Fatalf("error while retrieving the metrics.\nReason : %v", err)
+	}
+
+	defer res.Body.Close()
+
+	b, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(b), `zabbix_map_builder_builds_total{map="metrics-map",outcome="success"} 1`) {
+		t.Fatalf("wrong metrics returned.\nReturned : %d %s", res.StatusCode, string(b))
+	}
+
+	// The address is already used
+	if _, err = serveMetrics(address, registry, logging.NewLogger(logging.Critical)); err == nil {
+		t.Fatalf("an error should be returned when the address is already used")
+	}
+}
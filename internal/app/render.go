@@ -0,0 +1,153 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/render"
+)
+
+// readMapFile is used to read a map create request from the given file.
+func readMapFile(file string) (*zabbixgosdk.MapCreateParameters, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &zabbixgosdk.MapCreateParameters{}
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// getMap is used to retrieve the map to render, either from the input file or from the Zabbix server.
+func getMap(client api.ZabbixAPI, options *RenderOptions) (*zabbixgosdk.MapCreateParameters, error) {
+	if options.Input != "" {
+		return readMapFile(options.Input)
+	}
+
+	maps, err := client.GetMaps([]string{options.Map})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(maps) == 0 {
+		return nil, failure.Errorf(failure.NotFound, "no map named '%s' was found on the server", options.Map)
+	}
+
+	return &maps[0].MapCreateParameters, nil
+}
+
+// getRenderResources is used to retrieve the icons and the hosts name referenced by the elements of the given map.
+func getRenderResources(client api.ZabbixAPI, m *zabbixgosdk.MapCreateParameters) (*render.Options, error) {
+	imagesId := make([]string, 0)
+	hostsId := make([]string, 0)
+
+	for _, element := range m.Elements {
+		if element.IconIdOff != "" {
+			imagesId = append(imagesId, element.IconIdOff)
+		}
+
+		if id := zbxmap.GetElementHostId(element); id != "" {
+			hostsId = append(hostsId, id)
+		}
+	}
+
+	out := &render.Options{
+		Icons: make(map[string]string, 0),
+		Hosts: make(map[string]string, 0),
+	}
+
+	images, err := client.GetImagesData(imagesId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, image := range images {
+		out.Icons[image.Id] = image.Data
+	}
+
+	hosts, err := client.GetHostsById(hostsId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts {
+		out.Hosts[host.Id] = host.Host
+	}
+
+	return out, nil
+}
+
+// renderMap is used to draw the given map to the output files set in the options.
+func renderMap(client api.ZabbixAPI, options *RenderOptions, logger *logging.Logger) error {
+	logger.Debug("retrieving the map to render")
+	m, err := getMap(client, options)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("retrieving the icons and the hosts referenced by the map")
+	resources, err := getRenderResources(client, m)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("rendering the map to '%s'", options.SvgFile))
+	b, err := render.SVG(m, resources)
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(options.SvgFile, b, 0644); err != nil {
+		return err
+	}
+
+	if options.PngFile == "" {
+		logger.Debug("'--png' flag not used, skipping step.")
+		return nil
+	}
+
+	logger.Debug(fmt.Sprintf("rendering the map to '%s'", options.PngFile))
+	b, err = render.PNG(m, resources)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(options.PngFile, b, 0644)
+}
+
+// RunRender is used to draw a map to an SVG file (and optionally a PNG file).
+// The map is read from a map create request file or retrieved from the Zabbix server.
+func RunRender(options *Options, renderOptions *RenderOptions, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	if renderOptions.Input == "" && renderOptions.Map == "" {
+		return failure.Errorf(failure.Config, "an input file or the name of an existing map is required to render a map")
+	}
+
+	if renderOptions.SvgFile == "" {
+		return failure.Errorf(failure.Config, "an output file is required to render a map")
+	}
+
+	// Initialize an api client.
+	client, err := initClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	return renderMap(client, renderOptions, logger)
+}
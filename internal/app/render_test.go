@@ -0,0 +1,120 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// buildTestingMap is used to build the map described in the example mapping file using the fake client.
+func buildTestingMap(t *testing.T) *zabbixgosdk.MapCreateParameters {
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	m, err := buildMap(newFakeClient(), mappings, &Options{
+		Name:         "test-map-builder",
+		Color:        "7AC2E1",
+		TriggerColor: "EE445B",
+		Width:        "400",
+		Height:       "400",
+		Spacer:       50,
+		StackHosts:   true,
+	}, logging.NewLogger(logging.Warning))
+
+	if err != nil {
+		t.Fatalf("error while executing buildMap function.\nReason : %v", err)
+	}
+
+	return m
+}
+
+func TestRenderMapFromInput(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "map.json")
+	svgFile := filepath.Join(dir, "map.svg")
+	pngFile := filepath.Join(dir, "map.png")
+
+	b, err := json.Marshal(buildTestingMap(t))
+	if err != nil {
+		t.Fatalf("error while encoding the map.\nReason : %v", err)
+	}
+
+	if err = os.WriteFile(input, b, 0644); err != nil {
+		t.Fatalf("error while writing the input file '%s'.\nReason : %v", input, err)
+	}
+
+	err = renderMap(newFakeClient(), &RenderOptions{
+		Input:   input,
+		SvgFile: svgFile,
+		PngFile: pngFile,
+	}, logging.NewLogger(logging.Warning))
+
+	if err != nil {
+		t.Fatalf("error while executing renderMap function.\nReason : %v", err)
+	}
+
+	svg, err := os.ReadFile(svgFile)
+	if err != nil {
+		t.Fatalf("error while reading the SVG file '%s'.\nReason : %v", svgFile, err)
+	}
+
+	if !strings.Contains(string(svg), "router-1") {
+		t.Fatalf("the name of the hosts was not rendered.\nReturned : %s", string(svg))
+	}
+
+	if _, err = os.Stat(pngFile); err != nil {
+		t.Fatalf("the PNG file '%s' was not created.\nReason : %v", pngFile, err)
+	}
+}
+
+func TestRenderMapFromServer(t *testing.T) {
+	svgFile := filepath.Join(t.TempDir(), "map.svg")
+
+	client := newFakeClient()
+	if _, err := client.CreateMap(buildTestingMap(t)); err != nil {
+		t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+	}
+
+	err := renderMap(client, &RenderOptions{
+		Map:     "test-map-builder",
+		SvgFile: svgFile,
+	}, logging.NewLogger(logging.Warning))
+
+	if err != nil {
+		t.Fatalf("error while executing renderMap function.\nReason : %v", err)
+	}
+
+	if _, err = os.Stat(svgFile); err != nil {
+		t.Fatalf("the SVG file '%s' was not created.\nReason : %v", svgFile, err)
+	}
+}
+
+func TestRenderMapUnknownMap(t *testing.T) {
+	err := renderMap(newFakeClient(), &RenderOptions{
+		Map:     "unknown-map",
+		SvgFile: filepath.Join(t.TempDir(), "map.svg"),
+	}, logging.NewLogger(logging.Warning))
+
+	if err == nil {
+		t.Fatal("an error should be returned when the map does not exist")
+	}
+}
+
+func TestRunRenderMissingOptions(t *testing.T) {
+	err := RunRender(&Options{}, &RenderOptions{SvgFile: "map.svg"}, nil)
+	if err == nil {
+		t.Fatal("an error should be returned when no input file or map name is set")
+	}
+
+	err = RunRender(&Options{}, &RenderOptions{Map: "test-map-builder"}, nil)
+	if err == nil {
+		t.Fatal("an error should be returned when no output file is set")
+	}
+}
@@ -0,0 +1,497 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/filter"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/input"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/metrics"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/render"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// ServeOptions define the options of the HTTP API.
+type ServeOptions struct {
+	// Listen is the address the HTTP server listens on (ex: '127.0.0.1:8080').
+	Listen string
+	// Token is the bearer token required to call the API (except the health endpoint).
+	Token string
+	// Workers is the number of jobs executed concurrently.
+	Workers int
+	// QueueSize is the maximum number of pending jobs.
+	QueueSize int
+	// Retention is the delay during which the status of a finished job can be polled.
+	Retention time.Duration
+}
+
+// maxDocumentSize is the maximum size of a mapping document submitted to the API.
+const maxDocumentSize = 10 << 20
+
+// contentTypeFormats associate the content types of the mapping documents to the formats of the mapping files.
+var contentTypeFormats = map[string]string{
+	"application/json":   "json",
+	"application/yaml":   "yaml",
+	"application/x-yaml": "yaml",
+	"text/yaml":          "yaml",
+	"text/csv":           "csv",
+	"text/vnd.graphviz":  "dot",
+}
+
+// server define the HTTP API used to build maps.
+type server struct {
+	options *ServeOptions
+	// base contains the options shared by all the jobs (Zabbix server, host lookup, etc.).
+	base   *Options
+	logger *logging.Logger
+	queue  *jobQueue
+	// dir is the directory storing the mapping documents of the pending jobs.
+	dir string
+	// newClient is used to open a new session on the server for each job.
+	newClient func() (api.ZabbixAPI, error)
+}
+
+// newServer is used to create the HTTP API using the given options, the jobs are executed using the clients returned by newClient.
+func newServer(options *ServeOptions, base *Options, newClient func() (api.ZabbixAPI, error), logger *logging.Logger) (*server, error) {
+	if options.Token == "" {
+		return nil, failure.Errorf(failure.Config, "a token is required to authenticate the requests")
+	}
+
+	if options.Workers <= 0 || options.QueueSize <= 0 {
+		return nil, failure.Errorf(failure.Config, "the number of workers and the size of the queue must be greater than 0")
+	}
+
+	dir, err := os.MkdirTemp("", "zabbix-map-builder-")
+	if err != nil {
+		return nil, err
+	}
+
+	// The metrics of the jobs are exposed on the '/metrics' endpoint
+	if base.Metrics == nil {
+		base.Metrics = metrics.NewRegistry()
+	}
+
+	s := &server{
+		options:   options,
+		base:      base,
+		logger:    logger,
+		dir:       dir,
+		newClient: newClient,
+	}
+
+	s.queue = newJobQueue(options.QueueSize, options.Workers, options.Retention, s.runJob)
+
+	return s, nil
+}
+
+// close is used to wait for the pending jobs and to remove the mapping documents.
+func (s *server) close() error {
+	s.queue.close()
+	return os.RemoveAll(s.dir)
+}
+
+// runJob is used to build the map of the given job, then to create (or update) it on the server.
+// The map is not saved for a dry-run job, the create request and its SVG rendering are returned instead.
+func (s *server) runJob(j *Job) (*Preview, error) {
+	defer os.Remove(j.file)
+
+	logger := s.logger.With("map", j.Map, "job", j.Id)
+
+	client, err := s.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := client.Logout(); err != nil {
+			logger.Error("error while logging out", err)
+		}
+	}()
+
+	mappings, err := readMappings(j.file, j.options, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := prepareMap(client, mappings, j.options, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if !j.DryRun {
+		return nil, saveMap(client, m, j.options, logger)
+	}
+
+	resources, err := getRenderResources(client, m)
+	if err != nil {
+		return nil, err
+	}
+
+	svg, err := render.SVG(m, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Preview{Map: m, SVG: svg}, nil
+}
+
+// writeJSON is used to write the given value as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError is used to write the given error as the JSON body of the response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// authenticate is used to check the bearer token of the given request.
+func (s *server) authenticate(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+
+	token := strings.TrimPrefix(header, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.options.Token)) == 1
+}
+
+// parseBool is used to read the boolean query parameter with the given name, false is returned if not set.
+func parseBool(query map[string][]string, name string) (bool, error) {
+	values := query[name]
+	if len(values) == 0 || values[0] == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid value '%s' for the parameter '%s'", values[0], name)
+	}
+
+	return b, nil
+}
+
+// parseInt is used to read the integer query parameter with the given name, 0 is returned if not set.
+func parseInt(query map[string][]string, name string) (int64, error) {
+	values := query[name]
+	if len(values) == 0 || values[0] == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' for the parameter '%s'", values[0], name)
+	}
+
+	return i, nil
+}
+
+// documentFormat is used to retrieve the format of the mapping document using the 'format' parameter or the content type of the request.
+// The JSON format is used if neither is set.
+func documentFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if !utils.Contains(input.Formats, format) {
+			return "", fmt.Errorf("unsupported format '%s', supported formats are %v", format, input.Formats)
+		}
+
+		return format, nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return "json", nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type '%s'", contentType)
+	}
+
+	format, exist := contentTypeFormats[mediaType]
+	if !exist {
+		return "", fmt.Errorf("unsupported content type '%s', use the 'format' parameter", mediaType)
+	}
+
+	return format, nil
+}
+
+// jobOptions is used to build the options of a job from the query parameters of the given request.
+// The supported parameters match the options of the manifest of the build-all command, the files of the server (layout, icon rules, etc.) cannot be used.
+func (s *server) jobOptions(r *http.Request, format string) (*Options, bool, error) {
+	query := r.URL.Query()
+
+	entry := &ManifestMap{
+		Name:          query.Get("name"),
+		Format:        format,
+		Color:         query.Get("color"),
+		TriggerColor:  query.Get("trigger_color"),
+		Width:         query.Get("width"),
+		Height:        query.Get("height"),
+		Label:         query.Get("label"),
+		LabelLocation: query.Get("label_location"),
+		Filter: &filter.Filter{
+			IncludeHosts:  query["include_host"],
+			IncludeGroups: query["include_group"],
+			Tags:          query["tag"],
+			StubImage:     query.Get("stub_image"),
+		},
+	}
+
+	if entry.Name == "" {
+		return nil, false, fmt.Errorf("the 'name' parameter is required")
+	}
+
+	spacer, err := parseInt(query, "spacer")
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry.Spacer = spacer
+
+	hops, err := parseInt(query, "hops")
+	if err != nil {
+		return nil, false, err
+	}
+
+	entry.Filter.Hops = int(hops)
+
+	if entry.Filter.Stubs, err = parseBool(query, "stubs"); err != nil {
+		return nil, false, err
+	}
+
+	if query.Get("stack_hosts") != "" {
+		stack, err := parseBool(query, "stack_hosts")
+		if err != nil {
+			return nil, false, err
+		}
+
+		entry.StackHosts = &stack
+	}
+
+	if err = entry.Filter.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	options := entry.options(s.base)
+
+	dryRun, err := parseBool(query, "dry_run")
+	if err != nil {
+		return nil, false, err
+	}
+
+	if options.Update, err = parseBool(query, "update"); err != nil {
+		return nil, false, err
+	}
+
+	return options, dryRun, nil
+}
+
+// handleHealth is used to report the status of the server and the number of jobs of each status.
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method '%s' not allowed", r.Method))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status": "ok",
+		"jobs":   s.queue.counts(),
+	})
+}
+
+// handleMaps is used to submit a job building the map of the mapping document sent in the body of the request.
+func (s *server) handleMaps(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method '%s' not allowed", r.Method))
+		return
+	}
+
+	format, err := documentFormat(r)
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, err)
+		return
+	}
+
+	options, dryRun, err := s.jobOptions(r, format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDocumentSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	f, err := os.CreateTemp(s.dir, "mapping-*."+format)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, err = f.Write(body)
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Reject invalid documents before queuing the job
+	if err = input.Validate(f.Name(), format); err != nil {
+		os.Remove(f.Name())
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	j := &Job{
+		Map:     options.Name,
+		DryRun:  dryRun,
+		file:    f.Name(),
+		options: options,
+	}
+
+	if err = s.queue.submit(j); err != nil {
+		os.Remove(f.Name())
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+
+	s.logger.With("map", j.Map, "job", j.Id).Info(fmt.Sprintf("job %s submitted for the map '%s'", j.Id, j.Map))
+
+	submitted, _ := s.queue.get(j.Id)
+	w.Header().Set("Location", "/jobs/"+j.Id)
+	writeJSON(w, http.StatusAccepted, submitted)
+}
+
+// handleJobs is used to retrieve the status of a job (/jobs/<id>) or the preview of a dry-run job (/jobs/<id>/preview).
+func (s *server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method '%s' not allowed", r.Method))
+		return
+	}
+
+	id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+
+	j, exist := s.queue.get(id)
+	if !exist {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no job found with the id '%s'", id))
+		return
+	}
+
+	switch resource {
+	case "":
+		writeJSON(w, http.StatusOK, j)
+	case "preview":
+		s.writePreview(w, r, j)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown resource '%s'", resource))
+	}
+}
+
+// writePreview is used to write the map built by the given dry-run job as JSON (default) or as SVG ('format' parameter).
+func (s *server) writePreview(w http.ResponseWriter, r *http.Request, j *Job) {
+	if !j.DryRun {
+		writeError(w, http.StatusNotFound, fmt.Errorf("the job '%s' is not a dry-run, no preview is available", j.Id))
+		return
+	}
+
+	if j.Status != JobSucceeded {
+		writeError(w, http.StatusConflict, fmt.Errorf("the job '%s' is %s, the preview is available once the job succeeded", j.Id, j.Status))
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		writeJSON(w, http.StatusOK, j.preview.Map)
+	case "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(j.preview.SVG)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported preview format '%s', supported formats are [json svg]", r.URL.Query().Get("format")))
+	}
+}
+
+// handler is used to retrieve the routes of the API, all the routes except the health and the metrics endpoints require the bearer token.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.Handle("/metrics", s.base.Metrics.Handler())
+	mux.HandleFunc("/maps", s.handleMaps)
+	mux.HandleFunc("/jobs/", s.handleJobs)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" && r.URL.Path != "/metrics" && !s.authenticate(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("a valid bearer token is required"))
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// RunServe is used to expose the HTTP API until the given channel is closed.
+// The server stops accepting requests once the channel is closed, the pending jobs are executed before returning.
+func RunServe(serveOptions *ServeOptions, options *Options, stop <-chan struct{}, logger *logging.Logger) error {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	s, err := newServer(serveOptions, options, func() (api.ZabbixAPI, error) {
+		return initClient(options, logger)
+	}, logger)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Addr:              serveOptions.Listen,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info(fmt.Sprintf("listening on '%s'", serveOptions.Listen))
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errs:
+	case <-stop:
+		logger.Debug("stopping the HTTP server")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err = httpServer.Shutdown(ctx)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	logger.Debug("waiting for the pending jobs")
+	if closeErr := s.close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
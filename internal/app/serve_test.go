@@ -0,0 +1,296 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+const testingToken = "secret-token"
+
+// newTestingServer is used to start the HTTP API using the given fake client.
+func newTestingServer(t *testing.T, client *fake.Client) (*server, *httptest.Server) {
+	options := &ServeOptions{
+		Token:     testingToken,
+		Workers:   2,
+		QueueSize: 10,
+		Retention: time.Hour,
+	}
+
+	s, err := newServer(options, &Options{}, func() (api.ZabbixAPI, error) {
+		return client, nil
+	}, logging.NewLogger(logging.Critical))
+	if err != nil {
+		t.Fatalf("error while executing newServer function.\nReason : %v", err)
+	}
+
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(func() {
+		ts.Close()
+		s.close()
+	})
+
+	return s, ts
+}
+
+// doRequest is used to send a request authenticated with the given token to the testing server.
+func doRequest(t *testing.T, method string, url string, token string, contentType string, body string) (*http.Response, []byte) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error while creating the request.\nReason : %v", err)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("error while sending the request.\nReason : %v", err)
+	}
+
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("error while reading the response.\nReason : %v", err)
+	}
+
+	return res, b
+}
+
+// submitJob is used to submit the example mapping file using the given query and to wait for the job to finish.
+func submitJob(t *testing.T, ts *httptest.Server, query string) *Job {
+	b, err := os.ReadFile(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", mappingFilePath, err)
+	}
+
+	res, body := doRequest(t, http.MethodPost, ts.URL+"/maps?"+query, testingToken, "application/json", string(b))
+	if res.StatusCode != http.StatusAccepted {
+		t.Fatalf("wrong status code returned.\nExpected : %d\nReturned : %d (%s)", http.StatusAccepted, res.StatusCode, string(body))
+	}
+
+	j := &Job{}
+	if err = json.Unmarshal(body, j); err != nil {
+		t.Fatalf("error while decoding the job.\nReason : %v", err)
+	}
+
+	if res.Header.Get("Location") != "/jobs/"+j.Id {
+		t.Fatalf("wrong location returned.\nExpected : /jobs/%s\nReturned : %s", j.Id, res.Header.Get("Location"))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for j.Status == JobQueued || j.Status == JobRunning {
+		if time.Now().After(deadline) {
+			t.Fatalf("the job '%s' was not finished in time", j.Id)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		_, body = doRequest(t, http.MethodGet, ts.URL+"/jobs/"+j.Id, testingToken, "", "")
+		if err = json.Unmarshal(body, j); err != nil {
+			t.Fatalf("error while decoding the job.\nReason : %v", err)
+		}
+	}
+
+	return j
+}
+
+func TestNewServerFail(t *testing.T) {
+	newClient := func() (api.ZabbixAPI, error) {
+		return newFakeClient(), nil
+	}
+
+	if _, err := newServer(&ServeOptions{Workers: 1, QueueSize: 1}, &Options{}, newClient, nil); err == nil {
+		t.Fatalf("an error should be returned when no token is set")
+	}
+
+	if _, err := newServer(&ServeOptions{Token: testingToken, QueueSize: 1}, &Options{}, newClient, nil); err == nil {
+		t.Fatalf("an error should be returned when no worker is set")
+	}
+}
+
+func TestServeHealth(t *testing.T) {
+	_, ts := newTestingServer(t, newFakeClient())
+
+	// The health endpoint does not require the token
+	res, body := doRequest(t, http.MethodGet, ts.URL+"/health", "", "", "")
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), `"status":"ok"`) {
+		t.Fatalf("wrong response returned.\nReturned : %d %s", res.StatusCode, string(body))
+	}
+}
+
+func TestServeAuthentication(t *testing.T) {
+	_, ts := newTestingServer(t, newFakeClient())
+
+	for _, token := range []string{"", "wrong-token"} {
+		res, _ := doRequest(t, http.MethodPost, ts.URL+"/maps?name=test", token, "application/json", "[]")
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("wrong status code returned for the token '%s'.\nExpected : %d\nReturned : %d", token, http.StatusUnauthorized, res.StatusCode)
+		}
+	}
+}
+
+func TestServeBuildMap(t *testing.T) {
+	client := newFakeClient()
+	_, ts := newTestingServer(t, client)
+
+	j := submitJob(t, ts, "name=api-map&width=400&height=400&spacer=50")
+	if j.Status != JobSucceeded {
+		t.Fatalf("wrong status returned.\nExpected : %s\nReturned : %s (%s)", JobSucceeded, j.Status, j.Error)
+	}
+
+	maps, err := client.GetMaps([]string{"api-map"})
+	if err != nil || len(maps) != 1 || len(maps[0].Links) != 2 {
+		t.Fatalf("the map 'api-map' should be created.\nReturned : %+v", maps)
+	}
+
+	// The map is updated with the 'update' parameter
+	j = submitJob(t, ts, "name=api-map&width=400&height=400&spacer=50&update=true&include_host=router-1&include_host=router-2")
+	if j.Status != JobSucceeded {
+		t.Fatalf("wrong status returned.\nExpected : %s\nReturned : %s (%s)", JobSucceeded, j.Status, j.Error)
+	}
+
+	maps, err = client.GetMaps([]string{"api-map"})
+	if err != nil || len(maps) != 1 || len(maps[0].Links) != 1 {
+		t.Fatalf("the map 'api-map' should be updated.\nReturned : %+v", maps)
+	}
+
+	// A job whose map cannot be built is reported as failed
+	j = submitJob(t, ts, "name=api-map&include_host=switch-*")
+	if j.Status != JobFailed || j.Error == "" {
+		t.Fatalf("wrong status returned.\nExpected : %s\nReturned : %s", JobFailed, j.Status)
+	}
+
+	// The metrics endpoint does not require the token
+	res, body := doRequest(t, http.MethodGet, ts.URL+"/metrics", "", "", "")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("wrong status code returned.\nExpected : %d\nReturned : %d", http.StatusOK, res.StatusCode)
+	}
+
+	for _, line := range []string{
+		`zabbix_map_builder_builds_total{map="api-map",outcome="success"} 2`,
+		`zabbix_map_builder_builds_total{map="api-map",outcome="error"} 1`,
+		`zabbix_map_builder_links{map="api-map"} 1`,
+	} {
+		if !strings.Contains(string(body), line) {
+			t.Fatalf("missing metric.\nExpected : %s\nReturned :\n%s", line, string(body))
+		}
+	}
+}
+
+func TestServePreview(t *testing.T) {
+	client := newFakeClient()
+	_, ts := newTestingServer(t, client)
+
+	j := submitJob(t, ts, "name=api-preview&width=400&height=400&spacer=50&dry_run=true")
+	if j.Status != JobSucceeded {
+		t.Fatalf("wrong status returned.\nExpected : %s\nReturned : %s (%s)", JobSucceeded, j.Status, j.Error)
+	}
+
+	if len(client.Maps) != 0 {
+		t.Fatalf("no map should be created for a dry-run job.\nReturned : %d", len(client.Maps))
+	}
+
+	res, body := doRequest(t, http.MethodGet, ts.URL+"/jobs/"+j.Id+"/preview", testingToken, "", "")
+	m := &zabbixgosdk.MapCreateParameters{}
+	if err := json.Unmarshal(body, m); err != nil || res.StatusCode != http.StatusOK {
+		t.Fatalf("wrong JSON preview returned.\nReturned : %d %s", res.StatusCode, string(body))
+	}
+
+	if m.Name != "api-preview" || len(m.Links) != 2 {
+		t.Fatalf("wrong map returned.\nReturned : %+v", m)
+	}
+
+	res, body = doRequest(t, http.MethodGet, ts.URL+"/jobs/"+j.Id+"/preview?format=svg", testingToken, "", "")
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "image/svg+xml" || !strings.HasPrefix(string(body), "<svg") {
+		t.Fatalf("wrong SVG preview returned.\nReturned : %d %s", res.StatusCode, string(body))
+	}
+
+	res, _ = doRequest(t, http.MethodGet, ts.URL+"/jobs/"+j.Id+"/preview?format=png", testingToken, "", "")
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("wrong status code returned for an unsupported format.\nExpected : %d\nReturned : %d", http.StatusBadRequest, res.StatusCode)
+	}
+
+	// No preview is available for the jobs saving the map
+	j = submitJob(t, ts, "name=api-preview&width=400&height=400&spacer=50")
+	res, _ = doRequest(t, http.MethodGet, ts.URL+"/jobs/"+j.Id+"/preview", testingToken, "", "")
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("wrong status code returned.\nExpected : %d\nReturned : %d", http.StatusNotFound, res.StatusCode)
+	}
+}
+
+func TestServeBadRequests(t *testing.T) {
+	_, ts := newTestingServer(t, newFakeClient())
+
+	tests := []struct {
+		method      string
+		path        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{http.MethodGet, "/maps?name=test", "", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/maps", "application/json", "[]", http.StatusBadRequest},
+		{http.MethodPost, "/maps?name=test&spacer=wide", "application/json", "[]", http.StatusBadRequest},
+		{http.MethodPost, "/maps?name=test&include_host=[", "application/json", "[]", http.StatusBadRequest},
+		{http.MethodPost, "/maps?name=test", "application/xml", "<mappings/>", http.StatusUnsupportedMediaType},
+		{http.MethodPost, "/maps?name=test&format=xml", "", "", http.StatusUnsupportedMediaType},
+		{http.MethodPost, "/maps?name=test", "application/json", `[{"local_host": "router-1"}]`, http.StatusUnprocessableEntity},
+		{http.MethodGet, "/jobs/unknown", "", "", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		res, body := doRequest(t, test.method, ts.URL+test.path, testingToken, test.contentType, test.body)
+		if res.StatusCode != test.status {
+			t.Fatalf("wrong status code returned for '%s %s'.\nExpected : %d\nReturned : %d (%s)", test.method, test.path, test.status, res.StatusCode, string(body))
+		}
+	}
+}
+
+func TestDocumentFormat(t *testing.T) {
+	tests := map[string]string{
+		"":                                "json",
+		"application/json; charset=utf-8": "json",
+		"application/x-yaml":              "yaml",
+		"text/csv":                        "csv",
+		"text/vnd.graphviz":               "dot",
+	}
+
+	for contentType, expected := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/maps", nil)
+		r.Header.Set("Content-Type", contentType)
+
+		format, err := documentFormat(r)
+		if err != nil {
+			t.Fatalf("error while executing documentFormat function.\nReason : %v", err)
+		}
+
+		if format != expected {
+			t.Fatalf("wrong format returned for the content type '%s'.\nExpected : %s\nReturned : %s", contentType, expected, format)
+		}
+	}
+
+	// The 'format' parameter is used before the content type
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/maps?format=%s", "yaml"), nil)
+	r.Header.Set("Content-Type", "application/json")
+	if format, _ := documentFormat(r); format != "yaml" {
+		t.Fatalf("wrong format returned.\nExpected : yaml\nReturned : %s", format)
+	}
+}
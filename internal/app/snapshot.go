@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/snapshot"
+)
+
+// RunSnapshot is used to export the Zabbix objects referenced in the given mapping file to a snapshot file.
+// The snapshot is written to the file set in options.OutFile.
+func RunSnapshot(file string, options *Options, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	if options.OutFile == "" {
+		return failure.Errorf(failure.Config, "an output file is required to store the snapshot")
+	}
+
+	// Retrieve the list of hosts mappings for the input file
+	logger.Debug(fmt.Sprintf("reading input file '%s'", file))
+	mappings, err := ReadInputFormat(file, options.Format)
+	if err != nil {
+		return err
+	}
+
+	mappings = normalizeMappings(mappings, logger)
+
+	// Initialize an api client.
+	client, err := initServerClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	resolveOptions, err := options.resolveOptions()
+	if err != nil {
+		return err
+	}
+
+	rules, err := options.iconRules()
+	if err != nil {
+		return err
+	}
+
+	images := make([]string, 0)
+	if rules != nil {
+		images = rules.Images()
+	}
+
+	return writeSnapshot(client, mappings, resolveOptions, options.OutFile, logger, images...)
+}
+
+// writeSnapshot is used to retrieve the objects referenced by the given mappings and write them to a snapshot file.
+// Extra images are also stored in the snapshot.
+func writeSnapshot(client api.ZabbixAPI, mappings []*zbxmap.Mapping, resolveOptions *api.ResolveOptions, file string, logger *logging.Logger, images ...string) error {
+	logger.Debug("retrieving hosts, images, triggers and items information from the server")
+	s, err := snapshot.Create(client, mappings, resolveOptions, images...)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug(fmt.Sprintf("writing snapshot to '%s'", file))
+	return s.Write(file)
+}
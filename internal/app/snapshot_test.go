@@ -0,0 +1,108 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fakeserver"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/metrics"
+)
+
+func TestWriteSnapshot(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	err = writeSnapshot(newFakeClient(), mappings, nil, file, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing writeSnapshot function.\nReason : %v", err)
+	}
+
+	if _, err = os.Stat(file); err != nil {
+		t.Fatalf("error while retrieving information about the file '%s'.\nReason : %v", file, err)
+	}
+}
+
+func TestRunAppFromSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	snapshotFile := filepath.Join(dir, "snapshot.json")
+	outFile := filepath.Join(dir, "output.json")
+
+	mappings, err := ReadInput(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while executing ReadInput function.\nReason : %v", err)
+	}
+
+	err = writeSnapshot(newFakeClient(), mappings, nil, snapshotFile, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing writeSnapshot function.\nReason : %v", err)
+	}
+
+	opts := Options{
+		Name:         "test-map-builder",
+		Color:        "7AC2E1",
+		TriggerColor: "EE445B",
+		Width:        "400",
+		Height:       "400",
+		Spacer:       50,
+		StackHosts:   true,
+		OutFile:      outFile,
+		Snapshot:     snapshotFile,
+	}
+
+	err = RunApp(mappingFilePath, &opts, nil)
+	if err != nil {
+		t.Fatalf("error while executing RunApp function.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("error while reading output file '%s'.\nReason : %v", outFile, err)
+	}
+
+	if len(b) == 0 {
+		t.Fatalf("file '%s' does not contains the request used to create the map", outFile)
+	}
+}
+
+func TestRunSnapshotMissingOutput(t *testing.T) {
+	err := RunSnapshot(mappingFilePath, &Options{}, nil)
+	if err == nil {
+		t.Fatal("an error should be returned when no output file is set")
+	}
+}
+
+func TestRunSnapshotMetrics(t *testing.T) {
+	pwd, _ := os.Getwd()
+	dataset, err := fakeserver.LoadDataset(filepath.Join(pwd, "..", "..", "examples", "fake_dataset.json"))
+	if err != nil {
+		t.Fatalf("error while loading the fake server dataset.\nReason : %v", err)
+	}
+
+	s := fakeserver.NewServer(dataset)
+	t.Cleanup(s.Close)
+
+	options := &Options{
+		ZabbixUrl:  s.ApiUrl(),
+		ZabbixUser: ZABBIX_USER,
+		ZabbixPwd:  ZABBIX_PWD,
+		OutFile:    filepath.Join(t.TempDir(), "snapshot.json"),
+		Metrics:    metrics.NewRegistry(),
+	}
+
+	if err = RunSnapshot(mappingFilePath, options, logging.NewLogger(logging.Critical)); err != nil {
+		t.Fatalf("error while executing RunSnapshot function.\nReason : %v", err)
+	}
+
+	// The calls of the snapshot are recorded like the calls of the other commands
+	checkMetrics(t, options.Metrics,
+		`zabbix_map_builder_api_calls_total{method="user.login",outcome="success"} 1`,
+		`zabbix_map_builder_api_calls_total{method="host.get",outcome="success"}`,
+		`zabbix_map_builder_api_calls_total{method="user.logout",outcome="success"} 1`,
+	)
+}
@@ -1,40 +1,221 @@
 package app
 
 import (
-	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
 	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
 	zbxMap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
 )
 
+// normalizeMappings is used to merge the mappings declaring the same link (A -> B and B -> A).
+// Conflicting duplicates are reported as warnings, the values of the first declaration are kept.
+func normalizeMappings(mappings []*zbxMap.Mapping, logger *logging.Logger) []*zbxMap.Mapping {
+	out, conflicts := zbxMap.Normalize(mappings)
+
+	for _, c := range conflicts {
+		logger.Warning(c.String())
+	}
+
+	if merged := len(mappings) - len(out); merged > 0 {
+		logger.Debug(fmt.Sprintf("%d duplicate link(s) merged", merged))
+	}
+
+	return out
+}
+
 // getUniqueHosts is used to get a map where each key correspond to an host name reference in the list of Mapping and the value, the hostid associated on the Zabbix server.
-func getUniqueHosts(client *zabbixgosdk.ZabbixService, mappings []*zbxMap.Mapping) (map[string]string, error) {
+// Hosts are resolved using the lookup strategies set in the options, an empty id is associated to the hosts not found.
+// Only the host and trigger elements reference an host.
+func getUniqueHosts(client api.ZabbixAPI, mappings []*zbxMap.Mapping, options *Options, logger *logging.Logger) (map[string]string, error) {
+	names := make([]string, 0)
 	out := make(map[string]string, 0)
 
 	for _, m := range mappings {
-		_, exist := out[m.LocalHost]
-		if !exist {
-			out[m.LocalHost] = ""
+		for _, host := range elementNames(m, zbxMap.ElementHost, zbxMap.ElementTrigger) {
+			if _, exist := out[host]; !exist {
+				out[host] = ""
+				names = append(names, host)
+			}
 		}
+	}
 
-		_, exist = out[m.RemoteHost]
-		if !exist {
-			out[m.RemoteHost] = ""
-		}
+	resolveOptions, err := options.resolveOptions()
+	if err != nil {
+		return nil, err
 	}
 
 	// Retrieve the id of each hosts and provide a mapping 'host' -> 'hostid'
-	out, err := api.GetHostsId(client, out)
+	resolutions, err := api.ResolveHosts(client, names, resolveOptions)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, name := range names {
+		r := resolutions[name]
+		out[name] = r.Id
+
+		if r.Id == "" {
+			logger.With("host", name).Warning(fmt.Sprintf("no host found for '%s'", name))
+			continue
+		}
+
+		logger.With("host", name).Info(fmt.Sprintf("host '%s' resolved to '%s' (id %s) using the '%s' strategy", name, r.Host, r.Id, r.Strategy))
+	}
+
+	if options.HostReport != "" {
+		logger.Debug(fmt.Sprintf("writing the hosts resolution report to '%s'", options.HostReport))
+		if err = writeHostReport(options.HostReport, resolutions); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// writeHostReport is used to write the resolution of each host to the given file, sorted by name.
+func writeHostReport(file string, resolutions map[string]*api.Resolution) error {
+	report := make([]*api.Resolution, 0)
+	for _, r := range resolutions {
+		report = append(report, r)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Name < report[j].Name
+	})
+
+	b, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, b, 0644)
+}
+
+// skipUnresolvedMappings is used to remove the mappings referencing an host not found on the server (associated to an empty id).
+// Each skipped link is reported as a warning, the number of links skipped is returned with the remaining mappings.
+func skipUnresolvedMappings(mappings []*zbxMap.Mapping, hosts map[string]string, logger *logging.Logger) ([]*zbxMap.Mapping, int) {
+	out := make([]*zbxMap.Mapping, 0)
+
+	for _, m := range mappings {
+		unresolved := ""
+		for _, host := range elementNames(m, zbxMap.ElementHost, zbxMap.ElementTrigger) {
+			if hosts[host] == "" {
+				unresolved = host
+				break
+			}
+		}
+
+		if unresolved != "" {
+			logger.With("host", unresolved).Warning(fmt.Sprintf("skipping the link between '%s' and '%s', no host found for '%s'", m.LocalHost, m.RemoteHost, unresolved))
+			continue
+		}
+
+		out = append(out, m)
+	}
+
+	return out, len(mappings) - len(out)
+}
+
+// elementNames is used to retrieve the names of the elements of the mapping matching one of the given types.
+func elementNames(m *zbxMap.Mapping, types ...string) []string {
+	out := make([]string, 0)
+
+	if utils.Contains(types, m.LocalElementType()) {
+		out = append(out, m.LocalHost)
+	}
+
+	if utils.Contains(types, m.RemoteElementType()) {
+		out = append(out, m.RemoteHost)
+	}
+
+	return out
+}
+
+// getUniqueHostGroups is used to get a map where each key correspond to the name of an host group used as element and the value, the groupid associated on the Zabbix server.
+func getUniqueHostGroups(client api.ZabbixAPI, mappings []*zbxMap.Mapping) (map[string]string, error) {
+	names := make([]string, 0)
+	out := make(map[string]string, 0)
+
+	for _, m := range mappings {
+		for _, name := range elementNames(m, zbxMap.ElementHostGroup) {
+			if !utils.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return out, nil
+	}
+
+	groups, err := client.GetHostGroupsByName(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range groups {
+		out[g.Name] = g.Id
+	}
+
+	for _, name := range names {
+		if out[name] == "" {
+			return nil, failure.Errorf(failure.NotFound, "no host group was found with the name '%s'", name)
+		}
+	}
+
+	return out, nil
+}
+
+// getUniqueMaps is used to get a map where each key correspond to the name of a map used as element and the value, the sysmapid associated on the Zabbix server.
+func getUniqueMaps(client api.ZabbixAPI, mappings []*zbxMap.Mapping) (map[string]string, error) {
+	names := make([]string, 0)
+	out := make(map[string]string, 0)
+
+	for _, m := range mappings {
+		for _, name := range elementNames(m, zbxMap.ElementMap) {
+			if !utils.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return out, nil
+	}
+
+	maps, err := client.GetMaps(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range maps {
+		out[m.Name] = m.Id
+	}
+
+	for _, name := range names {
+		if out[name] == "" {
+			return nil, failure.Errorf(failure.NotFound, "no map was found with the name '%s'", name)
+		}
+	}
+
 	return out, nil
 }
 
 // getUniqueHosts is used to get a map where each key correspond to an image name reference in the list of Mapping and the value, the imageid associated on the Zabbix server.
-func getUniqueImages(client *zabbixgosdk.ZabbixService, mappings []*zbxMap.Mapping) (map[string]string, error) {
+// Extra images (used for the other states of the elements) can be added to the map.
+func getUniqueImages(client api.ZabbixAPI, mappings []*zbxMap.Mapping, extra ...string) (map[string]string, error) {
 	out := make(map[string]string, 0)
 
+	for _, image := range extra {
+		out[image] = ""
+	}
+
 	for _, m := range mappings {
 		_, exist := out[m.LocalImage]
 		if !exist {
@@ -1,11 +1,19 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
 	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
 	zbxMap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
 )
 
@@ -16,14 +24,14 @@ const (
 	host        = "Zabbix server"
 )
 
-var testingClient *zabbixgosdk.ZabbixService
-
-func init() {
-	var err error
-	testingClient, err = api.InitApi(ZABBIX_URL, ZABBIX_USER, ZABBIX_PWD)
+// getTestingClient is used to initialize an API client for the tests requiring a Zabbix server.
+func getTestingClient(t *testing.T) *api.Client {
+	service, err := api.InitApi(ZABBIX_URL, ZABBIX_USER, ZABBIX_PWD)
 	if err != nil {
-		log.Fatalf("error while executing InitApi function.\nReason : %v", err)
+		t.Fatalf("error while executing InitApi function.\nReason : %v", err)
 	}
+
+	return api.NewClient(service)
 }
 
 func TestGetUniqueHosts(t *testing.T) {
@@ -33,7 +41,7 @@ func TestGetUniqueHosts(t *testing.T) {
 		RemoteHost: host,
 	})
 
-	out, err := getUniqueHosts(testingClient, m)
+	out, err := getUniqueHosts(getTestingClient(t), m, &Options{}, logging.NewLogger(logging.Warning))
 	if err != nil {
 		t.Fatalf("error while executing getUniqueHosts function.\nReason : %v", err)
 	}
@@ -54,7 +62,7 @@ func TestGetUniqueImages(t *testing.T) {
 		RemoteImage: "Switch_(64)",
 	})
 
-	out, err := getUniqueImages(testingClient, m)
+	out, err := getUniqueImages(getTestingClient(t), m)
 	if err != nil {
 		t.Fatalf("error while executing getUniqueImages function.\nReason : %v", err)
 	}
@@ -75,3 +83,255 @@ func TestGetUniqueImages(t *testing.T) {
 		t.Fatalf("no hostid was associated with the key 'Switch_(64)' in the returned map.\nReturned : %v", out)
 	}
 }
+
+// newFakeClient is used to create a fake client containing the hosts, images and triggers referenced in the example mapping file.
+func newFakeClient() *fake.Client {
+	return fake.NewClient().
+		AddHost("1", "router-1").
+		AddHost("2", "router-2").
+		AddHost("3", "router-3").
+		AddImage("11", "Firewall_(64)").
+		AddImage("12", "Switch_(64)").
+		AddTrigger("1", "21", "Interface eth0(): Link down").
+		AddTrigger("1", "22", "Interface eth1(): Link down").
+		AddTrigger("2", "23", "Interface eth0(): Link down").
+		AddTrigger("3", "24", "Interface eth1(): Link down")
+}
+
+func TestGetUniqueHostsFake(t *testing.T) {
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "router-1",
+			RemoteHost: "router-2",
+		},
+		{
+			LocalHost:  "router-1",
+			RemoteHost: "router-3",
+		},
+	}
+
+	out, err := getUniqueHosts(newFakeClient(), m, &Options{}, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing getUniqueHosts function.\nReason : %v", err)
+	}
+
+	if len(out) != 3 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 3\nReturned : %d", len(out))
+	}
+
+	if out["router-3"] != "3" {
+		t.Fatalf("wrong hostid associated with the key 'router-3'.\nExpected : '3'\nReturned : %s", out["router-3"])
+	}
+}
+
+func TestGetUniqueHostsAmbiguous(t *testing.T) {
+	client := newFakeClient().
+		SetHostName("1", "Core router").
+		SetHostName("2", "Core router")
+
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "Core router",
+			RemoteHost: "router-3",
+		},
+	}
+
+	options := &Options{
+		HostLookup: []string{api.StrategyHost, api.StrategyName},
+	}
+
+	_, err := getUniqueHosts(client, m, options, logging.NewLogger(logging.Critical))
+	if err == nil {
+		t.Fatal("an error should be returned when multiple hosts match a value")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+}
+
+func TestGetUniqueHostsLookup(t *testing.T) {
+	client := newFakeClient().
+		SetHostName("2", "Router 2").
+		AddHostInterface("3", "10.0.0.3", "router-3.example.com")
+
+	rename := filepath.Join(t.TempDir(), "rename.yaml")
+	if err := os.WriteFile(rename, []byte("rt2: Router 2\n"), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", rename, err)
+	}
+
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "rt2",
+			RemoteHost: "router-3.example.com",
+		},
+	}
+
+	options := &Options{
+		HostLookup: []string{"host", "name", "interface"},
+		HostRename: rename,
+		HostReport: filepath.Join(t.TempDir(), "report.json"),
+	}
+
+	out, err := getUniqueHosts(client, m, options, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing getUniqueHosts function.\nReason : %v", err)
+	}
+
+	if out["rt2"] != "2" || out["router-3.example.com"] != "3" {
+		t.Fatalf("wrong hostid returned.\nExpected : map[router-3.example.com:3 rt2:2]\nReturned : %v", out)
+	}
+
+	b, err := os.ReadFile(options.HostReport)
+	if err != nil {
+		t.Fatalf("error while reading the report file.\nReason : %v", err)
+	}
+
+	report := make([]*api.Resolution, 0)
+	if err = json.Unmarshal(b, &report); err != nil {
+		t.Fatalf("error while decoding the report file.\nReason : %v", err)
+	}
+
+	if len(report) != 2 || report[0].Name != "router-3.example.com" || report[0].Strategy != "interface" || report[1].Strategy != "name" {
+		t.Fatalf("wrong report returned.\nReturned : %s", string(b))
+	}
+}
+
+func TestGetUniqueHostsUnsupportedLookup(t *testing.T) {
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "router-1",
+			RemoteHost: "router-2",
+		},
+	}
+
+	_, err := getUniqueHosts(newFakeClient(), m, &Options{HostLookup: []string{"mac"}}, logging.NewLogger(logging.Warning))
+	if err == nil {
+		t.Fatal("an error should be returned when using an unsupported lookup strategy")
+	}
+}
+
+func TestGetUniqueImagesFake(t *testing.T) {
+	m := []*zbxMap.Mapping{
+		{
+			LocalImage:  "Firewall_(64)",
+			RemoteImage: "Switch_(64)",
+		},
+	}
+
+	out, err := getUniqueImages(newFakeClient(), m)
+	if err != nil {
+		t.Fatalf("error while executing getUniqueImages function.\nReason : %v", err)
+	}
+
+	if out["Switch_(64)"] != "12" {
+		t.Fatalf("wrong imageid associated with the key 'Switch_(64)'.\nExpected : '12'\nReturned : %s", out["Switch_(64)"])
+	}
+}
+
+func TestNormalizeMappings(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	mappings := []*zbxMap.Mapping{
+		{
+			LocalHost:   "router-1",
+			LocalImage:  "Firewall_(64)",
+			RemoteHost:  "router-2",
+			RemoteImage: "Switch_(64)",
+		},
+		{
+			LocalHost:   "router-2",
+			LocalImage:  "Router_(64)",
+			RemoteHost:  "router-1",
+			RemoteImage: "Firewall_(64)",
+		},
+	}
+
+	out := normalizeMappings(mappings, logging.NewLogger(logging.Warning))
+	if len(out) != 1 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 1\nReturned : %d", len(out))
+	}
+
+	if !strings.Contains(buf.String(), "with a different 'remote_image'") {
+		t.Fatalf("the conflict was not reported.\nReturned : %s", buf.String())
+	}
+}
+
+func TestGetUniqueHostGroups(t *testing.T) {
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "router-1",
+			RemoteHost: "Routers",
+			RemoteType: zbxMap.ElementHostGroup,
+		},
+	}
+
+	out, err := getUniqueHostGroups(newFakeClient().AddHostGroup("1", "41", "Routers"), m)
+	if err != nil {
+		t.Fatalf("error while executing getUniqueHostGroups function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 || out["Routers"] != "41" {
+		t.Fatalf("wrong groupid associated with the key 'Routers'.\nExpected : '41'\nReturned : %v", out)
+	}
+
+	if _, err = getUniqueHostGroups(newFakeClient(), m); err == nil {
+		t.Fatal("an error should be returned when an host group is not found")
+	}
+}
+
+func TestGetUniqueMaps(t *testing.T) {
+	client := newFakeClient()
+	if _, err := client.CreateMap(&zabbixgosdk.MapCreateParameters{Map: zabbixgosdk.Map{Name: "Datacenter"}}); err != nil {
+		t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+	}
+
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "Datacenter",
+			LocalType:  zbxMap.ElementMap,
+			RemoteHost: "router-1",
+		},
+	}
+
+	out, err := getUniqueMaps(client, m)
+	if err != nil {
+		t.Fatalf("error while executing getUniqueMaps function.\nReason : %v", err)
+	}
+
+	if out["Datacenter"] != "1" {
+		t.Fatalf("wrong sysmapid associated with the key 'Datacenter'.\nExpected : '1'\nReturned : %v", out)
+	}
+
+	m[0].LocalHost = "unknown"
+	if _, err = getUniqueMaps(client, m); err == nil {
+		t.Fatal("an error should be returned when a map is not found")
+	}
+}
+
+func TestGetUniqueHostsElementTypes(t *testing.T) {
+	m := []*zbxMap.Mapping{
+		{
+			LocalHost:  "router-1",
+			RemoteHost: "Routers",
+			RemoteType: zbxMap.ElementHostGroup,
+		},
+		{
+			LocalHost:  "router-2",
+			LocalType:  zbxMap.ElementTrigger,
+			RemoteHost: "Internet",
+			RemoteType: zbxMap.ElementImage,
+		},
+	}
+
+	out, err := getUniqueHosts(newFakeClient(), m, &Options{}, logging.NewLogger(logging.Warning))
+	if err != nil {
+		t.Fatalf("error while executing getUniqueHosts function.\nReason : %v", err)
+	}
+
+	if len(out) != 2 || out["router-1"] != "1" || out["router-2"] != "2" {
+		t.Fatalf("only the host and trigger elements should be resolved.\nReturned : %v", out)
+	}
+}
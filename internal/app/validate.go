@@ -0,0 +1,26 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// RunValidate is used to validate the given mapping file without building the map.
+// A *input.ValidationError listing every issue found is returned if the file is not valid.
+// Links declared from both ends with different values are reported as warnings.
+func RunValidate(file string, options *Options, logger *logging.Logger) error {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	logger.Debug(fmt.Sprintf("validating input file '%s'", file))
+	mappings, err := ReadInputFormat(file, options.Format)
+	if err != nil {
+		return err
+	}
+
+	normalizeMappings(mappings, logger)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/input"
+)
+
+func TestRunValidate(t *testing.T) {
+	err := RunValidate(mappingFilePath, &Options{}, nil)
+	if err != nil {
+		t.Fatalf("error while executing RunValidate function.\nReason : %v", err)
+	}
+}
+
+func TestRunValidateFail(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "mapping.json")
+
+	err := os.WriteFile(file, []byte(`[{"local_host": "router-1", "remote_imgae": "Switch_(64)"}]`), 0644)
+	if err != nil {
+		t.Fatalf("an error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	err = RunValidate(file, &Options{}, nil)
+
+	var validationErr *input.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("a *input.ValidationError should be returned.\nReturned : %v", err)
+	}
+
+	// Unknown field and 3 missing required fields
+	if len(validationErr.Issues) != 4 {
+		t.Fatalf("wrong number of issues returned.\nExpected : 4\nReturned : %d", len(validationErr.Issues))
+	}
+}
+
+func TestReadInputValidation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "mapping.json")
+
+	err := os.WriteFile(file, []byte(`[{"local_host": "router-1", "remote_imgae": "Switch_(64)"}]`), 0644)
+	if err != nil {
+		t.Fatalf("an error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	m, err := ReadInput(file)
+	if err == nil {
+		t.Fatal("an error should be returned when the mapping file is not valid")
+	}
+
+	if m != nil {
+		t.Fatal("a nil pointer should be returned instead of *[]zbxMap.Mapping when the processing fails")
+	}
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// WatchOptions define how the input files are monitored in watch mode.
+type WatchOptions struct {
+	// Interval is the delay between two checks of the input files.
+	Interval time.Duration
+	// Debounce is the delay without changes waited before rebuilding the map, multiple changes made during this delay trigger a single build.
+	Debounce time.Duration
+}
+
+// watchedFiles is used to retrieve the files read to build the map (mapping file, layout, icon rules, sharing and rename files).
+func watchedFiles(file string, options *Options) []string {
+	out := []string{file}
+
+	for _, f := range []string{options.Layout, options.IconRules, options.SharingFile, options.HostRename} {
+		if f != "" {
+			out = append(out, f)
+		}
+	}
+
+	return out
+}
+
+// stampFiles is used to associate each of the given files to its modification time and size, used to detect the changes.
+// Missing files are associated to an empty value.
+func stampFiles(files []string) map[string]string {
+	out := make(map[string]string, 0)
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			out[file] = ""
+			continue
+		}
+
+		out[file] = fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+	}
+
+	return out
+}
+
+// changedFiles is used to retrieve the files whose stamp differs between the two given stamps.
+func changedFiles(previous map[string]string, current map[string]string) []string {
+	out := make([]string, 0)
+
+	for file, stamp := range current {
+		if previous[file] != stamp {
+			out = append(out, file)
+		}
+	}
+
+	return out
+}
+
+// rebuildMap is used to read the mappings of the given file, then build and apply the map.
+func rebuildMap(client api.ZabbixAPI, file string, options *Options, logger *logging.Logger) error {
+	mappings, err := readMappings(file, options, logger)
+	if err != nil {
+		return err
+	}
+
+	return applyMap(client, mappings, options, logger)
+}
+
+// watch is used to rebuild the map each time the input files change, until the given channel is closed.
+// The map is built once before monitoring the files. Errors (invalid mapping file, unknown host, etc.) are logged without stopping the monitoring.
+func watch(client api.ZabbixAPI, file string, options *Options, watchOptions *WatchOptions, stop <-chan struct{}, out io.Writer, logger *logging.Logger) {
+	files := watchedFiles(file, options)
+	stamps := stampFiles(files)
+
+	build := func() {
+		err := rebuildMap(client, file, options, logger)
+
+		if metricsErr := writeMetricsFile(options, logger); metricsErr != nil {
+			logger.Error("error while writing the metrics", metricsErr)
+		}
+
+		if err != nil {
+			logger.Error(fmt.Sprintf("error while building the map '%s', waiting for the next change", options.Name), err)
+			return
+		}
+
+		fmt.Fprintf(out, "[%s] map '%s' built\n", time.Now().Format("15:04:05"), options.Name)
+	}
+
+	build()
+
+	ticker := time.NewTicker(watchOptions.Interval)
+	defer ticker.Stop()
+
+	// lastChange is the time of the last change not built yet, zero if no change is pending
+	var lastChange time.Time
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			current := stampFiles(files)
+			if changed := changedFiles(stamps, current); len(changed) > 0 {
+				logger.Debug(fmt.Sprintf("change detected on %v", changed))
+				stamps = current
+				lastChange = now
+				continue
+			}
+
+			if !lastChange.IsZero() && now.Sub(lastChange) >= watchOptions.Debounce {
+				lastChange = time.Time{}
+				build()
+			}
+		}
+	}
+}
+
+// RunWatch is used to build the map, then update it each time the input files change, until the given channel is closed.
+// The existing map with the same name is always updated, a single session is used on the server.
+func RunWatch(file string, options *Options, watchOptions *WatchOptions, stop <-chan struct{}, out io.Writer, logger *logging.Logger) (err error) {
+	if logger == nil {
+		logger = logging.NewLogger(logging.Warning)
+	}
+
+	if watchOptions.Interval <= 0 {
+		return failure.Errorf(failure.Config, "the watch interval must be greater than 0")
+	}
+
+	if watchOptions.Debounce < 0 {
+		return failure.Errorf(failure.Config, "the watch debounce delay cannot be negative")
+	}
+
+	enableMetrics(options)
+	logger = logger.With("map", options.Name)
+
+	client, err := initClient(options, logger)
+	if err != nil {
+		return err
+	}
+
+	defer logout(client, &err)
+
+	// Each change is applied to the map created by the first build
+	options.Update = true
+
+	logger.Debug(fmt.Sprintf("watching the input files every %s", watchOptions.Interval))
+	watch(client, file, options, watchOptions, stop, out, logger)
+
+	logger.Debug("watch mode stopped, starting the exit process.")
+	return err
+}
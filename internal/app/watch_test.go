@@ -0,0 +1,158 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/logging"
+)
+
+// waitForLinks is used to wait until the map with the given name has the expected number of links.
+func waitForLinks(t *testing.T, client *fake.Client, name string, expected int) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		maps, err := client.GetMaps([]string{name})
+		if err != nil {
+			t.Fatalf("error while executing GetMaps function.\nReason : %v", err)
+		}
+
+		if len(maps) == 1 && len(maps[0].Links) == expected {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("the map '%s' was not built with %d link(s) in time", name, expected)
+}
+
+func TestWatchedFiles(t *testing.T) {
+	options := &Options{
+		Layout:      "layout.json",
+		SharingFile: "sharing.yaml",
+	}
+
+	files := watchedFiles("mapping.json", options)
+	if len(files) != 3 || files[0] != "mapping.json" || files[1] != "layout.json" || files[2] != "sharing.yaml" {
+		t.Fatalf("wrong files returned.\nExpected : [mapping.json layout.json sharing.yaml]\nReturned : %v", files)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "mapping.json")
+	if err := os.WriteFile(file, []byte("[]"), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	previous := stampFiles([]string{file, missing})
+	if previous[missing] != "" || previous[file] == "" {
+		t.Fatalf("wrong stamps returned.\nReturned : %v", previous)
+	}
+
+	if changed := changedFiles(previous, stampFiles([]string{file, missing})); len(changed) != 0 {
+		t.Fatalf("no change should be detected.\nReturned : %v", changed)
+	}
+
+	if err := os.WriteFile(file, []byte("[{}]"), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	if changed := changedFiles(previous, stampFiles([]string{file, missing})); len(changed) != 1 || changed[0] != file {
+		t.Fatalf("a change should be detected on the file '%s'.\nReturned : %v", file, changed)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	b, err := os.ReadFile(mappingFilePath)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", mappingFilePath, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "mapping.json")
+	if err = os.WriteFile(file, b, 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	client := newFakeClient()
+	options := &Options{
+		Name:         "watch",
+		Color:        "000000",
+		TriggerColor: "DD0000",
+		Width:        "400",
+		Height:       "400",
+		Spacer:       50,
+		StackHosts:   true,
+		Update:       true,
+	}
+	watchOptions := &WatchOptions{
+		Interval: 10 * time.Millisecond,
+		Debounce: 30 * time.Millisecond,
+	}
+
+	var out bytes.Buffer
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		watch(client, file, options, watchOptions, stop, &out, logging.NewLogger(logging.Critical))
+		close(done)
+	}()
+
+	// The map is built before monitoring the file
+	waitForLinks(t, client, "watch", 2)
+
+	// An invalid file is reported without stopping the monitoring
+	if err = os.WriteFile(file, []byte("[{\"local_host\": "), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// The existing map is updated once the file is fixed
+	mappings := make([]json.RawMessage, 0)
+	if err = json.Unmarshal(b, &mappings); err != nil {
+		t.Fatalf("error while decoding the file '%s'.\nReason : %v", mappingFilePath, err)
+	}
+
+	fixed, err := json.Marshal(mappings[:1])
+	if err != nil {
+		t.Fatalf("error while encoding the mappings.\nReason : %v", err)
+	}
+
+	if err = os.WriteFile(file, fixed, 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	waitForLinks(t, client, "watch", 1)
+
+	close(stop)
+	<-done
+
+	if len(client.Maps) != 1 {
+		t.Fatalf("the existing map should be updated instead of creating a new map.\nExpected : 1\nReturned : %d", len(client.Maps))
+	}
+
+	if strings.Count(out.String(), "map 'watch' built") < 2 {
+		t.Fatalf("each build should be reported.\nReturned : %s", out.String())
+	}
+}
+
+func TestRunWatchFailOptions(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+
+	if err := RunWatch(mappingFilePath, &Options{}, &WatchOptions{}, stop, &bytes.Buffer{}, nil); err == nil {
+		t.Fatalf("an error should be returned when the interval is not set")
+	}
+
+	if err := RunWatch(mappingFilePath, &Options{}, &WatchOptions{Interval: time.Second, Debounce: -1}, stop, &bytes.Buffer{}, nil); err == nil {
+		t.Fatalf("an error should be returned when the debounce delay is negative")
+	}
+}
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// dotQuote is used to quote a string before writing it to a DOT document.
+func dotQuote(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+	return fmt.Sprintf("\"%s\"", r.Replace(s))
+}
+
+// writeDotNode is used to write the declaration of a node to a DOT document.
+func writeDotNode(buf *bytes.Buffer, indent string, n *Node) {
+	attributes := []string{
+		fmt.Sprintf("label=%s", dotQuote(n.Host)),
+	}
+
+	if n.Image != "" {
+		attributes = append(attributes, fmt.Sprintf("image_name=%s", dotQuote(n.Image)))
+	}
+
+	fmt.Fprintf(buf, "%s%s [%s];\n", indent, dotQuote(n.Host), strings.Join(attributes, ", "))
+}
+
+// DOT is used to convert the topology to a Graphviz DOT document.
+// Host groups are drawn as clusters and interfaces names are used as edges label.
+func DOT(t *Topology) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "graph %s {\n", dotQuote(t.Name))
+	buf.WriteString("  node [shape=box];\n")
+
+	names, nodes := t.Clusters()
+	for i, name := range names {
+		fmt.Fprintf(&buf, "  subgraph %s {\n", dotQuote(fmt.Sprintf("cluster_%d", i)))
+		fmt.Fprintf(&buf, "    label=%s;\n", dotQuote(name))
+
+		for _, n := range nodes[name] {
+			writeDotNode(&buf, "    ", n)
+		}
+
+		buf.WriteString("  }\n")
+	}
+
+	for _, n := range nodes[""] {
+		writeDotNode(&buf, "  ", n)
+	}
+
+	for _, e := range t.Edges {
+		attributes := make([]string, 0)
+		if label := e.Label(); label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=%s", dotQuote(label)))
+		}
+
+		if e.LocalTrigger != "" {
+			attributes = append(attributes, fmt.Sprintf("local_trigger=%s", dotQuote(e.LocalTrigger)))
+		}
+
+		if e.RemoteTrigger != "" {
+			attributes = append(attributes, fmt.Sprintf("remote_trigger=%s", dotQuote(e.RemoteTrigger)))
+		}
+
+		fmt.Fprintf(&buf, "  %s -- %s", dotQuote(e.LocalHost), dotQuote(e.RemoteHost))
+		if len(attributes) > 0 {
+			fmt.Fprintf(&buf, " [%s]", strings.Join(attributes, ", "))
+		}
+
+		buf.WriteString(";\n")
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
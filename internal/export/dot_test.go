@@ -0,0 +1,25 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	out := string(DOT(newTestingTopology(t)))
+
+	expected := []string{
+		"graph \"test-topology\" {",
+		"label=\"DC1\";",
+		"\"router-1\" [label=\"router-1\", image_name=\"Router_(64)\"];",
+		"\"isp \\\"A\\\"\" [label=\"isp \\\"A\\\"\", image_name=\"Cloud_(24)\"];",
+		"\"router-1\" -- \"router-2\" [label=\"eth0 - eth1\", local_trigger=\"Interface eth0(): Link down\", remote_trigger=\"Interface eth1(): Link down\"];",
+		"\"router-2\" -- \"isp \\\"A\\\"\";",
+	}
+
+	for _, value := range expected {
+		if !strings.Contains(out, value) {
+			t.Fatalf("the DOT document does not contain the expected value.\nExpected : %s\nReturned : %s", value, out)
+		}
+	}
+}
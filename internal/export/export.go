@@ -0,0 +1,20 @@
+package export
+
+import "fmt"
+
+// Formats define the list of supported export formats.
+var Formats = []string{"dot", "mermaid", "graphml"}
+
+// Format is used to convert the topology to the given format.
+func Format(t *Topology, format string) ([]byte, error) {
+	switch format {
+	case "dot":
+		return DOT(t), nil
+	case "mermaid":
+		return Mermaid(t), nil
+	case "graphml":
+		return GraphML(t)
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s', supported formats are %v", format, Formats)
+	}
+}
@@ -0,0 +1,25 @@
+package export
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	topology := newTestingTopology(t)
+
+	for _, format := range Formats {
+		b, err := Format(topology, format)
+		if err != nil {
+			t.Fatalf("error while executing Format function with format '%s'.\nReason : %v", format, err)
+		}
+
+		if len(b) == 0 {
+			t.Fatalf("an empty document was returned for the format '%s'", format)
+		}
+	}
+}
+
+func TestFormatUnsupported(t *testing.T) {
+	_, err := Format(newTestingTopology(t), "svg")
+	if err == nil {
+		t.Fatal("an error should be returned when the format is not supported")
+	}
+}
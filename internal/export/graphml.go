@@ -0,0 +1,152 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// graphMLKeys define the attributes declared in the GraphML document.
+var graphMLKeys = []*graphMLKey{
+	{Id: "label", For: "node", Name: "label", Type: "string"},
+	{Id: "hostid", For: "node", Name: "hostid", Type: "string"},
+	{Id: "image", For: "node", Name: "image", Type: "string"},
+	{Id: "groups", For: "node", Name: "groups", Type: "string"},
+	{Id: "elabel", For: "edge", Name: "label", Type: "string"},
+	{Id: "local_interface", For: "edge", Name: "local_interface", Type: "string"},
+	{Id: "remote_interface", For: "edge", Name: "remote_interface", Type: "string"},
+	{Id: "local_trigger", For: "edge", Name: "local_trigger", Type: "string"},
+	{Id: "remote_trigger", For: "edge", Name: "remote_trigger", Type: "string"},
+}
+
+type graphMLDocument struct {
+	XMLName xml.Name      `xml:"graphml"`
+	Xmlns   string        `xml:"xmlns,attr"`
+	Keys    []*graphMLKey `xml:"key"`
+	Graph   *graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	Id   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	Id          string         `xml:"id,attr"`
+	EdgeDefault string         `xml:"edgedefault,attr"`
+	Nodes       []*graphMLNode `xml:"node"`
+	Edges       []*graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	Id    string         `xml:"id,attr"`
+	Data  []*graphMLData `xml:"data"`
+	Graph *graphMLGraph  `xml:"graph,omitempty"`
+}
+
+type graphMLEdge struct {
+	Id     string         `xml:"id,attr"`
+	Source string         `xml:"source,attr"`
+	Target string         `xml:"target,attr"`
+	Data   []*graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// appendData is used to add an attribute to a node or an edge, empty values are skipped.
+func appendData(data []*graphMLData, key string, value string) []*graphMLData {
+	if value == "" {
+		return data
+	}
+
+	return append(data, &graphMLData{
+		Key:   key,
+		Value: value,
+	})
+}
+
+// GraphML is used to convert the topology to a GraphML document (yEd, Gephi, etc.).
+// Host groups are represented as nested graphs and interfaces names are used as edges label.
+func GraphML(t *Topology) ([]byte, error) {
+	root := &graphMLGraph{
+		Id:          "G",
+		EdgeDefault: "undirected",
+		Nodes:       make([]*graphMLNode, 0),
+		Edges:       make([]*graphMLEdge, 0),
+	}
+
+	ids := make(map[string]string, 0)
+	for i, n := range t.Nodes {
+		ids[n.Host] = fmt.Sprintf("n%d", i)
+	}
+
+	newNode := func(n *Node) *graphMLNode {
+		data := make([]*graphMLData, 0)
+		data = appendData(data, "label", n.Host)
+		data = appendData(data, "hostid", n.Id)
+		data = appendData(data, "image", n.Image)
+		data = appendData(data, "groups", strings.Join(n.Groups, ","))
+
+		return &graphMLNode{
+			Id:   ids[n.Host],
+			Data: data,
+		}
+	}
+
+	names, nodes := t.Clusters()
+	for i, name := range names {
+		id := fmt.Sprintf("g%d", i)
+		group := &graphMLNode{
+			Id:   id,
+			Data: appendData(make([]*graphMLData, 0), "label", name),
+			Graph: &graphMLGraph{
+				Id:          fmt.Sprintf("%s:", id),
+				EdgeDefault: "undirected",
+				Nodes:       make([]*graphMLNode, 0),
+			},
+		}
+
+		for _, n := range nodes[name] {
+			group.Graph.Nodes = append(group.Graph.Nodes, newNode(n))
+		}
+
+		root.Nodes = append(root.Nodes, group)
+	}
+
+	for _, n := range nodes[""] {
+		root.Nodes = append(root.Nodes, newNode(n))
+	}
+
+	for i, e := range t.Edges {
+		data := make([]*graphMLData, 0)
+		data = appendData(data, "elabel", e.Label())
+		data = appendData(data, "local_interface", e.LocalInterface)
+		data = appendData(data, "remote_interface", e.RemoteInterface)
+		data = appendData(data, "local_trigger", e.LocalTrigger)
+		data = appendData(data, "remote_trigger", e.RemoteTrigger)
+
+		root.Edges = append(root.Edges, &graphMLEdge{
+			Id:     fmt.Sprintf("e%d", i),
+			Source: ids[e.LocalHost],
+			Target: ids[e.RemoteHost],
+			Data:   data,
+		})
+	}
+
+	b, err := xml.MarshalIndent(&graphMLDocument{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys:  graphMLKeys,
+		Graph: root,
+	}, "", "  ")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
@@ -0,0 +1,36 @@
+package export
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestGraphML(t *testing.T) {
+	b, err := GraphML(newTestingTopology(t))
+	if err != nil {
+		t.Fatalf("error while executing GraphML function.\nReason : %v", err)
+	}
+
+	doc := &graphMLDocument{}
+	if err = xml.Unmarshal(b, doc); err != nil {
+		t.Fatalf("the GraphML document is not a valid XML document.\nReason : %v", err)
+	}
+
+	// Two groups and the unknown host
+	if len(doc.Graph.Nodes) != 3 {
+		t.Fatalf("wrong number of top-level nodes returned.\nExpected : 3\nReturned : %d", len(doc.Graph.Nodes))
+	}
+
+	if doc.Graph.Nodes[0].Graph == nil || len(doc.Graph.Nodes[0].Graph.Nodes) != 1 {
+		t.Fatal("the hosts of the first group were not nested in the group node")
+	}
+
+	if len(doc.Graph.Edges) != 2 {
+		t.Fatalf("wrong number of edges returned.\nExpected : 2\nReturned : %d", len(doc.Graph.Edges))
+	}
+
+	edge := doc.Graph.Edges[0]
+	if edge.Source != "n0" || edge.Target != "n1" || edge.Data[0].Value != "eth0 - eth1" {
+		t.Fatalf("wrong edge returned.\nExpected : n0 -- n1 (eth0 - eth1)\nReturned : %s -- %s (%s)", edge.Source, edge.Target, edge.Data[0].Value)
+	}
+}
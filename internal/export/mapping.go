@@ -0,0 +1,373 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// interfacePattern is used to infer the name of an interface from the description of a trigger (ex: 'Interface Gi0/1(uplink): Link down').
+var interfacePattern = regexp.MustCompile(`(?i)interface\s+([^\s(:]+)`)
+
+// MapExport define the mappings and the layout built from an existing map.
+type MapExport struct {
+	Mappings []*zbxmap.Mapping
+	Layout   *zbxmap.Layout
+	// Stacked is set to false if an host is used by more than one element, the map must then be built without stacking the hosts.
+	Stacked bool
+	// Warnings contains the parts of the map that could not be exported as is.
+	Warnings []string
+}
+
+// exportedElement define a map element once the object it references was retrieved.
+type exportedElement struct {
+	elementType string
+	name        string
+	image       string
+	label       string
+	// hostId is the id of the host referenced by the element (host and trigger elements).
+	hostId string
+	// triggerId and pattern are the id and the description of the trigger referenced by a trigger element.
+	triggerId string
+	pattern   string
+	x         int64
+	y         int64
+}
+
+// mapObjects define the names of the objects referenced by a map, indexed by id.
+type mapObjects struct {
+	hosts    map[string]string
+	groups   map[string]string
+	maps     map[string]string
+	triggers map[string]*api.Trigger
+	images   map[string]string
+}
+
+// inferInterface is used to retrieve the name of the interface referenced by the given trigger description, empty if none was found.
+func inferInterface(description string) string {
+	match := interfacePattern.FindStringSubmatch(description)
+	if len(match) < 2 {
+		return ""
+	}
+
+	return match[1]
+}
+
+// getMapObjects is used to retrieve the hosts, host groups, maps, triggers and images referenced by the elements and links of the given map.
+func getMapObjects(client api.ZabbixAPI, m *api.Map) (*mapObjects, error) {
+	ids := make(map[string][]string, 0)
+	images := make([]string, 0)
+
+	for _, element := range m.Elements {
+		if id := zbxmap.GetElementObjectId(element); id != "" {
+			t := zbxmap.ElementTypeOf(element.ElementType)
+			ids[t] = append(ids[t], id)
+		}
+
+		if element.IconIdOff != "" && !utils.Contains(images, element.IconIdOff) {
+			images = append(images, element.IconIdOff)
+		}
+	}
+
+	for _, link := range m.Links {
+		for _, trigger := range link.LinkTriggers {
+			ids[zbxmap.ElementTrigger] = append(ids[zbxmap.ElementTrigger], trigger.TriggerId)
+		}
+	}
+
+	out := &mapObjects{
+		hosts:    make(map[string]string, 0),
+		groups:   make(map[string]string, 0),
+		maps:     make(map[string]string, 0),
+		triggers: make(map[string]*api.Trigger, 0),
+		images:   make(map[string]string, 0),
+	}
+
+	if len(ids[zbxmap.ElementTrigger]) > 0 {
+		triggers, err := client.GetTriggersById(ids[zbxmap.ElementTrigger])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, trigger := range triggers {
+			out.triggers[trigger.Id] = trigger
+			for _, host := range trigger.Hosts {
+				out.hosts[host.Id] = host.Host
+			}
+		}
+	}
+
+	if len(ids[zbxmap.ElementHost]) > 0 {
+		hosts, err := client.GetHostsById(ids[zbxmap.ElementHost])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, host := range hosts {
+			out.hosts[host.Id] = host.Host
+		}
+	}
+
+	if len(ids[zbxmap.ElementHostGroup]) > 0 {
+		groups, err := client.GetHostGroupsById(ids[zbxmap.ElementHostGroup])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			out.groups[group.Id] = group.Name
+		}
+	}
+
+	if len(ids[zbxmap.ElementMap]) > 0 {
+		maps, err := client.GetMapsById(ids[zbxmap.ElementMap])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range maps {
+			out.maps[m.Id] = m.Name
+		}
+	}
+
+	if len(images) > 0 {
+		list, err := client.GetImagesData(images)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, image := range list {
+			out.images[image.Id] = image.Name
+		}
+	}
+
+	return out, nil
+}
+
+// parsePosition is used to convert the position of an element, empty values are placed at 0.
+func parsePosition(x string, y string) (int64, int64, error) {
+	values := make([]int64, 0)
+
+	for _, value := range []string{x, y} {
+		if value == "" {
+			values = append(values, 0)
+			continue
+		}
+
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values[0], values[1], nil
+}
+
+// missingTriggerWarning is used to build the warning reported when no trigger is attached to an host element of a link.
+func missingTriggerWarning(local *exportedElement, remote *exportedElement, e *exportedElement) string {
+	return fmt.Sprintf("no trigger is attached to the host '%s' on the link between '%s' and '%s', the trigger pattern must be completed", e.name, local.name, remote.name)
+}
+
+// exportElement is used to retrieve the name used in the mappings for the given map element.
+// An error is returned if the object referenced by the element was not found.
+func exportElement(objects *mapObjects, element *exportedElement, objectId string, selementId string) error {
+	switch element.elementType {
+	case zbxmap.ElementHost:
+		element.hostId = objectId
+		element.name = objects.hosts[objectId]
+	case zbxmap.ElementTrigger:
+		trigger := objects.triggers[objectId]
+		if trigger == nil || len(trigger.Hosts) == 0 {
+			return failure.Errorf(failure.NotFound, "the trigger '%s' referenced by the element '%s' was not found", objectId, selementId)
+		}
+
+		element.triggerId = objectId
+		element.hostId = trigger.Hosts[0].Id
+		element.name = trigger.Hosts[0].Host
+		element.pattern = trigger.Description
+	case zbxmap.ElementHostGroup:
+		element.name = objects.groups[objectId]
+	case zbxmap.ElementMap:
+		element.name = objects.maps[objectId]
+	case zbxmap.ElementImage:
+		// Image elements are not linked to a Zabbix object, their label is used as name
+		element.name = element.label
+		element.label = ""
+		if element.name == "" {
+			element.name = fmt.Sprintf("image-%s", selementId)
+		}
+	default:
+		return fmt.Errorf("the element '%s' uses an unsupported element type", selementId)
+	}
+
+	if element.name == "" {
+		return failure.Errorf(failure.NotFound, "the %s '%s' referenced by the element '%s' was not found", element.elementType, objectId, selementId)
+	}
+
+	return nil
+}
+
+// FromMap is used to build the mappings and the layout reproducing the given map.
+// Each link is converted to a mapping, the triggers of the link are used as trigger patterns and to infer the interfaces.
+// Elements not linked to any other element cannot be expressed as a mapping and are reported in the warnings.
+func FromMap(client api.ZabbixAPI, m *api.Map) (*MapExport, error) {
+	objects, err := getMapObjects(client, m)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &MapExport{
+		Mappings: make([]*zbxmap.Mapping, 0),
+		Layout: &zbxmap.Layout{
+			Width:    m.Width,
+			Height:   m.Height,
+			Elements: make([]*zbxmap.LayoutElement, 0),
+		},
+		Stacked:  true,
+		Warnings: make([]string, 0),
+	}
+
+	elements := make(map[string]*exportedElement, 0)
+	hostElements := make(map[string]int, 0)
+
+	for _, element := range m.Elements {
+		e := &exportedElement{
+			elementType: zbxmap.ElementTypeOf(element.ElementType),
+			image:       objects.images[element.IconIdOff],
+			label:       element.Label,
+		}
+
+		x, y, err := parsePosition(element.X, element.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid position for the element '%s'.\nReason : %v", element.Id, err)
+		}
+
+		e.x = x
+		e.y = y
+
+		if err = exportElement(objects, e, zbxmap.GetElementObjectId(element), element.Id); err != nil {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("%v, the links of the element are not exported", err))
+			continue
+		}
+
+		if e.elementType == zbxmap.ElementHost {
+			hostElements[e.hostId]++
+			if hostElements[e.hostId] > 1 {
+				out.Stacked = false
+			}
+		}
+
+		elements[element.Id] = e
+	}
+
+	linked := make([]string, 0)
+	for _, link := range m.Links {
+		local := elements[link.SelementId1]
+		remote := elements[link.SelementId2]
+		if local == nil || remote == nil {
+			continue
+		}
+
+		mapping := &zbxmap.Mapping{
+			LocalHost:            local.name,
+			LocalTriggerPattern:  local.pattern,
+			LocalImage:           local.image,
+			LocalLabel:           local.label,
+			RemoteHost:           remote.name,
+			RemoteImage:          remote.image,
+			RemoteLabel:          remote.label,
+			RemoteTriggerPattern: remote.pattern,
+		}
+
+		if local.elementType != zbxmap.ElementHost {
+			mapping.LocalType = local.elementType
+		}
+
+		if remote.elementType != zbxmap.ElementHost {
+			mapping.RemoteType = remote.elementType
+		}
+
+		if link.Color != "" && link.Color != "000000" {
+			mapping.Color = link.Color
+		}
+
+		// Attach each trigger of the link to the element of its host
+		for _, linkTrigger := range link.LinkTriggers {
+			trigger := objects.triggers[linkTrigger.TriggerId]
+			if trigger == nil || len(trigger.Hosts) == 0 {
+				out.Warnings = append(out.Warnings, fmt.Sprintf("the trigger '%s' of the link '%s' was not found", linkTrigger.TriggerId, link.Id))
+				continue
+			}
+
+			hostId := trigger.Hosts[0].Id
+			switch {
+			case local.triggerId == trigger.Id || remote.triggerId == trigger.Id:
+				// The trigger is already referenced by a trigger element
+			case local.elementType == zbxmap.ElementHost && local.hostId == hostId && mapping.LocalTriggerPattern == "":
+				mapping.LocalTriggerPattern = trigger.Description
+			case remote.elementType == zbxmap.ElementHost && remote.hostId == hostId && mapping.RemoteTriggerPattern == "":
+				mapping.RemoteTriggerPattern = trigger.Description
+			default:
+				out.Warnings = append(out.Warnings, fmt.Sprintf("the trigger '%s' of the link between '%s' and '%s' cannot be attached to one of the elements", trigger.Description, local.name, remote.name))
+				continue
+			}
+
+			if linkTrigger.Color != "" && linkTrigger.Color != "DD0000" {
+				mapping.TriggerColor = linkTrigger.Color
+			}
+		}
+
+		mapping.LocalInterface = inferInterface(mapping.LocalTriggerPattern)
+		mapping.RemoteInterface = inferInterface(mapping.RemoteTriggerPattern)
+
+		if local.elementType == zbxmap.ElementHost && mapping.LocalTriggerPattern == "" {
+			out.Warnings = append(out.Warnings, missingTriggerWarning(local, remote, local))
+		}
+
+		if remote.elementType == zbxmap.ElementHost && mapping.RemoteTriggerPattern == "" {
+			out.Warnings = append(out.Warnings, missingTriggerWarning(local, remote, remote))
+		}
+
+		out.Mappings = append(out.Mappings, mapping)
+
+		// Stacked hosts use a single element, otherwise each mapping uses its own elements
+		for _, id := range []string{link.SelementId1, link.SelementId2} {
+			if out.Stacked && utils.Contains(linked, id) {
+				continue
+			}
+
+			linked = append(linked, id)
+			out.Layout.Elements = append(out.Layout.Elements, layoutElement(elements[id]))
+		}
+	}
+
+	for _, element := range m.Elements {
+		if e := elements[element.Id]; e != nil && !utils.Contains(linked, element.Id) {
+			out.Warnings = append(out.Warnings, fmt.Sprintf("the %s element '%s' is not linked to any other element and cannot be exported", e.elementType, e.name))
+		}
+	}
+
+	return out, nil
+}
+
+// layoutElement is used to build the layout element placing the given element.
+func layoutElement(e *exportedElement) *zbxmap.LayoutElement {
+	out := &zbxmap.LayoutElement{
+		Name: e.name,
+		X:    e.x,
+		Y:    e.y,
+	}
+
+	if e.elementType != zbxmap.ElementHost {
+		out.Type = e.elementType
+	}
+
+	return out
+}
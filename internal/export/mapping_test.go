@@ -0,0 +1,195 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// newTestingMap is used to create a map with two linked routers, a router linked to an host group, an image and an isolated host.
+func newTestingMap() (*fake.Client, *api.Map) {
+	client := fake.NewClient().
+		AddHost("1", "router-1").
+		AddHost("2", "router-2").
+		AddHost("3", "router-3").
+		AddTrigger("1", "21", "Interface Gi0/1(uplink): Link down").
+		AddTrigger("2", "22", "Interface eth1(): Link down").
+		AddHostGroup("1", "41", "Servers").
+		AddImage("11", "Router_(64)").
+		AddImage("12", "Cloud_(64)")
+
+	m := &api.Map{Id: "5"}
+	m.Name = "dc1"
+	m.Width = "1200"
+	m.Height = "600"
+	m.Elements = []*zabbixgosdk.MapElement{
+		{Id: "101", ElementType: zabbixgosdk.MapHost, Elements: []zabbixgosdk.MapElementHost{{Id: "1"}}, IconIdOff: "11", X: "100", Y: "50"},
+		{Id: "102", ElementType: zabbixgosdk.MapHost, Elements: []zabbixgosdk.MapElementHost{{Id: "2"}}, IconIdOff: "11", X: "300", Y: "50"},
+		{Id: "103", ElementType: zabbixgosdk.MapHostGroup, Elements: []zbxmap.MapElementHostGroup{{Id: "41"}}, IconIdOff: "11", Label: "{HOST.NAME}", X: "100", Y: "250"},
+		{Id: "104", ElementType: zabbixgosdk.MapImage, IconIdOff: "12", Label: "Internet", X: "500", Y: "50"},
+		{Id: "105", ElementType: zabbixgosdk.MapHost, Elements: []zabbixgosdk.MapElementHost{{Id: "3"}}, IconIdOff: "11", X: "500", Y: "250"},
+	}
+	m.Links = []*zabbixgosdk.MapLink{
+		{
+			Id:          "201",
+			SelementId1: "101",
+			SelementId2: "102",
+			Color:       "00AA00",
+			LinkTriggers: []*zabbixgosdk.MapLinkTrigger{
+				{TriggerId: "22", Color: "FF0000"},
+				{TriggerId: "21", Color: "FF0000"},
+			},
+		},
+		{Id: "202", SelementId1: "101", SelementId2: "103", Color: "000000"},
+		{Id: "203", SelementId1: "102", SelementId2: "104"},
+	}
+
+	return client, m
+}
+
+func TestInferInterface(t *testing.T) {
+	tests := map[string]string{
+		"Interface Gi0/1(uplink): Link down":   "Gi0/1",
+		"interface eth0: High bandwidth usage": "eth0",
+		"Interface ge-0/0/1(): Link down":      "ge-0/0/1",
+		"High CPU utilization":                 "",
+		"":                                     "",
+	}
+
+	for description, expected := range tests {
+		if out := inferInterface(description); out != expected {
+			t.Fatalf("wrong interface returned for the description '%s'.\nExpected : %s\nReturned : %s", description, expected, out)
+		}
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	client, m := newTestingMap()
+
+	out, err := FromMap(client, m)
+	if err != nil {
+		t.Fatalf("error while executing FromMap function.\nReason : %v", err)
+	}
+
+	if len(out.Mappings) != 3 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 3\nReturned : %d", len(out.Mappings))
+	}
+
+	first := out.Mappings[0]
+	if first.LocalHost != "router-1" || first.RemoteHost != "router-2" || first.LocalImage != "Router_(64)" {
+		t.Fatalf("wrong endpoints returned for the first mapping.\nReturned : %+v", first)
+	}
+
+	// The triggers are attached to the element of their host, whatever their order in the link
+	if first.LocalTriggerPattern != "Interface Gi0/1(uplink): Link down" || first.RemoteTriggerPattern != "Interface eth1(): Link down" {
+		t.Fatalf("wrong trigger patterns returned.\nReturned : %+v", first)
+	}
+
+	if first.LocalInterface != "Gi0/1" || first.RemoteInterface != "eth1" {
+		t.Fatalf("wrong interfaces returned.\nExpected : Gi0/1 - eth1\nReturned : %s - %s", first.LocalInterface, first.RemoteInterface)
+	}
+
+	if first.Color != "00AA00" || first.TriggerColor != "FF0000" {
+		t.Fatalf("wrong colors returned.\nExpected : 00AA00 - FF0000\nReturned : %s - %s", first.Color, first.TriggerColor)
+	}
+
+	second := out.Mappings[1]
+	if second.RemoteHost != "Servers" || second.RemoteType != zbxmap.ElementHostGroup || second.RemoteLabel != "{HOST.NAME}" || second.Color != "" {
+		t.Fatalf("wrong host group mapping returned.\nReturned : %+v", second)
+	}
+
+	third := out.Mappings[2]
+	if third.RemoteHost != "Internet" || third.RemoteType != zbxmap.ElementImage || third.RemoteLabel != "" || third.RemoteImage != "Cloud_(64)" {
+		t.Fatalf("wrong image mapping returned.\nReturned : %+v", third)
+	}
+
+	if !out.Stacked {
+		t.Fatal("the map should be built with stacked hosts")
+	}
+
+	names := make([]string, 0)
+	for _, e := range out.Layout.Elements {
+		names = append(names, e.Name)
+	}
+
+	if strings.Join(names, ",") != "router-1,router-2,Servers,Internet" {
+		t.Fatalf("wrong layout returned.\nExpected : router-1,router-2,Servers,Internet\nReturned : %s", strings.Join(names, ","))
+	}
+
+	if out.Layout.Width != "1200" || out.Layout.Elements[3].X != 500 || out.Layout.Elements[3].Type != zbxmap.ElementImage {
+		t.Fatalf("wrong layout returned.\nReturned : %+v", out.Layout)
+	}
+
+	// router-1 has no trigger on its link to the host group, router-3 is not linked
+	warnings := strings.Join(out.Warnings, "\n")
+	for _, expected := range []string{"no trigger is attached to the host 'router-1'", "no trigger is attached to the host 'router-2'", "'router-3' is not linked"} {
+		if !strings.Contains(warnings, expected) {
+			t.Fatalf("missing warning '%s'.\nReturned : %s", expected, warnings)
+		}
+	}
+}
+
+func TestFromMapUnstacked(t *testing.T) {
+	client, m := newTestingMap()
+	m.Elements = append(m.Elements, &zabbixgosdk.MapElement{Id: "106", ElementType: zabbixgosdk.MapHost, Elements: []zabbixgosdk.MapElementHost{{Id: "1"}}, IconIdOff: "11", X: "100", Y: "450"})
+	m.Links[1].SelementId1 = "106"
+
+	out, err := FromMap(client, m)
+	if err != nil {
+		t.Fatalf("error while executing FromMap function.\nReason : %v", err)
+	}
+
+	if out.Stacked {
+		t.Fatal("the map should be built without stacked hosts when an host is used by more than one element")
+	}
+
+	// Each mapping uses its own elements
+	if len(out.Layout.Elements) != 6 || out.Layout.Elements[2].Name != "router-1" || out.Layout.Elements[2].Y != 450 {
+		t.Fatalf("wrong layout returned.\nReturned : %+v", out.Layout.Elements)
+	}
+}
+
+func TestFromMapTriggerElement(t *testing.T) {
+	client, m := newTestingMap()
+	m.Elements[0] = &zabbixgosdk.MapElement{Id: "101", ElementType: zabbixgosdk.MapTrigger, Elements: []zbxmap.MapElementTrigger{{Id: "21"}}, IconIdOff: "11"}
+	m.Links = m.Links[:1]
+
+	out, err := FromMap(client, m)
+	if err != nil {
+		t.Fatalf("error while executing FromMap function.\nReason : %v", err)
+	}
+
+	mapping := out.Mappings[0]
+	if mapping.LocalType != zbxmap.ElementTrigger || mapping.LocalHost != "router-1" || mapping.LocalTriggerPattern != "Interface Gi0/1(uplink): Link down" {
+		t.Fatalf("wrong trigger mapping returned.\nReturned : %+v", mapping)
+	}
+
+	for _, warning := range out.Warnings {
+		if strings.Contains(warning, "cannot be attached") {
+			t.Fatalf("the trigger of the element should not be reported.\nReturned : %s", warning)
+		}
+	}
+}
+
+func TestFromMapMissingObject(t *testing.T) {
+	client, m := newTestingMap()
+	m.Elements[1].Elements = []zabbixgosdk.MapElementHost{{Id: "99"}}
+
+	out, err := FromMap(client, m)
+	if err != nil {
+		t.Fatalf("error while executing FromMap function.\nReason : %v", err)
+	}
+
+	// The links of router-2 are not exported
+	if len(out.Mappings) != 1 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 1\nReturned : %d", len(out.Mappings))
+	}
+
+	if !strings.Contains(strings.Join(out.Warnings, "\n"), "the host '99' referenced by the element '102' was not found") {
+		t.Fatalf("missing warning for the host not found.\nReturned : %v", out.Warnings)
+	}
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// mermaidQuote is used to quote a string before writing it to a Mermaid document.
+func mermaidQuote(s string) string {
+	r := strings.NewReplacer("\"", "#quot;", "\n", " ")
+
+	return fmt.Sprintf("\"%s\"", r.Replace(s))
+}
+
+// Mermaid is used to convert the topology to a Mermaid graph.
+// Host groups are drawn as subgraphs and interfaces names are used as edges label.
+func Mermaid(t *Topology) []byte {
+	// Mermaid identifiers cannot contain special characters, each host is identified by its position in the topology
+	ids := make(map[string]string, 0)
+	for i, n := range t.Nodes {
+		ids[n.Host] = fmt.Sprintf("n%d", i)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("graph LR\n")
+
+	names, nodes := t.Clusters()
+	for i, name := range names {
+		fmt.Fprintf(&buf, "  subgraph g%d [%s]\n", i, mermaidQuote(name))
+
+		for _, n := range nodes[name] {
+			fmt.Fprintf(&buf, "    %s[%s]\n", ids[n.Host], mermaidQuote(n.Host))
+		}
+
+		buf.WriteString("  end\n")
+	}
+
+	for _, n := range nodes[""] {
+		fmt.Fprintf(&buf, "  %s[%s]\n", ids[n.Host], mermaidQuote(n.Host))
+	}
+
+	for _, e := range t.Edges {
+		if label := e.Label(); label != "" {
+			fmt.Fprintf(&buf, "  %s ---|%s| %s\n", ids[e.LocalHost], mermaidQuote(label), ids[e.RemoteHost])
+		} else {
+			fmt.Fprintf(&buf, "  %s --- %s\n", ids[e.LocalHost], ids[e.RemoteHost])
+		}
+	}
+
+	return buf.Bytes()
+}
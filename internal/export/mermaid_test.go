@@ -0,0 +1,24 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMermaid(t *testing.T) {
+	out := string(Mermaid(newTestingTopology(t)))
+
+	expected := []string{
+		"graph LR\n",
+		"  subgraph g0 [\"DC1\"]\n    n0[\"router-1\"]\n  end\n",
+		"  n2[\"isp #quot;A#quot;\"]\n",
+		"  n0 ---|\"eth0 - eth1\"| n1\n",
+		"  n1 --- n2\n",
+	}
+
+	for _, value := range expected {
+		if !strings.Contains(out, value) {
+			t.Fatalf("the Mermaid document does not contain the expected value.\nExpected : %s\nReturned : %s", value, out)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package export
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// Topology define the hosts and links described by a list of mappings, completed with the information retrieved from the Zabbix server.
+type Topology struct {
+	Name  string
+	Nodes []*Node
+	Edges []*Edge
+}
+
+// Node define an host of the topology.
+type Node struct {
+	// Host is the technical name of the host.
+	Host string
+	// Id is the hostid of the host, empty if the host was not found on the server.
+	Id    string
+	Image string
+	// Groups contains the name of the host groups of the host sorted alphabetically.
+	Groups []string
+}
+
+// Edge define a link between two hosts of the topology.
+type Edge struct {
+	LocalHost       string
+	LocalInterface  string
+	RemoteHost      string
+	RemoteInterface string
+	// LocalTrigger is the description of the trigger matching the local pattern, empty if no trigger was found.
+	LocalTrigger string
+	// RemoteTrigger is the description of the trigger matching the remote pattern, empty if no trigger was found.
+	RemoteTrigger string
+}
+
+// Label is used to retrieve the label of the edge built from the interfaces names.
+func (e *Edge) Label() string {
+	interfaces := make([]string, 0)
+
+	if e.LocalInterface != "" {
+		interfaces = append(interfaces, e.LocalInterface)
+	}
+
+	if e.RemoteInterface != "" {
+		interfaces = append(interfaces, e.RemoteInterface)
+	}
+
+	return strings.Join(interfaces, " - ")
+}
+
+// Cluster is used to retrieve the group used to cluster the node.
+// A node belonging to multiple groups is placed in the first one, an empty string is returned for nodes without group.
+func (n *Node) Cluster() string {
+	if len(n.Groups) == 0 {
+		return ""
+	}
+
+	return n.Groups[0]
+}
+
+// Clusters is used to retrieve the list of clusters of the topology with the nodes placed in each one.
+// Clusters are sorted alphabetically, nodes without group are stored under an empty key.
+func (t *Topology) Clusters() ([]string, map[string][]*Node) {
+	names := make([]string, 0)
+	nodes := make(map[string][]*Node, 0)
+
+	for _, n := range t.Nodes {
+		cluster := n.Cluster()
+		if _, exist := nodes[cluster]; !exist && cluster != "" {
+			names = append(names, cluster)
+		}
+
+		nodes[cluster] = append(nodes[cluster], n)
+	}
+
+	sort.Strings(names)
+
+	return names, nodes
+}
+
+// NewTopology is used to create the topology described by the given mappings.
+// Hosts, host groups and triggers are resolved using the given client and resolve options (the technical name is used if nil).
+// Hosts not found on the server are kept without id or groups.
+func NewTopology(client api.ZabbixAPI, name string, mappings []*zbxmap.Mapping, options *api.ResolveOptions) (*Topology, error) {
+	t := &Topology{
+		Name:  name,
+		Nodes: make([]*Node, 0),
+		Edges: make([]*Edge, 0),
+	}
+
+	// Keep the order of appearance of the hosts to produce a stable output
+	nodes := make(map[string]*Node, 0)
+	names := make([]string, 0)
+	addNode := func(host string, image string) {
+		if _, exist := nodes[host]; exist {
+			return
+		}
+
+		nodes[host] = &Node{
+			Host:   host,
+			Image:  image,
+			Groups: make([]string, 0),
+		}
+
+		names = append(names, host)
+		t.Nodes = append(t.Nodes, nodes[host])
+	}
+
+	// Only the host and trigger elements reference an host on the server
+	hosts := make([]string, 0)
+	for _, m := range mappings {
+		addNode(m.LocalHost, m.LocalImage)
+		addNode(m.RemoteHost, m.RemoteImage)
+
+		if zbxmap.UsesTrigger(m.LocalType) && !utils.Contains(hosts, m.LocalHost) {
+			hosts = append(hosts, m.LocalHost)
+		}
+
+		if zbxmap.UsesTrigger(m.RemoteType) && !utils.Contains(hosts, m.RemoteHost) {
+			hosts = append(hosts, m.RemoteHost)
+		}
+	}
+
+	resolutions, err := api.ResolveHosts(client, hosts, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts {
+		n := nodes[host]
+		n.Id = resolutions[host].Id
+		if n.Id == "" {
+			continue
+		}
+
+		groups, err := client.GetHostGroups(n.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			if !utils.Contains(n.Groups, group.Name) {
+				n.Groups = append(n.Groups, group.Name)
+			}
+		}
+
+		sort.Strings(n.Groups)
+	}
+
+	for _, m := range mappings {
+		edge := &Edge{
+			LocalHost:       m.LocalHost,
+			LocalInterface:  m.LocalInterface,
+			RemoteHost:      m.RemoteHost,
+			RemoteInterface: m.RemoteInterface,
+		}
+
+		edge.LocalTrigger, err = getTriggerDescription(client, nodes[m.LocalHost].Id, m.LocalTriggerPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		edge.RemoteTrigger, err = getTriggerDescription(client, nodes[m.RemoteHost].Id, m.RemoteTriggerPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		t.Edges = append(t.Edges, edge)
+	}
+
+	return t, nil
+}
+
+// getTriggerDescription is used to retrieve the description of the trigger matching the given pattern.
+// An empty string is returned if the host is unknown or if no trigger match the pattern.
+func getTriggerDescription(client api.ZabbixAPI, hostId string, pattern string) (string, error) {
+	if hostId == "" || pattern == "" {
+		return "", nil
+	}
+
+	triggers, err := client.GetTriggers(hostId, pattern)
+	if err != nil {
+		return "", err
+	}
+
+	if len(triggers) == 0 {
+		return "", nil
+	}
+
+	return triggers[0].Description, nil
+}
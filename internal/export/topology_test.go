@@ -0,0 +1,97 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// newTestingTopology is used to create a topology with two routers in the same group and an unknown host.
+func newTestingTopology(t *testing.T) *Topology {
+	client := fake.NewClient().
+		AddHost("1", "router-1").
+		AddHost("2", "router-2").
+		AddTrigger("1", "21", "Interface eth0(): Link down").
+		AddTrigger("2", "22", "Interface eth1(): Link down").
+		AddHostGroup("1", "41", "Routers").
+		AddHostGroup("1", "42", "DC1").
+		AddHostGroup("2", "41", "Routers")
+
+	topology, err := NewTopology(client, "test-topology", []*zbxmap.Mapping{
+		{
+			LocalHost:            "router-1",
+			LocalInterface:       "eth0",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Router_(64)",
+			RemoteHost:           "router-2",
+			RemoteInterface:      "eth1",
+			RemoteTriggerPattern: "Interface eth1(): Link down",
+			RemoteImage:          "Router_(64)",
+		},
+		{
+			LocalHost:   "router-2",
+			LocalImage:  "Router_(64)",
+			RemoteHost:  "isp \"A\"",
+			RemoteImage: "Cloud_(24)",
+		},
+	}, nil)
+
+	if err != nil {
+		t.Fatalf("error while executing NewTopology function.\nReason : %v", err)
+	}
+
+	return topology
+}
+
+func TestNewTopology(t *testing.T) {
+	topology := newTestingTopology(t)
+
+	if len(topology.Nodes) != 3 {
+		t.Fatalf("wrong number of nodes returned.\nExpected : 3\nReturned : %d", len(topology.Nodes))
+	}
+
+	if topology.Nodes[0].Id != "1" || topology.Nodes[2].Id != "" {
+		t.Fatalf("wrong hosts id returned.\nReturned : %s, %s", topology.Nodes[0].Id, topology.Nodes[2].Id)
+	}
+
+	if len(topology.Nodes[0].Groups) != 2 || topology.Nodes[0].Groups[0] != "DC1" {
+		t.Fatalf("wrong host groups returned.\nExpected : [DC1 Routers]\nReturned : %v", topology.Nodes[0].Groups)
+	}
+
+	if len(topology.Edges) != 2 {
+		t.Fatalf("wrong number of edges returned.\nExpected : 2\nReturned : %d", len(topology.Edges))
+	}
+
+	if topology.Edges[0].LocalTrigger != "Interface eth0(): Link down" || topology.Edges[0].RemoteTrigger != "Interface eth1(): Link down" {
+		t.Fatalf("wrong triggers returned.\nReturned : %s, %s", topology.Edges[0].LocalTrigger, topology.Edges[0].RemoteTrigger)
+	}
+
+	if topology.Edges[1].LocalTrigger != "" {
+		t.Fatalf("no trigger should be returned without pattern.\nReturned : %s", topology.Edges[1].LocalTrigger)
+	}
+}
+
+func TestEdgeLabel(t *testing.T) {
+	e := &Edge{LocalInterface: "eth0", RemoteInterface: "eth1"}
+	if e.Label() != "eth0 - eth1" {
+		t.Fatalf("wrong label returned.\nExpected : eth0 - eth1\nReturned : %s", e.Label())
+	}
+
+	e = &Edge{RemoteInterface: "eth1"}
+	if e.Label() != "eth1" {
+		t.Fatalf("wrong label returned.\nExpected : eth1\nReturned : %s", e.Label())
+	}
+}
+
+func TestClusters(t *testing.T) {
+	names, nodes := newTestingTopology(t).Clusters()
+
+	if len(names) != 2 || names[0] != "DC1" || names[1] != "Routers" {
+		t.Fatalf("wrong clusters returned.\nExpected : [DC1 Routers]\nReturned : %v", names)
+	}
+
+	if len(nodes[""]) != 1 || nodes[""][0].Host != "isp \"A\"" {
+		t.Fatalf("the unknown host should not be placed in a cluster.\nReturned : %v", nodes[""])
+	}
+}
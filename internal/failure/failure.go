@@ -0,0 +1,172 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Kind define the class of an error, used to select the exit code of the CLI.
+type Kind int
+
+const (
+	// Unknown is the kind of the errors not classified.
+	Unknown Kind = iota
+	// Config is the kind of the errors caused by an invalid flag, environment variable or configuration file.
+	Config
+	// Auth is the kind of the errors returned when authenticating to the Zabbix API.
+	Auth
+	// NotFound is the kind of the errors returned when an object (host, trigger, map, etc.) is not found on the server.
+	NotFound
+	// API is the kind of the errors returned when the Zabbix API is unreachable or rejects a request.
+	API
+	// Validation is the kind of the errors caused by an invalid mapping file or map definition.
+	Validation
+)
+
+// Kinds contains the supported kinds, ordered by exit code.
+var Kinds = []Kind{Unknown, Config, Auth, NotFound, API, Validation}
+
+// String is used to retrieve the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Config:
+		return "configuration error"
+	case Auth:
+		return "authentication error"
+	case NotFound:
+		return "not found"
+	case API:
+		return "API error"
+	case Validation:
+		return "validation error"
+	default:
+		return "error"
+	}
+}
+
+// ExitCode is used to retrieve the exit code of the CLI for the errors of the kind.
+func (k Kind) ExitCode() int {
+	switch k {
+	case Config:
+		return 2
+	case Auth:
+		return 3
+	case NotFound:
+		return 4
+	case API:
+		return 5
+	case Validation:
+		return 6
+	default:
+		return 1
+	}
+}
+
+// Error define an error of a given kind, the stack trace of its creation is kept for debugging.
+type Error struct {
+	Kind  Kind
+	Err   error
+	stack []uintptr
+}
+
+// Error is used to retrieve the message of the wrapped error.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap is used to retrieve the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New is used to associate the given error to the given kind, nil is returned if the error is nil.
+// An error already associated to a kind is returned unchanged, the kind set closest to the cause is kept.
+func New(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	stack := make([]uintptr, 32)
+	n := runtime.Callers(2, stack)
+
+	return &Error{
+		Kind:  kind,
+		Err:   err,
+		stack: stack[:n],
+	}
+}
+
+// Errorf is used to create a new error of the given kind, the message is formatted using fmt.Errorf.
+func Errorf(kind Kind, format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+
+	stack := make([]uintptr, 32)
+	n := runtime.Callers(2, stack)
+
+	// Keep the kind of a wrapped error
+	var e *Error
+	if errors.As(err, &e) {
+		kind = e.Kind
+	}
+
+	return &Error{
+		Kind:  kind,
+		Err:   err,
+		stack: stack[:n],
+	}
+}
+
+// KindOf is used to retrieve the kind of the given error, Unknown is returned if the error is not associated to a kind.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	return Unknown
+}
+
+// Trace is used to retrieve the details of the given error : the kind, the messages of the wrapped errors and the stack trace of its creation.
+func Trace(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "kind : %s (exit code %d)\n", KindOf(err), KindOf(err).ExitCode())
+
+	// The classified errors are skipped, their message is the message of the wrapped error
+	i := 0
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if _, ok := current.(*Error); ok {
+			continue
+		}
+
+		fmt.Fprintf(&b, "cause %d : %s\n", i, strings.ReplaceAll(current.Error(), "\n", " "))
+		i++
+	}
+
+	var e *Error
+	if errors.As(err, &e) && len(e.stack) > 0 {
+		b.WriteString("stack :\n")
+
+		frames := runtime.CallersFrames(e.stack)
+		for {
+			frame, more := frames.Next()
+			fmt.Fprintf(&b, "    %s\n        %s:%d\n", frame.Function, frame.File, frame.Line)
+
+			if !more {
+				break
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	codes := make(map[int]Kind)
+
+	for _, kind := range Kinds {
+		code := kind.ExitCode()
+		if other, ok := codes[code]; ok {
+			t.Fatalf("the exit code %d is shared by the kinds '%s' and '%s'", code, other, kind)
+		}
+
+		codes[code] = kind
+	}
+
+	if Unknown.ExitCode() != 1 {
+		t.Fatalf("wrong exit code returned for unknown errors.\nExpected : 1\nReturned : %d", Unknown.ExitCode())
+	}
+}
+
+func TestNew(t *testing.T) {
+	if err := New(Config, nil); err != nil {
+		t.Fatalf("nil should be returned for a nil error.\nReturned : %v", err)
+	}
+
+	cause := fmt.Errorf("connection refused")
+	err := New(API, cause)
+
+	if err.Error() != cause.Error() {
+		t.Fatalf("wrong message returned.\nExpected : %s\nReturned : %s", cause.Error(), err.Error())
+	}
+
+	if !errors.Is(err, cause) {
+		t.Fatalf("the cause should be wrapped by the error")
+	}
+
+	if KindOf(err) != API {
+		t.Fatalf("wrong kind returned.\nExpected : %s\nReturned : %s", API, KindOf(err))
+	}
+
+	// The kind set closest to the cause is kept
+	if kind := KindOf(New(Validation, err)); kind != API {
+		t.Fatalf("wrong kind returned.\nExpected : %s\nReturned : %s", API, kind)
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	err := Errorf(NotFound, "no host found for name '%s'", "router-1")
+	if err.Error() != "no host found for name 'router-1'" {
+		t.Fatalf("wrong message returned.\nReturned : %s", err.Error())
+	}
+
+	if KindOf(err) != NotFound {
+		t.Fatalf("wrong kind returned.\nExpected : %s\nReturned : %s", NotFound, KindOf(err))
+	}
+
+	wrapped := Errorf(API, "error while building the map.\nReason : %w", err)
+	if KindOf(wrapped) != NotFound {
+		t.Fatalf("the kind of the wrapped error should be kept.\nExpected : %s\nReturned : %s", NotFound, KindOf(wrapped))
+	}
+
+	// Errors wrapped using fmt.Errorf keep their kind
+	if kind := KindOf(fmt.Errorf("error while building the map.\nReason : %w", err)); kind != NotFound {
+		t.Fatalf("wrong kind returned.\nExpected : %s\nReturned : %s", NotFound, kind)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	if kind := KindOf(fmt.Errorf("unexpected error")); kind != Unknown {
+		t.Fatalf("wrong kind returned.\nExpected : %s\nReturned : %s", Unknown, kind)
+	}
+
+	if kind := KindOf(nil); kind != Unknown {
+		t.Fatalf("wrong kind returned.\nExpected : %s\nReturned : %s", Unknown, kind)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	if out := Trace(nil); out != "" {
+		t.Fatalf("an empty string should be returned for a nil error.\nReturned : %s", out)
+	}
+
+	err := fmt.Errorf("error while building the map.\nReason : %w", New(Auth, fmt.Errorf("incorrect user name or password")))
+	out := Trace(err)
+
+	for _, expected := range []string{
+		"kind : authentication error (exit code 3)",
+		"cause 0 : error while building the map. Reason : incorrect user name or password",
+		"cause 1 : incorrect user name or password",
+		"stack :",
+		"failure.TestTrace",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("missing line in the trace.\nExpected : %s\nReturned :\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,666 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// Client is an in-memory implementation of the api.ZabbixAPI interface.
+// It can be used to run the map building process without a Zabbix server.
+type Client struct {
+	Hosts  []*api.Host
+	Images []*api.Image
+	// Interfaces contains the interfaces of all the hosts.
+	Interfaces []*api.HostInterface
+	// Triggers associate an hostid to the list of triggers configured for the host.
+	Triggers map[string][]*api.Trigger
+	// Items associate an hostid to the list of items configured for the host.
+	Items map[string][]*api.Item
+	// HostGroups associate an hostid to the list of groups of the host.
+	HostGroups map[string][]*api.HostGroup
+	// Maps contains the maps created using the CreateMap method.
+	Maps       []*zabbixgosdk.MapCreateParameters
+	Users      []*api.User
+	UserGroups []*api.UserGroup
+	LoggedOut  bool
+	// mutex is taken by every method to allow the maps to be built concurrently (see the build-all command and the daemon).
+	mutex sync.Mutex
+}
+
+// NewClient is used to create a new empty fake client.
+func NewClient() *Client {
+	return &Client{
+		Hosts:      make([]*api.Host, 0),
+		Images:     make([]*api.Image, 0),
+		Interfaces: make([]*api.HostInterface, 0),
+		Triggers:   make(map[string][]*api.Trigger, 0),
+		Items:      make(map[string][]*api.Item, 0),
+		HostGroups: make(map[string][]*api.HostGroup, 0),
+		Maps:       make([]*zabbixgosdk.MapCreateParameters, 0),
+		Users:      make([]*api.User, 0),
+		UserGroups: make([]*api.UserGroup, 0),
+	}
+}
+
+// AddHost is used to register a new host with the given id and technical name.
+func (c *Client) AddHost(id string, host string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Hosts = append(c.Hosts, &api.Host{
+		Id:   id,
+		Host: host,
+	})
+
+	return c
+}
+
+// getHost is used to retrieve the host with the given id, nil is returned if the host does not exist.
+func (c *Client) getHost(id string) *api.Host {
+	for _, host := range c.Hosts {
+		if host.Id == id {
+			return host
+		}
+	}
+
+	return nil
+}
+
+// SetHostName is used to set the visible name of the host with the given id.
+func (c *Client) SetHostName(id string, name string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if host := c.getHost(id); host != nil {
+		host.Name = name
+	}
+
+	return c
+}
+
+// AddHostTag is used to add a tag to the host with the given id.
+func (c *Client) AddHostTag(id string, tag string, value string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if host := c.getHost(id); host != nil {
+		host.Tags = append(host.Tags, &api.HostTag{
+			Tag:   tag,
+			Value: value,
+		})
+	}
+
+	return c
+}
+
+// AddHostTemplate is used to link a template to the host with the given id.
+func (c *Client) AddHostTemplate(id string, name string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if host := c.getHost(id); host != nil {
+		host.Templates = append(host.Templates, &api.Template{
+			Name: name,
+		})
+	}
+
+	return c
+}
+
+// SetHostInventoryType is used to set the inventory type of the host with the given id.
+func (c *Client) SetHostInventoryType(id string, value string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if host := c.getHost(id); host != nil {
+		host.Inventory = &api.HostInventory{
+			Type: value,
+		}
+	}
+
+	return c
+}
+
+// AddHostInterface is used to register a new interface for the host with the given id.
+func (c *Client) AddHostInterface(hostId string, ip string, dns string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Interfaces = append(c.Interfaces, &api.HostInterface{
+		Id:     strconv.Itoa(len(c.Interfaces) + 1),
+		HostId: hostId,
+		Ip:     ip,
+		Dns:    dns,
+	})
+
+	return c
+}
+
+// AddImage is used to register a new image with the given id and name.
+func (c *Client) AddImage(id string, name string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Images = append(c.Images, &api.Image{
+		Id:   id,
+		Name: name,
+	})
+
+	return c
+}
+
+// SetImageData is used to set the base64 encoded content of the image with the given id.
+func (c *Client) SetImageData(id string, data string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, image := range c.Images {
+		if image.Id == id {
+			image.Data = data
+		}
+	}
+
+	return c
+}
+
+// AddTrigger is used to register a new trigger for the host with the given id.
+func (c *Client) AddTrigger(hostId string, id string, description string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Triggers[hostId] = append(c.Triggers[hostId], &api.Trigger{
+		Id:          id,
+		Description: description,
+	})
+
+	return c
+}
+
+// AddItem is used to register a new item for the host with the given id.
+func (c *Client) AddItem(hostId string, id string, name string, key string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Items[hostId] = append(c.Items[hostId], &api.Item{
+		Id:   id,
+		Name: name,
+		Key:  key,
+	})
+
+	return c
+}
+
+// AddHostGroup is used to add the host with the given id to a host group.
+func (c *Client) AddHostGroup(hostId string, id string, name string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.HostGroups[hostId] = append(c.HostGroups[hostId], &api.HostGroup{
+		Id:   id,
+		Name: name,
+	})
+
+	return c
+}
+
+// AddUser is used to register a new user with the given id and username.
+func (c *Client) AddUser(id string, username string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Users = append(c.Users, &api.User{
+		Id:       id,
+		Username: username,
+	})
+
+	return c
+}
+
+// AddUserGroup is used to register a new user group with the given id and name.
+func (c *Client) AddUserGroup(id string, name string) *Client {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.UserGroups = append(c.UserGroups, &api.UserGroup{
+		Id:   id,
+		Name: name,
+	})
+
+	return c
+}
+
+// GetHosts is used to retrieve the hosts matching the given technical names.
+func (c *Client) GetHosts(names []string) ([]*api.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Host, 0)
+
+	for _, host := range c.Hosts {
+		if utils.Contains(names, host.Host) {
+			out = append(out, host)
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostsById is used to retrieve the hosts matching the given ids.
+func (c *Client) GetHostsById(ids []string) ([]*api.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Host, 0)
+
+	for _, host := range c.Hosts {
+		if utils.Contains(ids, host.Id) {
+			h := *host
+			h.HostGroups = append(make([]*api.HostGroup, 0), c.HostGroups[host.Id]...)
+			out = append(out, &h)
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostsByName is used to retrieve the hosts matching the given visible names.
+func (c *Client) GetHostsByName(names []string) ([]*api.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Host, 0)
+
+	for _, host := range c.Hosts {
+		if host.Name != "" && utils.Contains(names, host.Name) {
+			out = append(out, host)
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostsByTag is used to retrieve the hosts with the given tag set to one of the given values.
+func (c *Client) GetHostsByTag(tag string, values []string) ([]*api.Host, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Host, 0)
+
+	for _, host := range c.Hosts {
+		for _, t := range host.Tags {
+			if t.Tag == tag && utils.Contains(values, t.Value) {
+				out = append(out, host)
+				break
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostInterfaces is used to retrieve the host interfaces with an IP address or a DNS name matching one of the given addresses.
+func (c *Client) GetHostInterfaces(addresses []string) ([]*api.HostInterface, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.HostInterface, 0)
+
+	for _, i := range c.Interfaces {
+		if utils.Contains(addresses, i.Ip) || (i.Dns != "" && utils.Contains(addresses, i.Dns)) {
+			out = append(out, i)
+		}
+	}
+
+	return out, nil
+}
+
+// GetImages is used to retrieve the images matching the given names.
+func (c *Client) GetImages(names []string) ([]*api.Image, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Image, 0)
+
+	for _, image := range c.Images {
+		if utils.Contains(names, image.Name) {
+			out = append(out, image)
+		}
+	}
+
+	return out, nil
+}
+
+// GetImagesData is used to retrieve the images matching the given ids, including the content of each image.
+func (c *Client) GetImagesData(ids []string) ([]*api.Image, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Image, 0)
+
+	for _, image := range c.Images {
+		if utils.Contains(ids, image.Id) {
+			out = append(out, image)
+		}
+	}
+
+	return out, nil
+}
+
+// CreateImage is used to store a new image and return its generated id.
+func (c *Client) CreateImage(name string, data string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	last := 0
+	for _, image := range c.Images {
+		if image.Name == name {
+			return "", fmt.Errorf("the image '%s' already exists", name)
+		}
+
+		if id, err := strconv.Atoi(image.Id); err == nil && id > last {
+			last = id
+		}
+	}
+
+	id := strconv.Itoa(last + 1)
+	c.Images = append(c.Images, &api.Image{
+		Id:   id,
+		Name: name,
+		Data: data,
+	})
+
+	return id, nil
+}
+
+// UpdateImage is used to replace the content of the image with the given id.
+func (c *Client) UpdateImage(id string, data string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, image := range c.Images {
+		if image.Id == id {
+			image.Data = data
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no image found with the id '%s'", id)
+}
+
+// GetTriggers is used to retrieve the triggers of the given host matching the given description.
+// If the description is empty, all the triggers of the host are returned.
+func (c *Client) GetTriggers(hostId string, description string) ([]*api.Trigger, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Trigger, 0)
+
+	for _, trigger := range c.Triggers[hostId] {
+		if description == "" || trigger.Description == description {
+			out = append(out, trigger)
+		}
+	}
+
+	return out, nil
+}
+
+// GetTriggersById is used to retrieve the triggers matching the given ids, including the host of each trigger.
+func (c *Client) GetTriggersById(ids []string) ([]*api.Trigger, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Trigger, 0)
+
+	for _, host := range c.Hosts {
+		for _, trigger := range c.Triggers[host.Id] {
+			if utils.Contains(ids, trigger.Id) {
+				out = append(out, &api.Trigger{
+					Id:          trigger.Id,
+					Description: trigger.Description,
+					Hosts: []*api.Host{
+						{Id: host.Id, Host: host.Host},
+					},
+				})
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// GetItems is used to retrieve the items of the given host.
+func (c *Client) GetItems(hostId string) ([]*api.Item, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Item, 0)
+	out = append(out, c.Items[hostId]...)
+
+	return out, nil
+}
+
+// GetHostGroups is used to retrieve the host groups of the given host.
+func (c *Client) GetHostGroups(hostId string) ([]*api.HostGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.HostGroup, 0)
+	out = append(out, c.HostGroups[hostId]...)
+
+	return out, nil
+}
+
+// GetHostGroupsByName is used to retrieve the host groups matching the given names.
+func (c *Client) GetHostGroupsByName(names []string) ([]*api.HostGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.HostGroup, 0)
+	ids := make([]string, 0)
+
+	for _, groups := range c.HostGroups {
+		for _, g := range groups {
+			if utils.Contains(names, g.Name) && !utils.Contains(ids, g.Id) {
+				ids = append(ids, g.Id)
+				out = append(out, g)
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Id < out[j].Id
+	})
+
+	return out, nil
+}
+
+// GetHostGroupsById is used to retrieve the host groups matching the given ids.
+func (c *Client) GetHostGroupsById(ids []string) ([]*api.HostGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.HostGroup, 0)
+	found := make([]string, 0)
+
+	for _, groups := range c.HostGroups {
+		for _, g := range groups {
+			if utils.Contains(ids, g.Id) && !utils.Contains(found, g.Id) {
+				found = append(found, g.Id)
+				out = append(out, g)
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Id < out[j].Id
+	})
+
+	return out, nil
+}
+
+// GetMaps is used to retrieve the maps previously created matching the given names.
+// The id of each map is its position in the list of created maps.
+func (c *Client) GetMaps(names []string) ([]*api.Map, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Map, 0)
+
+	for i, m := range c.Maps {
+		if m != nil && utils.Contains(names, m.Name) {
+			out = append(out, &api.Map{
+				MapCreateParameters: *m,
+				Id:                  fmt.Sprintf("%d", i+1),
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// GetMapsById is used to retrieve the maps previously created matching the given ids.
+func (c *Client) GetMapsById(ids []string) ([]*api.Map, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Map, 0)
+
+	for i, m := range c.Maps {
+		id := fmt.Sprintf("%d", i+1)
+		if m != nil && utils.Contains(ids, id) {
+			out = append(out, &api.Map{
+				MapCreateParameters: *m,
+				Id:                  id,
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// SearchMaps is used to retrieve the maps previously created whose name starts with the given prefix.
+func (c *Client) SearchMaps(prefix string) ([]*api.Map, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.Map, 0)
+
+	for i, m := range c.Maps {
+		if m != nil && strings.HasPrefix(m.Name, prefix) {
+			out = append(out, &api.Map{
+				MapCreateParameters: *m,
+				Id:                  fmt.Sprintf("%d", i+1),
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// DeleteMaps is used to delete the maps with the given ids.
+// Deleted maps are kept as nil entries to preserve the id of the other maps.
+func (c *Client) DeleteMaps(ids []string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	indexes := make([]int, 0)
+
+	for _, id := range ids {
+		i, err := strconv.Atoi(id)
+		if err != nil || i < 1 || i > len(c.Maps) || c.Maps[i-1] == nil {
+			return fmt.Errorf("no map exists with the id '%s'", id)
+		}
+
+		indexes = append(indexes, i-1)
+	}
+
+	for _, i := range indexes {
+		c.Maps[i] = nil
+	}
+
+	return nil
+}
+
+// CreateMap is used to store the given map and return its generated id.
+func (c *Client) CreateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if m == nil {
+		return nil, fmt.Errorf("a nil map cannot be created")
+	}
+
+	c.Maps = append(c.Maps, m)
+
+	return []string{
+		fmt.Sprintf("%d", len(c.Maps)),
+	}, nil
+}
+
+// UpdateMap is used to replace the stored map with the id set in the given map.
+func (c *Client) UpdateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if m == nil {
+		return nil, fmt.Errorf("a nil map cannot be updated")
+	}
+
+	i, err := strconv.Atoi(m.Id)
+	if err != nil || i < 1 || i > len(c.Maps) || c.Maps[i-1] == nil {
+		return nil, fmt.Errorf("no map exists with the id '%s'", m.Id)
+	}
+
+	c.Maps[i-1] = m
+
+	return []string{m.Id}, nil
+}
+
+// GetUsers is used to retrieve the users matching the given usernames.
+func (c *Client) GetUsers(names []string) ([]*api.User, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.User, 0)
+
+	for _, u := range c.Users {
+		if utils.Contains(names, u.Username) {
+			out = append(out, u)
+		}
+	}
+
+	return out, nil
+}
+
+// GetUserGroups is used to retrieve the user groups matching the given names.
+func (c *Client) GetUserGroups(names []string) ([]*api.UserGroup, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make([]*api.UserGroup, 0)
+
+	for _, g := range c.UserGroups {
+		if utils.Contains(names, g.Name) {
+			out = append(out, g)
+		}
+	}
+
+	return out, nil
+}
+
+// Logout is used to mark the client as logged out.
+func (c *Client) Logout() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.LoggedOut = true
+
+	return nil
+}
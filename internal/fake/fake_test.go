@@ -0,0 +1,327 @@
+package fake
+
+import (
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+)
+
+// Ensure the fake client can be used in place of the SDK backed client.
+var _ api.ZabbixAPI = (*Client)(nil)
+
+func TestGetHosts(t *testing.T) {
+	c := NewClient().AddHost("1", "router-1").AddHost("2", "router-2")
+
+	hosts, err := c.GetHosts([]string{"router-2", "router-4"})
+	if err != nil {
+		t.Fatalf("error while executing GetHosts function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 1 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 1\nReturned : %d", len(hosts))
+	}
+
+	if hosts[0].Id != "2" {
+		t.Fatalf("wrong host returned.\nExpected : '2'\nReturned : %s", hosts[0].Id)
+	}
+}
+
+func TestGetImages(t *testing.T) {
+	c := NewClient().AddImage("1", "Firewall_(64)").AddImage("2", "Switch_(64)")
+
+	images, err := c.GetImages([]string{"Switch_(64)"})
+	if err != nil {
+		t.Fatalf("error while executing GetImages function.\nReason : %v", err)
+	}
+
+	if len(images) != 1 {
+		t.Fatalf("wrong number of images returned.\nExpected : 1\nReturned : %d", len(images))
+	}
+
+	if images[0].Id != "2" {
+		t.Fatalf("wrong image returned.\nExpected : '2'\nReturned : %s", images[0].Id)
+	}
+}
+
+func TestGetTriggers(t *testing.T) {
+	c := NewClient().AddTrigger("1", "11", "Interface eth0(): Link down").AddTrigger("1", "12", "Interface eth1(): Link down")
+
+	triggers, err := c.GetTriggers("1", "Interface eth1(): Link down")
+	if err != nil {
+		t.Fatalf("error while executing GetTriggers function.\nReason : %v", err)
+	}
+
+	if len(triggers) != 1 {
+		t.Fatalf("wrong number of triggers returned.\nExpected : 1\nReturned : %d", len(triggers))
+	}
+
+	if triggers[0].Id != "12" {
+		t.Fatalf("wrong trigger returned.\nExpected : '12'\nReturned : %s", triggers[0].Id)
+	}
+
+	triggers, err = c.GetTriggers("2", "Interface eth1(): Link down")
+	if err != nil {
+		t.Fatalf("error while executing GetTriggers function.\nReason : %v", err)
+	}
+
+	if len(triggers) != 0 {
+		t.Fatalf("no trigger should be returned for an unknown host.\nReturned : %d", len(triggers))
+	}
+}
+
+func TestCreateMap(t *testing.T) {
+	c := NewClient()
+
+	ids, err := c.CreateMap(&zabbixgosdk.MapCreateParameters{})
+	if err != nil {
+		t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+	}
+
+	if len(ids) != 1 || ids[0] != "1" {
+		t.Fatalf("wrong ids returned.\nExpected : [1]\nReturned : %v", ids)
+	}
+
+	if len(c.Maps) != 1 {
+		t.Fatalf("the map was not stored in the client.\nExpected : 1\nReturned : %d", len(c.Maps))
+	}
+}
+
+func TestCreateMapNil(t *testing.T) {
+	c := NewClient()
+
+	_, err := c.CreateMap(nil)
+	if err == nil {
+		t.Fatal("an error should be returned when a nil map is passed to the CreateMap function")
+	}
+}
+
+func TestCreateImage(t *testing.T) {
+	c := NewClient().AddImage("11", "Switch_(64)")
+
+	id, err := c.CreateImage("Router_(64)", "aW1hZ2U=")
+	if err != nil {
+		t.Fatalf("error while executing CreateImage function.\nReason : %v", err)
+	}
+
+	if id != "12" || len(c.Images) != 2 || c.Images[1].Data != "aW1hZ2U=" {
+		t.Fatalf("the image was not stored in the client.\nReturned : %s, %v", id, c.Images)
+	}
+
+	if _, err = c.CreateImage("Switch_(64)", "aW1hZ2U="); err == nil {
+		t.Fatal("an error should be returned when the image already exists")
+	}
+}
+
+func TestUpdateImage(t *testing.T) {
+	c := NewClient().AddImage("11", "Switch_(64)")
+
+	if err := c.UpdateImage("11", "aW1hZ2U="); err != nil {
+		t.Fatalf("error while executing UpdateImage function.\nReason : %v", err)
+	}
+
+	if c.Images[0].Data != "aW1hZ2U=" {
+		t.Fatalf("the content of the image was not updated.\nReturned : %s", c.Images[0].Data)
+	}
+
+	if err := c.UpdateImage("12", "aW1hZ2U="); err == nil {
+		t.Fatal("an error should be returned when the image does not exist")
+	}
+}
+
+func TestSearchDeleteMaps(t *testing.T) {
+	c := NewClient()
+	for _, name := range []string{"site-paris", "site-lyon", "core"} {
+		m := &zabbixgosdk.MapCreateParameters{}
+		m.Name = name
+
+		if _, err := c.CreateMap(m); err != nil {
+			t.Fatalf("error while executing CreateMap function.\nReason : %v", err)
+		}
+	}
+
+	maps, err := c.SearchMaps("site-")
+	if err != nil {
+		t.Fatalf("error while executing SearchMaps function.\nReason : %v", err)
+	}
+
+	if len(maps) != 2 || maps[1].Id != "2" {
+		t.Fatalf("wrong maps returned.\nReturned : %v", maps)
+	}
+
+	if err = c.DeleteMaps([]string{"1"}); err != nil {
+		t.Fatalf("error while executing DeleteMaps function.\nReason : %v", err)
+	}
+
+	// The ids of the other maps are kept
+	maps, err = c.GetMapsById([]string{"1", "3"})
+	if err != nil {
+		t.Fatalf("error while executing GetMapsById function.\nReason : %v", err)
+	}
+
+	if len(maps) != 1 || maps[0].Name != "core" {
+		t.Fatalf("wrong maps returned after the deletion.\nExpected : core\nReturned : %v", maps)
+	}
+
+	if err = c.DeleteMaps([]string{"1"}); err == nil {
+		t.Fatal("an error should be returned when deleting a map already deleted")
+	}
+}
+
+func TestLogout(t *testing.T) {
+	c := NewClient()
+
+	if err := c.Logout(); err != nil {
+		t.Fatalf("error while executing Logout function.\nReason : %v", err)
+	}
+
+	if !c.LoggedOut {
+		t.Fatal("the client was not marked as logged out")
+	}
+}
+
+func TestGetItems(t *testing.T) {
+	c := NewClient().AddItem("1", "31", "Interface eth0(): Operational status", "net.if.status[ifOperStatus.1]")
+
+	items, err := c.GetItems("1")
+	if err != nil {
+		t.Fatalf("error while executing GetItems function.\nReason : %v", err)
+	}
+
+	if len(items) != 1 || items[0].Key != "net.if.status[ifOperStatus.1]" {
+		t.Fatalf("wrong items returned.\nReturned : %v", items)
+	}
+}
+
+func TestGetHostGroups(t *testing.T) {
+	c := NewClient().AddHostGroup("1", "41", "Routers")
+
+	groups, err := c.GetHostGroups("1")
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroups function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Name != "Routers" {
+		t.Fatalf("wrong host groups returned.\nReturned : %v", groups)
+	}
+}
+
+func TestGetHostGroupsByName(t *testing.T) {
+	c := NewClient().
+		AddHostGroup("1", "41", "Routers").
+		AddHostGroup("2", "41", "Routers").
+		AddHostGroup("2", "42", "DC1")
+
+	groups, err := c.GetHostGroupsByName([]string{"Routers"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroupsByName function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Id != "41" {
+		t.Fatalf("wrong host groups returned.\nExpected : [41]\nReturned : %v", groups)
+	}
+}
+
+func TestGetHostGroupsById(t *testing.T) {
+	c := NewClient().
+		AddHostGroup("1", "42", "DC1").
+		AddHostGroup("2", "41", "Routers").
+		AddHostGroup("2", "42", "DC1")
+
+	groups, err := c.GetHostGroupsById([]string{"42", "41"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroupsById function.\nReason : %v", err)
+	}
+
+	if len(groups) != 2 || groups[0].Id != "41" || groups[1].Id != "42" {
+		t.Fatalf("wrong host groups returned.\nExpected : [41 42]\nReturned : %v", groups)
+	}
+}
+
+func TestGetTriggersById(t *testing.T) {
+	c := NewClient().
+		AddHost("1", "router-1").
+		AddTrigger("1", "21", "Interface eth0(): Link down").
+		AddTrigger("1", "22", "Interface eth1(): Link down")
+
+	triggers, err := c.GetTriggersById([]string{"22"})
+	if err != nil {
+		t.Fatalf("error while executing GetTriggersById function.\nReason : %v", err)
+	}
+
+	if len(triggers) != 1 || triggers[0].Id != "22" || triggers[0].Hosts[0].Host != "router-1" {
+		t.Fatalf("wrong triggers returned.\nReturned : %v", triggers)
+	}
+}
+
+func TestGetUsersAndUserGroups(t *testing.T) {
+	c := NewClient().
+		AddUser("1", "Admin").
+		AddUser("3", "noc").
+		AddUserGroup("15", "Network team")
+
+	users, err := c.GetUsers([]string{"noc"})
+	if err != nil {
+		t.Fatalf("error while executing GetUsers function.\nReason : %v", err)
+	}
+
+	if len(users) != 1 || users[0].Id != "3" {
+		t.Fatalf("wrong users returned.\nExpected : [3]\nReturned : %v", users)
+	}
+
+	groups, err := c.GetUserGroups([]string{"Network team", "unknown"})
+	if err != nil {
+		t.Fatalf("error while executing GetUserGroups function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Id != "15" {
+		t.Fatalf("wrong user groups returned.\nExpected : [15]\nReturned : %v", groups)
+	}
+}
+
+func TestGetHostsByName(t *testing.T) {
+	c := NewClient().AddHost("1", "router-1").AddHost("2", "router-2").SetHostName("2", "Router 2")
+
+	hosts, err := c.GetHostsByName([]string{"Router 2", "router-1"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostsByName function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 1 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 1\nReturned : %d", len(hosts))
+	}
+
+	if hosts[0].Id != "2" {
+		t.Fatalf("wrong host returned.\nExpected : '2'\nReturned : %s", hosts[0].Id)
+	}
+}
+
+func TestGetHostsByTag(t *testing.T) {
+	c := NewClient().AddHost("1", "router-1").AddHost("2", "router-2").AddHostTag("1", "sysName", "rt1").AddHostTag("2", "site", "rt1")
+
+	hosts, err := c.GetHostsByTag("sysName", []string{"rt1"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostsByTag function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 1 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 1\nReturned : %d", len(hosts))
+	}
+
+	if hosts[0].Id != "1" {
+		t.Fatalf("wrong host returned.\nExpected : '1'\nReturned : %s", hosts[0].Id)
+	}
+}
+
+func TestGetHostInterfaces(t *testing.T) {
+	c := NewClient().AddHostInterface("1", "10.0.0.1", "").AddHostInterface("2", "10.0.0.2", "router-2.example.com")
+
+	interfaces, err := c.GetHostInterfaces([]string{"router-2.example.com", "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostInterfaces function.\nReason : %v", err)
+	}
+
+	if len(interfaces) != 2 {
+		t.Fatalf("wrong number of interfaces returned.\nExpected : 2\nReturned : %d", len(interfaces))
+	}
+}
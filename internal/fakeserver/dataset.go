@@ -0,0 +1,167 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Dataset define the Zabbix objects exposed by the fake server.
+type Dataset struct {
+	Version    string       `json:"version"`
+	Users      []*User      `json:"users"`
+	Hosts      []*Host      `json:"hosts"`
+	Interfaces []*Interface `json:"interfaces"`
+	Images     []*Image     `json:"images"`
+	Triggers   []*Trigger   `json:"triggers"`
+	Items      []*Item      `json:"items"`
+	HostGroups []*HostGroup `json:"hostgroups"`
+	UserGroups []*UserGroup `json:"usergroups"`
+	Maps       []*Map       `json:"maps"`
+}
+
+// User define the credentials accepted by the user.login method.
+// The id and the username are returned by the user.get method.
+type User struct {
+	Id       string `json:"userid,omitempty"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// UserGroup define an user group returned by the usergroup.get method.
+type UserGroup struct {
+	Id   string `json:"usrgrpid"`
+	Name string `json:"name"`
+}
+
+// Host define an host returned by the host.get method.
+type Host struct {
+	Id   string     `json:"hostid"`
+	Host string     `json:"host"`
+	Name string     `json:"name"`
+	Tags []*HostTag `json:"tags,omitempty"`
+	// Templates and Inventory are returned by the selectParentTemplates and selectInventory parameters.
+	Templates []*Template       `json:"parentTemplates,omitempty"`
+	Inventory map[string]string `json:"inventory,omitempty"`
+}
+
+// Template define a template linked to an host.
+type Template struct {
+	Id   string `json:"templateid"`
+	Name string `json:"name"`
+}
+
+// HostTag define a tag of an host.
+type HostTag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// Interface define an host interface returned by the hostinterface.get method.
+type Interface struct {
+	Id     string `json:"interfaceid"`
+	HostId string `json:"hostid"`
+	Ip     string `json:"ip"`
+	Dns    string `json:"dns"`
+}
+
+// Image define an image returned by the image.get method.
+// The content of the image is only returned when the 'select_image' parameter is set.
+type Image struct {
+	Id   string `json:"imageid"`
+	Name string `json:"name"`
+	Data string `json:"image,omitempty"`
+}
+
+// Trigger define a trigger returned by the trigger.get method.
+type Trigger struct {
+	Id          string `json:"triggerid"`
+	Description string `json:"description"`
+	HostId      string `json:"hostid"`
+}
+
+// Item define an item returned by the item.get method.
+type Item struct {
+	Id     string `json:"itemid"`
+	Name   string `json:"name"`
+	Key    string `json:"key_"`
+	HostId string `json:"hostid"`
+}
+
+// HostGroup define an host group returned by the hostgroup.get method.
+// The list of hosts is only used to filter the groups and is not returned.
+type HostGroup struct {
+	Id      string   `json:"groupid"`
+	Name    string   `json:"name"`
+	HostIds []string `json:"hostids,omitempty"`
+}
+
+// Map define a map stored by the map.create and map.update methods.
+// The raw definition sent by the client is kept as is, only the id and name are extracted.
+type Map struct {
+	Id         string
+	Name       string
+	Definition map[string]json.RawMessage
+}
+
+// MarshalJSON is used to return the raw definition of the map with its id.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	out := make(map[string]json.RawMessage, len(m.Definition)+1)
+	for key, value := range m.Definition {
+		out[key] = value
+	}
+
+	id, err := json.Marshal(m.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	out["sysmapid"] = id
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON is used to load a map definition, extracting its id and name.
+func (m *Map) UnmarshalJSON(b []byte) error {
+	definition := make(map[string]json.RawMessage, 0)
+	if err := json.Unmarshal(b, &definition); err != nil {
+		return err
+	}
+
+	if raw, exist := definition["sysmapid"]; exist {
+		if err := json.Unmarshal(raw, &m.Id); err != nil {
+			return err
+		}
+	}
+
+	if raw, exist := definition["name"]; exist {
+		if err := json.Unmarshal(raw, &m.Name); err != nil {
+			return err
+		}
+	}
+
+	delete(definition, "sysmapid")
+	m.Definition = definition
+
+	return nil
+}
+
+// LoadDataset is used to read a dataset from the given JSON file.
+func LoadDataset(file string) (*Dataset, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := &Dataset{}
+	err = json.Unmarshal(b, dataset)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading dataset '%s'.\nReason : %v", file, err)
+	}
+
+	if dataset.Version == "" {
+		dataset.Version = "6.0.0"
+	}
+
+	return dataset, nil
+}
@@ -0,0 +1,58 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLoadDataset(t *testing.T) {
+	dataset, err := LoadDataset(datasetFilePath)
+	if err != nil {
+		t.Fatalf("error while executing LoadDataset function.\nReason : %v", err)
+	}
+
+	if len(dataset.Hosts) == 0 {
+		t.Fatal("no hosts were loaded from the dataset")
+	}
+
+	if len(dataset.Users) == 0 {
+		t.Fatal("no users were loaded from the dataset")
+	}
+}
+
+func TestLoadDatasetMissingFile(t *testing.T) {
+	dataset, err := LoadDataset("file-does-not-exist")
+	if err == nil {
+		t.Fatal("an error should be returned when the given file does not exist")
+	}
+
+	if dataset != nil {
+		t.Fatal("a nil pointer should be returned instead of *Dataset when the file does not exist")
+	}
+}
+
+func TestMapJSON(t *testing.T) {
+	m := &Map{}
+	err := json.Unmarshal([]byte(`{"sysmapid":"3","name":"test-map","width":"800"}`), m)
+	if err != nil {
+		t.Fatalf("error while decoding the map.\nReason : %v", err)
+	}
+
+	if m.Id != "3" || m.Name != "test-map" {
+		t.Fatalf("wrong id or name extracted.\nReturned : %s - %s", m.Id, m.Name)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("error while encoding the map.\nReason : %v", err)
+	}
+
+	out := make(map[string]string, 0)
+	if err = json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("error while decoding the encoded map.\nReason : %v", err)
+	}
+
+	if out["sysmapid"] != "3" || out["width"] != "800" {
+		t.Fatalf("wrong map encoded.\nReturned : %s", string(b))
+	}
+}
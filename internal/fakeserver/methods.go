@@ -0,0 +1,640 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// stringList is used to decode a parameter that can be either a string or a list of string.
+type stringList []string
+
+// UnmarshalJSON is used to decode either a string or a list of string.
+func (l *stringList) UnmarshalJSON(b []byte) error {
+	var value string
+	if err := json.Unmarshal(b, &value); err == nil {
+		*l = []string{value}
+		return nil
+	}
+
+	values := make([]string, 0)
+	if err := json.Unmarshal(b, &values); err != nil {
+		return err
+	}
+
+	*l = values
+
+	return nil
+}
+
+// getParameters define the common parameters supported by the get methods.
+type getParameters struct {
+	HostIds      stringList            `json:"hostids"`
+	ImageIds     stringList            `json:"imageids"`
+	SelectImage  bool                  `json:"select_image"`
+	MapIds       stringList            `json:"sysmapids"`
+	TriggerIds   stringList            `json:"triggerids"`
+	GroupIds     stringList            `json:"groupids"`
+	SelectHosts  json.RawMessage       `json:"selectHosts"`
+	SelectGroups json.RawMessage       `json:"selectHostGroups"`
+	Filter       map[string]stringList `json:"filter"`
+	Search       map[string]stringList `json:"search"`
+	SearchByAny  bool                  `json:"searchByAny"`
+	Tags         []*HostTag            `json:"tags"`
+}
+
+// decodeGetParameters is used to decode the parameters of a get method.
+func decodeGetParameters(params json.RawMessage) (*getParameters, *responseError) {
+	p := &getParameters{}
+	if len(params) == 0 {
+		return p, nil
+	}
+
+	if err := json.Unmarshal(params, p); err != nil {
+		return nil, invalidParams(err.Error())
+	}
+
+	return p, nil
+}
+
+// match is used to check if the given value respect the filter and search conditions set for the given field.
+func (p *getParameters) match(field string, value string) bool {
+	return p.matchAll(map[string]string{field: value})
+}
+
+// matchAll is used to check if the given values (field -> value) respect the filter and search conditions.
+// Like the Zabbix API, every filter must match, 'searchByAny' only allows one of the search conditions to match instead of all of them.
+func (p *getParameters) matchAll(values map[string]string) bool {
+	searched, matched := 0, 0
+
+	for field, value := range values {
+		if filter, exist := p.Filter[field]; exist && !utils.Contains(filter, value) {
+			return false
+		}
+
+		search, exist := p.Search[field]
+		if !exist {
+			continue
+		}
+
+		searched++
+		found := true
+		for _, v := range search {
+			if !strings.Contains(strings.ToLower(value), strings.ToLower(v)) {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			matched++
+		}
+	}
+
+	if p.SearchByAny {
+		return searched == 0 || matched > 0
+	}
+
+	return matched == searched
+}
+
+// invalidParams is used to create the error returned when the parameters of a request are invalid.
+func invalidParams(data string) *responseError {
+	return &responseError{
+		Code:    -32602,
+		Message: "Invalid params.",
+		Data:    data,
+	}
+}
+
+// apiInfoVersion is used to handle the apiinfo.version method.
+func apiInfoVersion(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	return s.dataset.Version, nil
+}
+
+// userLogin is used to handle the user.login method.
+// Both the 'username' and the deprecated 'user' parameters are supported.
+func userLogin(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p := struct {
+		Username string `json:"username"`
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}{}
+
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err.Error())
+	}
+
+	if p.Username == "" {
+		p.Username = p.User
+	}
+
+	for _, u := range s.dataset.Users {
+		if u.Username == p.Username && u.Password == p.Password {
+			s.lastToken++
+			token := fmt.Sprintf("fake-token-%d", s.lastToken)
+			s.tokens[token] = true
+
+			return token, nil
+		}
+	}
+
+	return nil, &responseError{
+		Code:    -32500,
+		Message: "Application error.",
+		Data:    "Incorrect user name or password or account is temporarily blocked.",
+	}
+}
+
+// userLogout is used to handle the user.logout method.
+func userLogout(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	return true, nil
+}
+
+// hostGet is used to handle the host.get method.
+func hostGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*hostResult, 0)
+	for _, h := range s.dataset.Hosts {
+		if len(p.HostIds) > 0 && !utils.Contains(p.HostIds, h.Id) {
+			continue
+		}
+
+		if len(p.Tags) > 0 && !matchTags(h.Tags, p.Tags) {
+			continue
+		}
+
+		if !p.match("host", h.Host) || !p.match("name", h.Name) {
+			continue
+		}
+
+		r := &hostResult{Host: h}
+		if len(p.SelectGroups) > 0 {
+			r.HostGroups = make([]*HostGroup, 0)
+			for _, g := range s.dataset.HostGroups {
+				if utils.Contains(g.HostIds, h.Id) {
+					r.HostGroups = append(r.HostGroups, &HostGroup{Id: g.Id, Name: g.Name})
+				}
+			}
+		}
+
+		out = append(out, r)
+	}
+
+	return out, nil
+}
+
+// hostResult define an host returned by the host.get method, the host groups are only set when requested with 'selectHostGroups'.
+type hostResult struct {
+	*Host
+	HostGroups []*HostGroup `json:"hostgroups,omitempty"`
+}
+
+// matchTags is used to check if one of the tags is equal to one of the filters (evaltype 'Or', operator 'Equals').
+func matchTags(tags []*HostTag, filters []*HostTag) bool {
+	for _, f := range filters {
+		for _, t := range tags {
+			if t.Tag == f.Tag && t.Value == f.Value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hostInterfaceGet is used to handle the hostinterface.get method.
+func hostInterfaceGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Interface, 0)
+	for _, i := range s.dataset.Interfaces {
+		if len(p.HostIds) > 0 && !utils.Contains(p.HostIds, i.HostId) {
+			continue
+		}
+
+		if p.matchAll(map[string]string{"ip": i.Ip, "dns": i.Dns}) {
+			out = append(out, i)
+		}
+	}
+
+	return out, nil
+}
+
+// imageGet is used to handle the image.get method.
+func imageGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Image, 0)
+	for _, i := range s.dataset.Images {
+		if len(p.ImageIds) > 0 && !utils.Contains(p.ImageIds, i.Id) {
+			continue
+		}
+
+		if !p.match("name", i.Name) {
+			continue
+		}
+
+		image := *i
+		if !p.SelectImage {
+			image.Data = ""
+		}
+
+		out = append(out, &image)
+	}
+
+	return out, nil
+}
+
+// imageParameters define the parameters of the image.create and image.update methods.
+type imageParameters struct {
+	Id        string `json:"imageid"`
+	Name      string `json:"name"`
+	ImageType int    `json:"imagetype"`
+	Image     string `json:"image"`
+}
+
+// imageCreate is used to handle the image.create method.
+func imageCreate(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p := &imageParameters{}
+	if err := json.Unmarshal(params, p); err != nil {
+		return nil, invalidParams(err.Error())
+	}
+
+	if p.Name == "" || p.Image == "" {
+		return nil, invalidParams("Invalid parameter \"/1\": the parameters \"name\" and \"image\" are required.")
+	}
+
+	last := 0
+	for _, i := range s.dataset.Images {
+		if i.Name == p.Name {
+			return nil, invalidParams(fmt.Sprintf("Image \"%s\" already exists.", p.Name))
+		}
+
+		last = maxId(last, i.Id)
+	}
+
+	image := &Image{
+		Id:   strconv.Itoa(last + 1),
+		Name: p.Name,
+		Data: p.Image,
+	}
+	s.dataset.Images = append(s.dataset.Images, image)
+
+	return map[string][]string{
+		"imageids": {image.Id},
+	}, nil
+}
+
+// imageUpdate is used to handle the image.update method.
+func imageUpdate(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p := &imageParameters{}
+	if err := json.Unmarshal(params, p); err != nil {
+		return nil, invalidParams(err.Error())
+	}
+
+	for _, i := range s.dataset.Images {
+		if i.Id != p.Id {
+			continue
+		}
+
+		if p.Name != "" {
+			i.Name = p.Name
+		}
+
+		if p.Image != "" {
+			i.Data = p.Image
+		}
+
+		return map[string][]string{
+			"imageids": {i.Id},
+		}, nil
+	}
+
+	return nil, invalidParams("No permissions to referred object or it does not exist!")
+}
+
+// triggerGet is used to handle the trigger.get method.
+func triggerGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*triggerResult, 0)
+	for _, t := range s.dataset.Triggers {
+		if len(p.HostIds) > 0 && !utils.Contains(p.HostIds, t.HostId) {
+			continue
+		}
+
+		if len(p.TriggerIds) > 0 && !utils.Contains(p.TriggerIds, t.Id) {
+			continue
+		}
+
+		if !p.match("description", t.Description) {
+			continue
+		}
+
+		r := &triggerResult{Trigger: t}
+		if len(p.SelectHosts) > 0 {
+			r.Hosts = make([]*hostReference, 0)
+			if h := s.findHost(t.HostId); h != nil {
+				r.Hosts = append(r.Hosts, &hostReference{Id: h.Id, Host: h.Host})
+			}
+		}
+
+		out = append(out, r)
+	}
+
+	return out, nil
+}
+
+// triggerResult define a trigger returned by the trigger.get method, the hosts are only set when requested with 'selectHosts'.
+type triggerResult struct {
+	*Trigger
+	Hosts []*hostReference `json:"hosts,omitempty"`
+}
+
+// hostReference define an host returned with another object.
+type hostReference struct {
+	Id   string `json:"hostid"`
+	Host string `json:"host"`
+}
+
+// findHost is used to retrieve the host with the given id, nil is returned if the host does not exist.
+func (s *Server) findHost(id string) *Host {
+	for _, h := range s.dataset.Hosts {
+		if h.Id == id {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// itemGet is used to handle the item.get method.
+func itemGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Item, 0)
+	for _, i := range s.dataset.Items {
+		if len(p.HostIds) > 0 && !utils.Contains(p.HostIds, i.HostId) {
+			continue
+		}
+
+		if p.match("name", i.Name) && p.match("key_", i.Key) {
+			out = append(out, i)
+		}
+	}
+
+	return out, nil
+}
+
+// hostGroupGet is used to handle the hostgroup.get method.
+func hostGroupGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*HostGroup, 0)
+	for _, g := range s.dataset.HostGroups {
+		if len(p.HostIds) > 0 && !containsAny(g.HostIds, p.HostIds) {
+			continue
+		}
+
+		if len(p.GroupIds) > 0 && !utils.Contains(p.GroupIds, g.Id) {
+			continue
+		}
+
+		if p.match("name", g.Name) {
+			out = append(out, &HostGroup{
+				Id:   g.Id,
+				Name: g.Name,
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// userReference define an user returned by the user.get method (the password is not returned).
+type userReference struct {
+	Id       string `json:"userid"`
+	Username string `json:"username"`
+}
+
+// userGet is used to handle the user.get method.
+func userGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*userReference, 0)
+	for _, u := range s.dataset.Users {
+		if p.match("username", u.Username) {
+			out = append(out, &userReference{
+				Id:       u.Id,
+				Username: u.Username,
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// userGroupGet is used to handle the usergroup.get method.
+func userGroupGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*UserGroup, 0)
+	for _, g := range s.dataset.UserGroups {
+		if p.match("name", g.Name) {
+			out = append(out, g)
+		}
+	}
+
+	return out, nil
+}
+
+// containsAny is used to check if at least one of the values is present in the list.
+func containsAny(list []string, values []string) bool {
+	for _, v := range values {
+		if utils.Contains(list, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeMaps is used to decode the maps passed to the map.create and map.update methods.
+// Both a single object and a list of objects are supported.
+func decodeMaps(params json.RawMessage) ([]*Map, *responseError) {
+	maps := make([]*Map, 0)
+	if err := json.Unmarshal(params, &maps); err == nil {
+		return maps, nil
+	}
+
+	m := &Map{}
+	if err := json.Unmarshal(params, m); err != nil {
+		return nil, invalidParams(err.Error())
+	}
+
+	return append(maps, m), nil
+}
+
+// mapCreate is used to handle the map.create method.
+func mapCreate(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	maps, err := decodeMaps(params)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+	for _, m := range maps {
+		if m.Name == "" {
+			return nil, invalidParams("Invalid parameter \"/1\": the parameter \"name\" is missing.")
+		}
+
+		for _, existing := range s.dataset.Maps {
+			if existing.Name == m.Name {
+				return nil, invalidParams(fmt.Sprintf("Map \"%s\" already exists.", m.Name))
+			}
+		}
+
+		s.lastId++
+		m.Id = strconv.Itoa(s.lastId)
+		s.dataset.Maps = append(s.dataset.Maps, m)
+		ids = append(ids, m.Id)
+	}
+
+	return map[string][]string{
+		"sysmapids": ids,
+	}, nil
+}
+
+// mapUpdate is used to handle the map.update method.
+// Properties sent by the client replace the properties of the stored map.
+func mapUpdate(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	maps, err := decodeMaps(params)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0)
+	for _, m := range maps {
+		existing := s.findMap(m.Id)
+		if existing == nil {
+			return nil, invalidParams("No permissions to referred object or it does not exist!")
+		}
+
+		if existing.Definition == nil {
+			existing.Definition = make(map[string]json.RawMessage, 0)
+		}
+
+		for key, value := range m.Definition {
+			existing.Definition[key] = value
+		}
+
+		if m.Name != "" {
+			existing.Name = m.Name
+		}
+
+		ids = append(ids, existing.Id)
+	}
+
+	return map[string][]string{
+		"sysmapids": ids,
+	}, nil
+}
+
+// mapGet is used to handle the map.get method.
+// The stored definitions are returned as is, whatever the select parameters passed.
+func mapGet(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	p, err := decodeGetParameters(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Map, 0)
+	for _, m := range s.dataset.Maps {
+		if len(p.MapIds) > 0 && !utils.Contains(p.MapIds, m.Id) {
+			continue
+		}
+
+		if p.match("name", m.Name) {
+			out = append(out, m)
+		}
+	}
+
+	return out, nil
+}
+
+// mapDelete is used to handle the map.delete method.
+func mapDelete(s *Server, params json.RawMessage) (interface{}, *responseError) {
+	ids := make([]string, 0)
+	if err := json.Unmarshal(params, &ids); err != nil {
+		return nil, invalidParams(err.Error())
+	}
+
+	for _, id := range ids {
+		if s.findMap(id) == nil {
+			return nil, invalidParams("No permissions to referred object or it does not exist!")
+		}
+	}
+
+	for _, id := range ids {
+		for i, m := range s.dataset.Maps {
+			if m.Id == id {
+				s.dataset.Maps = append(s.dataset.Maps[:i], s.dataset.Maps[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return map[string][]string{
+		"sysmapids": ids,
+	}, nil
+}
+
+// findMap is used to retrieve a stored map using its id.
+func (s *Server) findMap(id string) *Map {
+	for _, m := range s.dataset.Maps {
+		if m.Id == id {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// maxId is used to return the greatest value between the given int and the given string id.
+func maxId(current int, id string) int {
+	value, err := strconv.Atoi(id)
+	if err != nil || value < current {
+		return current
+	}
+
+	return value
+}
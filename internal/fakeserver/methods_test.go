@@ -0,0 +1,400 @@
+package fakeserver
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHostGet(t *testing.T) {
+	s := newTestingServer(t)
+
+	hosts := make([]*Host, 0)
+	err := call(t, s, "host.get", map[string]interface{}{
+		"output": []string{"hostid", "host"},
+		"filter": map[string][]string{
+			"host": {"router-1", "router-3"},
+		},
+	}, login(t, s), &hosts)
+
+	if err != nil {
+		t.Fatalf("error while executing host.get method.\nReason : %s", err.Data)
+	}
+
+	if len(hosts) != 2 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 2\nReturned : %d", len(hosts))
+	}
+}
+
+func TestHostGetSelectHostGroups(t *testing.T) {
+	s := newTestingServer(t)
+
+	hosts := make([]*hostResult, 0)
+	err := call(t, s, "host.get", map[string]interface{}{
+		"output":           []string{"hostid", "host"},
+		"selectHostGroups": []string{"groupid", "name"},
+		"hostids":          []string{"10503"},
+	}, login(t, s), &hosts)
+
+	if err != nil {
+		t.Fatalf("error while executing host.get method.\nReason : %s", err.Data)
+	}
+
+	if len(hosts) != 1 {
+		t.Fatalf("wrong number of hosts returned.\nExpected : 1\nReturned : %d", len(hosts))
+	}
+
+	if len(hosts[0].HostGroups) != 1 || hosts[0].HostGroups[0].Name != "Routers" {
+		t.Fatalf("wrong host groups returned.\nExpected : [Routers]\nReturned : %+v", hosts[0].HostGroups)
+	}
+}
+
+func TestImageGet(t *testing.T) {
+	s := newTestingServer(t)
+
+	images := make([]*Image, 0)
+	err := call(t, s, "image.get", map[string]interface{}{
+		"filter": map[string]string{
+			"name": "Cloud_(24)",
+		},
+	}, login(t, s), &images)
+
+	if err != nil {
+		t.Fatalf("error while executing image.get method.\nReason : %s", err.Data)
+	}
+
+	if len(images) != 1 || images[0].Id != "1" {
+		t.Fatalf("wrong images returned.\nReturned : %v", images)
+	}
+}
+
+func TestImageGetSelectImage(t *testing.T) {
+	s := newTestingServer(t)
+	s.dataset.Images[0].Data = "aW1hZ2U="
+
+	images := make([]*Image, 0)
+	err := call(t, s, "image.get", map[string]interface{}{
+		"imageids":     []string{"1"},
+		"select_image": true,
+	}, login(t, s), &images)
+
+	if err != nil {
+		t.Fatalf("error while executing image.get method.\nReason : %s", err.Data)
+	}
+
+	if len(images) != 1 || images[0].Data != "aW1hZ2U=" {
+		t.Fatalf("the content of the image was not returned.\nReturned : %v", images)
+	}
+
+	images = make([]*Image, 0)
+	err = call(t, s, "image.get", map[string]interface{}{
+		"imageids": []string{"1"},
+	}, login(t, s), &images)
+
+	if err != nil {
+		t.Fatalf("error while executing image.get method.\nReason : %s", err.Data)
+	}
+
+	if len(images) != 1 || images[0].Data != "" {
+		t.Fatalf("the content of the image should only be returned when 'select_image' is set.\nReturned : %v", images)
+	}
+}
+
+func TestImageCreateUpdate(t *testing.T) {
+	s := newTestingServer(t)
+	token := login(t, s)
+
+	res := make(map[string][]string, 0)
+	err := call(t, s, "image.create", map[string]interface{}{
+		"name":      "Router_(96)",
+		"imagetype": 1,
+		"image":     "aW1hZ2U=",
+	}, token, &res)
+
+	if err != nil {
+		t.Fatalf("error while executing image.create method.\nReason : %s", err.Data)
+	}
+
+	if len(res["imageids"]) != 1 || res["imageids"][0] != "6" {
+		t.Fatalf("wrong ids returned.\nExpected : [6]\nReturned : %v", res["imageids"])
+	}
+
+	err = call(t, s, "image.create", map[string]interface{}{
+		"name":  "Router_(96)",
+		"image": "aW1hZ2U=",
+	}, token, &res)
+
+	if err == nil {
+		t.Fatal("an error should be returned when the image already exists")
+	}
+
+	err = call(t, s, "image.update", map[string]interface{}{
+		"imageid": "6",
+		"image":   "dXBkYXRlZA==",
+	}, token, &res)
+
+	if err != nil {
+		t.Fatalf("error while executing image.update method.\nReason : %s", err.Data)
+	}
+
+	images := make([]*Image, 0)
+	err = call(t, s, "image.get", map[string]interface{}{
+		"imageids":     []string{"6"},
+		"select_image": true,
+	}, token, &images)
+
+	if err != nil {
+		t.Fatalf("error while executing image.get method.\nReason : %s", err.Data)
+	}
+
+	if len(images) != 1 || images[0].Data != "dXBkYXRlZA==" {
+		t.Fatalf("the content of the image was not updated.\nReturned : %v", images)
+	}
+}
+
+func TestTriggerGet(t *testing.T) {
+	s := newTestingServer(t)
+
+	triggers := make([]*Trigger, 0)
+	err := call(t, s, "trigger.get", map[string]interface{}{
+		"hostids": []string{"10501"},
+		"filter": map[string]string{
+			"description": "Interface eth1(): Link down",
+		},
+	}, login(t, s), &triggers)
+
+	if err != nil {
+		t.Fatalf("error while executing trigger.get method.\nReason : %s", err.Data)
+	}
+
+	if len(triggers) != 1 {
+		t.Fatalf("wrong number of triggers returned.\nExpected : 1\nReturned : %d", len(triggers))
+	}
+}
+
+func TestItemGet(t *testing.T) {
+	s := newTestingServer(t)
+
+	items := make([]*Item, 0)
+	err := call(t, s, "item.get", map[string]interface{}{
+		"hostids": "10502",
+		"search": map[string]string{
+			"name": "eth0",
+		},
+	}, login(t, s), &items)
+
+	if err != nil {
+		t.Fatalf("error while executing item.get method.\nReason : %s", err.Data)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("wrong number of items returned.\nExpected : 3\nReturned : %d", len(items))
+	}
+}
+
+func TestHostGroupGet(t *testing.T) {
+	s := newTestingServer(t)
+
+	groups := make([]*HostGroup, 0)
+	err := call(t, s, "hostgroup.get", map[string]interface{}{
+		"hostids": []string{"10501"},
+	}, login(t, s), &groups)
+
+	if err != nil {
+		t.Fatalf("error while executing hostgroup.get method.\nReason : %s", err.Data)
+	}
+
+	if len(groups) != 2 {
+		t.Fatalf("wrong number of host groups returned.\nExpected : 2\nReturned : %d", len(groups))
+	}
+
+	if groups[0].HostIds != nil {
+		t.Fatalf("the list of hosts should not be returned.\nReturned : %v", groups[0].HostIds)
+	}
+}
+
+func TestUserGet(t *testing.T) {
+	s := newTestingServer(t)
+
+	users := make([]*User, 0)
+	err := call(t, s, "user.get", map[string]interface{}{
+		"filter": map[string][]string{
+			"username": {"noc"},
+		},
+	}, login(t, s), &users)
+
+	if err != nil {
+		t.Fatalf("error while executing user.get method.\nReason : %s", err.Data)
+	}
+
+	if len(users) != 1 || users[0].Id != "3" {
+		t.Fatalf("wrong users returned.\nExpected : [3]\nReturned : %v", users)
+	}
+
+	if users[0].Password != "" {
+		t.Fatal("the password of the users should not be returned")
+	}
+}
+
+func TestUserGroupGet(t *testing.T) {
+	s := newTestingServer(t)
+
+	groups := make([]*UserGroup, 0)
+	err := call(t, s, "usergroup.get", map[string]interface{}{
+		"filter": map[string][]string{
+			"name": {"Network team"},
+		},
+	}, login(t, s), &groups)
+
+	if err != nil {
+		t.Fatalf("error while executing usergroup.get method.\nReason : %s", err.Data)
+	}
+
+	if len(groups) != 1 || groups[0].Id != "15" {
+		t.Fatalf("wrong user groups returned.\nExpected : [15]\nReturned : %v", groups)
+	}
+}
+
+func TestMapLifecycle(t *testing.T) {
+	s := newTestingServer(t)
+	token := login(t, s)
+
+	created := struct {
+		MapIds []string `json:"sysmapids"`
+	}{}
+
+	err := call(t, s, "map.create", map[string]string{
+		"name":   "test-map",
+		"width":  "800",
+		"height": "800",
+	}, token, &created)
+
+	if err != nil {
+		t.Fatalf("error while executing map.create method.\nReason : %s", err.Data)
+	}
+
+	if len(created.MapIds) != 1 {
+		t.Fatalf("wrong number of map ids returned.\nExpected : 1\nReturned : %d", len(created.MapIds))
+	}
+
+	err = call(t, s, "map.create", map[string]string{"name": "test-map"}, token, nil)
+	if err == nil {
+		t.Fatal("an error should be returned when a map with the same name already exists")
+	}
+
+	err = call(t, s, "map.update", map[string]string{
+		"sysmapid": created.MapIds[0],
+		"width":    "400",
+	}, token, nil)
+
+	if err != nil {
+		t.Fatalf("error while executing map.update method.\nReason : %s", err.Data)
+	}
+
+	maps := make([]map[string]interface{}, 0)
+	err = call(t, s, "map.get", map[string]interface{}{
+		"sysmapids": created.MapIds,
+	}, token, &maps)
+
+	if err != nil {
+		t.Fatalf("error while executing map.get method.\nReason : %s", err.Data)
+	}
+
+	if len(maps) != 1 || maps[0]["width"] != "400" || maps[0]["height"] != "800" {
+		t.Fatalf("wrong map returned.\nReturned : %v", maps)
+	}
+
+	err = call(t, s, "map.delete", created.MapIds, token, nil)
+	if err != nil {
+		t.Fatalf("error while executing map.delete method.\nReason : %s", err.Data)
+	}
+
+	if len(s.Maps()) != 0 {
+		t.Fatalf("the map was not deleted.\nReturned : %d maps", len(s.Maps()))
+	}
+
+	err = call(t, s, "map.delete", created.MapIds, token, nil)
+	if err == nil {
+		t.Fatal("an error should be returned when deleting a map that does not exist")
+	}
+}
+
+func TestHostGetTags(t *testing.T) {
+	s := newTestingServer(t)
+
+	hosts := make([]*Host, 0)
+	err := call(t, s, "host.get", map[string]interface{}{
+		"output":   []string{"hostid", "host"},
+		"evaltype": 2,
+		"tags": []map[string]string{
+			{"tag": "sysName", "value": "rt1.dc1", "operator": "1"},
+		},
+	}, login(t, s), &hosts)
+
+	if err != nil {
+		t.Fatalf("error while executing host.get method.\nReason : %s", err.Data)
+	}
+
+	if len(hosts) != 1 || hosts[0].Id != "10501" {
+		t.Fatalf("wrong hosts returned.\nReturned : %v", hosts)
+	}
+}
+
+func TestHostInterfaceGet(t *testing.T) {
+	s := newTestingServer(t)
+	token := login(t, s)
+	addresses := []string{"192.168.1.2", "router-3.example.com"}
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		expected []string
+	}{
+		{
+			name: "ip filter",
+			params: map[string]interface{}{
+				"filter": map[string][]string{"ip": addresses},
+			},
+			expected: []string{"10502"},
+		},
+		{
+			name: "dns filter",
+			params: map[string]interface{}{
+				"filter": map[string][]string{"dns": addresses},
+			},
+			expected: []string{"10503"},
+		},
+		{
+			// The filters are combined with a logical AND, 'searchByAny' only applies to the search
+			name: "ip and dns filters",
+			params: map[string]interface{}{
+				"filter":      map[string][]string{"ip": addresses, "dns": addresses},
+				"searchByAny": true,
+			},
+			expected: []string{},
+		},
+		{
+			name: "search by any",
+			params: map[string]interface{}{
+				"search":      map[string][]string{"ip": {"192.168.1.2"}, "dns": {"router-3"}},
+				"searchByAny": true,
+			},
+			expected: []string{"10502", "10503"},
+		},
+	}
+
+	for _, test := range tests {
+		interfaces := make([]*Interface, 0)
+		if err := call(t, s, "hostinterface.get", test.params, token, &interfaces); err != nil {
+			t.Fatalf("error while executing hostinterface.get method for the test '%s'.\nReason : %s", test.name, err.Data)
+		}
+
+		hosts := make([]string, 0)
+		for _, i := range interfaces {
+			hosts = append(hosts, i.HostId)
+		}
+
+		if fmt.Sprint(hosts) != fmt.Sprint(test.expected) {
+			t.Fatalf("wrong interfaces returned for the test '%s'.\nExpected : %v\nReturned : %v", test.name, test.expected, hosts)
+		}
+	}
+}
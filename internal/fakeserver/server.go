@@ -0,0 +1,204 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// Request define a JSON-RPC request received by the fake server.
+type Request struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Auth    string          `json:"auth,omitempty"`
+	Id      int             `json:"id"`
+}
+
+// responseError define the error object of a JSON-RPC response.
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+// response define a JSON-RPC response returned by the fake server.
+type response struct {
+	Jsonrpc string         `json:"jsonrpc"`
+	Result  interface{}    `json:"result,omitempty"`
+	Error   *responseError `json:"error,omitempty"`
+	Id      int            `json:"id"`
+}
+
+// handlerFunc define the signature of the functions used to handle a JSON-RPC method.
+type handlerFunc func(s *Server, params json.RawMessage) (interface{}, *responseError)
+
+// Server is an httptest based server implementing a subset of the Zabbix JSON-RPC API.
+// Every request received is recorded and can be retrieved using the Requests method.
+type Server struct {
+	*httptest.Server
+	dataset  *Dataset
+	handlers map[string]handlerFunc
+	tokens   map[string]bool
+	requests []*Request
+	lastId   int
+	// lastToken is incremented for each token generated, tokens are never reused once released.
+	lastToken int
+	mutex     sync.Mutex
+}
+
+// NewServer is used to start a new fake server exposing the given dataset.
+func NewServer(dataset *Dataset) *Server {
+	if dataset == nil {
+		dataset = &Dataset{}
+	}
+
+	if dataset.Version == "" {
+		dataset.Version = "6.0.0"
+	}
+
+	s := &Server{
+		dataset:  dataset,
+		tokens:   make(map[string]bool, 0),
+		requests: make([]*Request, 0),
+		handlers: map[string]handlerFunc{
+			"apiinfo.version":   apiInfoVersion,
+			"user.login":        userLogin,
+			"user.logout":       userLogout,
+			"user.get":          userGet,
+			"usergroup.get":     userGroupGet,
+			"host.get":          hostGet,
+			"image.get":         imageGet,
+			"image.create":      imageCreate,
+			"image.update":      imageUpdate,
+			"trigger.get":       triggerGet,
+			"item.get":          itemGet,
+			"hostgroup.get":     hostGroupGet,
+			"hostinterface.get": hostInterfaceGet,
+			"map.create":        mapCreate,
+			"map.update":        mapUpdate,
+			"map.get":           mapGet,
+			"map.delete":        mapDelete,
+		},
+	}
+
+	for _, m := range dataset.Maps {
+		s.lastId = maxId(s.lastId, m.Id)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// ApiUrl is used to retrieve the URL of the JSON-RPC endpoint.
+func (s *Server) ApiUrl() string {
+	return fmt.Sprintf("%s/api_jsonrpc.php", s.URL)
+}
+
+// Requests is used to retrieve the requests received by the server.
+// If methods are given, only the requests matching one of the methods are returned.
+func (s *Server) Requests(methods ...string) []*Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	out := make([]*Request, 0)
+	for _, r := range s.requests {
+		if len(methods) == 0 || utils.Contains(methods, r.Method) {
+			out = append(out, r)
+		}
+	}
+
+	return out
+}
+
+// Maps is used to retrieve the maps currently stored on the server.
+func (s *Server) Maps() []*Map {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	out := make([]*Map, len(s.dataset.Maps))
+	copy(out, s.dataset.Maps)
+
+	return out
+}
+
+// handle is used to decode a JSON-RPC request and dispatch it to the matching method handler.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	req := &Request{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeResponse(w, &response{
+			Jsonrpc: "2.0",
+			Error: &responseError{
+				Code:    -32700,
+				Message: "Parse error.",
+				Data:    err.Error(),
+			},
+		})
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Keep track of the request without the authentication token
+	s.requests = append(s.requests, &Request{
+		Jsonrpc: req.Jsonrpc,
+		Method:  req.Method,
+		Params:  req.Params,
+		Id:      req.Id,
+	})
+
+	res := &response{
+		Jsonrpc: "2.0",
+		Id:      req.Id,
+	}
+
+	handler, exist := s.handlers[req.Method]
+	if !exist {
+		res.Error = &responseError{
+			Code:    -32601,
+			Message: "Method not found.",
+			Data:    fmt.Sprintf("Incorrect API \"%s\".", req.Method),
+		}
+		writeResponse(w, res)
+		return
+	}
+
+	if req.Method != "apiinfo.version" && req.Method != "user.login" && !s.tokens[getToken(req, r)] {
+		res.Error = &responseError{
+			Code:    -32602,
+			Message: "Invalid params.",
+			Data:    "Not authorized.",
+		}
+		writeResponse(w, res)
+		return
+	}
+
+	res.Result, res.Error = handler(s, req.Params)
+	if req.Method == "user.logout" && res.Error == nil {
+		delete(s.tokens, getToken(req, r))
+	}
+
+	writeResponse(w, res)
+}
+
+// getToken is used to retrieve the authentication token from the request body or the Authorization header.
+func getToken(req *Request, r *http.Request) string {
+	if req.Auth != "" {
+		return req.Auth
+	}
+
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// writeResponse is used to write the given response in JSON format.
+func writeResponse(w http.ResponseWriter, res *response) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,186 @@
+package fakeserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var datasetFilePath string
+
+func init() {
+	pwd, _ := os.Getwd()
+	datasetFilePath = filepath.Join(pwd, "..", "..", "examples", "fake_dataset.json")
+}
+
+// newTestingServer is used to start a fake server using the example dataset.
+func newTestingServer(t *testing.T) *Server {
+	dataset, err := LoadDataset(datasetFilePath)
+	if err != nil {
+		t.Fatalf("error while executing LoadDataset function.\nReason : %v", err)
+	}
+
+	s := NewServer(dataset)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// call is used to send a JSON-RPC request to the given server and decode the result in v.
+// The error object of the response is returned if the server returned one.
+func call(t *testing.T, s *Server, method string, params interface{}, token string, v interface{}) *responseError {
+	b, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"auth":    token,
+		"id":      1,
+	})
+	if err != nil {
+		t.Fatalf("error while encoding the request.\nReason : %v", err)
+	}
+
+	res, err := http.Post(s.ApiUrl(), "application/json-rpc", bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("error while sending the request.\nReason : %v", err)
+	}
+
+	defer res.Body.Close()
+
+	out := struct {
+		Result json.RawMessage `json:"result"`
+		Error  *responseError  `json:"error"`
+	}{}
+
+	if err = json.NewDecoder(res.Body).Decode(&out); err != nil {
+		t.Fatalf("error while decoding the response.\nReason : %v", err)
+	}
+
+	if out.Error != nil {
+		return out.Error
+	}
+
+	if v != nil {
+		if err = json.Unmarshal(out.Result, v); err != nil {
+			t.Fatalf("error while decoding the result.\nReason : %v", err)
+		}
+	}
+
+	return nil
+}
+
+// login is used to retrieve a token from the given server.
+func login(t *testing.T, s *Server) string {
+	var token string
+	if err := call(t, s, "user.login", map[string]string{"username": "Admin", "password": "zabbix"}, "", &token); err != nil {
+		t.Fatalf("error while executing user.login method.\nReason : %s", err.Data)
+	}
+
+	return token
+}
+
+func TestNewServer(t *testing.T) {
+	s := NewServer(nil)
+	defer s.Close()
+
+	if s.dataset.Version == "" {
+		t.Fatal("a default version should be set when no dataset is given")
+	}
+}
+
+func TestApiInfoVersion(t *testing.T) {
+	s := newTestingServer(t)
+
+	var version string
+	if err := call(t, s, "apiinfo.version", []string{}, "", &version); err != nil {
+		t.Fatalf("error while executing apiinfo.version method.\nReason : %s", err.Data)
+	}
+
+	if version != "6.0.0" {
+		t.Fatalf("wrong version returned.\nExpected : '6.0.0'\nReturned : %s", version)
+	}
+}
+
+func TestUserLoginFail(t *testing.T) {
+	s := newTestingServer(t)
+
+	err := call(t, s, "user.login", map[string]string{"user": "Admin", "password": "random-password"}, "", nil)
+	if err == nil {
+		t.Fatal("an error should be returned when the credentials are invalid")
+	}
+}
+
+func TestUserLogout(t *testing.T) {
+	s := newTestingServer(t)
+	token := login(t, s)
+
+	if err := call(t, s, "user.logout", []string{}, token, nil); err != nil {
+		t.Fatalf("error while executing user.logout method.\nReason : %s", err.Data)
+	}
+
+	if err := call(t, s, "host.get", map[string]string{}, token, nil); err == nil {
+		t.Fatal("an error should be returned when the token was released")
+	}
+}
+
+func TestUserLoginAfterLogout(t *testing.T) {
+	s := newTestingServer(t)
+	first := login(t, s)
+	second := login(t, s)
+
+	if err := call(t, s, "user.logout", []string{}, first, nil); err != nil {
+		t.Fatalf("error while executing user.logout method.\nReason : %s", err.Data)
+	}
+
+	third := login(t, s)
+	if third == first || third == second {
+		t.Fatalf("a new token should be generated after a logout.\nReturned : %s, %s and %s", first, second, third)
+	}
+
+	if err := call(t, s, "host.get", map[string]string{}, second, nil); err != nil {
+		t.Fatalf("the other tokens should remain valid after a logout.\nReason : %s", err.Data)
+	}
+}
+
+func TestNotAuthorized(t *testing.T) {
+	s := newTestingServer(t)
+
+	if err := call(t, s, "host.get", map[string]string{}, "random-token", nil); err == nil {
+		t.Fatal("an error should be returned when the token is invalid")
+	}
+}
+
+func TestMethodNotFound(t *testing.T) {
+	s := newTestingServer(t)
+
+	err := call(t, s, "random.method", map[string]string{}, login(t, s), nil)
+	if err == nil {
+		t.Fatal("an error should be returned when the method is not supported")
+	}
+
+	if err.Code != -32601 {
+		t.Fatalf("wrong error code returned.\nExpected : -32601\nReturned : %d", err.Code)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	s := newTestingServer(t)
+	token := login(t, s)
+	call(t, s, "host.get", map[string]string{}, token, nil)
+
+	if len(s.Requests()) != 2 {
+		t.Fatalf("wrong number of requests recorded.\nExpected : 2\nReturned : %d", len(s.Requests()))
+	}
+
+	requests := s.Requests("host.get")
+	if len(requests) != 1 {
+		t.Fatalf("wrong number of 'host.get' requests recorded.\nExpected : 1\nReturned : %d", len(requests))
+	}
+
+	if requests[0].Auth != "" {
+		t.Fatal("the authentication token should not be recorded")
+	}
+}
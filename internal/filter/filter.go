@@ -0,0 +1,287 @@
+package filter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// Filter define the expressions used to select the part of the mappings to draw.
+// An host is selected if it matches at least one of the expressions.
+type Filter struct {
+	// IncludeHosts are glob patterns (ex: 'dc1-*') matching the name of the hosts to select.
+	IncludeHosts []string `yaml:"include_hosts,omitempty" json:"include_hosts,omitempty"`
+	// IncludeGroups are glob patterns matching the name of the host groups whose hosts are selected.
+	IncludeGroups []string `yaml:"include_groups,omitempty" json:"include_groups,omitempty"`
+	// Tags are written as 'name=value' (the value can be a glob pattern) or 'name' to select the hosts with the tag set to any value.
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Hops is the number of neighbors added around the selection.
+	Hops int `yaml:"hops,omitempty" json:"hops,omitempty"`
+	// Stubs is used to keep the links leaving the selection, the elements outside of the selection are replaced by image elements.
+	Stubs bool `yaml:"stubs,omitempty" json:"stubs,omitempty"`
+	// StubImage is the image of the stub elements, the image of the mapping is used if empty.
+	StubImage string `yaml:"stub_image,omitempty" json:"stub_image,omitempty"`
+}
+
+// Host define the host groups and the tags of an host, used by the group and tag expressions.
+type Host struct {
+	Groups []string
+	Tags   []*Tag
+}
+
+// Tag define a tag of an host.
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// IsEmpty is used to check if no expression is set, all the mappings are then selected.
+func (f *Filter) IsEmpty() bool {
+	return f == nil || (len(f.IncludeHosts) == 0 && len(f.IncludeGroups) == 0 && len(f.Tags) == 0)
+}
+
+// NeedsHosts is used to check if the host groups and the tags of the hosts are required to apply the filter.
+func (f *Filter) NeedsHosts() bool {
+	return f != nil && (len(f.IncludeGroups) > 0 || len(f.Tags) > 0)
+}
+
+// Validate is used to validate the expressions of the filter.
+func (f *Filter) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	for _, pattern := range f.IncludeHosts {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid host pattern '%s'.\nReason : %v", pattern, err)
+		}
+	}
+
+	for _, pattern := range f.IncludeGroups {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid host group pattern '%s'.\nReason : %v", pattern, err)
+		}
+	}
+
+	for _, expression := range f.Tags {
+		name, value := parseTag(expression)
+		if name == "" {
+			return fmt.Errorf("invalid tag '%s', the value must be written as 'name=value' or 'name'", expression)
+		}
+
+		if _, err := path.Match(value, ""); err != nil {
+			return fmt.Errorf("invalid tag pattern '%s'.\nReason : %v", expression, err)
+		}
+	}
+
+	if f.Hops < 0 {
+		return fmt.Errorf("the number of hops cannot be negative")
+	}
+
+	return nil
+}
+
+// parseTag is used to read a tag expression written as 'name=value' or 'name', '*' is returned as value if not set.
+func parseTag(expression string) (string, string) {
+	name, value, found := strings.Cut(expression, "=")
+	if !found {
+		value = "*"
+	}
+
+	return strings.TrimSpace(name), strings.TrimSpace(value)
+}
+
+// matchAny is used to check if the given value matches one of the given glob patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchHost is used to check if the host with the given name matches one of the expressions of the filter.
+// The host groups and the tags of the host are retrieved from the given hosts, the expressions using them are ignored if the host is missing.
+func (f *Filter) matchHost(name string, hosts map[string]*Host) bool {
+	if matchAny(f.IncludeHosts, name) {
+		return true
+	}
+
+	host := hosts[name]
+	if host == nil {
+		return false
+	}
+
+	for _, group := range host.Groups {
+		if matchAny(f.IncludeGroups, group) {
+			return true
+		}
+	}
+
+	for _, expression := range f.Tags {
+		tagName, tagValue := parseTag(expression)
+		for _, tag := range host.Tags {
+			if ok, _ := path.Match(tagValue, tag.Value); ok && tag.Name == tagName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// node is used to identify an element of the mappings, trigger elements are identified by their host.
+type node struct {
+	elementType string
+	name        string
+}
+
+// newNode is used to create the node of an element of the given type.
+func newNode(elementType string, name string) node {
+	if elementType == zbxmap.ElementTrigger {
+		elementType = zbxmap.ElementHost
+	}
+
+	return node{
+		elementType: elementType,
+		name:        name,
+	}
+}
+
+// endpoints is used to retrieve the local and the remote nodes of the given mapping.
+func endpoints(m *zbxmap.Mapping) (node, node) {
+	return newNode(m.LocalElementType(), m.LocalHost), newNode(m.RemoteElementType(), m.RemoteHost)
+}
+
+// selection is used to retrieve the hosts matching the filter, extended by the given number of hops.
+func (f *Filter) selection(mappings []*zbxmap.Mapping, hosts map[string]*Host) map[node]bool {
+	selected := make(map[node]bool, 0)
+	neighbors := make(map[node][]node, 0)
+
+	for _, m := range mappings {
+		local, remote := endpoints(m)
+		neighbors[local] = append(neighbors[local], remote)
+		neighbors[remote] = append(neighbors[remote], local)
+
+		for _, n := range []node{local, remote} {
+			if n.elementType == zbxmap.ElementHost && f.matchHost(n.name, hosts) {
+				selected[n] = true
+			}
+		}
+	}
+
+	// Add the neighbors of the selection, one hop at a time
+	for hop := 0; hop < f.Hops; hop++ {
+		added := make([]node, 0)
+		for n := range selected {
+			for _, neighbor := range neighbors[n] {
+				if !selected[neighbor] {
+					added = append(added, neighbor)
+				}
+			}
+		}
+
+		if len(added) == 0 {
+			break
+		}
+
+		for _, n := range added {
+			selected[n] = true
+		}
+	}
+
+	return selected
+}
+
+// stub is used to replace the remote element of the given mapping by an image element using the name of the element as label.
+func (f *Filter) stub(m *zbxmap.Mapping) (*zbxmap.Mapping, error) {
+	out := *m
+	out.RemoteType = zbxmap.ElementImage
+	out.RemoteTriggerPattern = ""
+	out.RemoteCapabilities = ""
+
+	if f.StubImage != "" {
+		out.RemoteImage = f.StubImage
+	}
+
+	if out.RemoteImage == "" {
+		return nil, fmt.Errorf("no image is available for the stub element '%s', an image is required for the stubs", m.RemoteHost)
+	}
+
+	return &out, nil
+}
+
+// reverse is used to swap the local and the remote elements of the given mapping.
+func reverse(m *zbxmap.Mapping) *zbxmap.Mapping {
+	return &zbxmap.Mapping{
+		LocalHost:            m.RemoteHost,
+		LocalInterface:       m.RemoteInterface,
+		LocalTriggerPattern:  m.RemoteTriggerPattern,
+		LocalImage:           m.RemoteImage,
+		LocalLabel:           m.RemoteLabel,
+		LocalCapabilities:    m.RemoteCapabilities,
+		LocalType:            m.RemoteType,
+		RemoteHost:           m.LocalHost,
+		RemoteInterface:      m.LocalInterface,
+		RemoteTriggerPattern: m.LocalTriggerPattern,
+		RemoteImage:          m.LocalImage,
+		RemoteLabel:          m.LocalLabel,
+		RemoteCapabilities:   m.LocalCapabilities,
+		RemoteType:           m.LocalType,
+		Color:                m.Color,
+		TriggerColor:         m.TriggerColor,
+	}
+}
+
+// Apply is used to retrieve the mappings whose local and remote elements are both part of the selection.
+// Elements that are not hosts (host groups, maps and images) are part of the selection when linked to a selected host.
+// When using stubs, the links leaving the selection are kept and the elements outside of the selection are replaced by image elements.
+// The host groups and the tags of the hosts are retrieved from the given hosts (see NeedsHosts).
+// All the mappings are returned if the filter is empty.
+func (f *Filter) Apply(mappings []*zbxmap.Mapping, hosts map[string]*Host) ([]*zbxmap.Mapping, error) {
+	if f.IsEmpty() {
+		return mappings, nil
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	selected := f.selection(mappings, hosts)
+
+	out := make([]*zbxmap.Mapping, 0)
+	for _, m := range mappings {
+		local, remote := endpoints(m)
+		localSelected := selected[local] || (local.elementType != zbxmap.ElementHost && selected[remote])
+		remoteSelected := selected[remote] || (remote.elementType != zbxmap.ElementHost && selected[local])
+
+		switch {
+		case localSelected && remoteSelected:
+			out = append(out, m)
+		case f.Stubs && localSelected:
+			s, err := f.stub(m)
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, s)
+		case f.Stubs && remoteSelected:
+			s, err := f.stub(reverse(m))
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, s)
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no mapping matches the filter")
+	}
+
+	return out, nil
+}
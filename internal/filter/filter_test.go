@@ -0,0 +1,256 @@
+package filter
+
+import (
+	"testing"
+
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// newTestingMappings is used to generate mappings linking hosts of two datacenters.
+func newTestingMappings() []*zbxmap.Mapping {
+	return []*zbxmap.Mapping{
+		{LocalHost: "dc1-core", RemoteHost: "dc1-access-1"},
+		{LocalHost: "dc1-core", RemoteHost: "dc1-access-2"},
+		{LocalHost: "dc1-core", RemoteHost: "dc2-core"},
+		{LocalHost: "dc2-core", RemoteHost: "dc2-access-1"},
+	}
+}
+
+func TestFilterIsEmpty(t *testing.T) {
+	var f *Filter
+	if !f.IsEmpty() {
+		t.Fatalf("a nil filter should be empty")
+	}
+
+	if !(&Filter{}).IsEmpty() {
+		t.Fatalf("a filter without expressions should be empty")
+	}
+
+	if (&Filter{IncludeHosts: []string{"dc1-*"}}).IsEmpty() {
+		t.Fatalf("a filter with an host pattern should not be empty")
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	if err := (&Filter{IncludeHosts: []string{"dc1-*", "core-?"}}).Validate(); err != nil {
+		t.Fatalf("error while executing Validate function.\nReason : %v", err)
+	}
+
+	tests := map[string]*Filter{
+		"host pattern":  {IncludeHosts: []string{"dc1-["}},
+		"group pattern": {IncludeGroups: []string{"DC["}},
+		"tag name":      {Tags: []string{"=paris"}},
+		"tag pattern":   {Tags: []string{"site=["}},
+		"hops":          {IncludeHosts: []string{"dc1-*"}, Hops: -1},
+	}
+
+	for name, f := range tests {
+		if err := f.Validate(); err == nil {
+			t.Fatalf("an error should be returned for the test '%s'", name)
+		}
+	}
+}
+
+func TestFilterNeedsHosts(t *testing.T) {
+	if (&Filter{IncludeHosts: []string{"dc1-*"}}).NeedsHosts() {
+		t.Fatalf("the hosts should not be required when only host patterns are used")
+	}
+
+	if !(&Filter{IncludeGroups: []string{"DC1"}}).NeedsHosts() || !(&Filter{Tags: []string{"site=paris"}}).NeedsHosts() {
+		t.Fatalf("the hosts should be required when group or tag expressions are used")
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	name, value := parseTag("site = paris")
+	if name != "site" || value != "paris" {
+		t.Fatalf("wrong tag returned.\nExpected : site=paris\nReturned : %s=%s", name, value)
+	}
+
+	name, value = parseTag("site")
+	if name != "site" || value != "*" {
+		t.Fatalf("wrong tag returned.\nExpected : site=*\nReturned : %s=%s", name, value)
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	f := &Filter{
+		IncludeHosts: []string{"dc1-*"},
+	}
+
+	out, err := f.Apply(newTestingMappings(), nil)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 2\nReturned : %d", len(out))
+	}
+
+	for _, m := range out {
+		if m.RemoteHost == "dc2-core" {
+			t.Fatalf("a mapping linking an host not selected should be removed.\nReturned : %+v", m)
+		}
+	}
+}
+
+func TestFilterApplyEmpty(t *testing.T) {
+	mappings := newTestingMappings()
+
+	out, err := (*Filter)(nil).Apply(mappings, nil)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != len(mappings) {
+		t.Fatalf("all the mappings should be returned.\nExpected : %d\nReturned : %d", len(mappings), len(out))
+	}
+}
+
+func TestFilterApplyNoMatch(t *testing.T) {
+	f := &Filter{
+		IncludeHosts: []string{"dc3-*"},
+	}
+
+	if _, err := f.Apply(newTestingMappings(), nil); err == nil {
+		t.Fatalf("an error should be returned when no mapping matches the filter")
+	}
+}
+
+func TestFilterApplyGroupsAndTags(t *testing.T) {
+	hosts := map[string]*Host{
+		"dc1-core":     {Groups: []string{"DC1", "Core"}},
+		"dc1-access-1": {Groups: []string{"DC1"}},
+		"dc2-core":     {Groups: []string{"DC2", "Core"}, Tags: []*Tag{{Name: "site", Value: "paris"}}},
+		"dc2-access-1": {Tags: []*Tag{{Name: "site", Value: "paris"}}},
+	}
+
+	out, err := (&Filter{IncludeGroups: []string{"DC1"}}).Apply(newTestingMappings(), hosts)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	// dc1-access-2 is not part of the group DC1
+	if len(out) != 1 || out[0].RemoteHost != "dc1-access-1" {
+		t.Fatalf("wrong mappings returned for the group 'DC1'.\nReturned : %+v", out)
+	}
+
+	out, err = (&Filter{Tags: []string{"site=par*"}}).Apply(newTestingMappings(), hosts)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 || out[0].LocalHost != "dc2-core" {
+		t.Fatalf("wrong mappings returned for the tag 'site=par*'.\nReturned : %+v", out)
+	}
+
+	// Hosts without groups or tags are only selected by their name
+	out, err = (&Filter{IncludeHosts: []string{"dc1-access-2"}, IncludeGroups: []string{"Core"}}).Apply(newTestingMappings(), hosts)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 2\nReturned : %d", len(out))
+	}
+}
+
+func TestFilterApplyHops(t *testing.T) {
+	f := &Filter{
+		IncludeHosts: []string{"dc1-access-1"},
+		Hops:         1,
+	}
+
+	out, err := f.Apply(newTestingMappings(), nil)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 {
+		t.Fatalf("wrong number of mappings returned with 1 hop.\nExpected : 1\nReturned : %d", len(out))
+	}
+
+	f.Hops = 2
+	out, err = f.Apply(newTestingMappings(), nil)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	// dc1-core is 1 hop away, dc1-access-2 and dc2-core are 2 hops away
+	if len(out) != 3 {
+		t.Fatalf("wrong number of mappings returned with 2 hops.\nExpected : 3\nReturned : %d", len(out))
+	}
+}
+
+func TestFilterApplyStubs(t *testing.T) {
+	mappings := newTestingMappings()
+	mappings[2].LocalTriggerPattern = "Interface eth0(): Link down"
+	mappings[2].RemoteTriggerPattern = "Interface eth1(): Link down"
+	mappings[2].RemoteImage = "Router_(64)"
+
+	f := &Filter{
+		IncludeHosts: []string{"dc1-*"},
+		Stubs:        true,
+	}
+
+	out, err := f.Apply(mappings, nil)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != 3 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 3\nReturned : %d", len(out))
+	}
+
+	stub := out[2]
+	if stub.RemoteHost != "dc2-core" || stub.RemoteType != zbxmap.ElementImage || stub.RemoteTriggerPattern != "" || stub.RemoteImage != "Router_(64)" {
+		t.Fatalf("the element outside of the selection should be replaced by a stub.\nReturned : %+v", stub)
+	}
+
+	if stub.LocalTriggerPattern != "Interface eth0(): Link down" {
+		t.Fatalf("the element of the selection should not be modified.\nReturned : %+v", stub)
+	}
+
+	if mappings[2].RemoteType != "" {
+		t.Fatalf("the given mappings should not be modified.\nReturned : %+v", mappings[2])
+	}
+
+	// The stub is always the remote element, the mapping is reversed if the selected host is the remote element
+	f.IncludeHosts = []string{"dc2-access-1"}
+	f.StubImage = "Cloud_(24)"
+	out, err = f.Apply(mappings, nil)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 || out[0].LocalHost != "dc2-access-1" || out[0].RemoteHost != "dc2-core" || out[0].RemoteImage != "Cloud_(24)" {
+		t.Fatalf("wrong stub returned.\nReturned : %+v", out[0])
+	}
+}
+
+func TestFilterApplyStubsNoImage(t *testing.T) {
+	f := &Filter{
+		IncludeHosts: []string{"dc1-*"},
+		Stubs:        true,
+	}
+
+	if _, err := f.Apply(newTestingMappings(), nil); err == nil {
+		t.Fatalf("an error should be returned when no image is available for a stub")
+	}
+}
+
+func TestFilterApplyOtherElements(t *testing.T) {
+	mappings := []*zbxmap.Mapping{
+		{LocalHost: "dc1-edge", RemoteHost: "Internet", RemoteType: zbxmap.ElementImage},
+		{LocalHost: "dc2-edge", RemoteHost: "Internet", RemoteType: zbxmap.ElementImage},
+	}
+
+	out, err := (&Filter{IncludeHosts: []string{"dc1-*"}}).Apply(mappings, nil)
+	if err != nil {
+		t.Fatalf("error while executing Apply function.\nReason : %v", err)
+	}
+
+	if len(out) != 1 || out[0].LocalHost != "dc1-edge" {
+		t.Fatalf("the elements linked to a selected host should be kept.\nReturned : %+v", out)
+	}
+}
@@ -0,0 +1,252 @@
+package icon
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// capabilities define the CDP capabilities ordered by bit (see examples/data/cdp-doc.txt).
+var capabilities = []struct {
+	bit    uint64
+	letter string
+}{
+	{0x001, "R"}, // Router
+	{0x002, "T"}, // Trans Bridge
+	{0x004, "B"}, // Source Route Bridge
+	{0x008, "S"}, // Switch
+	{0x010, "H"}, // Host
+	{0x020, "I"}, // IGMP
+	{0x040, "r"}, // Repeater
+	{0x080, "P"}, // Phone
+	{0x100, "D"}, // Remote
+	{0x200, "C"}, // CVTA
+	{0x400, "M"}, // Two-port Mac Relay
+}
+
+// isCapability is used to check if the given letter is a CDP capability.
+func isCapability(letter string) bool {
+	for _, c := range capabilities {
+		if c.letter == letter {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseCapabilities is used to decode the CDP capabilities of an host.
+// Both the list of letters ('R S I') and the hexadecimal bitmask returned by the cdpCacheCapabilities OID ('00 00 00 29' or '0x29') are supported.
+func ParseCapabilities(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	out := make([]string, 0)
+
+	if value == "" {
+		return out, nil
+	}
+
+	// A bitmask contains at least one digit, a list of letters does not
+	if strings.ContainsAny(value, "0123456789") {
+		hexa := strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(value), "0x"), " ", "")
+
+		bits, err := strconv.ParseUint(hexa, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CDP capabilities '%s', expected letters ('R S I') or an hexadecimal value ('00 00 00 29')", value)
+		}
+
+		for _, c := range capabilities {
+			if bits&c.bit != 0 {
+				out = append(out, c.letter)
+			}
+		}
+
+		return out, nil
+	}
+
+	for _, r := range strings.ReplaceAll(value, " ", "") {
+		letter := string(r)
+		if !isCapability(letter) {
+			return nil, fmt.Errorf("unknown CDP capability '%s' in '%s'", letter, value)
+		}
+
+		out = append(out, letter)
+	}
+
+	return out, nil
+}
+
+// Tag define a tag of an host.
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Host define the metadata of an host used to select its icon.
+type Host struct {
+	Name          string
+	Templates     []string
+	Groups        []string
+	InventoryType string
+	Tags          []*Tag
+	Capabilities  []string
+}
+
+// Rule define the conditions used to select the images of an host.
+// All the conditions set must match, patterns support the '*' and '?' wildcards and are case insensitive.
+type Rule struct {
+	Template      string `yaml:"template"`
+	Group         string `yaml:"group"`
+	InventoryType string `yaml:"inventory_type"`
+	// Tag is written as 'name' (any value) or 'name=value'.
+	Tag string `yaml:"tag"`
+	// Capabilities is the list of CDP capabilities that must be set ('R', 'S I', etc.).
+	Capabilities string `yaml:"capabilities"`
+	// Image is used as default image (iconid_off), the other images are optional.
+	Image            string `yaml:"image"`
+	ImageProblem     string `yaml:"image_problem"`
+	ImageMaintenance string `yaml:"image_maintenance"`
+	ImageDisabled    string `yaml:"image_disabled"`
+}
+
+// Rules define the list of rules used to select the images of the hosts, the first matching rule is used.
+type Rules struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Load is used to read the rules from the given file (YAML or JSON).
+func Load(file string) (*Rules, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Rules{}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(r); err != nil {
+		return nil, fmt.Errorf("error while reading the icon rules '%s'.\nReason : %v", file, err)
+	}
+
+	if err = r.Validate(); err != nil {
+		return nil, fmt.Errorf("error while reading the icon rules '%s'.\nReason : %v", file, err)
+	}
+
+	return r, nil
+}
+
+// Validate is used to validate the rules.
+func (r *Rules) Validate() error {
+	if len(r.Rules) == 0 {
+		return fmt.Errorf("no rules were found")
+	}
+
+	for i, rule := range r.Rules {
+		if rule == nil || rule.Image == "" {
+			return fmt.Errorf("rule %d : an image is required", i)
+		}
+
+		if _, err := ParseCapabilities(rule.Capabilities); err != nil {
+			return fmt.Errorf("rule %d : %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Images is used to retrieve the names of all the images referenced by the rules.
+func (r *Rules) Images() []string {
+	out := make([]string, 0)
+
+	for _, rule := range r.Rules {
+		for _, image := range []string{rule.Image, rule.ImageProblem, rule.ImageMaintenance, rule.ImageDisabled} {
+			if image != "" && !utils.Contains(out, image) {
+				out = append(out, image)
+			}
+		}
+	}
+
+	return out
+}
+
+// Match is used to retrieve the first rule matching the given host, nil is returned if no rule match.
+func (r *Rules) Match(h *Host) *Rule {
+	for _, rule := range r.Rules {
+		if rule.match(h) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// match is used to check if all the conditions of the rule match the given host.
+func (r *Rule) match(h *Host) bool {
+	if r.Template != "" && !matchAny(r.Template, h.Templates) {
+		return false
+	}
+
+	if r.Group != "" && !matchAny(r.Group, h.Groups) {
+		return false
+	}
+
+	if r.InventoryType != "" && !matchPattern(r.InventoryType, h.InventoryType) {
+		return false
+	}
+
+	if r.Tag != "" {
+		name, value, hasValue := strings.Cut(r.Tag, "=")
+		found := false
+
+		for _, t := range h.Tags {
+			if t.Name == strings.TrimSpace(name) && (!hasValue || matchPattern(strings.TrimSpace(value), t.Value)) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if r.Capabilities != "" {
+		// Already validated when loading the rules
+		required, _ := ParseCapabilities(r.Capabilities)
+		for _, c := range required {
+			if !utils.Contains(h.Capabilities, c) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// matchPattern is used to check if the value match the given pattern ('*' and '?' wildcards, case insensitive).
+func matchPattern(pattern string, value string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+
+	matched, err := regexp.MatchString(fmt.Sprintf("(?i)^%s$", expr), value)
+
+	return err == nil && matched
+}
+
+// matchAny is used to check if at least one of the values match the given pattern.
+func matchAny(pattern string, values []string) bool {
+	for _, v := range values {
+		if matchPattern(pattern, v) {
+			return true
+		}
+	}
+
+	return false
+}
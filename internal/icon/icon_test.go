@@ -0,0 +1,129 @@
+package icon
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTestingRules is used to write the given rules to a temporary file.
+func writeTestingRules(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "icons.yaml")
+
+	err := os.WriteFile(file, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	return file
+}
+
+func TestParseCapabilities(t *testing.T) {
+	tests := map[string][]string{
+		"":            {},
+		"R S I":       {"R", "S", "I"},
+		"00 00 00 29": {"R", "S", "I"},
+		"00 00 00 28": {"S", "I"},
+		"0x29":        {"R", "S", "I"},
+		"r P":         {"r", "P"},
+	}
+
+	for value, expected := range tests {
+		c, err := ParseCapabilities(value)
+		if err != nil {
+			t.Fatalf("error while executing ParseCapabilities function for '%s'.\nReason : %v", value, err)
+		}
+
+		if !reflect.DeepEqual(c, expected) {
+			t.Fatalf("wrong capabilities returned for '%s'.\nExpected : %v\nReturned : %v", value, expected, c)
+		}
+	}
+}
+
+func TestParseCapabilitiesFail(t *testing.T) {
+	for _, value := range []string{"R X", "00 00 0G 29"} {
+		if _, err := ParseCapabilities(value); err == nil {
+			t.Fatalf("an error should be returned for the value '%s'", value)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := writeTestingRules(t, `rules:
+  - template: "Cisco IOS*"
+    image: Router_(64)
+    image_problem: Router_(64)_problem
+  - capabilities: S
+    image: Switch_(64)
+  - image: Server_(64)
+`)
+
+	r, err := Load(file)
+	if err != nil {
+		t.Fatalf("error while executing Load function.\nReason : %v", err)
+	}
+
+	if len(r.Rules) != 3 {
+		t.Fatalf("wrong number of rules returned.\nExpected : 3\nReturned : %d", len(r.Rules))
+	}
+
+	expected := []string{"Router_(64)", "Router_(64)_problem", "Switch_(64)", "Server_(64)"}
+	if !reflect.DeepEqual(r.Images(), expected) {
+		t.Fatalf("wrong images returned.\nExpected : %v\nReturned : %v", expected, r.Images())
+	}
+}
+
+func TestLoadFail(t *testing.T) {
+	tests := map[string]string{
+		"empty":              "rules: []\n",
+		"missing image":      "rules:\n  - template: Linux*\n",
+		"unknown field":      "rules:\n  - templat: Linux*\n    image: Server_(64)\n",
+		"wrong capabilities": "rules:\n  - capabilities: X\n    image: Server_(64)\n",
+	}
+
+	for name, content := range tests {
+		if _, err := Load(writeTestingRules(t, content)); err == nil {
+			t.Fatalf("an error should be returned for the test '%s'", name)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	r := &Rules{
+		Rules: []*Rule{
+			{Template: "cisco ios*", Image: "Router_(64)"},
+			{Group: "Network/Firewalls", Image: "Firewall_(64)"},
+			{InventoryType: "switch", Image: "Switch_(64)"},
+			{Tag: "role=core-*", Image: "Core_(64)"},
+			{Capabilities: "R S", Image: "Router_Switch_(64)"},
+			{Tag: "virtual", Image: "Cloud_(24)"},
+		},
+	}
+
+	tests := []struct {
+		host     *Host
+		expected string
+	}{
+		{&Host{Templates: []string{"Linux by Zabbix agent", "Cisco IOS by SNMP"}}, "Router_(64)"},
+		{&Host{Groups: []string{"Network/Firewalls"}}, "Firewall_(64)"},
+		{&Host{InventoryType: "Switch"}, "Switch_(64)"},
+		{&Host{Tags: []*Tag{{Name: "role", Value: "core-1"}}}, "Core_(64)"},
+		{&Host{Capabilities: []string{"R", "S", "I"}}, "Router_Switch_(64)"},
+		{&Host{Tags: []*Tag{{Name: "virtual", Value: ""}}}, "Cloud_(24)"},
+		{&Host{Capabilities: []string{"S", "I"}}, ""},
+	}
+
+	for i, test := range tests {
+		rule := r.Match(test.host)
+
+		image := ""
+		if rule != nil {
+			image = rule.Image
+		}
+
+		if image != test.expected {
+			t.Fatalf("wrong image returned for the test %d.\nExpected : '%s'\nReturned : '%s'", i, test.expected, image)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package icon
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+const (
+	// ActionCreate is used when the image does not exist on the server.
+	ActionCreate = "created"
+	// ActionUpdate is used when the content of the image on the server is different from the local file.
+	ActionUpdate = "updated"
+	// ActionNone is used when the image on the server is identical to the local file.
+	ActionNone = "unchanged"
+)
+
+// imageExtensions define the extensions of the files read from an images directory.
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif"}
+
+// LocalImage define an image file read from a local directory.
+type LocalImage struct {
+	// Name is the name of the image on the server, the name of the file without extension.
+	Name string
+	Path string
+	// Data is the base64 encoded content of the file.
+	Data string
+	// Hash is the SHA-256 hash of the content of the file.
+	Hash string
+}
+
+// SyncResult define the action executed for an image during a synchronization.
+type SyncResult struct {
+	Name   string
+	Id     string
+	Action string
+}
+
+// hashContent is used to compute the SHA-256 hash of the given content.
+func hashContent(b []byte) string {
+	h := sha256.Sum256(b)
+
+	return hex.EncodeToString(h[:])
+}
+
+// ReadDir is used to read the images (PNG, JPEG or GIF) of the given directory, sorted by name.
+// Each file is used as an image named after the file without its extension ('Router_(64).png' -> 'Router_(64)').
+func ReadDir(dir string) ([]*LocalImage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*LocalImage, 0)
+	paths := make(map[string]string, 0)
+
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || !utils.Contains(imageExtensions, ext) {
+			continue
+		}
+
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		path := filepath.Join(dir, e.Name())
+
+		if existing, exist := paths[name]; exist {
+			return nil, failure.Errorf(failure.Config, "the files '%s' and '%s' use the same image name '%s'", existing, path, name)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		paths[name] = path
+		out = append(out, &LocalImage{
+			Name: name,
+			Path: path,
+			Data: base64.StdEncoding.EncodeToString(b),
+			Hash: hashContent(b),
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+// remoteHash is used to compute the hash of the base64 encoded content of an image retrieved from the server.
+// An empty value is returned if the content cannot be decoded.
+func remoteHash(data string) string {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return ""
+	}
+
+	return hashContent(b)
+}
+
+// Sync is used to create the missing images on the server and update the images whose content changed.
+// When dryRun is true, the actions are only reported and the id of the images to create is empty.
+func Sync(client api.ZabbixAPI, images []*LocalImage, dryRun bool) ([]*SyncResult, error) {
+	names := make([]string, 0)
+	for _, i := range images {
+		names = append(names, i.Name)
+	}
+
+	out := make([]*SyncResult, 0)
+	if len(names) == 0 {
+		return out, nil
+	}
+
+	existing, err := client.GetImages(names)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]string, 0)
+	idList := make([]string, 0)
+	for _, e := range existing {
+		ids[e.Name] = e.Id
+		idList = append(idList, e.Id)
+	}
+
+	hashes := make(map[string]string, 0)
+	if len(idList) > 0 {
+		data, err := client.GetImagesData(idList)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range data {
+			hashes[d.Id] = remoteHash(d.Data)
+		}
+	}
+
+	for _, i := range images {
+		r := &SyncResult{
+			Name: i.Name,
+			Id:   ids[i.Name],
+		}
+
+		switch {
+		case r.Id == "":
+			r.Action = ActionCreate
+			if !dryRun {
+				r.Id, err = client.CreateImage(i.Name, i.Data)
+				if err != nil {
+					return nil, failure.Errorf(failure.API, "error while creating the image '%s'.\nReason : %w", i.Name, err)
+				}
+			}
+		case hashes[r.Id] != i.Hash:
+			r.Action = ActionUpdate
+			if !dryRun {
+				if err = client.UpdateImage(r.Id, i.Data); err != nil {
+					return nil, failure.Errorf(failure.API, "error while updating the image '%s'.\nReason : %w", i.Name, err)
+				}
+			}
+		default:
+			r.Action = ActionNone
+		}
+
+		out = append(out, r)
+	}
+
+	return out, nil
+}
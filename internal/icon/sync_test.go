@@ -0,0 +1,141 @@
+package icon
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+)
+
+// writeTestingImages is used to write the given files to a temporary directory.
+func writeTestingImages(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+		}
+	}
+
+	return dir
+}
+
+func TestReadDir(t *testing.T) {
+	dir := writeTestingImages(t, map[string]string{
+		"Switch_(64).png": "switch",
+		"Router_(64).PNG": "router",
+		"README.md":       "ignored",
+	})
+
+	images, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("error while executing ReadDir function.\nReason : %v", err)
+	}
+
+	if len(images) != 2 || images[0].Name != "Router_(64)" || images[1].Name != "Switch_(64)" {
+		t.Fatalf("wrong images returned.\nExpected : [Router_(64) Switch_(64)]\nReturned : %v", images)
+	}
+
+	if images[0].Data != base64.StdEncoding.EncodeToString([]byte("router")) {
+		t.Fatalf("wrong content returned.\nReturned : %s", images[0].Data)
+	}
+}
+
+func TestReadDirFail(t *testing.T) {
+	dir := writeTestingImages(t, map[string]string{
+		"Switch_(64).png": "switch",
+		"Switch_(64).gif": "switch",
+	})
+
+	if _, err := ReadDir(dir); err == nil {
+		t.Fatal("an error should be returned when two files use the same image name")
+	}
+
+	if _, err := ReadDir(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("an error should be returned when the directory does not exist")
+	}
+}
+
+func TestSync(t *testing.T) {
+	dir := writeTestingImages(t, map[string]string{
+		"Cloud_(24).png":  "cloud",
+		"Router_(64).png": "router",
+		"Switch_(64).png": "switch v2",
+	})
+
+	client := fake.NewClient().
+		AddImage("1", "Cloud_(24)").
+		SetImageData("1", base64.StdEncoding.EncodeToString([]byte("cloud"))).
+		AddImage("2", "Switch_(64)").
+		SetImageData("2", base64.StdEncoding.EncodeToString([]byte("switch")))
+
+	images, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("error while executing ReadDir function.\nReason : %v", err)
+	}
+
+	// Nothing is changed when using the dry-run mode
+	results, err := Sync(client, images, true)
+	if err != nil {
+		t.Fatalf("error while executing Sync function.\nReason : %v", err)
+	}
+
+	if len(client.Images) != 2 || results[1].Action != ActionCreate || results[1].Id != "" {
+		t.Fatalf("no image should be created when using the dry-run mode.\nReturned : %v", client.Images)
+	}
+
+	results, err = Sync(client, images, false)
+	if err != nil {
+		t.Fatalf("error while executing Sync function.\nReason : %v", err)
+	}
+
+	expected := []*SyncResult{
+		{Name: "Cloud_(24)", Id: "1", Action: ActionNone},
+		{Name: "Router_(64)", Id: "3", Action: ActionCreate},
+		{Name: "Switch_(64)", Id: "2", Action: ActionUpdate},
+	}
+
+	for i, r := range results {
+		if *r != *expected[i] {
+			t.Fatalf("wrong result returned.\nExpected : %+v\nReturned : %+v", expected[i], r)
+		}
+	}
+
+	if client.Images[1].Data != base64.StdEncoding.EncodeToString([]byte("switch v2")) {
+		t.Fatalf("the content of the image 'Switch_(64)' was not updated.\nReturned : %s", client.Images[1].Data)
+	}
+}
+
+// failingImagesClient is used to simulate the API rejecting the creation and the update of the images.
+type failingImagesClient struct {
+	*fake.Client
+}
+
+// CreateImage is used to return an API error.
+func (c *failingImagesClient) CreateImage(name string, data string) (string, error) {
+	return "", failure.Errorf(failure.API, "the image '%s' already exists", name)
+}
+
+func TestSyncFail(t *testing.T) {
+	dir := writeTestingImages(t, map[string]string{
+		"Router_(64).png": "router",
+	})
+
+	images, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("error while executing ReadDir function.\nReason : %v", err)
+	}
+
+	_, err = Sync(&failingImagesClient{Client: fake.NewClient()}, images, false)
+	if err == nil {
+		t.Fatal("an error should be returned when the image cannot be created")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.API {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.API, kind)
+	}
+}
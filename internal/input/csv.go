@@ -0,0 +1,82 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// csvColumns associate the name of each supported column (the JSON tags of a Mapping) to the field to set.
+var csvColumns = map[string]func(m *zbxmap.Mapping, value string){
+	"local_host":             func(m *zbxmap.Mapping, value string) { m.LocalHost = value },
+	"local_interface":        func(m *zbxmap.Mapping, value string) { m.LocalInterface = value },
+	"local_trigger_pattern":  func(m *zbxmap.Mapping, value string) { m.LocalTriggerPattern = value },
+	"local_image":            func(m *zbxmap.Mapping, value string) { m.LocalImage = value },
+	"remote_host":            func(m *zbxmap.Mapping, value string) { m.RemoteHost = value },
+	"remote_interface":       func(m *zbxmap.Mapping, value string) { m.RemoteInterface = value },
+	"remote_trigger_pattern": func(m *zbxmap.Mapping, value string) { m.RemoteTriggerPattern = value },
+	"remote_image":           func(m *zbxmap.Mapping, value string) { m.RemoteImage = value },
+	"local_label":            func(m *zbxmap.Mapping, value string) { m.LocalLabel = value },
+	"remote_label":           func(m *zbxmap.Mapping, value string) { m.RemoteLabel = value },
+	"color":                  func(m *zbxmap.Mapping, value string) { m.Color = value },
+	"trigger_color":          func(m *zbxmap.Mapping, value string) { m.TriggerColor = value },
+	"local_capabilities":     func(m *zbxmap.Mapping, value string) { m.LocalCapabilities = value },
+	"remote_capabilities":    func(m *zbxmap.Mapping, value string) { m.RemoteCapabilities = value },
+	"local_type":             func(m *zbxmap.Mapping, value string) { m.LocalType = value },
+	"remote_type":            func(m *zbxmap.Mapping, value string) { m.RemoteType = value },
+}
+
+// readCSV is used to read mappings from a CSV document.
+// The first line is used as header, each column is mapped to the Mapping field with the same JSON tag.
+// Columns can be declared in any order, lines starting with '#' are ignored.
+func readCSV(b []byte) ([]*zbxmap.Mapping, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return make([]*zbxmap.Mapping, 0), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	setters := make([]func(m *zbxmap.Mapping, value string), len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+
+		setter, exist := csvColumns[column]
+		if !exist {
+			return nil, fmt.Errorf("unknown column '%s' in the CSV header", column)
+		}
+
+		setters[i] = setter
+	}
+
+	entries := make([]*zbxmap.Mapping, 0)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		m := &zbxmap.Mapping{}
+		for i, value := range record {
+			setters[i](m, strings.TrimSpace(value))
+		}
+
+		entries = append(entries, m)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,56 @@
+package input
+
+import "testing"
+
+func TestReadCSV(t *testing.T) {
+	m, err := readCSV([]byte(`# Core links
+remote_host, local_host, local_interface, remote_interface, local_image, remote_image, local_trigger_pattern, remote_trigger_pattern
+router-2, router-1, eth0, eth1, Firewall_(64), Switch_(64), "Interface eth0(): Link down", "Interface eth1(): Link down"
+router-3, router-1, eth1, eth0, Firewall_(64), Switch_(64), , 
+`))
+
+	if err != nil {
+		t.Fatalf("error while executing readCSV function.\nReason : %v", err)
+	}
+
+	if len(m) != 2 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 2\nReturned : %d", len(m))
+	}
+
+	if m[0].LocalHost != "router-1" || m[0].RemoteHost != "router-2" || m[0].RemoteInterface != "eth1" {
+		t.Fatalf("the columns were not mapped to the right fields.\nReturned : %+v", m[0])
+	}
+
+	if m[0].LocalTriggerPattern != "Interface eth0(): Link down" {
+		t.Fatalf("wrong local trigger pattern returned.\nExpected : Interface eth0(): Link down\nReturned : %s", m[0].LocalTriggerPattern)
+	}
+
+	if m[1].RemoteTriggerPattern != "" {
+		t.Fatalf("an empty column should result in an empty field.\nReturned : %s", m[1].RemoteTriggerPattern)
+	}
+}
+
+func TestReadCSVUnknownColumn(t *testing.T) {
+	_, err := readCSV([]byte("local_host,remote_imgae\nrouter-1,Switch_(64)\n"))
+	if err == nil {
+		t.Fatal("an error should be returned when the header contains an unknown column")
+	}
+}
+
+func TestReadCSVWrongNumberOfFields(t *testing.T) {
+	_, err := readCSV([]byte("local_host,remote_host\nrouter-1\n"))
+	if err == nil {
+		t.Fatal("an error should be returned when a line does not have the same number of fields as the header")
+	}
+}
+
+func TestReadCSVEmpty(t *testing.T) {
+	m, err := readCSV([]byte(""))
+	if err != nil {
+		t.Fatalf("error while executing readCSV function.\nReason : %v", err)
+	}
+
+	if len(m) != 0 {
+		t.Fatalf("no mapping should be returned for an empty document.\nReturned : %d", len(m))
+	}
+}
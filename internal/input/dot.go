@@ -0,0 +1,467 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// dotToken define a token of a DOT document.
+type dotToken struct {
+	value string
+	// quoted is set for identifiers written as a quoted string, to distinguish them from keywords and operators.
+	quoted bool
+	line   int
+}
+
+// isDotIdChar is used to check if the given character can be used in an unquoted DOT identifier.
+func isDotIdChar(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenizeDOT is used to split a DOT document into tokens.
+// Comments (//, # and /* */) are removed.
+func tokenizeDOT(b []byte) ([]*dotToken, error) {
+	s := []rune(string(b))
+	tokens := make([]*dotToken, 0)
+	line := 1
+
+	for i := 0; i < len(s); i++ {
+		r := s[i]
+
+		switch {
+		case r == '\n':
+			line++
+		case unicode.IsSpace(r):
+			continue
+		case r == '#' || (r == '/' && i+1 < len(s) && s[i+1] == '/'):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+			line++
+		case r == '/' && i+1 < len(s) && s[i+1] == '*':
+			i += 2
+			for i+1 < len(s) && !(s[i] == '*' && s[i+1] == '/') {
+				if s[i] == '\n' {
+					line++
+				}
+				i++
+			}
+
+			if i+1 >= len(s) {
+				return nil, fmt.Errorf("line %d : unterminated comment", line)
+			}
+			i++
+		case r == '"':
+			var value strings.Builder
+			start := line
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && s[i+1] == '"' {
+					i++
+				} else if s[i] == '\n' {
+					line++
+				}
+				value.WriteRune(s[i])
+			}
+
+			if i >= len(s) {
+				return nil, fmt.Errorf("line %d : unterminated string", start)
+			}
+
+			tokens = append(tokens, &dotToken{value: value.String(), quoted: true, line: start})
+		case r == '-' && i+1 < len(s) && (s[i+1] == '-' || s[i+1] == '>'):
+			tokens = append(tokens, &dotToken{value: string(s[i : i+2]), line: line})
+			i++
+		case strings.ContainsRune("{}[]=;,:", r):
+			tokens = append(tokens, &dotToken{value: string(r), line: line})
+		case isDotIdChar(r) || r == '-':
+			start := i
+			// Hyphens are accepted inside unquoted identifiers (router-1) as long as they are not an edge operator
+			for i+1 < len(s) && (isDotIdChar(s[i+1]) || (s[i+1] == '-' && i+2 < len(s) && isDotIdChar(s[i+2]))) {
+				i++
+			}
+			tokens = append(tokens, &dotToken{value: string(s[start : i+1]), line: line})
+		default:
+			return nil, fmt.Errorf("line %d : unexpected character '%c'", line, r)
+		}
+	}
+
+	return tokens, nil
+}
+
+// dotEndpoint define a node referenced by an edge, with the optional port used as interface.
+type dotEndpoint struct {
+	node string
+	port string
+}
+
+// dotEdge define an edge between two nodes with its attributes.
+type dotEdge struct {
+	from       *dotEndpoint
+	to         *dotEndpoint
+	attributes map[string]string
+}
+
+// dotParser is used to parse the tokens of a DOT document.
+type dotParser struct {
+	tokens []*dotToken
+	pos    int
+	// nodes associate the name of each node to its attributes.
+	nodes map[string]map[string]string
+	edges []*dotEdge
+	// nodeDefaults and edgeDefaults contain the attributes set with the 'node [...]' and 'edge [...]' statements.
+	nodeDefaults map[string]string
+	edgeDefaults map[string]string
+}
+
+// peek is used to retrieve the current token without consuming it.
+func (p *dotParser) peek() *dotToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+
+	return p.tokens[p.pos]
+}
+
+// next is used to consume the current token.
+func (p *dotParser) next() *dotToken {
+	t := p.peek()
+	if t != nil {
+		p.pos++
+	}
+
+	return t
+}
+
+// is is used to check if the current token is the given operator or keyword.
+func (p *dotParser) is(value string) bool {
+	t := p.peek()
+	return t != nil && !t.quoted && strings.EqualFold(t.value, value)
+}
+
+// expect is used to consume the given operator, an error is returned if the current token does not match.
+func (p *dotParser) expect(value string) error {
+	t := p.next()
+	if t == nil {
+		return fmt.Errorf("unexpected end of document, expected '%s'", value)
+	}
+
+	if t.quoted || t.value != value {
+		return fmt.Errorf("line %d : expected '%s', found '%s'", t.line, value, t.value)
+	}
+
+	return nil
+}
+
+// id is used to consume an identifier.
+func (p *dotParser) id() (string, error) {
+	t := p.next()
+	if t == nil {
+		return "", fmt.Errorf("unexpected end of document, expected an identifier")
+	}
+
+	if !t.quoted && (strings.ContainsAny(t.value, "{}[]=;,:") || t.value == "--" || t.value == "->") {
+		return "", fmt.Errorf("line %d : expected an identifier, found '%s'", t.line, t.value)
+	}
+
+	return t.value, nil
+}
+
+// attributes is used to consume a list of attributes ('[key=value, ...]'), multiple lists can follow each other.
+func (p *dotParser) attributes() (map[string]string, error) {
+	out := make(map[string]string, 0)
+
+	for p.is("[") {
+		p.next()
+
+		for !p.is("]") {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+
+			if err = p.expect("="); err != nil {
+				return nil, err
+			}
+
+			value, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+
+			out[strings.ToLower(key)] = value
+
+			if p.is(",") || p.is(";") {
+				p.next()
+			}
+		}
+
+		p.next()
+	}
+
+	return out, nil
+}
+
+// endpoint is used to consume a node identifier with its optional port.
+func (p *dotParser) endpoint() (*dotEndpoint, error) {
+	node, err := p.id()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &dotEndpoint{node: node}
+	if p.is(":") {
+		p.next()
+
+		e.port, err = p.id()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// addNode is used to register a node, attributes already set are overridden by the new ones.
+func (p *dotParser) addNode(name string, attributes map[string]string) {
+	node, exist := p.nodes[name]
+	if !exist {
+		node = make(map[string]string, 0)
+		for key, value := range p.nodeDefaults {
+			node[key] = value
+		}
+
+		p.nodes[name] = node
+	}
+
+	for key, value := range attributes {
+		node[key] = value
+	}
+}
+
+// statements is used to consume the statements of a graph or subgraph until the closing brace.
+func (p *dotParser) statements() error {
+	for !p.is("}") {
+		if p.peek() == nil {
+			return fmt.Errorf("unexpected end of document, expected '}'")
+		}
+
+		if err := p.statement(); err != nil {
+			return err
+		}
+
+		if p.is(";") {
+			p.next()
+		}
+	}
+
+	p.next()
+
+	return nil
+}
+
+// statement is used to consume a single statement.
+func (p *dotParser) statement() error {
+	switch {
+	case p.is("node") || p.is("edge") || p.is("graph"):
+		keyword := strings.ToLower(p.next().value)
+		attributes, err := p.attributes()
+		if err != nil {
+			return err
+		}
+
+		defaults := p.nodeDefaults
+		if keyword == "edge" {
+			defaults = p.edgeDefaults
+		}
+
+		// Graph attributes are not used
+		if keyword != "graph" {
+			for key, value := range attributes {
+				defaults[key] = value
+			}
+		}
+
+		return nil
+	case p.is("subgraph") || p.is("{"):
+		if p.is("subgraph") {
+			p.next()
+			if !p.is("{") {
+				if _, err := p.id(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := p.expect("{"); err != nil {
+			return err
+		}
+
+		return p.statements()
+	}
+
+	from, err := p.endpoint()
+	if err != nil {
+		return err
+	}
+
+	// Graph attribute (key = value)
+	if p.is("=") {
+		p.next()
+		_, err = p.id()
+
+		return err
+	}
+
+	if !p.is("--") && !p.is("->") {
+		attributes, err := p.attributes()
+		if err != nil {
+			return err
+		}
+
+		p.addNode(from.node, attributes)
+
+		return nil
+	}
+
+	// Edge statement, chains (a -- b -- c) are converted to one edge per pair of nodes
+	endpoints := []*dotEndpoint{from}
+	for p.is("--") || p.is("->") {
+		p.next()
+
+		to, err := p.endpoint()
+		if err != nil {
+			return err
+		}
+
+		endpoints = append(endpoints, to)
+	}
+
+	attributes, err := p.attributes()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(endpoints)-1; i++ {
+		edge := &dotEdge{
+			from:       endpoints[i],
+			to:         endpoints[i+1],
+			attributes: make(map[string]string, 0),
+		}
+
+		for key, value := range p.edgeDefaults {
+			edge.attributes[key] = value
+		}
+
+		for key, value := range attributes {
+			edge.attributes[key] = value
+		}
+
+		p.edges = append(p.edges, edge)
+	}
+
+	for _, e := range endpoints {
+		p.addNode(e.node, nil)
+	}
+
+	return nil
+}
+
+// firstOf is used to retrieve the first non empty value of the given attributes.
+func firstOf(attributes map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := attributes[key]; value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// readDOT is used to read mappings from a Graphviz DOT document.
+// Nodes are used as hosts with the 'image' (or 'image_name') attribute as image and the 'capabilities' attribute as CDP capabilities.
+// The 'type' attribute of a node is used as element type (host by default).
+// Edges are used as links with the following attributes :
+//   - 'local_interface' / 'remote_interface' (or 'taillabel' / 'headlabel', or the ports of the nodes)
+//   - 'local_trigger_pattern' / 'remote_trigger_pattern' (or 'local_trigger' / 'remote_trigger')
+func readDOT(b []byte) ([]*zbxmap.Mapping, error) {
+	tokens, err := tokenizeDOT(b)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dotParser{
+		tokens:       tokens,
+		nodes:        make(map[string]map[string]string, 0),
+		edges:        make([]*dotEdge, 0),
+		nodeDefaults: make(map[string]string, 0),
+		edgeDefaults: make(map[string]string, 0),
+	}
+
+	if p.peek() == nil {
+		return make([]*zbxmap.Mapping, 0), nil
+	}
+
+	if p.is("strict") {
+		p.next()
+	}
+
+	if !p.is("graph") && !p.is("digraph") {
+		return nil, fmt.Errorf("line %d : expected 'graph' or 'digraph', found '%s'", p.peek().line, p.peek().value)
+	}
+
+	p.next()
+	if !p.is("{") {
+		if _, err = p.id(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	if err = p.statements(); err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t != nil {
+		return nil, fmt.Errorf("line %d : unexpected content after the end of the graph '%s'", t.line, t.value)
+	}
+
+	entries := make([]*zbxmap.Mapping, 0)
+	for _, e := range p.edges {
+		local := p.nodes[e.from.node]
+		remote := p.nodes[e.to.node]
+
+		m := &zbxmap.Mapping{
+			LocalHost:            e.from.node,
+			LocalInterface:       firstOf(e.attributes, "local_interface", "taillabel"),
+			LocalTriggerPattern:  firstOf(e.attributes, "local_trigger_pattern", "local_trigger"),
+			LocalImage:           firstOf(local, "image", "image_name"),
+			LocalCapabilities:    firstOf(local, "capabilities"),
+			LocalType:            firstOf(local, "type"),
+			RemoteHost:           e.to.node,
+			RemoteInterface:      firstOf(e.attributes, "remote_interface", "headlabel"),
+			RemoteTriggerPattern: firstOf(e.attributes, "remote_trigger_pattern", "remote_trigger"),
+			RemoteImage:          firstOf(remote, "image", "image_name"),
+			RemoteCapabilities:   firstOf(remote, "capabilities"),
+			RemoteType:           firstOf(remote, "type"),
+		}
+
+		if m.LocalInterface == "" {
+			m.LocalInterface = e.from.port
+		}
+
+		if m.RemoteInterface == "" {
+			m.RemoteInterface = e.to.port
+		}
+
+		entries = append(entries, m)
+	}
+
+	return entries, nil
+}
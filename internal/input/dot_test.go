@@ -0,0 +1,91 @@
+package input
+
+import "testing"
+
+func TestReadDOT(t *testing.T) {
+	m, err := readDOT([]byte(`// Core network
+strict graph "core" {
+    node [image="Switch_(64)"];
+    edge [local_trigger_pattern="Interface eth0(): Link down"];
+
+    subgraph cluster_dc1 {
+        label = "DC1";
+        "router-1" [image="Firewall_(64)", shape=box];
+        router-2;
+    }
+
+    /* Links */
+    "router-1" -- router-2 [local_interface=eth0, remote_interface="eth1", remote_trigger_pattern="Interface eth1(): Link down"];
+    "router-1":eth1 -- "router-3":eth0
+    router-2 -- router-3 -- router-4 [taillabel=ge0, headlabel=ge1];
+}
+`))
+
+	if err != nil {
+		t.Fatalf("error while executing readDOT function.\nReason : %v", err)
+	}
+
+	if len(m) != 4 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 4\nReturned : %d", len(m))
+	}
+
+	expected := [][]string{
+		{"router-1", "eth0", "Firewall_(64)", "router-2", "eth1", "Switch_(64)"},
+		{"router-1", "eth1", "Firewall_(64)", "router-3", "eth0", "Switch_(64)"},
+		{"router-2", "ge0", "Switch_(64)", "router-3", "ge1", "Switch_(64)"},
+		{"router-3", "ge0", "Switch_(64)", "router-4", "ge1", "Switch_(64)"},
+	}
+
+	for i, e := range expected {
+		returned := []string{m[i].LocalHost, m[i].LocalInterface, m[i].LocalImage, m[i].RemoteHost, m[i].RemoteInterface, m[i].RemoteImage}
+		for j := range e {
+			if e[j] != returned[j] {
+				t.Fatalf("wrong mapping returned for the edge %d.\nExpected : %v\nReturned : %v", i, e, returned)
+			}
+		}
+	}
+
+	if m[0].LocalTriggerPattern != "Interface eth0(): Link down" || m[0].RemoteTriggerPattern != "Interface eth1(): Link down" {
+		t.Fatalf("wrong trigger patterns returned.\nReturned : %s, %s", m[0].LocalTriggerPattern, m[0].RemoteTriggerPattern)
+	}
+}
+
+func TestReadDOTExport(t *testing.T) {
+	// Document generated by the 'graph' command
+	m, err := readDOT([]byte(`graph "topology" {
+  node [shape=box];
+  subgraph "cluster_0" {
+    label="Routers";
+    "router-1" [label="router-1", image_name="Firewall_(64)"];
+    "router-2" [label="router-2", image_name="Switch_(64)"];
+  }
+  "router-1" -- "router-2" [label="eth0 - eth0", local_trigger="Interface eth0(): Link down", remote_trigger="Interface eth0(): Link down"];
+}
+`))
+
+	if err != nil {
+		t.Fatalf("error while executing readDOT function.\nReason : %v", err)
+	}
+
+	if len(m) != 1 || m[0].LocalImage != "Firewall_(64)" || m[0].RemoteTriggerPattern != "Interface eth0(): Link down" {
+		t.Fatalf("wrong mappings returned.\nReturned : %+v", m[0])
+	}
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	documents := map[string]string{
+		"not a graph":          `node [image="Switch_(64)"];`,
+		"missing brace":        `graph { a -- b`,
+		"unterminated string":  `graph { "a -- b }`,
+		"unterminated comment": `graph { a -- b /* }`,
+		"missing value":        `graph { a -- b [label=] }`,
+		"trailing content":     `graph { a -- b } c`,
+		"unexpected character": `graph { a -- b @ }`,
+	}
+
+	for name, document := range documents {
+		if _, err := readDOT([]byte(document)); err == nil {
+			t.Fatalf("an error should be returned for the document '%s'", name)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// Formats define the list of supported mapping file formats.
+var Formats = []string{"json", "yaml", "csv", "dot"}
+
+// DetectFormat is used to retrieve the format of a mapping file from its extension.
+// The JSON format is used for unknown extensions.
+func DetectFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".csv":
+		return "csv"
+	case ".dot", ".gv":
+		return "dot"
+	default:
+		return "json"
+	}
+}
+
+// Read is used to read the mappings from the given file.
+// If the format is empty, it is detected from the extension of the file.
+func Read(file string, format string) ([]*zbxmap.Mapping, error) {
+	if format == "" {
+		format = DetectFormat(file)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadBytes(b, format)
+}
+
+// ReadBytes is used to read the mappings from the given content of a mapping file written in the given format.
+func ReadBytes(b []byte, format string) ([]*zbxmap.Mapping, error) {
+	switch format {
+	case "json":
+		return readJSON(b)
+	case "yaml":
+		return readYAML(b)
+	case "csv":
+		return readCSV(b)
+	case "dot":
+		return readDOT(b)
+	default:
+		return nil, fmt.Errorf("unsupported mapping format '%s', supported formats are %v", format, Formats)
+	}
+}
+
+// readJSON is used to read mappings from a JSON document.
+func readJSON(b []byte) ([]*zbxmap.Mapping, error) {
+	entries := make([]*zbxmap.Mapping, 0)
+	err := json.Unmarshal(b, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,122 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var mappingFilePath string
+
+func init() {
+	pwd, _ := os.Getwd()
+	mappingFilePath = filepath.Join(pwd, "..", "..", "examples", "mapping.json")
+}
+
+// writeTestingFile is used to write the given content to a temporary file with the given name.
+func writeTestingFile(t *testing.T, name string, content string) string {
+	file := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(file, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	return file
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"mapping.json": "json",
+		"mapping.CSV":  "csv",
+		"mapping.yml":  "yaml",
+		"mapping.dot":  "dot",
+		"mapping.gv":   "dot",
+		"mapping":      "json",
+	}
+
+	for file, expected := range tests {
+		if format := DetectFormat(file); format != expected {
+			t.Fatalf("wrong format returned for the file '%s'.\nExpected : %s\nReturned : %s", file, expected, format)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	m, err := Read(mappingFilePath, "")
+	if err != nil {
+		t.Fatalf("error while executing Read function.\nReason : %v", err)
+	}
+
+	if len(m) != 2 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 2\nReturned : %d", len(m))
+	}
+}
+
+func TestReadFormatOverride(t *testing.T) {
+	file := writeTestingFile(t, "mapping.txt", "local_host,remote_host\nrouter-1,router-2\n")
+
+	m, err := Read(file, "csv")
+	if err != nil {
+		t.Fatalf("error while executing Read function.\nReason : %v", err)
+	}
+
+	if len(m) != 1 || m[0].RemoteHost != "router-2" {
+		t.Fatalf("wrong mappings returned.\nReturned : %v", m)
+	}
+}
+
+func TestReadBytes(t *testing.T) {
+	m, err := ReadBytes([]byte("local_host,remote_host\nrouter-1,router-2\n"), "csv")
+	if err != nil {
+		t.Fatalf("error while executing ReadBytes function.\nReason : %v", err)
+	}
+
+	if len(m) != 1 || m[0].LocalHost != "router-1" || m[0].RemoteHost != "router-2" {
+		t.Fatalf("wrong mappings returned.\nReturned : %v", m)
+	}
+
+	if _, err = ReadBytes([]byte("local_host,remote_host\n"), "xml"); err == nil {
+		t.Fatal("an error should be returned when the format is not supported")
+	}
+}
+
+func TestReadUnsupportedFormat(t *testing.T) {
+	_, err := Read(mappingFilePath, "xml")
+	if err == nil {
+		t.Fatal("an error should be returned when the format is not supported")
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing.json"), "")
+	if err == nil {
+		t.Fatal("an error should be returned when the given file does not exist")
+	}
+}
+
+func TestReadExamples(t *testing.T) {
+	expected, err := Read(mappingFilePath, "")
+	if err != nil {
+		t.Fatalf("error while executing Read function.\nReason : %v", err)
+	}
+
+	for _, name := range []string{"mapping.yaml", "mapping.csv", "mapping.dot"} {
+		file := filepath.Join(filepath.Dir(mappingFilePath), name)
+
+		m, err := Read(file, "")
+		if err != nil {
+			t.Fatalf("error while reading the file '%s'.\nReason : %v", file, err)
+		}
+
+		if len(m) != len(expected) {
+			t.Fatalf("wrong number of mappings returned for the file '%s'.\nExpected : %d\nReturned : %d", file, len(expected), len(m))
+		}
+
+		for i := range m {
+			if *m[i] != *expected[i] {
+				t.Fatalf("the mapping %d of the file '%s' does not match the JSON example.\nExpected : %+v\nReturned : %+v", i, file, *expected[i], *m[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// RequiredFields define the fields that must be set for each mapping.
+// Images are optional since they can be selected using icon rules.
+var RequiredFields = []string{
+	"local_host",
+	"remote_host",
+}
+
+// TriggerFields define the fields required only when the element of the endpoint uses a trigger (host and trigger elements).
+var TriggerFields = []string{
+	"local_trigger_pattern",
+	"remote_trigger_pattern",
+}
+
+// Issue define a problem found while validating a mapping file.
+type Issue struct {
+	// Entry is the index of the mapping, -1 if the issue is not related to a single mapping.
+	Entry int
+	// Line and Column are the position of the issue in the file, 0 if the position is not known.
+	Line    int
+	Column  int
+	Message string
+}
+
+// String is used to format the issue with its position.
+func (i *Issue) String() string {
+	position := make([]string, 0)
+
+	if i.Entry >= 0 {
+		position = append(position, fmt.Sprintf("entry %d", i.Entry))
+	}
+
+	if i.Line > 0 {
+		position = append(position, fmt.Sprintf("line %d, column %d", i.Line, i.Column))
+	}
+
+	if len(position) == 0 {
+		return i.Message
+	}
+
+	return fmt.Sprintf("%s : %s", strings.Join(position, ", "), i.Message)
+}
+
+// ValidationError is returned when issues were found in a mapping file.
+type ValidationError struct {
+	File   string
+	Issues []*Issue
+}
+
+// Error is used to format the list of issues.
+func (e *ValidationError) Error() string {
+	lines := []string{
+		fmt.Sprintf("%d issue(s) found in the mapping file '%s'", len(e.Issues), e.File),
+	}
+
+	for _, i := range e.Issues {
+		lines = append(lines, fmt.Sprintf("  - %s", i.String()))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// position is used to convert an offset in the given document to a line and a column (starting at 1).
+func position(b []byte, offset int64) (int, int) {
+	if offset > int64(len(b)) {
+		offset = int64(len(b))
+	}
+
+	before := b[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// Validate is used to validate the given mapping file.
+// If the format is empty, it is detected from the extension of the file.
+// A *ValidationError is returned when issues are found in the file.
+func Validate(file string, format string) error {
+	if format == "" {
+		format = DetectFormat(file)
+	}
+
+	if !utils.Contains(Formats, format) {
+		return fmt.Errorf("unsupported mapping format '%s', supported formats are %v", format, Formats)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	return ValidateBytes(file, b, format)
+}
+
+// ValidateBytes is used to validate the given content of a mapping file written in the given format.
+// The name of the file is only used in the returned error, a *ValidationError is returned when issues are found.
+func ValidateBytes(file string, b []byte, format string) error {
+	if !utils.Contains(Formats, format) {
+		return fmt.Errorf("unsupported mapping format '%s', supported formats are %v", format, Formats)
+	}
+
+	var issues []*Issue
+	if format == "json" {
+		issues = validateJSON(b)
+	} else {
+		issues = validateFormat(b, format)
+	}
+
+	if len(issues) > 0 {
+		return &ValidationError{
+			File:   file,
+			Issues: issues,
+		}
+	}
+
+	return nil
+}
+
+// validateFormat is used to validate the content of a mapping file written in a format without position information for each mapping.
+func validateFormat(b []byte, format string) []*Issue {
+	entries, err := ReadBytes(b, format)
+	if err != nil {
+		return []*Issue{
+			{
+				Entry:   -1,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	issues := make([]*Issue, 0)
+	for i, m := range entries {
+		for _, message := range fieldIssues(m) {
+			issues = append(issues, &Issue{
+				Entry:   i,
+				Message: message,
+			})
+		}
+	}
+
+	return append(issues, validateLinks(entries, nil)...)
+}
+
+// validateJSON is used to validate a JSON mapping file.
+// Unknown fields, missing required fields and invalid values are reported with the position of the mapping.
+func validateJSON(b []byte) []*Issue {
+	issues := make([]*Issue, 0)
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	syntaxIssue := func(err error) []*Issue {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := position(b, syntaxErr.Offset)
+			return append(issues, &Issue{Entry: -1, Line: line, Column: column, Message: syntaxErr.Error()})
+		}
+
+		return append(issues, &Issue{Entry: -1, Message: err.Error()})
+	}
+
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return append(issues, &Issue{Entry: -1, Message: "no mapping were found"})
+	}
+
+	if err != nil {
+		return syntaxIssue(err)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return append(issues, &Issue{Entry: -1, Line: 1, Column: 1, Message: "the mapping file must contain a list of mappings"})
+	}
+
+	// Invalid entries are stored as nil to keep the index of each entry
+	entries := make([]*zbxmap.Mapping, 0)
+	starts := make([]int64, 0)
+
+	for i := 0; decoder.More(); i++ {
+		// Skip the separators to retrieve the position of the first character of the entry
+		start := decoder.InputOffset()
+		for start < int64(len(b)) && strings.ContainsRune(" \t\r\n,", rune(b[start])) {
+			start++
+		}
+
+		line, column := position(b, start)
+		entries = append(entries, nil)
+		starts = append(starts, start)
+
+		fields := make(map[string]json.RawMessage, 0)
+		if err = decoder.Decode(&fields); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				issues = append(issues, &Issue{Entry: i, Line: line, Column: column, Message: "a mapping must be a JSON object"})
+				continue
+			}
+
+			return syntaxIssue(err)
+		}
+
+		end := decoder.InputOffset()
+		m := &zbxmap.Mapping{}
+		raw, _ := json.Marshal(fields)
+
+		// Decode the entry using the same rules as the reader, unknown fields are rejected
+		entryDecoder := json.NewDecoder(bytes.NewReader(raw))
+		entryDecoder.DisallowUnknownFields()
+		if err = entryDecoder.Decode(m); err != nil {
+			// Report each unknown field at its own position
+			keys := make([]string, 0)
+			for key := range fields {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			unknown := false
+			for _, key := range keys {
+				if !isKnownField(key) {
+					keyLine, keyColumn := line, column
+					if index := bytes.Index(b[start:end], []byte(fmt.Sprintf("%q", key))); index >= 0 {
+						keyLine, keyColumn = position(b, start+int64(index))
+					}
+
+					issues = append(issues, &Issue{Entry: i, Line: keyLine, Column: keyColumn, Message: fmt.Sprintf("unknown field '%s'", key)})
+					unknown = true
+				}
+			}
+
+			if !unknown {
+				issues = append(issues, &Issue{Entry: i, Line: line, Column: column, Message: err.Error()})
+				continue
+			}
+
+			// Decode the known fields to continue the validation
+			m = &zbxmap.Mapping{}
+			if err = json.Unmarshal(raw, m); err != nil {
+				issues = append(issues, &Issue{Entry: i, Line: line, Column: column, Message: err.Error()})
+				continue
+			}
+		}
+
+		for _, message := range fieldIssues(m) {
+			issues = append(issues, &Issue{Entry: i, Line: line, Column: column, Message: message})
+		}
+
+		entries[i] = m
+	}
+
+	if _, err = decoder.Token(); err != nil {
+		return syntaxIssue(err)
+	}
+
+	if len(entries) == 0 {
+		issues = append(issues, &Issue{Entry: -1, Message: "no mapping were found"})
+	}
+
+	// Add the position of each entry to the link issues
+	linkIssues := validateLinks(entries, func(entry int) (int, int) {
+		return position(b, starts[entry])
+	})
+
+	return append(issues, linkIssues...)
+}
+
+// isKnownField is used to check if the given key is a field of a Mapping.
+func isKnownField(key string) bool {
+	_, exist := csvColumns[key]
+
+	return exist
+}
+
+// missingFields is used to retrieve the required fields not set in the given mapping.
+// Trigger patterns are only required for the host and trigger elements.
+func missingFields(m *zbxmap.Mapping) []string {
+	fields := []struct {
+		name     string
+		value    string
+		required bool
+	}{
+		{"local_host", m.LocalHost, true},
+		{"local_trigger_pattern", m.LocalTriggerPattern, zbxmap.UsesTrigger(m.LocalType)},
+		{"remote_host", m.RemoteHost, true},
+		{"remote_trigger_pattern", m.RemoteTriggerPattern, zbxmap.UsesTrigger(m.RemoteType)},
+	}
+
+	out := make([]string, 0)
+	for _, field := range fields {
+		if field.required && strings.TrimSpace(field.value) == "" {
+			out = append(out, field.name)
+		}
+	}
+
+	return out
+}
+
+// fieldIssues is used to retrieve the problems found in the fields of the given mapping (missing required fields and unsupported element types).
+func fieldIssues(m *zbxmap.Mapping) []string {
+	out := make([]string, 0)
+
+	for _, field := range missingFields(m) {
+		out = append(out, fmt.Sprintf("missing required field '%s'", field))
+	}
+
+	types := []struct {
+		name  string
+		value string
+	}{
+		{"local_type", m.LocalType},
+		{"remote_type", m.RemoteType},
+	}
+
+	for _, t := range types {
+		if t.value != "" && !utils.Contains(zbxmap.ElementTypes, t.value) {
+			out = append(out, fmt.Sprintf("unsupported element type '%s' for the field '%s', supported types are %v", t.value, t.name, zbxmap.ElementTypes))
+		}
+	}
+
+	return out
+}
+
+// linkKey is used to identify a link using the host and interface of each endpoint.
+func linkKey(m *zbxmap.Mapping) string {
+	return fmt.Sprintf("%s:%s|%s:%s", m.LocalHost, m.LocalInterface, m.RemoteHost, m.RemoteInterface)
+}
+
+// validateLinks is used to detect self-links and duplicate links.
+// The position function is used to retrieve the position of an entry, it can be nil if positions are not known.
+func validateLinks(entries []*zbxmap.Mapping, position func(entry int) (int, int)) []*Issue {
+	issues := make([]*Issue, 0)
+	seen := make(map[string]int, 0)
+
+	newIssue := func(entry int, message string) *Issue {
+		i := &Issue{Entry: entry, Message: message}
+		if position != nil {
+			i.Line, i.Column = position(entry)
+		}
+
+		return i
+	}
+
+	for i, m := range entries {
+		if m == nil {
+			continue
+		}
+
+		if m.LocalHost != "" && m.LocalHost == m.RemoteHost && m.LocalInterface == m.RemoteInterface {
+			issues = append(issues, newIssue(i, fmt.Sprintf("self-link on the host '%s' (the local and remote endpoints are the same)", m.LocalHost)))
+			continue
+		}
+
+		key := linkKey(m)
+		if first, exist := seen[key]; exist {
+			issues = append(issues, newIssue(i, fmt.Sprintf("duplicate link, already declared by the entry %d", first)))
+			continue
+		}
+
+		seen[key] = i
+	}
+
+	return issues
+}
@@ -0,0 +1,255 @@
+package input
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// validateContent is used to validate the given content written to a file with the given name.
+func validateContent(t *testing.T, name string, content string) []*Issue {
+	err := Validate(writeTestingFile(t, name, content), "")
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("a *ValidationError should be returned.\nReturned : %v", err)
+	}
+
+	return validationErr.Issues
+}
+
+func TestValidateExamples(t *testing.T) {
+	for _, name := range []string{"mapping.json", "mapping.yaml", "mapping.csv", "mapping.dot"} {
+		file := filepath.Join(filepath.Dir(mappingFilePath), name)
+
+		if err := Validate(file, ""); err != nil {
+			t.Fatalf("error while validating the file '%s'.\nReason : %v", file, err)
+		}
+	}
+}
+
+func TestValidateJSONUnknownField(t *testing.T) {
+	issues := validateContent(t, "mapping.json", `[
+    {
+        "local_host": "router-1",
+        "local_trigger_pattern": "Interface eth0(): Link down",
+        "local_image": "Firewall_(64)",
+        "remote_host": "router-2",
+        "remote_trigger_patern": "Interface eth0(): Link down",
+        "remote_image": "Switch_(64)"
+    }
+]`)
+
+	if len(issues) != 2 {
+		t.Fatalf("wrong number of issues returned.\nExpected : 2\nReturned : %v", issues)
+	}
+
+	if issues[0].String() != "entry 0, line 7, column 9 : unknown field 'remote_trigger_patern'" {
+		t.Fatalf("wrong issue returned.\nExpected : entry 0, line 7, column 9 : unknown field 'remote_trigger_patern'\nReturned : %s", issues[0].String())
+	}
+
+	if issues[1].String() != "entry 0, line 2, column 5 : missing required field 'remote_trigger_pattern'" {
+		t.Fatalf("wrong issue returned.\nExpected : entry 0, line 2, column 5 : missing required field 'remote_trigger_pattern'\nReturned : %s", issues[1].String())
+	}
+}
+
+func TestValidateJSONLinks(t *testing.T) {
+	entry := `{"local_host": "%s", "local_interface": "eth0", "local_trigger_pattern": "p", "local_image": "i", "remote_host": "%s", "remote_interface": "eth0", "remote_trigger_pattern": "p", "remote_image": "i"}`
+	content := "[\n" + strings.Join([]string{
+		fmtEntry(entry, "router-1", "router-1"),
+		"1",
+		fmtEntry(entry, "router-1", "router-2"),
+		fmtEntry(entry, "router-1", "router-2"),
+	}, ",\n") + "\n]"
+
+	issues := validateContent(t, "mapping.json", content)
+	if len(issues) != 3 {
+		t.Fatalf("wrong number of issues returned.\nExpected : 3\nReturned : %v", issues)
+	}
+
+	expected := []string{
+		"entry 1, line 3, column 1 : a mapping must be a JSON object",
+		"entry 0, line 2, column 1 : self-link on the host 'router-1' (the local and remote endpoints are the same)",
+		"entry 3, line 5, column 1 : duplicate link, already declared by the entry 2",
+	}
+
+	for i := range expected {
+		if issues[i].String() != expected[i] {
+			t.Fatalf("wrong issue returned.\nExpected : %s\nReturned : %s", expected[i], issues[i].String())
+		}
+	}
+}
+
+// fmtEntry is used to set the local and remote hosts of a JSON entry template.
+func fmtEntry(entry string, local string, remote string) string {
+	entry = strings.Replace(entry, "%s", local, 1)
+
+	return strings.Replace(entry, "%s", remote, 1)
+}
+
+func TestValidateJSONElementTypes(t *testing.T) {
+	issues := validateContent(t, "mapping.json", `[
+    {
+        "local_host": "router-1",
+        "local_trigger_pattern": "Interface eth0(): Link down",
+        "remote_type": "hostgroup",
+        "remote_host": "Routers"
+    },
+    {
+        "local_type": "router",
+        "local_host": "router-1",
+        "remote_type": "trigger",
+        "remote_host": "router-2"
+    }
+]`)
+
+	expected := []string{
+		"entry 1, line 8, column 5 : missing required field 'remote_trigger_pattern'",
+		"entry 1, line 8, column 5 : unsupported element type 'router' for the field 'local_type', supported types are [host hostgroup trigger map image]",
+	}
+
+	if len(issues) != len(expected) {
+		t.Fatalf("wrong number of issues returned.\nExpected : %d\nReturned : %v", len(expected), issues)
+	}
+
+	for i := range expected {
+		if issues[i].String() != expected[i] {
+			t.Fatalf("wrong issue returned.\nExpected : %s\nReturned : %s", expected[i], issues[i].String())
+		}
+	}
+}
+
+func TestValidateJSONSyntax(t *testing.T) {
+	issues := validateContent(t, "mapping.json", "[\n  {\"local_host\": \"router-1\",}\n]")
+	if len(issues) != 1 || issues[0].Line != 2 {
+		t.Fatalf("the syntax error should be reported with its position.\nReturned : %v", issues)
+	}
+}
+
+func TestValidateJSONNotAList(t *testing.T) {
+	issues := validateContent(t, "mapping.json", `{"local_host": "router-1"}`)
+	if len(issues) != 1 {
+		t.Fatalf("wrong number of issues returned.\nExpected : 1\nReturned : %v", issues)
+	}
+}
+
+func TestValidateJSONEmpty(t *testing.T) {
+	for _, content := range []string{"", "[]"} {
+		issues := validateContent(t, "mapping.json", content)
+		if len(issues) != 1 || issues[0].Message != "no mapping were found" {
+			t.Fatalf("an issue should be returned when no mapping are found.\nReturned : %v", issues)
+		}
+	}
+}
+
+func TestValidateCSV(t *testing.T) {
+	issues := validateContent(t, "mapping.csv", "local_host,remote_host,local_image,remote_image,local_trigger_pattern,remote_trigger_pattern\nrouter-1,router-1,i,i,p,p\nrouter-1,router-2,i,,p,\n")
+
+	if len(issues) != 2 {
+		t.Fatalf("wrong number of issues returned.\nExpected : 2\nReturned : %v", issues)
+	}
+
+	if issues[0].String() != "entry 1 : missing required field 'remote_trigger_pattern'" {
+		t.Fatalf("wrong issue returned.\nExpected : entry 1 : missing required field 'remote_trigger_pattern'\nReturned : %s", issues[0].String())
+	}
+}
+
+func TestValidateYAMLUnknownField(t *testing.T) {
+	issues := validateContent(t, "mapping.yaml", "links:\n  - local: a\n    remote: b\n    remote_imgae: Switch_(64)\n")
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "remote_imgae") {
+		t.Fatalf("the unknown field should be reported.\nReturned : %v", issues)
+	}
+}
+
+func TestValidateUnsupportedFormat(t *testing.T) {
+	err := Validate(mappingFilePath, "xml")
+	if err == nil {
+		t.Fatal("an error should be returned when the format is not supported")
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		t.Fatal("an unsupported format should not be reported as a validation issue")
+	}
+}
+
+func TestValidateBytes(t *testing.T) {
+	b := []byte("local_host,remote_host,local_image,remote_image,local_trigger_pattern,remote_trigger_pattern\nrouter-1,router-1,i,i,p,p\n")
+
+	err := ValidateBytes("mapping.csv", b, "csv")
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("a validation error should be returned.\nReturned : %v", err)
+	}
+
+	if validationErr.File != "mapping.csv" || len(validationErr.Issues) != 1 {
+		t.Fatalf("wrong validation error returned.\nReturned : %v", err)
+	}
+
+	if err = ValidateBytes("mapping.csv", b, "xml"); err == nil || errors.As(err, &validationErr) {
+		t.Fatalf("an error should be returned when the format is not supported.\nReturned : %v", err)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{
+		File: "mapping.json",
+		Issues: []*Issue{
+			{Entry: -1, Message: "no mapping were found"},
+		},
+	}
+
+	expected := "1 issue(s) found in the mapping file 'mapping.json'\n  - no mapping were found"
+	if err.Error() != expected {
+		t.Fatalf("wrong error message returned.\nExpected : %s\nReturned : %s", expected, err.Error())
+	}
+}
+
+func TestSchemaFields(t *testing.T) {
+	file := filepath.Join(filepath.Dir(mappingFilePath), "..", "schema", "mapping.schema.json")
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("error while reading the schema file '%s'.\nReason : %v", file, err)
+	}
+
+	schema := struct {
+		Items struct {
+			Required   []string                   `json:"required"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"items"`
+	}{}
+
+	if err = json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("error while decoding the schema file '%s'.\nReason : %v", file, err)
+	}
+
+	if !reflect.DeepEqual(schema.Items.Required, RequiredFields) {
+		t.Fatalf("the required fields of the schema do not match.\nExpected : %v\nReturned : %v", RequiredFields, schema.Items.Required)
+	}
+
+	properties := make([]string, 0)
+	for key := range schema.Items.Properties {
+		properties = append(properties, key)
+	}
+	sort.Strings(properties)
+
+	fields := make([]string, 0)
+	for key := range csvColumns {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+
+	if !reflect.DeepEqual(properties, fields) {
+		t.Fatalf("the properties of the schema do not match the mapping fields.\nExpected : %v\nReturned : %v", fields, properties)
+	}
+}
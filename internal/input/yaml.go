@@ -0,0 +1,192 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlDocument define the structure of a YAML mapping file.
+type yamlDocument struct {
+	Defaults *yamlDefaults        `yaml:"defaults"`
+	Hosts    map[string]*yamlHost `yaml:"hosts"`
+	Links    []*yamlLink          `yaml:"links"`
+}
+
+// yamlDefaults define the values used when a host or a link does not set them.
+type yamlDefaults struct {
+	Image string `yaml:"image"`
+	// TriggerPattern is a template, '{host}' and '{interface}' are replaced by the values of each endpoint.
+	TriggerPattern string `yaml:"trigger_pattern"`
+	Color          string `yaml:"color"`
+	TriggerColor   string `yaml:"trigger_color"`
+}
+
+// yamlHost define the properties of a host shared by all the links referencing it.
+type yamlHost struct {
+	Image          string `yaml:"image"`
+	Label          string `yaml:"label"`
+	TriggerPattern string `yaml:"trigger_pattern"`
+	// Capabilities are the CDP capabilities of the host, used by the icon rules when no image is set.
+	Capabilities string `yaml:"capabilities"`
+	// Type is the type of the element on the map (host by default).
+	Type string `yaml:"type"`
+	// Tier is used to order the hosts on the map, hosts with the lowest tier are placed first.
+	Tier int `yaml:"tier"`
+}
+
+// yamlLink define a link between two endpoints written as 'host:interface' (the interface is optional).
+type yamlLink struct {
+	Local                string `yaml:"local"`
+	Remote               string `yaml:"remote"`
+	TriggerPattern       string `yaml:"trigger_pattern"`
+	LocalTriggerPattern  string `yaml:"local_trigger_pattern"`
+	RemoteTriggerPattern string `yaml:"remote_trigger_pattern"`
+	Color                string `yaml:"color"`
+	TriggerColor         string `yaml:"trigger_color"`
+}
+
+// splitEndpoint is used to split an endpoint written as 'host:interface'.
+func splitEndpoint(endpoint string) (string, string) {
+	host, iface, _ := strings.Cut(endpoint, ":")
+
+	return strings.TrimSpace(host), strings.TrimSpace(iface)
+}
+
+// firstNonEmpty is used to retrieve the first non empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// expandPattern is used to replace the placeholders of a trigger pattern template.
+func expandPattern(pattern string, host string, iface string) string {
+	r := strings.NewReplacer("{host}", host, "{interface}", iface)
+
+	return r.Replace(pattern)
+}
+
+// readYAML is used to read mappings from a YAML document.
+// Hosts are declared once in the 'hosts' section and links reference them as 'host:interface'.
+// Values not set by a link are taken from the host, then from the 'defaults' section.
+func readYAML(b []byte) ([]*zbxmap.Mapping, error) {
+	doc := &yamlDocument{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(doc)
+	if err == io.EOF {
+		return make([]*zbxmap.Mapping, 0), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Defaults == nil {
+		doc.Defaults = &yamlDefaults{}
+	}
+
+	if doc.Hosts == nil {
+		doc.Hosts = make(map[string]*yamlHost, 0)
+	}
+
+	entries := make([]*zbxmap.Mapping, 0)
+	for i, link := range doc.Links {
+		localHost, localInterface := splitEndpoint(link.Local)
+		remoteHost, remoteInterface := splitEndpoint(link.Remote)
+
+		if localHost == "" || remoteHost == "" {
+			return nil, fmt.Errorf("link %d : the 'local' and 'remote' endpoints are required", i)
+		}
+
+		local := doc.Hosts[localHost]
+		if local == nil {
+			local = &yamlHost{}
+		}
+
+		remote := doc.Hosts[remoteHost]
+		if remote == nil {
+			remote = &yamlHost{}
+		}
+
+		m := &zbxmap.Mapping{
+			LocalHost:          localHost,
+			LocalInterface:     localInterface,
+			LocalImage:         firstNonEmpty(local.Image, doc.Defaults.Image),
+			LocalLabel:         local.Label,
+			LocalCapabilities:  local.Capabilities,
+			LocalType:          local.Type,
+			RemoteHost:         remoteHost,
+			RemoteInterface:    remoteInterface,
+			RemoteImage:        firstNonEmpty(remote.Image, doc.Defaults.Image),
+			RemoteLabel:        remote.Label,
+			RemoteCapabilities: remote.Capabilities,
+			RemoteType:         remote.Type,
+			Color:              firstNonEmpty(link.Color, doc.Defaults.Color),
+			TriggerColor:       firstNonEmpty(link.TriggerColor, doc.Defaults.TriggerColor),
+		}
+
+		m.LocalTriggerPattern = expandPattern(
+			firstNonEmpty(link.LocalTriggerPattern, link.TriggerPattern, local.TriggerPattern, doc.Defaults.TriggerPattern),
+			localHost,
+			localInterface,
+		)
+
+		m.RemoteTriggerPattern = expandPattern(
+			firstNonEmpty(link.RemoteTriggerPattern, link.TriggerPattern, remote.TriggerPattern, doc.Defaults.TriggerPattern),
+			remoteHost,
+			remoteInterface,
+		)
+
+		entries = append(entries, m)
+	}
+
+	sortByTier(entries, doc.Hosts)
+
+	return entries, nil
+}
+
+// sortByTier is used to order the mappings using the tier of their hosts.
+// Hosts are placed on the map in their order of appearance, the host with the lowest tier of each mapping is used as local host
+// and mappings are sorted by tier to place the hosts of the lowest tiers first.
+func sortByTier(entries []*zbxmap.Mapping, hosts map[string]*yamlHost) {
+	tier := func(host string) int {
+		if h, exist := hosts[host]; exist && h != nil {
+			return h.Tier
+		}
+
+		return 0
+	}
+
+	for _, m := range entries {
+		if tier(m.LocalHost) > tier(m.RemoteHost) {
+			m.LocalHost, m.RemoteHost = m.RemoteHost, m.LocalHost
+			m.LocalInterface, m.RemoteInterface = m.RemoteInterface, m.LocalInterface
+			m.LocalTriggerPattern, m.RemoteTriggerPattern = m.RemoteTriggerPattern, m.LocalTriggerPattern
+			m.LocalImage, m.RemoteImage = m.RemoteImage, m.LocalImage
+			m.LocalLabel, m.RemoteLabel = m.RemoteLabel, m.LocalLabel
+			m.LocalCapabilities, m.RemoteCapabilities = m.RemoteCapabilities, m.LocalCapabilities
+			m.LocalType, m.RemoteType = m.RemoteType, m.LocalType
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if tier(entries[i].LocalHost) != tier(entries[j].LocalHost) {
+			return tier(entries[i].LocalHost) < tier(entries[j].LocalHost)
+		}
+
+		return tier(entries[i].RemoteHost) < tier(entries[j].RemoteHost)
+	})
+}
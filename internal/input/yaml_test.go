@@ -0,0 +1,156 @@
+package input
+
+import "testing"
+
+func TestReadYAML(t *testing.T) {
+	m, err := readYAML([]byte(`
+defaults:
+  image: Switch_(64)
+  trigger_pattern: "Interface {interface}(): Link down"
+  color: 7AC2E1
+hosts:
+  core-1:
+    image: Firewall_(64)
+    label: Core router
+  dist-1:
+    trigger_pattern: "{host} is unreachable"
+links:
+  - local: core-1:eth0
+    remote: dist-1:ge0
+    trigger_color: EE445B
+  - local: core-1:eth1
+    remote: access-1
+    remote_trigger_pattern: "Unavailable by ICMP ping"
+    color: 000000
+`))
+
+	if err != nil {
+		t.Fatalf("error while executing readYAML function.\nReason : %v", err)
+	}
+
+	if len(m) != 2 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 2\nReturned : %d", len(m))
+	}
+
+	if m[0].LocalImage != "Firewall_(64)" || m[0].RemoteImage != "Switch_(64)" {
+		t.Fatalf("wrong images returned.\nExpected : Firewall_(64), Switch_(64)\nReturned : %s, %s", m[0].LocalImage, m[0].RemoteImage)
+	}
+
+	if m[0].LocalTriggerPattern != "Interface eth0(): Link down" {
+		t.Fatalf("the default trigger pattern was not expanded.\nExpected : Interface eth0(): Link down\nReturned : %s", m[0].LocalTriggerPattern)
+	}
+
+	if m[0].RemoteTriggerPattern != "dist-1 is unreachable" {
+		t.Fatalf("the host trigger pattern was not used.\nExpected : dist-1 is unreachable\nReturned : %s", m[0].RemoteTriggerPattern)
+	}
+
+	if m[0].LocalLabel != "Core router" || m[0].Color != "7AC2E1" || m[0].TriggerColor != "EE445B" {
+		t.Fatalf("wrong label or colors returned.\nReturned : %+v", m[0])
+	}
+
+	if m[1].RemoteHost != "access-1" || m[1].RemoteInterface != "" || m[1].RemoteTriggerPattern != "Unavailable by ICMP ping" {
+		t.Fatalf("wrong remote endpoint returned for the second link.\nReturned : %+v", m[1])
+	}
+
+	if m[1].Color != "000000" {
+		t.Fatalf("the link color should override the default color.\nExpected : 000000\nReturned : %s", m[1].Color)
+	}
+}
+
+func TestReadYAMLTier(t *testing.T) {
+	m, err := readYAML([]byte(`
+defaults:
+  image: Switch_(64)
+hosts:
+  core-1:
+    tier: 0
+  dist-1:
+    tier: 1
+  access-1:
+    tier: 2
+links:
+  - local: access-1:eth0
+    remote: dist-1:eth1
+  - local: dist-1:eth0
+    remote: core-1:eth0
+`))
+
+	if err != nil {
+		t.Fatalf("error while executing readYAML function.\nReason : %v", err)
+	}
+
+	if m[0].LocalHost != "core-1" || m[0].RemoteHost != "dist-1" || m[0].LocalInterface != "eth0" {
+		t.Fatalf("the mapping with the lowest tier should be placed first.\nReturned : %+v", m[0])
+	}
+
+	if m[1].LocalHost != "dist-1" || m[1].LocalInterface != "eth1" || m[1].RemoteHost != "access-1" {
+		t.Fatalf("the host with the lowest tier should be used as local host.\nReturned : %+v", m[1])
+	}
+}
+
+func TestReadYAMLErrors(t *testing.T) {
+	documents := map[string]string{
+		"unknown field":    "links:\n  - local: a\n    remote: b\n    remote_imgae: Switch_(64)\n",
+		"missing endpoint": "defaults:\n  image: Switch_(64)\nlinks:\n  - local: a\n",
+		"wrong tier":       "hosts:\n  a:\n    tier: first\nlinks:\n  - local: a\n    remote: b\n",
+		"invalid document": "links: [",
+	}
+
+	for name, document := range documents {
+		if _, err := readYAML([]byte(document)); err == nil {
+			t.Fatalf("an error should be returned for the document '%s'", name)
+		}
+	}
+}
+
+func TestReadYAMLCapabilities(t *testing.T) {
+	document := "hosts:\n  core-1:\n    capabilities: R S I\n    tier: 1\n  access-1:\n    capabilities: S I\n    tier: 0\nlinks:\n  - local: core-1:eth0\n    remote: access-1:eth1\n"
+
+	m, err := readYAML([]byte(document))
+	if err != nil {
+		t.Fatalf("error while executing readYAML function.\nReason : %v", err)
+	}
+
+	if m[0].LocalImage != "" || m[0].RemoteImage != "" {
+		t.Fatalf("no image should be set when the hosts and the defaults do not set one.\nReturned : %+v", m[0])
+	}
+
+	if m[0].LocalCapabilities != "S I" || m[0].RemoteCapabilities != "R S I" {
+		t.Fatalf("wrong capabilities returned.\nExpected : 'S I' / 'R S I'\nReturned : '%s' / '%s'", m[0].LocalCapabilities, m[0].RemoteCapabilities)
+	}
+}
+
+func TestReadYAMLType(t *testing.T) {
+	document := "hosts:\n  Internet:\n    type: image\n    image: Cloud_(24)\n  core-1:\n    tier: 1\nlinks:\n  - local: core-1:eth0\n    remote: Internet\n    local_trigger_pattern: Link down\n"
+
+	m, err := readYAML([]byte(document))
+	if err != nil {
+		t.Fatalf("error while executing readYAML function.\nReason : %v", err)
+	}
+
+	if m[0].LocalHost != "Internet" || m[0].LocalType != "image" || m[0].RemoteType != "" {
+		t.Fatalf("wrong element types returned.\nExpected : 'Internet' (image) / 'core-1' (host)\nReturned : %+v", m[0])
+	}
+}
+
+func TestReadYAMLEmpty(t *testing.T) {
+	m, err := readYAML([]byte(""))
+	if err != nil {
+		t.Fatalf("error while executing readYAML function.\nReason : %v", err)
+	}
+
+	if len(m) != 0 {
+		t.Fatalf("no mapping should be returned for an empty document.\nReturned : %d", len(m))
+	}
+}
+
+func TestReadYAMLEmptyHost(t *testing.T) {
+	m, err := readYAML([]byte("defaults:\n  image: Switch_(64)\nhosts:\n  router-1:\nlinks:\n  - local: router-1:eth0\n    remote: router-2:eth0\n"))
+	if err != nil {
+		t.Fatalf("error while executing readYAML function.\nReason : %v", err)
+	}
+
+	if len(m) != 1 || m[0].LocalImage != "Switch_(64)" {
+		t.Fatalf("the default image should be used for a host declared without properties.\nReturned : %+v", m)
+	}
+}
@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// Formats contains the supported output formats of the records, used by the '--log-format' flag.
+var Formats = []string{"text", "json"}
+
+// Record define a log entry written by a handler.
+type Record struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	// Details are the values logged after the message (the error of an operation for example).
+	Details []string
+	Fields  []*Field
+}
+
+// Field define a key-value pair added to a record (ex: the map, the host or the API method the record is about).
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Handler is used to write the records of a logger.
+type Handler interface {
+	Handle(r *Record) error
+}
+
+// NewHandler is used to create the handler writing the records to the given writer using the given format (text or json).
+func NewHandler(format string, w io.Writer) (Handler, error) {
+	switch format {
+	case "", "text":
+		return NewTextHandler(w), nil
+	case "json":
+		return NewJSONHandler(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format '%s', supported formats are %v", format, Formats)
+	}
+}
+
+// TextHandler is used to write the records as prefixed text lines, each detail is written on its own line.
+// The fields are appended to the message as 'key=value'.
+type TextHandler struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+// NewTextHandler is used to create a TextHandler writing to the given writer.
+func NewTextHandler(w io.Writer) *TextHandler {
+	return &TextHandler{
+		w: w,
+	}
+}
+
+// formatValue is used to retrieve the string representation of a field value, quoted if it contains spaces.
+func formatValue(value any) string {
+	s := fmt.Sprint(value)
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// Handle is used to write the given record.
+func (h *TextHandler) Handle(r *Record) error {
+	prefix := fmt.Sprintf("[map-builder][%s] ", getLevel(r.Level))
+
+	var buf bytes.Buffer
+	buf.WriteString(prefix + r.Message)
+	for _, f := range r.Fields {
+		buf.WriteString(fmt.Sprintf(" %s=%s", f.Key, formatValue(f.Value)))
+	}
+
+	buf.WriteString("\n")
+
+	for _, detail := range r.Details {
+		buf.WriteString(prefix + detail + "\n")
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// JSONHandler is used to write each record as a JSON object on a single line (ex: to be ingested by Loki).
+// The details are joined in the 'error' key, the fields are added as keys of the object.
+// The fields using a reserved key ('time', 'level', 'msg' or 'error') are prefixed with 'fields.' (ex: 'fields.msg').
+type JSONHandler struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+// NewJSONHandler is used to create a JSONHandler writing to the given writer.
+func NewJSONHandler(w io.Writer) *JSONHandler {
+	return &JSONHandler{
+		w: w,
+	}
+}
+
+// reservedKeys contains the keys written by the JSONHandler for each record.
+var reservedKeys = []string{"time", "level", "msg", "error"}
+
+// jsonValue is used to retrieve the value of a field encoded in JSON, errors and values that cannot be encoded are written as strings.
+func jsonValue(value any) []byte {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(value))
+	}
+
+	return b
+}
+
+// Handle is used to write the given record.
+func (h *JSONHandler) Handle(r *Record) error {
+	var buf bytes.Buffer
+
+	write := func(key string, value any) {
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.Write(jsonValue(key))
+		buf.WriteByte(':')
+		buf.Write(jsonValue(value))
+	}
+
+	write("time", r.Time.Format(time.RFC3339Nano))
+	write("level", strings.ToLower(getLevel(r.Level)))
+	write("msg", r.Message)
+
+	if len(r.Details) > 0 {
+		write("error", strings.Join(r.Details, "\n"))
+	}
+
+	for _, f := range r.Fields {
+		key := f.Key
+		if utils.Contains(reservedKeys, key) {
+			key = "fields." + key
+		}
+
+		write(key, f.Value)
+	}
+
+	line := append([]byte{'{'}, buf.Bytes()...)
+	line = append(line, '}', '\n')
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	_, err := h.w.Write(line)
+	return err
+}
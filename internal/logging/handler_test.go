@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNewHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	if h, err := NewHandler("", &buf); err != nil || h == nil {
+		t.Fatalf("a text handler should be returned by default.\nReturned : %v", err)
+	}
+
+	if h, err := NewHandler("json", &buf); err != nil {
+		t.Fatalf("error while executing NewHandler function.\nReason : %v", err)
+	} else if _, ok := h.(*JSONHandler); !ok {
+		t.Fatalf("wrong handler returned.\nExpected : *JSONHandler\nReturned : %T", h)
+	}
+
+	if _, err := NewHandler("logfmt", &buf); err == nil {
+		t.Fatalf("an error should be returned for an unsupported format")
+	}
+}
+
+func TestTextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewTextHandler(&buf)
+
+	err := h.Handle(&Record{
+		Level:   Error,
+		Message: "error while building the map",
+		Details: []string{"no host found"},
+		Fields:  []*Field{{Key: "map", Value: "dc1"}},
+	})
+	if err != nil {
+		t.Fatalf("error while executing Handle function.\nReason : %v", err)
+	}
+
+	expected := "[map-builder][ERROR] error while building the map map=dc1\n[map-builder][ERROR] no host found\n"
+	if buf.String() != expected {
+		t.Fatalf("Wrong log format returned\nExpected : %s\nReturned : %s", expected, buf.String())
+	}
+}
+
+func TestJSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(Debug)
+	l.SetHandler(NewJSONHandler(&buf))
+
+	l.With("map", "dc1", "method", "host.get", "duration", 0.25).Error("error while building the map", fmt.Errorf("no host found"))
+
+	out := make(map[string]interface{}, 0)
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("the record should be a JSON object.\nReason : %v\nReturned : %s", err, buf.String())
+	}
+
+	expected := map[string]interface{}{
+		"level":    "error",
+		"msg":      "error while building the map",
+		"error":    "no host found",
+		"map":      "dc1",
+		"method":   "host.get",
+		"duration": 0.25,
+	}
+
+	for key, value := range expected {
+		if out[key] != value {
+			t.Fatalf("wrong value returned for the key '%s'.\nExpected : %v\nReturned : %v", key, value, out[key])
+		}
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, fmt.Sprint(out["time"])); err != nil {
+		t.Fatalf("wrong time returned.\nReason : %v", err)
+	}
+
+	if buf.Bytes()[buf.Len()-1] != '\n' || bytes.Count(buf.Bytes(), []byte("\n")) != 1 {
+		t.Fatalf("each record should be written on a single line.\nReturned : %s", buf.String())
+	}
+}
+
+func TestJSONHandlerReservedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(Debug)
+	l.SetHandler(NewJSONHandler(&buf))
+
+	l.With("msg", "custom", "level", 3, "time", "yesterday", "error", "none").Info("map built")
+
+	out := make(map[string]interface{}, 0)
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("the record should be a JSON object.\nReason : %v\nReturned : %s", err, buf.String())
+	}
+
+	expected := map[string]interface{}{
+		"level":        "info",
+		"msg":          "map built",
+		"fields.msg":   "custom",
+		"fields.level": float64(3),
+		"fields.time":  "yesterday",
+		"fields.error": "none",
+	}
+
+	for key, value := range expected {
+		if out[key] != value {
+			t.Fatalf("wrong value returned for the key '%s'.\nExpected : %v\nReturned : %v", key, value, out[key])
+		}
+	}
+
+	if _, exist := out["error"]; exist {
+		t.Fatalf("the 'error' key should only be set by the details of the record.\nReturned : %s", buf.String())
+	}
+
+	if bytes.Count(buf.Bytes(), []byte(`"msg"`)) != 1 {
+		t.Fatalf("the 'msg' key should be written once.\nReturned : %s", buf.String())
+	}
+}
+
+func TestJsonValue(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{"dc1", `"dc1"`},
+		{3, `3`},
+		{fmt.Errorf("no host found"), `"no host found"`},
+	}
+
+	for _, test := range tests {
+		if out := string(jsonValue(test.value)); out != test.expected {
+			t.Fatalf("wrong value returned.\nExpected : %s\nReturned : %s", test.expected, out)
+		}
+	}
+
+	// Values that cannot be encoded are written as strings
+	if out := jsonValue(make(chan int)); out[0] != '"' {
+		t.Fatalf("the value should be written as a string.\nReturned : %s", string(out))
+	}
+}
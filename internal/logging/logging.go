@@ -3,6 +3,9 @@ package logging
 import (
 	"fmt"
 	"log"
+	"os"
+	"strings"
+	"time"
 )
 
 // LogLevel is used to defined the cricity level of the logger.
@@ -16,18 +19,61 @@ const (
 	Debug
 )
 
+// Levels contains the name of the supported log levels, used by the '--log-level' flag.
+var Levels = []string{"critical", "error", "warning", "info", "debug"}
+
 // Logger is used as a wrapper to handle custom shell logging format.
-// Logging to a file is not supported.
+// The records are written by the handler of the logger (text or JSON output, to the shell or to a file).
 type Logger struct {
-	Level  LogLevel
-	logger *log.Logger
+	Level   LogLevel
+	handler Handler
+	// fields are added to each record written by the logger (see With).
+	fields []*Field
+}
+
+// defaultOutput is used to write to the output of the standard logger (os.Stderr unless changed using log.SetOutput).
+type defaultOutput struct{}
+
+// Write is used to write the given bytes to the current output of the standard logger.
+func (defaultOutput) Write(b []byte) (int, error) {
+	return log.Writer().Write(b)
 }
 
-// NewLogger is used to create a new logger.
+// NewLogger is used to create a new logger writing text records to the shell.
 func NewLogger(level LogLevel) *Logger {
 	return &Logger{
-		Level:  level,
-		logger: log.Default(),
+		Level:   level,
+		handler: NewTextHandler(defaultOutput{}),
+	}
+}
+
+// SetHandler is used to replace the handler writing the records of the logger.
+func (l *Logger) SetHandler(handler Handler) {
+	l.handler = handler
+}
+
+// With is used to create a logger adding the given fields to each record, the fields are given as key-value pairs (ex: "map", "dc1").
+// The new logger shares the handler and the level of the current logger.
+func (l *Logger) With(args ...any) *Logger {
+	fields := make([]*Field, 0)
+	fields = append(fields, l.fields...)
+
+	for i := 0; i < len(args); i += 2 {
+		f := &Field{
+			Key: fmt.Sprint(args[i]),
+		}
+
+		if i+1 < len(args) {
+			f.Value = args[i+1]
+		}
+
+		fields = append(fields, f)
+	}
+
+	return &Logger{
+		Level:   l.Level,
+		handler: l.handler,
+		fields:  fields,
 	}
 }
 
@@ -49,48 +95,42 @@ func getLevel(level LogLevel) string {
 	}
 }
 
-// setFlags is used to set the logger flags based on the given log level.
-func (l *Logger) setFlags() {
-	l.logger.SetFlags(log.Lmsgprefix)
+// ParseLevel is used to retrieve the LogLevel matching the given name (critical, error, warning, info or debug).
+func ParseLevel(name string) (LogLevel, error) {
+	for i, level := range Levels {
+		if strings.EqualFold(name, level) {
+			return LogLevel(i), nil
+		}
+	}
+
+	return Critical, fmt.Errorf("unsupported log level '%s', supported levels are %v", name, Levels)
 }
 
 // log is used as a wrapper function to log the given data while handling logger flags, prefix and content assignments.
 func (l *Logger) writeLog(level LogLevel, v ...any) {
 	// Do not write log if the level is not configured
-	if level <= l.Level {
-		// Set the logger flags
-		l.setFlags()
-		// Retrieve the string representation of the log level
-		strLevel := getLevel(level)
-
-		// Configure the logger prefix
-		l.logger.SetPrefix(fmt.Sprintf("[map-builder][%s] ", strLevel))
-
-		// For critical level, log all data to the shell and
-		// execute panic function on the last entry
-		if level == Critical {
-			// Directly use panic function if only one entry was passed
-			if len(v) == 1 {
-				panic(v[0])
-			}
-
-			// Log all entries to the shell, minus the latest one
-			for i := 0; i < len(v)-1; i++ {
-				l.logger.Println(v[i])
-			}
-
-			panic(v[len(v)-1])
-
-		} else {
-			// Log all entries to the shell
-			for _, entry := range v {
-				l.logger.Println(entry)
-			}
-		}
+	if level > l.Level || len(v) == 0 {
+		return
+	}
+
+	r := &Record{
+		Time:    time.Now(),
+		Level:   level,
+		Message: fmt.Sprint(v[0]),
+		Fields:  l.fields,
+	}
+
+	for _, entry := range v[1:] {
+		r.Details = append(r.Details, fmt.Sprint(entry))
+	}
+
+	if err := l.handler.Handle(r); err != nil {
+		fmt.Fprintf(os.Stderr, "error while writing a log record.\nReason : %v\n", err)
 	}
 }
 
 // Critical is used to log data to the shell with the level logging.Critical.
+// The execution is not interrupted, the caller is responsible for returning an error.
 func (l *Logger) Critical(v ...any) {
 	l.writeLog(Critical, v...)
 }
@@ -2,7 +2,6 @@ package logging
 
 import (
 	"bytes"
-	"log"
 	"strings"
 	"testing"
 )
@@ -41,19 +40,12 @@ func TestGetLevel(t *testing.T) {
 
 }
 
-func TestSetFlags(t *testing.T) {
-	testLogger.setFlags()
-	if testLogger.logger.Flags() != log.Lmsgprefix {
-		t.Fatalf("Wrong logger flags set\nExpected : %d\nReturned : %d", log.Lmsgprefix, testLogger.logger.Flags())
-	}
-}
-
 func TestWriteLog(t *testing.T) {
 	var buf bytes.Buffer
 	expectedOutput := "[map-builder][INFO] test-value\n"
 
 	// Set the logger output to a buffer instead of os.Stderr file
-	testLogger.logger.SetOutput(&buf)
+	testLogger.SetHandler(NewTextHandler(&buf))
 
 	testLogger.writeLog(Info, "test-value")
 
@@ -63,10 +55,10 @@ func TestWriteLog(t *testing.T) {
 	}
 }
 
-func TestWriteLogPanic(t *testing.T) {
+func TestWriteLogCritical(t *testing.T) {
 	defer func() {
-		if err := recover(); err == nil {
-			t.Fatal("panic function was not trigger")
+		if err := recover(); err != nil {
+			t.Fatalf("the critical level should not panic.\nReturned : %v", err)
 		}
 	}()
 
@@ -74,7 +66,7 @@ func TestWriteLogPanic(t *testing.T) {
 	expectedOutput := "[map-builder][CRITICAL] test-value\n"
 
 	// Set the logger output to a buffer instead of os.Stderr file
-	testLogger.logger.SetOutput(&buf)
+	testLogger.SetHandler(NewTextHandler(&buf))
 
 	testLogger.writeLog(Critical, "test-value")
 
@@ -85,8 +77,8 @@ func TestWriteLogPanic(t *testing.T) {
 
 func TestCritical(t *testing.T) {
 	defer func() {
-		if err := recover(); err == nil {
-			t.Fatal("panic function was not trigger")
+		if err := recover(); err != nil {
+			t.Fatalf("the critical level should not panic.\nReturned : %v", err)
 		}
 	}()
 
@@ -94,7 +86,7 @@ func TestCritical(t *testing.T) {
 	expectedOutput := "[map-builder][CRITICAL] test-value\n"
 
 	// Set the logger output to a buffer instead of os.Stderr file
-	testLogger.logger.SetOutput(&buf)
+	testLogger.SetHandler(NewTextHandler(&buf))
 
 	testLogger.Critical("test-value")
 
@@ -108,7 +100,7 @@ func TestError(t *testing.T) {
 	expectedOutput := "[map-builder][ERROR] test-value\n"
 
 	// Set the logger output to a buffer instead of os.Stderr file
-	testLogger.logger.SetOutput(&buf)
+	testLogger.SetHandler(NewTextHandler(&buf))
 
 	testLogger.Error("test-value")
 
@@ -123,7 +115,7 @@ func TestWarning(t *testing.T) {
 	expectedOutput := "[map-builder][WARNING] test-value\n"
 
 	// Set the logger output to a buffer instead of os.Stderr file
-	testLogger.logger.SetOutput(&buf)
+	testLogger.SetHandler(NewTextHandler(&buf))
 
 	testLogger.Warning("test-value")
 
@@ -137,7 +129,7 @@ func TestInfo(t *testing.T) {
 	expectedOutput := "[map-builder][INFO] test-value\n"
 
 	// Set the logger output to a buffer instead of os.Stderr file
-	testLogger.logger.SetOutput(&buf)
+	testLogger.SetHandler(NewTextHandler(&buf))
 
 	testLogger.Info("test-value")
 
@@ -151,7 +143,7 @@ func TestDebug(t *testing.T) {
 	expectedOutput := "[map-builder][DEBUG] test-value\n"
 
 	// Set the logger output to a buffer instead of os.Stderr file
-	testLogger.logger.SetOutput(&buf)
+	testLogger.SetHandler(NewTextHandler(&buf))
 
 	testLogger.Debug("test-value")
 
@@ -159,3 +151,44 @@ func TestDebug(t *testing.T) {
 		t.Fatalf("Wrong log format returned\nExpected : %s\nReturned : %s", expectedOutput, buf.String())
 	}
 }
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]LogLevel{
+		"critical": Critical,
+		"error":    Error,
+		"WARNING":  Warning,
+		"info":     Info,
+		"debug":    Debug,
+	}
+
+	for name, expected := range tests {
+		level, err := ParseLevel(name)
+		if err != nil {
+			t.Fatalf("error while executing ParseLevel function.\nReason : %v", err)
+		}
+
+		if level != expected {
+			t.Fatalf("wrong level returned for '%s'.\nExpected : %d\nReturned : %d", name, expected, level)
+		}
+	}
+
+	if _, err := ParseLevel("trace"); err == nil {
+		t.Fatalf("an error should be returned for an unsupported level")
+	}
+}
+
+func TestWith(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(Info)
+	l.SetHandler(NewTextHandler(&buf))
+
+	child := l.With("map", "dc1").With("host", "router 1")
+	child.Info("host resolved")
+	child.Debug("not written")
+	l.Info("no field")
+
+	expected := "[map-builder][INFO] host resolved map=dc1 host=\"router 1\"\n[map-builder][INFO] no field\n"
+	if buf.String() != expected {
+		t.Fatalf("Wrong log format returned\nExpected : %s\nReturned : %s", expected, buf.String())
+	}
+}
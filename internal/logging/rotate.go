@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile is a log file rotated once it reaches its maximum size.
+// The rotated files are renamed '<file>.1' (most recent) to '<file>.<backups>', the oldest file is removed.
+type RotatingFile struct {
+	mutex   sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+// NewRotatingFile is used to open the given log file, the records are appended to the existing file.
+// The file is rotated once it reaches maxSize bytes (never if 0), up to backups rotated files are kept.
+func NewRotatingFile(path string, maxSize int64, backups int) (*RotatingFile, error) {
+	if maxSize < 0 || backups < 0 {
+		return nil, fmt.Errorf("the maximum size and the number of backups of the log file cannot be negative")
+	}
+
+	f := &RotatingFile{
+		path:    path,
+		maxSize: maxSize,
+		backups: backups,
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// open is used to open the log file and to retrieve its current size.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("error while opening the log file '%s'.\nReason : %v", f.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// backup is used to retrieve the name of the rotated file with the given index.
+func (f *RotatingFile) backup(index int) string {
+	return fmt.Sprintf("%s.%d", f.path, index)
+}
+
+// rotate is used to shift the rotated files, then to replace the log file by an empty file.
+// If the rotated files cannot be shifted, the current log file is reopened to keep writing the next records.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	if err == nil {
+		err = f.shift()
+	}
+
+	if err != nil {
+		if openErr := f.open(); openErr != nil {
+			return fmt.Errorf("error while rotating the log file '%s'.\nReason : %v\n%v", f.path, err, openErr)
+		}
+
+		return err
+	}
+
+	return f.open()
+}
+
+// shift is used to rename the log file and the rotated files, the oldest file is removed.
+func (f *RotatingFile) shift() error {
+	if f.backups == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	if err := os.Remove(f.backup(f.backups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i := f.backups - 1; i > 0; i-- {
+		if err := os.Rename(f.backup(i), f.backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := os.Rename(f.path, f.backup(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Write is used to append the given bytes to the log file, the file is rotated before the write if the maximum size would be exceeded.
+// A record larger than the maximum size is written to an empty file.
+func (f *RotatingFile) Write(b []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(b)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(b)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close is used to close the log file.
+func (f *RotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.file.Close()
+}
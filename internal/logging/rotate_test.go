@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readTestingFile is used to read the content of the given file, an empty string is returned if the file does not exist.
+func readTestingFile(t *testing.T, file string) string {
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return ""
+	}
+
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", file, err)
+	}
+
+	return string(b)
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map-builder.log")
+
+	f, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("error while executing NewRotatingFile function.\nReason : %v", err)
+	}
+
+	for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+		if _, err = f.Write([]byte(line)); err != nil {
+			t.Fatalf("error while executing Write function.\nReason : %v", err)
+		}
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatalf("error while executing Close function.\nReason : %v", err)
+	}
+
+	// Each line fills the file, the oldest line is removed
+	expected := map[string]string{
+		path:        "line-4\n",
+		path + ".1": "line-3\n",
+		path + ".2": "line-2\n",
+		path + ".3": "",
+	}
+
+	for file, content := range expected {
+		if out := readTestingFile(t, file); out != content {
+			t.Fatalf("wrong content returned for the file '%s'.\nExpected : %q\nReturned : %q", file, content, out)
+		}
+	}
+
+	// The records are appended to the existing file
+	f, err = NewRotatingFile(path, 0, 2)
+	if err != nil {
+		t.Fatalf("error while executing NewRotatingFile function.\nReason : %v", err)
+	}
+
+	defer f.Close()
+
+	if _, err = f.Write([]byte(strings.Repeat("x", 20) + "\n")); err != nil {
+		t.Fatalf("error while executing Write function.\nReason : %v", err)
+	}
+
+	if out := readTestingFile(t, path); !strings.HasPrefix(out, "line-4\nxxx") {
+		t.Fatalf("the file should not be rotated without maximum size.\nReturned : %q", out)
+	}
+}
+
+func TestRotatingFileWithoutBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map-builder.log")
+
+	f, err := NewRotatingFile(path, 10, 0)
+	if err != nil {
+		t.Fatalf("error while executing NewRotatingFile function.\nReason : %v", err)
+	}
+
+	defer f.Close()
+
+	f.Write([]byte("line-1\n"))
+	f.Write([]byte("line-2\n"))
+
+	if out := readTestingFile(t, path); out != "line-2\n" {
+		t.Fatalf("the file should be truncated.\nReturned : %q", out)
+	}
+
+	if out := readTestingFile(t, path+".1"); out != "" {
+		t.Fatalf("no backup should be kept.\nReturned : %q", out)
+	}
+}
+
+func TestRotatingFileRotateFail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map-builder.log")
+
+	// The oldest rotated file cannot be removed since it is a non-empty directory
+	if err := os.MkdirAll(filepath.Join(path+".1", "data"), 0755); err != nil {
+		t.Fatalf("error while creating the directory '%s'.\nReason : %v", path+".1", err)
+	}
+
+	f, err := NewRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("error while executing NewRotatingFile function.\nReason : %v", err)
+	}
+
+	defer f.Close()
+
+	f.Write([]byte("line-1\n"))
+	if _, err = f.Write([]byte("line-2\n")); err == nil {
+		t.Fatalf("an error should be returned when the log file cannot be rotated")
+	}
+
+	// The log file is reopened, the next records are written once the rotation succeeds
+	if err = os.RemoveAll(path + ".1"); err != nil {
+		t.Fatalf("error while removing the directory '%s'.\nReason : %v", path+".1", err)
+	}
+
+	if _, err = f.Write([]byte("line-3\n")); err != nil {
+		t.Fatalf("error while executing Write function.\nReason : %v", err)
+	}
+
+	if out := readTestingFile(t, path); out != "line-3\n" {
+		t.Fatalf("wrong content returned for the file '%s'.\nExpected : %q\nReturned : %q", path, "line-3\n", out)
+	}
+
+	if out := readTestingFile(t, path+".1"); out != "line-1\n" {
+		t.Fatalf("wrong content returned for the file '%s'.\nExpected : %q\nReturned : %q", path+".1", "line-1\n", out)
+	}
+}
+
+func TestNewRotatingFileFail(t *testing.T) {
+	if _, err := NewRotatingFile(filepath.Join(t.TempDir(), "missing", "map-builder.log"), 10, 1); err == nil {
+		t.Fatalf("an error should be returned when the directory does not exist")
+	}
+
+	if _, err := NewRotatingFile(filepath.Join(t.TempDir(), "map-builder.log"), -1, 1); err == nil {
+		t.Fatalf("an error should be returned for a negative size")
+	}
+}
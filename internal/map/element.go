@@ -0,0 +1,160 @@
+package _map
+
+import (
+	"fmt"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+)
+
+// MapElementHostGroup define the object referenced by an host group element.
+type MapElementHostGroup struct {
+	Id string `json:"groupid"`
+}
+
+// MapElementTrigger define the object referenced by a trigger element.
+type MapElementTrigger struct {
+	Id string `json:"triggerid"`
+}
+
+// MapElementMap define the object referenced by a map element.
+type MapElementMap struct {
+	Id string `json:"sysmapid"`
+}
+
+// endpoint define an element of a mapping once the referenced object was retrieved.
+type endpoint struct {
+	// elementId is used to reference the element in the links.
+	elementId   string
+	elementType string
+	// objectId is the id of the host, host group, trigger or map referenced by the element, empty for an image element.
+	objectId string
+	// triggerId is the id of the trigger attached to the link, empty if the element does not use a trigger.
+	triggerId string
+	name      string
+}
+
+// label is used to retrieve the label of the element, the label of the mapping is used if set, otherwise the given template.
+// Image elements are not linked to a Zabbix object, their name is used if no label was set.
+func (e *endpoint) label(label string, template string, fields map[string]string) string {
+	if label == "" {
+		label = template
+	}
+
+	if label == "" && e.elementType == ElementImage {
+		return e.name
+	}
+
+	return expandTemplate(label, fields)
+}
+
+// resolveEndpoint is used to retrieve the object referenced by an element of the given type.
+// Host elements use the id of the host as element id (to be able to stack them), the other types use '<type>-<id>'.
+func resolveEndpoint(client api.ZabbixAPI, options *MapOptions, elementType string, name string, pattern string) (*endpoint, error) {
+	e := &endpoint{
+		elementType: elementType,
+		name:        name,
+	}
+
+	switch elementType {
+	case ElementHost:
+		e.objectId = options.Hosts[name]
+		if e.objectId == "" {
+			return nil, failure.Errorf(failure.NotFound, "no host was found with the name '%s'", name)
+		}
+
+		e.elementId = e.objectId
+
+		triggerId, err := getTriggerId(client, e.objectId, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		e.triggerId = triggerId
+	case ElementTrigger:
+		hostId := options.Hosts[name]
+		if hostId == "" {
+			return nil, failure.Errorf(failure.NotFound, "no host was found with the name '%s'", name)
+		}
+
+		triggerId, err := getTriggerId(client, hostId, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		e.objectId = triggerId
+		e.triggerId = triggerId
+		e.elementId = fmt.Sprintf("%s-%s", ElementTrigger, triggerId)
+	case ElementHostGroup:
+		e.objectId = options.HostGroups[name]
+		if e.objectId == "" {
+			return nil, failure.Errorf(failure.NotFound, "no host group was found with the name '%s'", name)
+		}
+
+		e.elementId = fmt.Sprintf("%s-%s", ElementHostGroup, e.objectId)
+	case ElementMap:
+		e.objectId = options.Maps[name]
+		if e.objectId == "" {
+			return nil, failure.Errorf(failure.NotFound, "no map was found with the name '%s'", name)
+		}
+
+		e.elementId = fmt.Sprintf("%s-%s", ElementMap, e.objectId)
+	case ElementImage:
+		e.elementId = fmt.Sprintf("%s-%s", ElementImage, name)
+	default:
+		return nil, fmt.Errorf("unsupported element type '%s', supported types are %v", elementType, ElementTypes)
+	}
+
+	return e, nil
+}
+
+// ElementTypeOf is used to retrieve the type of element (host, hostgroup, trigger, map or image) matching the given Zabbix element type.
+// An empty string is returned for an unknown type.
+func ElementTypeOf(t zabbixgosdk.MapElementType) string {
+	switch t {
+	case zabbixgosdk.MapHost:
+		return ElementHost
+	case zabbixgosdk.MapHostGroup:
+		return ElementHostGroup
+	case zabbixgosdk.MapTrigger:
+		return ElementTrigger
+	case zabbixgosdk.MapMap:
+		return ElementMap
+	case zabbixgosdk.MapImage:
+		return ElementImage
+	default:
+		return ""
+	}
+}
+
+// createElement is used to create a new map element of the given type referencing the object with the given id.
+// The given id is used to reference the element in the map links.
+func createElement(id string, elementType string, objectId string, image string, x string, y string) *zabbixgosdk.MapElement {
+	element := &zabbixgosdk.MapElement{
+		Id:        id,
+		IconIdOff: image,
+		X:         x,
+		Y:         y,
+	}
+
+	switch elementType {
+	case ElementHostGroup:
+		element.ElementType = zabbixgosdk.MapHostGroup
+		element.Elements = []MapElementHostGroup{{Id: objectId}}
+	case ElementTrigger:
+		element.ElementType = zabbixgosdk.MapTrigger
+		element.Elements = []MapElementTrigger{{Id: objectId}}
+	case ElementMap:
+		element.ElementType = zabbixgosdk.MapMap
+		element.Elements = []MapElementMap{{Id: objectId}}
+	case ElementImage:
+		// Image elements do not reference any object
+		element.ElementType = zabbixgosdk.MapImage
+	default:
+		element.ElementType = zabbixgosdk.MapHost
+		element.Elements = []zabbixgosdk.MapElementHost{{Id: objectId}}
+	}
+
+	return element
+}
@@ -0,0 +1,151 @@
+package _map
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+func TestCreateElement(t *testing.T) {
+	tests := map[string]struct {
+		elementType zabbixgosdk.MapElementType
+		payload     string
+	}{
+		ElementHost:      {zabbixgosdk.MapHost, `"elements":[{"hostid":"5"}]`},
+		ElementHostGroup: {zabbixgosdk.MapHostGroup, `"elements":[{"groupid":"5"}]`},
+		ElementTrigger:   {zabbixgosdk.MapTrigger, `"elements":[{"triggerid":"5"}]`},
+		ElementMap:       {zabbixgosdk.MapMap, `"elements":[{"sysmapid":"5"}]`},
+		ElementImage:     {zabbixgosdk.MapImage, ""},
+	}
+
+	for name, test := range tests {
+		element := createElement("1", name, "5", "11", "0", "0")
+
+		if element.ElementType != test.elementType {
+			t.Fatalf("wrong elementtype set for the type '%s'.\nExpected : %s\nReturned : %s", name, test.elementType, element.ElementType)
+		}
+
+		b, err := json.Marshal(element)
+		if err != nil {
+			t.Fatalf("error while encoding the element.\nReason : %v", err)
+		}
+
+		if test.payload != "" && !strings.Contains(string(b), test.payload) {
+			t.Fatalf("wrong payload set for the type '%s'.\nExpected : %s\nReturned : %s", name, test.payload, string(b))
+		}
+
+		if test.payload == "" && strings.Contains(string(b), `"elements"`) {
+			t.Fatalf("no object should be referenced by an image element.\nReturned : %s", string(b))
+		}
+	}
+}
+
+func TestResolveEndpointFail(t *testing.T) {
+	options := &MapOptions{
+		Hosts:      map[string]string{},
+		HostGroups: map[string]string{},
+		Maps:       map[string]string{},
+	}
+
+	for _, elementType := range []string{ElementHostGroup, ElementMap, "switch"} {
+		if _, err := resolveEndpoint(newFakeClient(), options, elementType, "missing", ""); err == nil {
+			t.Fatalf("an error should be returned for the type '%s'", elementType)
+		}
+	}
+}
+
+func TestBuildMapElementTypes(t *testing.T) {
+	client := newFakeClient().
+		AddTrigger("2", "23", "BGP session to ISP-A is down")
+
+	opts := MapOptions{
+		Name:       "test-map",
+		Height:     "800",
+		Width:      "800",
+		Spacer:     100,
+		StackHosts: true,
+		Mappings: []*Mapping{
+			{
+				LocalHost:           "router-1",
+				LocalTriggerPattern: "Interface eth0(): Link down",
+				LocalImage:          "Firewall_(64)",
+				RemoteHost:          "Servers",
+				RemoteType:          ElementHostGroup,
+				RemoteImage:         "Switch_(64)",
+			},
+			{
+				LocalHost:           "router-2",
+				LocalType:           ElementTrigger,
+				LocalTriggerPattern: "BGP session to ISP-A is down",
+				LocalImage:          "Firewall_(64)",
+				RemoteHost:          "ISP-A",
+				RemoteType:          ElementImage,
+				RemoteImage:         "Switch_(64)",
+			},
+			{
+				LocalHost:           "router-1",
+				LocalTriggerPattern: "Interface eth0(): Link down",
+				LocalImage:          "Firewall_(64)",
+				RemoteHost:          "Datacenter",
+				RemoteType:          ElementMap,
+				RemoteImage:         "Switch_(64)",
+			},
+		},
+		Hosts: map[string]string{
+			"router-1": "1",
+			"router-2": "2",
+		},
+		HostGroups: map[string]string{
+			"Servers": "31",
+		},
+		Maps: map[string]string{
+			"Datacenter": "41",
+		},
+		Images: map[string]string{
+			"Firewall_(64)": "11",
+			"Switch_(64)":   "12",
+		},
+	}
+
+	m, err := BuildMap(client, &opts)
+	if err != nil {
+		t.Fatalf("error while executing BuildMap function.\nReason : %v", err)
+	}
+
+	ids := make([]string, 0)
+	for _, e := range m.Elements {
+		ids = append(ids, e.Id)
+	}
+
+	expected := "1,hostgroup-31,trigger-23,image-ISP-A,map-41"
+	if strings.Join(ids, ",") != expected {
+		t.Fatalf("wrong elements set.\nExpected : %s\nReturned : %s", expected, strings.Join(ids, ","))
+	}
+
+	if m.Elements[3].Label != "ISP-A" {
+		t.Fatalf("the name of an image element should be used as label.\nReturned : %s", m.Elements[3].Label)
+	}
+
+	if len(m.Links[0].LinkTriggers) != 1 || m.Links[0].LinkTriggers[0].TriggerId != "21" {
+		t.Fatalf("only the trigger of the host should be attached to the first link.\nReturned : %v", m.Links[0].LinkTriggers)
+	}
+
+	if len(m.Links[1].LinkTriggers) != 1 || m.Links[1].LinkTriggers[0].TriggerId != "23" {
+		t.Fatalf("the trigger of the trigger element should be attached to the second link.\nReturned : %v", m.Links[1].LinkTriggers)
+	}
+}
+
+func TestElementTypeOf(t *testing.T) {
+	for _, elementType := range ElementTypes {
+		element := createElement("1", elementType, "5", "11", "0", "0")
+		if out := ElementTypeOf(element.ElementType); out != elementType {
+			t.Fatalf("wrong type returned.\nExpected : %s\nReturned : %s", elementType, out)
+		}
+	}
+
+	if out := ElementTypeOf("9"); out != "" {
+		t.Fatalf("an empty string should be returned for an unknown type.\nReturned : %s", out)
+	}
+}
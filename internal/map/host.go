@@ -16,10 +16,22 @@ type hostPosition struct {
 }
 
 type hostParameters struct {
-	id       string
-	name     string
-	image    string
-	position *hostPosition
+	id string
+	// elementType is the type of the element, an host element is created if empty.
+	elementType string
+	// name is the id of the object referenced by the element.
+	name  string
+	image string
+	icons *ElementIcons
+	label string
+	// labelLocation is the value of the label location used by the Zabbix API, the location of the map is used if empty.
+	labelLocation string
+	urls          []*zabbixgosdk.MapElementUrl
+	position      *hostPosition
+	// endpoint is the name of the element used in the mappings, used to retrieve its position in the layout.
+	endpoint string
+	// layout contains the positions set by a layout (optional), the element is placed automatically if none is left.
+	layout layoutPositions
 }
 
 // updateHostPosition is used to update the position for the next host
@@ -66,28 +78,37 @@ func initPosition(width string, height string, spacer int64) (*hostPosition, err
 // createHostElement is used to create a new MapElementHost.
 // The given id is used to reference the host in the map links.
 func createHostElement(id string, host string, image string, x string, y string) *zabbixgosdk.MapElement {
-	return &zabbixgosdk.MapElement{
-		Id: id,
-		Elements: []zabbixgosdk.MapElementHost{
-			{
-				Id: host,
-			},
-		},
-		ElementType: zabbixgosdk.MapHost,
-		IconIdOff:   image,
-		X:           x,
-		Y:           y,
-	}
+	return createElement(id, ElementHost, host, image, x, y)
 }
 
 // addHosts is used to add hosts (local and remote) for a given mapping if they do not already exist in the map.
 func addHosts(zbxMap *zabbixgosdk.MapCreateParameters, params *hostParameters) *zabbixgosdk.MapCreateParameters {
 	if exist := elementExist(params.id, zbxMap.Elements); !exist {
-		element := createHostElement(params.id, params.name, params.image, fmt.Sprintf("%d", params.position.x), fmt.Sprintf("%d", params.position.y))
+		x := params.position.x
+		y := params.position.y
+
+		fixed := params.layout.next(elementType(params.elementType), params.endpoint)
+		if fixed != nil {
+			x = fixed.X
+			y = fixed.Y
+		}
+
+		element := createElement(params.id, params.elementType, params.name, params.image, fmt.Sprintf("%d", x), fmt.Sprintf("%d", y))
+		element.Label = params.label
+		element.LabelLocation = params.labelLocation
+		element.Urls = params.urls
+
+		if params.icons != nil {
+			element.IconIdOn = params.icons.Problem
+			element.IconIdMaintenance = params.icons.Maintenance
+			element.IconIdDisabled = params.icons.Disabled
+		}
 		zbxMap.Elements = append(zbxMap.Elements, element)
 
-		// Update placement for the next host
-		params.position.updateHostPosition()
+		// Update placement for the next host, positions set by the layout do not use the automatic placement
+		if fixed == nil {
+			params.position.updateHostPosition()
+		}
 	}
 
 	return zbxMap
@@ -0,0 +1,107 @@
+package _map
+
+import (
+	"fmt"
+	"strings"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+// LabelLocations associate the supported label locations to the value used by the Zabbix API.
+var LabelLocations = map[string]string{
+	"default": "-1",
+	"bottom":  "0",
+	"left":    "1",
+	"right":   "2",
+	"top":     "3",
+}
+
+// UrlTemplate define an URL added to the elements of the map.
+// Both the name and the URL are templates (see expandTemplate).
+type UrlTemplate struct {
+	Name string
+	Url  string
+}
+
+// ParseUrlTemplate is used to read an URL template written as 'name=url'.
+func ParseUrlTemplate(value string) (*UrlTemplate, error) {
+	name, url, found := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	url = strings.TrimSpace(url)
+
+	if !found || name == "" || url == "" {
+		return nil, fmt.Errorf("invalid URL '%s', expected 'name=url'", value)
+	}
+
+	return &UrlTemplate{
+		Name: name,
+		Url:  url,
+	}, nil
+}
+
+// templateFields is used to retrieve the values of the mapping fields available in the templates for an element.
+func templateFields(e *endpoint, iface string, image string) map[string]string {
+	return map[string]string{
+		"{host}":      e.name,
+		"{interface}": iface,
+		"{image}":     image,
+		"{type}":      e.elementType,
+	}
+}
+
+// expandTemplate is used to replace the mapping fields ('{host}', '{interface}', '{image}' and '{type}') of the given template.
+// Zabbix macros ('{HOST.NAME}', '{HOST.IP}', '{INVENTORY.*}', etc.) are kept as is and resolved by the Zabbix frontend.
+func expandTemplate(template string, fields map[string]string) string {
+	if template == "" {
+		return ""
+	}
+
+	values := make([]string, 0)
+	for field, value := range fields {
+		values = append(values, field, value)
+	}
+
+	return strings.NewReplacer(values...).Replace(template)
+}
+
+// elementUrls is used to build the URLs of an element from the URL templates of the map.
+// Image elements do not reference any Zabbix object and do not get any URL.
+func (o *MapOptions) elementUrls(e *endpoint, fields map[string]string) []*zabbixgosdk.MapElementUrl {
+	if len(o.Urls) == 0 || e.elementType == ElementImage {
+		return nil
+	}
+
+	out := make([]*zabbixgosdk.MapElementUrl, 0)
+	for _, u := range o.Urls {
+		out = append(out, &zabbixgosdk.MapElementUrl{
+			Name: expandTemplate(u.Name, fields),
+			Url:  expandTemplate(u.Url, fields),
+		})
+	}
+
+	return out
+}
+
+// validateLabels is used to validate the label location and the URL templates of the map.
+func (o *MapOptions) validateLabels() error {
+	if o.LabelLocation != "" {
+		if _, exist := LabelLocations[o.LabelLocation]; !exist {
+			return fmt.Errorf("unsupported label location '%s', supported locations are default, bottom, left, right and top", o.LabelLocation)
+		}
+	}
+
+	names := make(map[string]bool, 0)
+	for _, u := range o.Urls {
+		if u == nil || u.Name == "" || u.Url == "" {
+			return fmt.Errorf("a name and an URL are required for each URL of the elements")
+		}
+
+		if names[u.Name] {
+			return fmt.Errorf("the URL name '%s' is used multiple times, the URLs of an element must have unique names", u.Name)
+		}
+
+		names[u.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package _map
+
+import (
+	"testing"
+)
+
+func TestParseUrlTemplate(t *testing.T) {
+	u, err := ParseUrlTemplate("NetBox = https://netbox/dcim/devices/?q={HOST.HOST}&a=b")
+	if err != nil {
+		t.Fatalf("error while executing ParseUrlTemplate function.\nReason : %v", err)
+	}
+
+	if u.Name != "NetBox" || u.Url != "https://netbox/dcim/devices/?q={HOST.HOST}&a=b" {
+		t.Fatalf("wrong URL template returned.\nReturned : %+v", u)
+	}
+
+	for _, value := range []string{"", "NetBox", "=https://netbox", "NetBox="} {
+		if _, err = ParseUrlTemplate(value); err == nil {
+			t.Fatalf("an error should be returned for the value '%s'", value)
+		}
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	e := &endpoint{
+		elementType: ElementHost,
+		name:        "router-1",
+	}
+
+	out := expandTemplate("{HOST.NAME} - {host}:{interface} ({type}, {image})", templateFields(e, "eth0", "Router_(64)"))
+	if out != "{HOST.NAME} - router-1:eth0 (host, Router_(64))" {
+		t.Fatalf("wrong value returned.\nExpected : {HOST.NAME} - router-1:eth0 (host, Router_(64))\nReturned : %s", out)
+	}
+}
+
+func TestEndpointLabel(t *testing.T) {
+	host := &endpoint{elementType: ElementHost, name: "router-1"}
+	image := &endpoint{elementType: ElementImage, name: "Internet"}
+
+	tests := []struct {
+		e        *endpoint
+		label    string
+		template string
+		expected string
+	}{
+		{host, "", "", ""},
+		{host, "", "{HOST.IP}", "{HOST.IP}"},
+		{host, "core {host}", "{HOST.IP}", "core router-1"},
+		{image, "", "", "Internet"},
+		{image, "", "{host}", "Internet"},
+	}
+
+	for _, test := range tests {
+		out := test.e.label(test.label, test.template, templateFields(test.e, "", ""))
+		if out != test.expected {
+			t.Fatalf("wrong label returned for the label '%s' and the template '%s'.\nExpected : %s\nReturned : %s", test.label, test.template, test.expected, out)
+		}
+	}
+}
+
+func TestElementUrls(t *testing.T) {
+	opts := &MapOptions{
+		Urls: []*UrlTemplate{
+			{Name: "NetBox", Url: "https://netbox/dcim/devices/?q={HOST.HOST}"},
+			{Name: "Grafana", Url: "https://grafana/d/device?var-host={host}"},
+		},
+	}
+
+	host := &endpoint{elementType: ElementHost, name: "router-1"}
+	urls := opts.elementUrls(host, templateFields(host, "", ""))
+	if len(urls) != 2 {
+		t.Fatalf("wrong number of URLs returned.\nExpected : 2\nReturned : %d", len(urls))
+	}
+
+	if urls[1].Name != "Grafana" || urls[1].Url != "https://grafana/d/device?var-host=router-1" {
+		t.Fatalf("wrong URL returned.\nReturned : %+v", urls[1])
+	}
+
+	image := &endpoint{elementType: ElementImage, name: "Internet"}
+	if urls = opts.elementUrls(image, templateFields(image, "", "")); urls != nil {
+		t.Fatalf("no URL should be returned for an image element.\nReturned : %v", urls)
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	tests := []*MapOptions{
+		{LabelLocation: "center"},
+		{Urls: []*UrlTemplate{{Name: "NetBox"}}},
+		{Urls: []*UrlTemplate{{Name: "NetBox", Url: "https://a"}, {Name: "NetBox", Url: "https://b"}}},
+	}
+
+	for _, opts := range tests {
+		if err := opts.validateLabels(); err == nil {
+			t.Fatalf("an error should be returned for the options %+v", opts)
+		}
+	}
+
+	opts := &MapOptions{LabelLocation: "top", Urls: []*UrlTemplate{{Name: "NetBox", Url: "https://a"}}}
+	if err := opts.validateLabels(); err != nil {
+		t.Fatalf("error while executing validateLabels function.\nReason : %v", err)
+	}
+}
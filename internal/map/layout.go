@@ -0,0 +1,114 @@
+package _map
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// Layout define the size of a map and the position of its elements.
+// A layout is used to reproduce an existing map instead of placing the elements automatically.
+type Layout struct {
+	Width    string           `json:"width,omitempty"`
+	Height   string           `json:"height,omitempty"`
+	Elements []*LayoutElement `json:"elements"`
+}
+
+// LayoutElement define the position of an element on the map.
+// The element is referenced by its type and the name used in the mappings.
+// If several elements share the same type and name (hosts not stacked), the positions are used in order.
+type LayoutElement struct {
+	// Type is the type of the element (host, hostgroup, trigger, map or image), 'host' is used if empty.
+	Type string `json:"type,omitempty"`
+	Name string `json:"name"`
+	X    int64  `json:"x"`
+	Y    int64  `json:"y"`
+}
+
+// layoutPositions associate the key of an element to the positions not yet used.
+type layoutPositions map[string][]*LayoutElement
+
+// layoutKey is used to build the key identifying an element in the layout.
+func layoutKey(elementType string, name string) string {
+	return fmt.Sprintf("%s/%s", elementType, name)
+}
+
+// LoadLayout is used to read a layout from the given JSON file.
+func LoadLayout(file string) (*Layout, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Layout{}
+	if err = json.Unmarshal(b, l); err != nil {
+		return nil, fmt.Errorf("error while decoding the layout file '%s'.\nReason : %v", file, err)
+	}
+
+	if err = l.Validate(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Write is used to write the layout to the given file as JSON.
+func (l *Layout) Write(file string) error {
+	b, err := json.MarshalIndent(l, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, b, 0644)
+}
+
+// Validate is used to validate the elements of the layout.
+func (l *Layout) Validate() error {
+	for _, e := range l.Elements {
+		if e.Name == "" {
+			return fmt.Errorf("a name is required for each element of the layout")
+		}
+
+		if e.Type != "" && !utils.Contains(ElementTypes, e.Type) {
+			return fmt.Errorf("unsupported element type '%s' for the layout element '%s', supported types are %v", e.Type, e.Name, ElementTypes)
+		}
+
+		if e.X < 0 || e.Y < 0 {
+			return fmt.Errorf("the position of the layout element '%s' cannot be negative", e.Name)
+		}
+	}
+
+	return nil
+}
+
+// positions is used to index the positions of the layout by element.
+// A nil layout returns no positions.
+func (l *Layout) positions() layoutPositions {
+	out := make(layoutPositions, 0)
+	if l == nil {
+		return out
+	}
+
+	for _, e := range l.Elements {
+		key := layoutKey(elementType(e.Type), e.Name)
+		out[key] = append(out[key], e)
+	}
+
+	return out
+}
+
+// next is used to retrieve the next position of the given element, nil is returned if no position is left.
+func (p layoutPositions) next(elementType string, name string) *LayoutElement {
+	key := layoutKey(elementType, name)
+
+	positions := p[key]
+	if len(positions) == 0 {
+		return nil
+	}
+
+	p[key] = positions[1:]
+
+	return positions[0]
+}
@@ -0,0 +1,120 @@
+package _map
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLayoutWriteAndLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "layout.json")
+	l := &Layout{
+		Width:  "1200",
+		Height: "600",
+		Elements: []*LayoutElement{
+			{Name: "router-1", X: 100, Y: 50},
+			{Type: ElementImage, Name: "Internet", X: 300, Y: 50},
+		},
+	}
+
+	if err := l.Write(file); err != nil {
+		t.Fatalf("error while executing Write function.\nReason : %v", err)
+	}
+
+	out, err := LoadLayout(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadLayout function.\nReason : %v", err)
+	}
+
+	if out.Width != "1200" || len(out.Elements) != 2 || out.Elements[1].Type != ElementImage || out.Elements[1].X != 300 {
+		t.Fatalf("wrong layout returned.\nReturned : %+v", out)
+	}
+}
+
+func TestLoadLayoutFail(t *testing.T) {
+	if _, err := LoadLayout(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("an error should be returned when the file does not exist")
+	}
+
+	file := filepath.Join(t.TempDir(), "layout.json")
+	if err := os.WriteFile(file, []byte(`{"elements":[{"type":"switch","name":"router-1"}]}`), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+	}
+
+	if _, err := LoadLayout(file); err == nil {
+		t.Fatal("an error should be returned when an element type is not supported")
+	}
+}
+
+func TestLayoutPositions(t *testing.T) {
+	l := &Layout{
+		Elements: []*LayoutElement{
+			{Name: "router-1", X: 100, Y: 50},
+			{Type: ElementHost, Name: "router-1", X: 200, Y: 50},
+		},
+	}
+
+	positions := l.positions()
+	for _, expected := range []int64{100, 200} {
+		p := positions.next(ElementHost, "router-1")
+		if p == nil || p.X != expected {
+			t.Fatalf("wrong position returned.\nExpected : %d\nReturned : %+v", expected, p)
+		}
+	}
+
+	if p := positions.next(ElementHost, "router-1"); p != nil {
+		t.Fatalf("no position should be left.\nReturned : %+v", p)
+	}
+
+	var empty *Layout
+	if p := empty.positions().next(ElementHost, "router-1"); p != nil {
+		t.Fatalf("no position should be returned for a nil layout.\nReturned : %+v", p)
+	}
+}
+
+func TestBuildMapLayout(t *testing.T) {
+	opts := MapOptions{
+		Name:       "test-map",
+		Height:     "800",
+		Width:      "800",
+		Spacer:     100,
+		StackHosts: true,
+		Mappings: []*Mapping{
+			{
+				LocalHost:            "router-1",
+				LocalTriggerPattern:  "Interface eth0(): Link down",
+				LocalImage:           "Firewall_(64)",
+				RemoteHost:           "router-2",
+				RemoteTriggerPattern: "Interface eth0(): Link down",
+				RemoteImage:          "Switch_(64)",
+			},
+		},
+		Hosts: map[string]string{
+			"router-1": "1",
+			"router-2": "2",
+		},
+		Images: map[string]string{
+			"Firewall_(64)": "11",
+			"Switch_(64)":   "12",
+		},
+		Layout: &Layout{
+			Elements: []*LayoutElement{
+				{Name: "router-2", X: 420, Y: 310},
+			},
+		},
+	}
+
+	m, err := BuildMap(newFakeClient(), &opts)
+	if err != nil {
+		t.Fatalf("error while executing BuildMap function.\nReason : %v", err)
+	}
+
+	// router-1 is placed automatically, router-2 uses the layout
+	if m.Elements[0].X != "100" || m.Elements[0].Y != "100" {
+		t.Fatalf("wrong position set for the element 'router-1'.\nExpected : 100,100\nReturned : %s,%s", m.Elements[0].X, m.Elements[0].Y)
+	}
+
+	if m.Elements[1].X != "420" || m.Elements[1].Y != "310" {
+		t.Fatalf("wrong position set for the element 'router-2'.\nExpected : 420,310\nReturned : %s,%s", m.Elements[1].X, m.Elements[1].Y)
+	}
+}
@@ -4,8 +4,32 @@ import (
 	"fmt"
 
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
 )
 
+const (
+	// ElementHost is used for an host element (default), the endpoint is the name of the host.
+	ElementHost = "host"
+	// ElementHostGroup is used for an host group element, the endpoint is the name of the host group.
+	ElementHostGroup = "hostgroup"
+	// ElementTrigger is used for a trigger element, the endpoint is the name of the host and the trigger pattern is used to select the trigger.
+	ElementTrigger = "trigger"
+	// ElementMap is used for an element linking to another map, the endpoint is the name of the map.
+	ElementMap = "map"
+	// ElementImage is used for an image element not linked to a Zabbix object (cloud, ISP, etc.), the endpoint is used as label.
+	ElementImage = "image"
+)
+
+// ElementTypes define the supported types of element.
+var ElementTypes = []string{
+	ElementHost,
+	ElementHostGroup,
+	ElementTrigger,
+	ElementMap,
+	ElementImage,
+}
+
 // Mapping define the properties used to create an hosts mapping on a Zabbix map.
 type Mapping struct {
 	LocalHost            string `json:"local_host"`
@@ -16,6 +40,53 @@ type Mapping struct {
 	RemoteInterface      string `json:"remote_interface,omitempty"`
 	RemoteTriggerPattern string `json:"remote_trigger_pattern"`
 	RemoteImage          string `json:"remote_image"`
+	// Optional fields used to override the map options for a single mapping.
+	LocalLabel   string `json:"local_label,omitempty"`
+	RemoteLabel  string `json:"remote_label,omitempty"`
+	Color        string `json:"color,omitempty"`
+	TriggerColor string `json:"trigger_color,omitempty"`
+	// Optional CDP capabilities of each host ('R S I' or '00 00 00 29'), used by the icon rules.
+	LocalCapabilities  string `json:"local_capabilities,omitempty"`
+	RemoteCapabilities string `json:"remote_capabilities,omitempty"`
+	// Optional type of each element (host, hostgroup, trigger, map or image), 'host' is used if empty.
+	LocalType  string `json:"local_type,omitempty"`
+	RemoteType string `json:"remote_type,omitempty"`
+}
+
+// elementType is used to retrieve the type of an element, 'host' is used if empty.
+func elementType(t string) string {
+	if t == "" {
+		return ElementHost
+	}
+
+	return t
+}
+
+// LocalElementType is used to retrieve the type of the local element.
+func (m *Mapping) LocalElementType() string {
+	return elementType(m.LocalType)
+}
+
+// RemoteElementType is used to retrieve the type of the remote element.
+func (m *Mapping) RemoteElementType() string {
+	return elementType(m.RemoteType)
+}
+
+// UsesTrigger is used to check if an element of the given type references a trigger using its trigger pattern.
+func UsesTrigger(t string) bool {
+	t = elementType(t)
+
+	return t == ElementHost || t == ElementTrigger
+}
+
+// ElementIcons define the images used for each state of an host element.
+// Each field is the name of an image, empty fields are not set on the element.
+type ElementIcons struct {
+	// Image is the default image (iconid_off), the other images are only used when the element uses this image.
+	Image       string
+	Problem     string
+	Maintenance string
+	Disabled    string
 }
 
 // MapOptions define the available options that can be passed to customize the map rendering.
@@ -30,6 +101,36 @@ type MapOptions struct {
 	Mappings     []*Mapping
 	Hosts        map[string]string
 	Images       map[string]string
+	// HostIcons associate an host name to the images used for each state of its element (optional).
+	HostIcons map[string]*ElementIcons
+	// HostGroups associate the name of the host groups used as element to their id (optional).
+	HostGroups map[string]string
+	// Maps associate the name of the maps used as element to their id (optional).
+	Maps map[string]string
+	// LabelTemplate is the label of the elements without a label set in the mappings (optional).
+	LabelTemplate string
+	// LabelLocation is the location of the labels (default, bottom, left, right or top), the location of the map is used if empty.
+	LabelLocation string
+	// Urls are the URLs added to the elements (optional).
+	Urls []*UrlTemplate
+	// Layout contains the position of the elements (optional), elements not part of the layout are placed automatically.
+	Layout *Layout
+}
+
+// elementIcons is used to retrieve the ids of the images used for each state of the element of the given host.
+// Nil is returned if no icons were set for the host or if the host uses another image.
+func (o *MapOptions) elementIcons(host string, image string) *ElementIcons {
+	icons, exist := o.HostIcons[host]
+	if !exist || icons == nil || icons.Image != image {
+		return nil
+	}
+
+	return &ElementIcons{
+		Image:       o.Images[icons.Image],
+		Problem:     o.Images[icons.Problem],
+		Maintenance: o.Images[icons.Maintenance],
+		Disabled:    o.Images[icons.Disabled],
+	}
 }
 
 // Validate is used to validate options that will be passed to a map.
@@ -58,10 +159,40 @@ func (o *MapOptions) Validate() error {
 		}
 	}
 
+	if err := o.validateLabels(); err != nil {
+		return err
+	}
+
+	if o.Layout != nil {
+		if err := o.Layout.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if o.Mappings == nil {
 		return fmt.Errorf("no mappings were passed to the build function")
 	}
 
+	for _, m := range o.Mappings {
+		if m.Color != "" {
+			if err := validateHexa(m.Color); err != nil {
+				return err
+			}
+		}
+
+		if m.TriggerColor != "" {
+			if err := validateHexa(m.TriggerColor); err != nil {
+				return err
+			}
+		}
+
+		for _, t := range []string{m.LocalType, m.RemoteType} {
+			if t != "" && !utils.Contains(ElementTypes, t) {
+				return fmt.Errorf("unsupported element type '%s', supported types are %v", t, ElementTypes)
+			}
+		}
+	}
+
 	if o.Hosts == nil {
 		return fmt.Errorf("no mapping 'host' -> 'hostid' was passed to the build function")
 	}
@@ -73,22 +204,15 @@ func (o *MapOptions) Validate() error {
 	return nil
 }
 
-// buildElementId is used to update the local and remote elements id based on the number of hosts that already exists with the same id.
+// buildElementId is used to update the local and remote elements id based on the number of elements that already exists with the same id.
+// The counts map associate each id to the number of elements already created, it is updated with the given ids.
 // Used only is --stack-hosts is set to false.
-func buildElementsId(zbxMap *zabbixgosdk.MapCreateParameters, localElementId string, remoteElementId string) (string, string) {
-	localCount := 0
-	remoteCount := 0
+func buildElementsId(counts map[string]int, localElementId string, remoteElementId string) (string, string) {
+	localCount := counts[localElementId]
+	remoteCount := counts[remoteElementId]
 
-	for _, element := range zbxMap.Elements {
-		elementId := element.Elements.([]zabbixgosdk.MapElementHost)[0].Id
-
-		if elementId == localElementId {
-			localCount++
-		}
-		if elementId == remoteElementId {
-			remoteCount++
-		}
-	}
+	counts[localElementId]++
+	counts[remoteElementId]++
 
 	if localCount > 0 {
 		localElementId = fmt.Sprintf("%s-%d", localElementId, localCount+1)
@@ -102,7 +226,7 @@ func buildElementsId(zbxMap *zabbixgosdk.MapCreateParameters, localElementId str
 }
 
 // BuildMap is used to build a map with the given mapping.
-func BuildMap(client *zabbixgosdk.ZabbixService, options *MapOptions) (*zabbixgosdk.MapCreateParameters, error) {
+func BuildMap(client api.ZabbixAPI, options *MapOptions) (*zabbixgosdk.MapCreateParameters, error) {
 	zbxMap := &zabbixgosdk.MapCreateParameters{}
 	zbxMap.Name = options.Name
 	zbxMap.Height = options.Height
@@ -113,39 +237,73 @@ func BuildMap(client *zabbixgosdk.ZabbixService, options *MapOptions) (*zabbixgo
 		return nil, err
 	}
 
+	counts := make(map[string]int, 0)
+	layout := options.Layout.positions()
+
 	// Loop over each mapping
 	for _, mapping := range options.Mappings {
-		localElementId := options.Hosts[mapping.LocalHost]
-		remoteElementId := options.Hosts[mapping.RemoteHost]
+		// Retrieve the object referenced by each element and the trigger attached to the link
+		local, err := resolveEndpoint(client, options, mapping.LocalElementType(), mapping.LocalHost, mapping.LocalTriggerPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		remote, err := resolveEndpoint(client, options, mapping.RemoteElementType(), mapping.RemoteHost, mapping.RemoteTriggerPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		localElementId := local.elementId
+		remoteElementId := remote.elementId
 
 		// If hosts should not be stacked, update the elementsId by appending '-<number-of-element-already-present + 1>'
 		if !options.StackHosts {
-			localElementId, remoteElementId = buildElementsId(zbxMap, localElementId, remoteElementId)
+			localElementId, remoteElementId = buildElementsId(counts, localElementId, remoteElementId)
 		}
 
-		// Add the hosts to the map
+		localFields := templateFields(local, mapping.LocalInterface, mapping.LocalImage)
+		remoteFields := templateFields(remote, mapping.RemoteInterface, mapping.RemoteImage)
+
+		// Add the elements to the map
 		zbxMap = addHosts(zbxMap, &hostParameters{
-			id:       localElementId,
-			name:     options.Hosts[mapping.LocalHost],
-			image:    options.Images[mapping.LocalImage],
-			position: position,
+			id:            localElementId,
+			elementType:   local.elementType,
+			name:          local.objectId,
+			image:         options.Images[mapping.LocalImage],
+			icons:         options.elementIcons(mapping.LocalHost, mapping.LocalImage),
+			label:         local.label(mapping.LocalLabel, options.LabelTemplate, localFields),
+			labelLocation: LabelLocations[options.LabelLocation],
+			urls:          options.elementUrls(local, localFields),
+			position:      position,
+			endpoint:      mapping.LocalHost,
+			layout:        layout,
 		})
 		zbxMap = addHosts(zbxMap, &hostParameters{
-			id:       remoteElementId,
-			name:     options.Hosts[mapping.RemoteHost],
-			image:    options.Images[mapping.RemoteImage],
-			position: position,
+			id:            remoteElementId,
+			elementType:   remote.elementType,
+			name:          remote.objectId,
+			image:         options.Images[mapping.RemoteImage],
+			icons:         options.elementIcons(mapping.RemoteHost, mapping.RemoteImage),
+			label:         remote.label(mapping.RemoteLabel, options.LabelTemplate, remoteFields),
+			labelLocation: LabelLocations[options.LabelLocation],
+			urls:          options.elementUrls(remote, remoteFields),
+			position:      position,
+			endpoint:      mapping.RemoteHost,
+			layout:        layout,
 		})
 
-		// Retriev the triggers id based on the given pattern for each hosts
-		localTriggerId, err := getTriggerId(client, options.Hosts[mapping.LocalHost], mapping.LocalTriggerPattern)
-		if err != nil {
-			return nil, err
+		localTriggerId := local.triggerId
+		remoteTriggerId := remote.triggerId
+
+		// Use the colors of the mapping if set, otherwise the colors of the map
+		linkColor := options.Color
+		if mapping.Color != "" {
+			linkColor = mapping.Color
 		}
 
-		remoteTriggerId, err := getTriggerId(client, options.Hosts[mapping.RemoteHost], mapping.RemoteTriggerPattern)
-		if err != nil {
-			return nil, err
+		triggerLinkColor := options.TriggerColor
+		if mapping.TriggerColor != "" {
+			triggerLinkColor = mapping.TriggerColor
 		}
 
 		// Add the link to the map
@@ -154,8 +312,8 @@ func BuildMap(client *zabbixgosdk.ZabbixService, options *MapOptions) (*zabbixgo
 			localTrigger:     localTriggerId,
 			remoteElement:    remoteElementId,
 			remoteTrigger:    remoteTriggerId,
-			linkColor:        options.Color,
-			triggerLinkColor: options.TriggerColor,
+			linkColor:        linkColor,
+			triggerLinkColor: triggerLinkColor,
 		})
 	}
 
@@ -164,15 +322,29 @@ func BuildMap(client *zabbixgosdk.ZabbixService, options *MapOptions) (*zabbixgo
 
 // CreateMap is used to create the given map.
 // The map create parameters can also be exported to a file if a file path is specified.
-func CreateMap(client *zabbixgosdk.ZabbixService, m *zabbixgosdk.MapCreateParameters) error {
-	res, err := client.Map.Create(m)
+func CreateMap(client api.ZabbixAPI, m *zabbixgosdk.MapCreateParameters) error {
+	ids, err := client.CreateMap(m)
 	if err != nil {
 		return err
 	}
 
-	if res == nil || len(res.MapIds) == 0 {
+	if len(ids) == 0 {
 		return fmt.Errorf("an empty response was returned when creating the map")
 	}
 
 	return nil
 }
+
+// UpdateMap is used to replace the definition of an existing map with the given map, the id of the existing map must be set.
+func UpdateMap(client api.ZabbixAPI, m *zabbixgosdk.MapCreateParameters) error {
+	ids, err := client.UpdateMap(m)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("an empty response was returned when updating the map")
+	}
+
+	return nil
+}
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
 )
 
 const (
@@ -213,20 +216,11 @@ func TestValidateFailImages(t *testing.T) {
 }
 
 func TestBuildElementsId(t *testing.T) {
-	zbxMap := &zabbixgosdk.MapCreateParameters{
-		Elements: []*zabbixgosdk.MapElement{
-			{
-				Id: "1",
-				Elements: []zabbixgosdk.MapElementHost{
-					{
-						Id: "1",
-					},
-				},
-			},
-		},
+	counts := map[string]int{
+		"1": 1,
 	}
 
-	local, remote := buildElementsId(zbxMap, "1", "2")
+	local, remote := buildElementsId(counts, "1", "2")
 
 	if local != "1-2" {
 		t.Fatalf("local id should have been incremented.\nExpected : '1-1'\nReturned : %s", local)
@@ -235,12 +229,14 @@ func TestBuildElementsId(t *testing.T) {
 	if remote != "2" {
 		t.Fatalf("remote id should not have been incremented.\nExpected : '2'\nReturned : %s", remote)
 	}
+
+	if counts["1"] != 2 || counts["2"] != 1 {
+		t.Fatalf("the number of elements was not updated.\nExpected : map[1:2 2:1]\nReturned : %v", counts)
+	}
 }
 
 func TestBuildElementsIdNoIncrement(t *testing.T) {
-	zbxMap := &zabbixgosdk.MapCreateParameters{}
-
-	local, remote := buildElementsId(zbxMap, "1", "2")
+	local, remote := buildElementsId(make(map[string]int, 0), "1", "2")
 
 	if local != "1" {
 		t.Fatalf("local id should not have been incremented.\nExpected : '1'\nReturned : %s", local)
@@ -266,7 +262,7 @@ func TestCreateMap(t *testing.T) {
 		t.Fatalf("error during Zabbix API authentification.\nReason : %v", err)
 	}
 
-	err = CreateMap(client, &zabbixgosdk.MapCreateParameters{
+	err = CreateMap(api.NewClient(client), &zabbixgosdk.MapCreateParameters{
 		Map: zabbixgosdk.Map{
 			Height: "800",
 			Width:  "800",
@@ -278,3 +274,332 @@ func TestCreateMap(t *testing.T) {
 		t.Fatalf("error when executing CreateMap function.\nReason : %v", err)
 	}
 }
+
+// newFakeClient is used to create a fake client containing the hosts, images and triggers used in the tests.
+func newFakeClient() *fake.Client {
+	return fake.NewClient().
+		AddHost("1", "router-1").
+		AddHost("2", "router-2").
+		AddImage("11", "Firewall_(64)").
+		AddImage("12", "Switch_(64)").
+		AddTrigger("1", "21", "Interface eth0(): Link down").
+		AddTrigger("2", "22", "Interface eth0(): Link down")
+}
+
+func TestBuildMap(t *testing.T) {
+	opts := MapOptions{
+		Name:         "test-map",
+		Color:        "000000",
+		TriggerColor: "DD0000",
+		Height:       "800",
+		Width:        "800",
+		Spacer:       100,
+		StackHosts:   true,
+		Mappings: []*Mapping{
+			{
+				LocalHost:            "router-1",
+				LocalTriggerPattern:  "Interface eth0(): Link down",
+				LocalImage:           "Firewall_(64)",
+				RemoteHost:           "router-2",
+				RemoteTriggerPattern: "Interface eth0(): Link down",
+				RemoteImage:          "Switch_(64)",
+			},
+		},
+		Hosts: map[string]string{
+			"router-1": "1",
+			"router-2": "2",
+		},
+		Images: map[string]string{
+			"Firewall_(64)": "11",
+			"Switch_(64)":   "12",
+		},
+	}
+
+	m, err := BuildMap(newFakeClient(), &opts)
+	if err != nil {
+		t.Fatalf("error while executing BuildMap function.\nReason : %v", err)
+	}
+
+	if m.Name != "test-map" {
+		t.Fatalf("wrong map name set.\nExpected : 'test-map'\nReturned : %s", m.Name)
+	}
+
+	if len(m.Elements) != 2 {
+		t.Fatalf("wrong number of elements set.\nExpected : 2\nReturned : %d", len(m.Elements))
+	}
+
+	if m.Elements[0].IconIdOff != "11" {
+		t.Fatalf("wrong icon id set for the first element.\nExpected : '11'\nReturned : %s", m.Elements[0].IconIdOff)
+	}
+
+	if len(m.Links) != 1 {
+		t.Fatalf("wrong number of links set.\nExpected : 1\nReturned : %d", len(m.Links))
+	}
+
+	if m.Links[0].LinkTriggers[0].TriggerId != "21" {
+		t.Fatalf("wrong trigger id (1) set.\nExpected : '21'\nReturned : %s", m.Links[0].LinkTriggers[0].TriggerId)
+	}
+
+	if m.Links[0].LinkTriggers[1].TriggerId != "22" {
+		t.Fatalf("wrong trigger id (2) set.\nExpected : '22'\nReturned : %s", m.Links[0].LinkTriggers[1].TriggerId)
+	}
+}
+
+func TestBuildMapOverride(t *testing.T) {
+	opts := MapOptions{
+		Name:         "test-map",
+		Color:        "000000",
+		TriggerColor: "DD0000",
+		Height:       "800",
+		Width:        "800",
+		Spacer:       100,
+		StackHosts:   true,
+		Mappings: []*Mapping{
+			{
+				LocalHost:            "router-1",
+				LocalTriggerPattern:  "Interface eth0(): Link down",
+				LocalImage:           "Firewall_(64)",
+				LocalLabel:           "Core router",
+				RemoteHost:           "router-2",
+				RemoteTriggerPattern: "Interface eth0(): Link down",
+				RemoteImage:          "Switch_(64)",
+				Color:                "7AC2E1",
+				TriggerColor:         "EE445B",
+			},
+		},
+		Hosts: map[string]string{
+			"router-1": "1",
+			"router-2": "2",
+		},
+		Images: map[string]string{
+			"Firewall_(64)": "11",
+			"Switch_(64)":   "12",
+		},
+	}
+
+	m, err := BuildMap(newFakeClient(), &opts)
+	if err != nil {
+		t.Fatalf("error while executing BuildMap function.\nReason : %v", err)
+	}
+
+	if m.Elements[0].Label != "Core router" || m.Elements[1].Label != "" {
+		t.Fatalf("wrong labels set.\nExpected : 'Core router', ''\nReturned : '%s', '%s'", m.Elements[0].Label, m.Elements[1].Label)
+	}
+
+	if m.Links[0].Color != "7AC2E1" {
+		t.Fatalf("wrong link color set.\nExpected : '7AC2E1'\nReturned : %s", m.Links[0].Color)
+	}
+
+	if m.Links[0].LinkTriggers[0].Color != "EE445B" {
+		t.Fatalf("wrong trigger color set.\nExpected : 'EE445B'\nReturned : %s", m.Links[0].LinkTriggers[0].Color)
+	}
+}
+
+func TestBuildMapHostIcons(t *testing.T) {
+	opts := MapOptions{
+		Name:         "test-map",
+		Color:        "000000",
+		TriggerColor: "DD0000",
+		Height:       "800",
+		Width:        "800",
+		Spacer:       100,
+		StackHosts:   true,
+		Mappings: []*Mapping{
+			{
+				LocalHost:            "router-1",
+				LocalTriggerPattern:  "Interface eth0(): Link down",
+				LocalImage:           "Router_(64)",
+				RemoteHost:           "router-2",
+				RemoteTriggerPattern: "Interface eth0(): Link down",
+				RemoteImage:          "Switch_(64)",
+			},
+		},
+		Hosts: map[string]string{
+			"router-1": "1",
+			"router-2": "2",
+		},
+		Images: map[string]string{
+			"Router_(64)":             "11",
+			"Router_(64)_problem":     "12",
+			"Router_(64)_maintenance": "13",
+			"Switch_(64)":             "14",
+			"Switch_(64)_problem":     "15",
+		},
+		HostIcons: map[string]*ElementIcons{
+			"router-1": {
+				Image:       "Router_(64)",
+				Problem:     "Router_(64)_problem",
+				Maintenance: "Router_(64)_maintenance",
+			},
+			// The icons are not used if the element uses another image
+			"router-2": {
+				Image:   "Firewall_(64)",
+				Problem: "Switch_(64)_problem",
+			},
+		},
+	}
+
+	m, err := BuildMap(newFakeClient(), &opts)
+	if err != nil {
+		t.Fatalf("error while executing BuildMap function.\nReason : %v", err)
+	}
+
+	local := m.Elements[0]
+	if local.IconIdOff != "11" || local.IconIdOn != "12" || local.IconIdMaintenance != "13" || local.IconIdDisabled != "" {
+		t.Fatalf("wrong icons set for the local host.\nExpected : 11, 12, 13, ''\nReturned : %s, %s, %s, '%s'", local.IconIdOff, local.IconIdOn, local.IconIdMaintenance, local.IconIdDisabled)
+	}
+
+	remote := m.Elements[1]
+	if remote.IconIdOff != "14" || remote.IconIdOn != "" {
+		t.Fatalf("wrong icons set for the remote host.\nExpected : 14, ''\nReturned : %s, '%s'", remote.IconIdOff, remote.IconIdOn)
+	}
+}
+
+func TestValidateFailMappingColor(t *testing.T) {
+	opts := MapOptions{
+		Name: "test-map",
+		Mappings: []*Mapping{
+			{
+				LocalHost: "local-host",
+				Color:     "not-a-color",
+			},
+		},
+		Hosts:  map[string]string{},
+		Images: map[string]string{},
+	}
+
+	if err := opts.Validate(); err == nil {
+		t.Fatal("an error should be returned when the color of a mapping is not a valid hexadecimal color")
+	}
+}
+
+func TestValidateFailElementType(t *testing.T) {
+	opts := MapOptions{
+		Name: "test-map",
+		Mappings: []*Mapping{
+			{
+				LocalHost: "local-host",
+				LocalType: "router",
+			},
+		},
+		Hosts:  map[string]string{},
+		Images: map[string]string{},
+	}
+
+	if err := opts.Validate(); err == nil {
+		t.Fatal("an error should be returned when the type of an element is not supported")
+	}
+}
+
+func TestBuildMapFailTrigger(t *testing.T) {
+	opts := MapOptions{
+		Name:   "test-map",
+		Height: "800",
+		Width:  "800",
+		Spacer: 100,
+		Mappings: []*Mapping{
+			{
+				LocalHost:            "router-1",
+				LocalTriggerPattern:  "pattern-does-not-exist",
+				RemoteHost:           "router-2",
+				RemoteTriggerPattern: "Interface eth0(): Link down",
+			},
+		},
+		Hosts: map[string]string{
+			"router-1": "1",
+			"router-2": "2",
+		},
+		Images: map[string]string{},
+	}
+
+	_, err := BuildMap(newFakeClient(), &opts)
+	if err == nil {
+		t.Fatal("an error should be returned when no trigger matches the given pattern")
+	}
+}
+
+func TestCreateMapFake(t *testing.T) {
+	client := newFakeClient()
+
+	err := CreateMap(client, &zabbixgosdk.MapCreateParameters{
+		Map: zabbixgosdk.Map{
+			Height: "800",
+			Width:  "800",
+			Name:   "test-map",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("error when executing CreateMap function.\nReason : %v", err)
+	}
+
+	if len(client.Maps) != 1 {
+		t.Fatalf("wrong number of maps created.\nExpected : 1\nReturned : %d", len(client.Maps))
+	}
+}
+
+func TestUpdateMapFake(t *testing.T) {
+	client := newFakeClient()
+
+	m := &zabbixgosdk.MapCreateParameters{}
+	m.Name = "test-map"
+	if err := CreateMap(client, m); err != nil {
+		t.Fatalf("error when executing CreateMap function.\nReason : %v", err)
+	}
+
+	updated := &zabbixgosdk.MapCreateParameters{}
+	updated.Id = "1"
+	updated.Name = "test-map"
+	updated.Width = "1200"
+	if err := UpdateMap(client, updated); err != nil {
+		t.Fatalf("error when executing UpdateMap function.\nReason : %v", err)
+	}
+
+	if len(client.Maps) != 1 || client.Maps[0].Width != "1200" {
+		t.Fatalf("the map was not updated.\nReturned : %+v", client.Maps)
+	}
+
+	updated.Id = "2"
+	if err := UpdateMap(client, updated); err == nil {
+		t.Fatal("an error should be returned when the map does not exist")
+	}
+}
+
+func TestBuildMapFailUnresolvedHost(t *testing.T) {
+	for _, elementType := range []string{ElementHost, ElementTrigger} {
+		opts := MapOptions{
+			Name:   "test-map",
+			Height: "800",
+			Width:  "800",
+			Spacer: 100,
+			Mappings: []*Mapping{
+				{
+					LocalHost:            "router-1",
+					LocalTriggerPattern:  "Interface eth0(): Link down",
+					RemoteHost:           "router-unknown",
+					RemoteTriggerPattern: "Interface eth0(): Link down",
+					RemoteType:           elementType,
+				},
+			},
+			Hosts: map[string]string{
+				"router-1":       "1",
+				"router-unknown": "",
+			},
+			Images: map[string]string{},
+		}
+
+		_, err := BuildMap(newFakeClient(), &opts)
+		if err == nil {
+			t.Fatalf("an error should be returned when the host of a '%s' element is not found", elementType)
+		}
+
+		if kind := failure.KindOf(err); kind != failure.NotFound {
+			t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.NotFound, kind)
+		}
+
+		expected := "no host was found with the name 'router-unknown'"
+		if err.Error() != expected {
+			t.Fatalf("wrong error returned.\nExpected : %s\nReturned : %s", expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package _map
+
+import (
+	"fmt"
+)
+
+// Conflict define a field of a link declared multiple times with different values.
+type Conflict struct {
+	// Entry is the index of the first declaration of the link, Duplicate the index of the ignored declaration.
+	Entry     int
+	Duplicate int
+	Field     string
+	Kept      string
+	Ignored   string
+}
+
+// String is used to format the conflict.
+func (c *Conflict) String() string {
+	return fmt.Sprintf("entry %d duplicates the link of the entry %d with a different '%s' (kept '%s', ignored '%s')", c.Duplicate, c.Entry, c.Field, c.Kept, c.Ignored)
+}
+
+// endpointKey is used to identify an endpoint of a link using its host and interface.
+func endpointKey(host string, iface string) string {
+	return fmt.Sprintf("%s:%s", host, iface)
+}
+
+// undirectedKey is used to identify a link independently of the order of its endpoints.
+func undirectedKey(m *Mapping) string {
+	local := endpointKey(m.LocalHost, m.LocalInterface)
+	remote := endpointKey(m.RemoteHost, m.RemoteInterface)
+
+	if remote < local {
+		local, remote = remote, local
+	}
+
+	return fmt.Sprintf("%s|%s", local, remote)
+}
+
+// reverse is used to retrieve a copy of the mapping with the local and remote endpoints swapped.
+func reverse(m *Mapping) *Mapping {
+	return &Mapping{
+		LocalHost:            m.RemoteHost,
+		LocalInterface:       m.RemoteInterface,
+		LocalTriggerPattern:  m.RemoteTriggerPattern,
+		LocalImage:           m.RemoteImage,
+		LocalLabel:           m.RemoteLabel,
+		RemoteHost:           m.LocalHost,
+		RemoteInterface:      m.LocalInterface,
+		RemoteTriggerPattern: m.LocalTriggerPattern,
+		RemoteImage:          m.LocalImage,
+		RemoteLabel:          m.LocalLabel,
+		Color:                m.Color,
+		TriggerColor:         m.TriggerColor,
+		LocalCapabilities:    m.RemoteCapabilities,
+		RemoteCapabilities:   m.LocalCapabilities,
+		LocalType:            m.RemoteType,
+		RemoteType:           m.LocalType,
+	}
+}
+
+// mergeField is used to set an empty field using the value of a duplicate.
+// A conflict is returned if both values are set and are different.
+func mergeField(field string, kept *string, value string) *Conflict {
+	if value == "" || *kept == value {
+		return nil
+	}
+
+	if *kept == "" {
+		*kept = value
+		return nil
+	}
+
+	return &Conflict{
+		Field:   field,
+		Kept:    *kept,
+		Ignored: value,
+	}
+}
+
+// Normalize is used to merge the mappings declaring the same link.
+// Links are undirected, A:eth0 -> B:eth0 and B:eth0 -> A:eth0 are the same link.
+// The first declaration of a link is kept, its empty fields are set using the duplicates (trigger patterns, images, etc.).
+// When a duplicate set a different value, the value of the first declaration is kept and a conflict is returned.
+func Normalize(mappings []*Mapping) ([]*Mapping, []*Conflict) {
+	out := make([]*Mapping, 0)
+	conflicts := make([]*Conflict, 0)
+	// Associate each link to its position in the output and the index of its first declaration
+	seen := make(map[string]int, 0)
+	entries := make([]int, 0)
+
+	for i, m := range mappings {
+		key := undirectedKey(m)
+
+		position, exist := seen[key]
+		if !exist {
+			// Copy the mapping to keep the input unchanged
+			c := *m
+			seen[key] = len(out)
+			out = append(out, &c)
+			entries = append(entries, i)
+			continue
+		}
+
+		kept := out[position]
+		duplicate := m
+		if endpointKey(m.LocalHost, m.LocalInterface) != endpointKey(kept.LocalHost, kept.LocalInterface) {
+			duplicate = reverse(m)
+		}
+
+		fields := []struct {
+			name  string
+			kept  *string
+			value string
+		}{
+			{"local_trigger_pattern", &kept.LocalTriggerPattern, duplicate.LocalTriggerPattern},
+			{"local_image", &kept.LocalImage, duplicate.LocalImage},
+			{"local_label", &kept.LocalLabel, duplicate.LocalLabel},
+			{"remote_trigger_pattern", &kept.RemoteTriggerPattern, duplicate.RemoteTriggerPattern},
+			{"remote_image", &kept.RemoteImage, duplicate.RemoteImage},
+			{"remote_label", &kept.RemoteLabel, duplicate.RemoteLabel},
+			{"color", &kept.Color, duplicate.Color},
+			{"trigger_color", &kept.TriggerColor, duplicate.TriggerColor},
+			{"local_capabilities", &kept.LocalCapabilities, duplicate.LocalCapabilities},
+			{"remote_capabilities", &kept.RemoteCapabilities, duplicate.RemoteCapabilities},
+			{"local_type", &kept.LocalType, duplicate.LocalType},
+			{"remote_type", &kept.RemoteType, duplicate.RemoteType},
+		}
+
+		for _, f := range fields {
+			if c := mergeField(f.name, f.kept, f.value); c != nil {
+				c.Entry = entries[position]
+				c.Duplicate = i
+				conflicts = append(conflicts, c)
+			}
+		}
+	}
+
+	return out, conflicts
+}
@@ -0,0 +1,148 @@
+package _map
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	mappings := []*Mapping{
+		{
+			LocalHost:            "router-1",
+			LocalInterface:       "eth0",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Firewall_(64)",
+			RemoteHost:           "router-2",
+			RemoteInterface:      "eth0",
+			RemoteTriggerPattern: "",
+			RemoteImage:          "Switch_(64)",
+		},
+		{
+			LocalHost:            "router-2",
+			LocalInterface:       "eth0",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Switch_(64)",
+			RemoteHost:           "router-1",
+			RemoteInterface:      "eth0",
+			RemoteTriggerPattern: "",
+			RemoteImage:          "Firewall_(64)",
+			Color:                "00FF00",
+		},
+		{
+			LocalHost:            "router-2",
+			LocalInterface:       "eth1",
+			LocalTriggerPattern:  "Interface eth1(): Link down",
+			LocalImage:           "Switch_(64)",
+			RemoteHost:           "router-3",
+			RemoteInterface:      "eth0",
+			RemoteTriggerPattern: "Interface eth0(): Link down",
+			RemoteImage:          "Switch_(64)",
+		},
+	}
+
+	out, conflicts := Normalize(mappings)
+
+	if len(conflicts) != 0 {
+		t.Fatalf("no conflict should be returned.\nReturned : %v", conflicts)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 2\nReturned : %d", len(out))
+	}
+
+	expected := &Mapping{
+		LocalHost:            "router-1",
+		LocalInterface:       "eth0",
+		LocalTriggerPattern:  "Interface eth0(): Link down",
+		LocalImage:           "Firewall_(64)",
+		RemoteHost:           "router-2",
+		RemoteInterface:      "eth0",
+		RemoteTriggerPattern: "Interface eth0(): Link down",
+		RemoteImage:          "Switch_(64)",
+		Color:                "00FF00",
+	}
+
+	if !reflect.DeepEqual(out[0], expected) {
+		t.Fatalf("wrong mapping returned.\nExpected : %+v\nReturned : %+v", expected, out[0])
+	}
+
+	if out[1].LocalHost != "router-2" || out[1].RemoteHost != "router-3" {
+		t.Fatalf("wrong mapping returned.\nExpected : router-2 -> router-3\nReturned : %s -> %s", out[1].LocalHost, out[1].RemoteHost)
+	}
+
+	// The input should not be modified
+	if mappings[0].RemoteTriggerPattern != "" {
+		t.Fatalf("the input mappings should not be modified.\nReturned : %+v", mappings[0])
+	}
+}
+
+func TestNormalizeDifferentInterfaces(t *testing.T) {
+	mappings := []*Mapping{
+		{LocalHost: "router-1", LocalInterface: "eth0", RemoteHost: "router-2", RemoteInterface: "eth0"},
+		{LocalHost: "router-2", LocalInterface: "eth1", RemoteHost: "router-1", RemoteInterface: "eth1"},
+	}
+
+	out, conflicts := Normalize(mappings)
+
+	if len(conflicts) != 0 {
+		t.Fatalf("no conflict should be returned.\nReturned : %v", conflicts)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("links using different interfaces should not be merged.\nExpected : 2\nReturned : %d", len(out))
+	}
+}
+
+func TestNormalizeConflict(t *testing.T) {
+	mappings := []*Mapping{
+		{
+			LocalHost:            "router-1",
+			LocalInterface:       "eth0",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Firewall_(64)",
+			RemoteHost:           "router-2",
+			RemoteInterface:      "eth0",
+			RemoteTriggerPattern: "Interface eth0(): Link down",
+			RemoteImage:          "Switch_(64)",
+		},
+		{
+			LocalHost:            "router-2",
+			LocalInterface:       "eth0",
+			LocalTriggerPattern:  "Interface eth0(): Link down",
+			LocalImage:           "Router_(64)",
+			RemoteHost:           "router-1",
+			RemoteInterface:      "eth0",
+			RemoteTriggerPattern: "Interface eth0(): Operational down",
+			RemoteImage:          "Firewall_(64)",
+		},
+	}
+
+	out, conflicts := Normalize(mappings)
+
+	if len(out) != 1 {
+		t.Fatalf("wrong number of mappings returned.\nExpected : 1\nReturned : %d", len(out))
+	}
+
+	expected := []*Conflict{
+		{Entry: 0, Duplicate: 1, Field: "local_trigger_pattern", Kept: "Interface eth0(): Link down", Ignored: "Interface eth0(): Operational down"},
+		{Entry: 0, Duplicate: 1, Field: "remote_image", Kept: "Switch_(64)", Ignored: "Router_(64)"},
+	}
+
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Fatalf("wrong conflicts returned.\nExpected : %v\nReturned : %v", expected, conflicts)
+	}
+
+	// The first declaration should be kept
+	if out[0].RemoteImage != "Switch_(64)" {
+		t.Fatalf("wrong remote image returned.\nExpected : Switch_(64)\nReturned : %s", out[0].RemoteImage)
+	}
+}
+
+func TestConflictString(t *testing.T) {
+	c := &Conflict{Entry: 0, Duplicate: 3, Field: "local_image", Kept: "Firewall_(64)", Ignored: "Switch_(64)"}
+
+	expected := "entry 3 duplicates the link of the entry 0 with a different 'local_image' (kept 'Firewall_(64)', ignored 'Switch_(64)')"
+	if c.String() != expected {
+		t.Fatalf("wrong string returned.\nExpected : %s\nReturned : %s", expected, c.String())
+	}
+}
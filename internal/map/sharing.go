@@ -0,0 +1,225 @@
+package _map
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PermissionRead is used to share a map in read-only mode.
+	PermissionRead = "read"
+	// PermissionReadWrite is used to share a map in read-write mode.
+	PermissionReadWrite = "read-write"
+)
+
+// Permissions associate the supported permissions to the values used by the Zabbix API.
+var Permissions = map[string]string{
+	PermissionRead:      "2",
+	PermissionReadWrite: "3",
+}
+
+// Sharing define the owner of a map, its type (private or public) and the users and user groups the map is shared with.
+type Sharing struct {
+	// Private is used to set the type of the map, the default type of the server (private) is used if not set.
+	Private *bool `yaml:"private,omitempty" json:"private,omitempty"`
+	// Owner is the username of the owner of the map, the API user is used if empty.
+	Owner      string   `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Users      []*Share `yaml:"users,omitempty" json:"users,omitempty"`
+	UserGroups []*Share `yaml:"user_groups,omitempty" json:"user_groups,omitempty"`
+}
+
+// Share define an user or an user group the map is shared with.
+type Share struct {
+	Name string `yaml:"name" json:"name"`
+	// Permission is the permission given on the map (read or read-write).
+	Permission string `yaml:"permission" json:"permission"`
+}
+
+// ParseShare is used to read an user or an user group written as 'name:permission'.
+func ParseShare(value string) (*Share, error) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid share '%s', the value must be written as 'name:permission'", value)
+	}
+
+	s := &Share{
+		Name:       strings.TrimSpace(value[:i]),
+		Permission: strings.TrimSpace(value[i+1:]),
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// LoadSharing is used to read the sharing options from the given file (YAML or JSON).
+func LoadSharing(file string) (*Sharing, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sharing{}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(s); err != nil {
+		return nil, fmt.Errorf("error while reading the sharing file '%s'.\nReason : %v", file, err)
+	}
+
+	if err = s.Validate(); err != nil {
+		return nil, fmt.Errorf("error while reading the sharing file '%s'.\nReason : %v", file, err)
+	}
+
+	return s, nil
+}
+
+// validate is used to validate the name and the permission of the share.
+func (s *Share) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("a name is required for each user and user group the map is shared with")
+	}
+
+	if _, exist := Permissions[s.Permission]; !exist {
+		return fmt.Errorf("unsupported permission '%s' for '%s', supported permissions are [%s %s]", s.Permission, s.Name, PermissionRead, PermissionReadWrite)
+	}
+
+	return nil
+}
+
+// Validate is used to validate the users and the user groups the map is shared with.
+func (s *Sharing) Validate() error {
+	for _, list := range [][]*Share{s.Users, s.UserGroups} {
+		for _, share := range list {
+			if err := share.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsEmpty is used to check if no sharing option is set.
+func (s *Sharing) IsEmpty() bool {
+	return s == nil || (s.Private == nil && s.Owner == "" && len(s.Users) == 0 && len(s.UserGroups) == 0)
+}
+
+// shareNames is used to retrieve the names of the given shares.
+func shareNames(shares []*Share) []string {
+	out := make([]string, 0)
+	for _, s := range shares {
+		out = append(out, s.Name)
+	}
+
+	return out
+}
+
+// resolveUsers is used to associate the given usernames to their id, an error is returned if an user is not found.
+func resolveUsers(client api.ZabbixAPI, names []string) (map[string]string, error) {
+	out := make(map[string]string, 0)
+	if len(names) == 0 {
+		return out, nil
+	}
+
+	users, err := client.GetUsers(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users {
+		out[u.Username] = u.Id
+	}
+
+	for _, name := range names {
+		if out[name] == "" {
+			return nil, failure.Errorf(failure.NotFound, "no user was found with the username '%s'", name)
+		}
+	}
+
+	return out, nil
+}
+
+// resolveUserGroups is used to associate the given user group names to their id, an error is returned if a group is not found.
+func resolveUserGroups(client api.ZabbixAPI, names []string) (map[string]string, error) {
+	out := make(map[string]string, 0)
+	if len(names) == 0 {
+		return out, nil
+	}
+
+	groups, err := client.GetUserGroups(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range groups {
+		out[g.Name] = g.Id
+	}
+
+	for _, name := range names {
+		if out[name] == "" {
+			return nil, failure.Errorf(failure.NotFound, "no user group was found with the name '%s'", name)
+		}
+	}
+
+	return out, nil
+}
+
+// ApplySharing is used to set the owner, the type and the users and user groups of the given map.
+// The usernames and the user group names are resolved to their id using the given client.
+func ApplySharing(client api.ZabbixAPI, m *zabbixgosdk.MapCreateParameters, sharing *Sharing) error {
+	if sharing.IsEmpty() {
+		return nil
+	}
+
+	usernames := shareNames(sharing.Users)
+	if sharing.Owner != "" {
+		usernames = append(usernames, sharing.Owner)
+	}
+
+	users, err := resolveUsers(client, usernames)
+	if err != nil {
+		return err
+	}
+
+	groups, err := resolveUserGroups(client, shareNames(sharing.UserGroups))
+	if err != nil {
+		return err
+	}
+
+	if sharing.Private != nil {
+		m.Private = "0"
+		if *sharing.Private {
+			m.Private = "1"
+		}
+	}
+
+	if sharing.Owner != "" {
+		m.UserId = users[sharing.Owner]
+	}
+
+	for _, u := range sharing.Users {
+		m.Users = append(m.Users, &zabbixgosdk.MapUser{
+			UserId:     users[u.Name],
+			Permission: Permissions[u.Permission],
+		})
+	}
+
+	for _, g := range sharing.UserGroups {
+		m.UserGroups = append(m.UserGroups, &zabbixgosdk.MapUserGroup{
+			UserGroupId: groups[g.Name],
+			Permission:  Permissions[g.Permission],
+		})
+	}
+
+	return nil
+}
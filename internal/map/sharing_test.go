@@ -0,0 +1,110 @@
+package _map
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+func TestParseShare(t *testing.T) {
+	s, err := ParseShare("Network team : read-write")
+	if err != nil {
+		t.Fatalf("error while executing ParseShare function.\nReason : %v", err)
+	}
+
+	if s.Name != "Network team" || s.Permission != PermissionReadWrite {
+		t.Fatalf("wrong share returned.\nReturned : %+v", s)
+	}
+
+	for _, value := range []string{"", "noc", ":read", "noc:write"} {
+		if _, err = ParseShare(value); err == nil {
+			t.Fatalf("an error should be returned for the value '%s'", value)
+		}
+	}
+}
+
+func TestLoadSharing(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sharing.yaml")
+	content := "private: false\nowner: noc\nusers:\n  - name: Admin\n    permission: read-write\nuser_groups:\n  - name: Network team\n    permission: read\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+	}
+
+	s, err := LoadSharing(file)
+	if err != nil {
+		t.Fatalf("error while executing LoadSharing function.\nReason : %v", err)
+	}
+
+	if s.Private == nil || *s.Private || s.Owner != "noc" || len(s.Users) != 1 || s.UserGroups[0].Name != "Network team" {
+		t.Fatalf("wrong sharing options returned.\nReturned : %+v", s)
+	}
+}
+
+func TestLoadSharingFail(t *testing.T) {
+	for _, content := range []string{"public: true\n", "users:\n  - name: Admin\n    permission: write\n"} {
+		file := filepath.Join(t.TempDir(), "sharing.yaml")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("error while writing the file '%s'.\nReason : %v", file, err)
+		}
+
+		if _, err := LoadSharing(file); err == nil {
+			t.Fatalf("an error should be returned for the content '%s'", content)
+		}
+	}
+}
+
+func TestApplySharing(t *testing.T) {
+	client := newFakeClient().
+		AddUser("1", "Admin").
+		AddUser("3", "noc").
+		AddUserGroup("15", "Network team")
+
+	private := true
+	m := &zabbixgosdk.MapCreateParameters{}
+	err := ApplySharing(client, m, &Sharing{
+		Private:    &private,
+		Owner:      "noc",
+		Users:      []*Share{{Name: "Admin", Permission: PermissionRead}},
+		UserGroups: []*Share{{Name: "Network team", Permission: PermissionReadWrite}},
+	})
+
+	if err != nil {
+		t.Fatalf("error while executing ApplySharing function.\nReason : %v", err)
+	}
+
+	if m.Private != "1" || m.UserId != "3" {
+		t.Fatalf("wrong type or owner set.\nExpected : 1 - 3\nReturned : %s - %s", m.Private, m.UserId)
+	}
+
+	if len(m.Users) != 1 || m.Users[0].UserId != "1" || m.Users[0].Permission != "2" {
+		t.Fatalf("wrong users set.\nReturned : %+v", m.Users)
+	}
+
+	if len(m.UserGroups) != 1 || m.UserGroups[0].UserGroupId != "15" || m.UserGroups[0].Permission != "3" {
+		t.Fatalf("wrong user groups set.\nReturned : %+v", m.UserGroups)
+	}
+
+	// Nothing is set when no sharing option is used
+	m = &zabbixgosdk.MapCreateParameters{}
+	if err = ApplySharing(client, m, &Sharing{}); err != nil || m.Private != "" || m.Users != nil {
+		t.Fatalf("no sharing option should be set.\nReturned : %+v", m.Map)
+	}
+}
+
+func TestApplySharingFail(t *testing.T) {
+	client := newFakeClient().AddUser("1", "Admin")
+
+	tests := []*Sharing{
+		{Owner: "unknown"},
+		{Users: []*Share{{Name: "unknown", Permission: PermissionRead}}},
+		{UserGroups: []*Share{{Name: "unknown", Permission: PermissionRead}}},
+	}
+
+	for _, s := range tests {
+		if err := ApplySharing(client, &zabbixgosdk.MapCreateParameters{}, s); err == nil {
+			t.Fatalf("an error should be returned for the sharing options %+v", s)
+		}
+	}
+}
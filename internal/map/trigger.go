@@ -1,31 +1,24 @@
 package _map
 
 import (
-	"fmt"
-
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
 )
 
 // getTriggerId is used to retrive the triggerId for a given host with a specific pattern (used to filtrer the description field).
-func getTriggerId(client *zabbixgosdk.ZabbixService, hostId string, pattern string) (string, error) {
-	t, err := client.Trigger.Get(&zabbixgosdk.TriggerGetParameters{
-		Output: []string{
-			"triggerid",
-		},
-		HostIds: []string{
-			hostId,
-		},
-		Filter: map[string]string{
-			"description": pattern,
-		},
-	})
-
+func getTriggerId(client api.ZabbixAPI, hostId string, pattern string) (string, error) {
+	t, err := client.GetTriggers(hostId, pattern)
 	if err != nil {
 		return "", err
 	}
 
+	if len(t) == 0 {
+		return "", failure.Errorf(failure.NotFound, "no trigger was found for the host '%s' with the given pattern '%s'", hostId, pattern)
+	}
+
 	if len(t) > 1 {
-		return "", fmt.Errorf("more than one trigger was found for the host '%s' with the given pattern '%s'", hostId, pattern)
+		return "", failure.Errorf(failure.Validation, "more than one trigger was found for the host '%s' with the given pattern '%s'", hostId, pattern)
 	}
 
 	return t[0].Id, nil
@@ -42,21 +35,22 @@ type linkParameters struct {
 }
 
 // addLink is used to a link between a remote and local hosts for a given mapping.
+// Elements without trigger (host groups, maps and images) do not add a trigger to the link.
 func addLink(zbxMap *zabbixgosdk.MapCreateParameters, p *linkParameters) *zabbixgosdk.MapCreateParameters {
 	link := zabbixgosdk.MapLink{
-		SelementId1: p.localElement,
-		SelementId2: p.remoteElement,
-		Color:       p.linkColor,
-		LinkTriggers: []*zabbixgosdk.MapLinkTrigger{
-			{
-				TriggerId: p.localTrigger,
-				Color:     p.triggerLinkColor,
-			},
-			{
-				TriggerId: p.remoteTrigger,
+		SelementId1:  p.localElement,
+		SelementId2:  p.remoteElement,
+		Color:        p.linkColor,
+		LinkTriggers: make([]*zabbixgosdk.MapLinkTrigger, 0),
+	}
+
+	for _, triggerId := range []string{p.localTrigger, p.remoteTrigger} {
+		if triggerId != "" {
+			link.LinkTriggers = append(link.LinkTriggers, &zabbixgosdk.MapLinkTrigger{
+				TriggerId: triggerId,
 				Color:     p.triggerLinkColor,
-			},
-		},
+			})
+		}
 	}
 
 	zbxMap.Links = append(zbxMap.Links, &link)
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
 )
 
 func TestGetTriggerId(t *testing.T) {
@@ -35,7 +37,7 @@ func TestGetTriggerId(t *testing.T) {
 		t.Fatal("an empty list of hosts was returned")
 	}
 
-	triggerId, err := getTriggerId(client, h[0].HostId, "High CPU utilization")
+	triggerId, err := getTriggerId(api.NewClient(client), h[0].HostId, "High CPU utilization")
 	if err != nil {
 		t.Fatalf("error when executing getTriggerId function.\nReason : %v", err)
 	}
@@ -92,3 +94,34 @@ func TestAddLink(t *testing.T) {
 		t.Fatalf("wrong trigger color (2) set.\nExpected : 'DD0000'\nReturned : %s", link.LinkTriggers[1].Color)
 	}
 }
+
+func TestGetTriggerIdFake(t *testing.T) {
+	triggerId, err := getTriggerId(newFakeClient(), "1", "Interface eth0(): Link down")
+	if err != nil {
+		t.Fatalf("error when executing getTriggerId function.\nReason : %v", err)
+	}
+
+	if triggerId != "21" {
+		t.Fatalf("wrong trigger id returned.\nExpected : '21'\nReturned : %s", triggerId)
+	}
+}
+
+func TestGetTriggerIdNotFound(t *testing.T) {
+	_, err := getTriggerId(newFakeClient(), "1", "pattern-does-not-exist")
+	if err == nil {
+		t.Fatal("an error should be returned when no trigger matches the given pattern")
+	}
+}
+
+func TestGetTriggerIdMultiple(t *testing.T) {
+	client := newFakeClient().AddTrigger("1", "23", "Interface eth0(): Link down")
+
+	_, err := getTriggerId(client, "1", "Interface eth0(): Link down")
+	if err == nil {
+		t.Fatal("an error should be returned when multiple triggers match the given pattern")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+}
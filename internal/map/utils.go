@@ -1,6 +1,7 @@
 package _map
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -50,3 +51,57 @@ func convertPositionToInt64(x string, y string) (int64, int64, error) {
 
 	return xToInt, yToInt, nil
 }
+
+// GetElementHostId is used to retrieve the id of the host referenced by a map element.
+// Elements built by this package and elements decoded from a JSON document (map.get, output file) are supported.
+// An empty string is returned if the element does not reference an host.
+func GetElementHostId(element *zabbixgosdk.MapElement) string {
+	switch elements := element.Elements.(type) {
+	case []zabbixgosdk.MapElementHost:
+		if len(elements) > 0 {
+			return elements[0].Id
+		}
+	case []interface{}:
+		if len(elements) > 0 {
+			if e, ok := elements[0].(map[string]interface{}); ok {
+				if id, ok := e["hostid"].(string); ok {
+					return id
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// GetElementObjectId is used to retrieve the id of the object (host, host group, trigger or map) referenced by a map element.
+// Elements built by this package and elements decoded from a JSON document (map.get, output file) are supported.
+// An empty string is returned if the element does not reference an object (image element).
+func GetElementObjectId(element *zabbixgosdk.MapElement) string {
+	keys := map[zabbixgosdk.MapElementType]string{
+		zabbixgosdk.MapHost:      "hostid",
+		zabbixgosdk.MapHostGroup: "groupid",
+		zabbixgosdk.MapTrigger:   "triggerid",
+		zabbixgosdk.MapMap:       "sysmapid",
+	}
+
+	key, exist := keys[element.ElementType]
+	if !exist || element.Elements == nil {
+		return ""
+	}
+
+	// Use a JSON round trip to support both the typed and the decoded elements
+	b, err := json.Marshal(element.Elements)
+	if err != nil {
+		return ""
+	}
+
+	elements := make([]map[string]interface{}, 0)
+	if err = json.Unmarshal(b, &elements); err != nil || len(elements) == 0 {
+		return ""
+	}
+
+	id, _ := elements[0][key].(string)
+
+	return id
+}
@@ -56,3 +56,52 @@ func TestValidateHexaFail(t *testing.T) {
 	}
 
 }
+
+func TestGetElementHostId(t *testing.T) {
+	id := GetElementHostId(createHostElement("1", "10", "11", "0", "0"))
+	if id != "10" {
+		t.Fatalf("wrong host id returned.\nExpected : '10'\nReturned : %s", id)
+	}
+
+	id = GetElementHostId(&zabbixgosdk.MapElement{
+		Elements: []interface{}{
+			map[string]interface{}{
+				"hostid": "12",
+			},
+		},
+	})
+	if id != "12" {
+		t.Fatalf("wrong host id returned for a decoded element.\nExpected : '12'\nReturned : %s", id)
+	}
+
+	id = GetElementHostId(&zabbixgosdk.MapElement{})
+	if id != "" {
+		t.Fatalf("an empty string should be returned when the element does not reference an host.\nReturned : %s", id)
+	}
+}
+
+func TestGetElementObjectId(t *testing.T) {
+	for _, elementType := range []string{ElementHost, ElementHostGroup, ElementTrigger, ElementMap} {
+		id := GetElementObjectId(createElement("1", elementType, "10", "11", "0", "0"))
+		if id != "10" {
+			t.Fatalf("wrong object id returned for the type '%s'.\nExpected : '10'\nReturned : %s", elementType, id)
+		}
+	}
+
+	id := GetElementObjectId(&zabbixgosdk.MapElement{
+		ElementType: zabbixgosdk.MapHostGroup,
+		Elements: []interface{}{
+			map[string]interface{}{
+				"groupid": "12",
+			},
+		},
+	})
+	if id != "12" {
+		t.Fatalf("wrong object id returned for a decoded element.\nExpected : '12'\nReturned : %s", id)
+	}
+
+	id = GetElementObjectId(createElement("1", ElementImage, "", "11", "0", "0"))
+	if id != "" {
+		t.Fatalf("an empty string should be returned for an image element.\nReturned : %s", id)
+	}
+}
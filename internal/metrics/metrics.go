@@ -0,0 +1,345 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// namespace is the prefix of the name of each metric.
+const namespace = "zabbix_map_builder_"
+
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+)
+
+// Outcome of an API call or a build, used as label value.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+var (
+	apiBuckets   = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	buildBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+)
+
+// Registry define the metrics collected while building the maps, written using the Prometheus text format.
+// The methods of a nil Registry do nothing, allowing the metrics to be disabled.
+type Registry struct {
+	mutex    sync.Mutex
+	families []*family
+
+	apiCalls      *family
+	apiDuration   *family
+	builds        *family
+	buildDuration *family
+	hosts         *family
+	unresolved    *family
+	links         *family
+	triggers      *family
+	skipped       *family
+	lastSuccess   *family
+}
+
+// family define a metric and its series, one series is created for each combination of label values.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series define the value of a metric for a combination of label values.
+// The buckets of an histogram are not cumulative, they are summed when writing the metric.
+type series struct {
+	values  []string
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// MapStats define the elements of a built map, recorded after each build.
+type MapStats struct {
+	// Links is the number of links drawn, SkippedLinks the number of links skipped because one of their hosts was not found.
+	Links        int
+	SkippedLinks int
+	// Triggers is the number of distinct triggers attached to the links.
+	Triggers int
+}
+
+// NewRegistry is used to create a new Registry holding the metrics of the application.
+func NewRegistry() *Registry {
+	r := &Registry{}
+
+	r.apiCalls = r.register("api_calls_total", "Number of calls to the Zabbix API by method and outcome.", counterType, nil, "method", "outcome")
+	r.apiDuration = r.register("api_call_duration_seconds", "Duration of the calls to the Zabbix API by method.", histogramType, apiBuckets, "method")
+	r.builds = r.register("builds_total", "Number of map builds by map and outcome.", counterType, nil, "map", "outcome")
+	r.buildDuration = r.register("build_duration_seconds", "Duration of the map builds (hosts resolution and map construction).", histogramType, buildBuckets, "map")
+	r.hosts = r.register("resolved_hosts", "Number of hosts resolved during the last build of the map.", gaugeType, nil, "map")
+	r.unresolved = r.register("unresolved_hosts", "Number of hosts not found on the server during the last build of the map.", gaugeType, nil, "map")
+	r.links = r.register("links", "Number of links drawn during the last build of the map.", gaugeType, nil, "map")
+	r.triggers = r.register("triggers", "Number of triggers attached to the links during the last build of the map.", gaugeType, nil, "map")
+	r.skipped = r.register("skipped_links", "Number of links skipped during the last build of the map, one of their hosts was not found.", gaugeType, nil, "map")
+	r.lastSuccess = r.register("last_success_timestamp_seconds", "Time of the last successful sync of the map with the server.", gaugeType, nil, "map")
+
+	return r
+}
+
+// register is used to add a new metric to the registry.
+func (r *Registry) register(name string, help string, kind string, buckets []float64, labels ...string) *family {
+	f := &family{
+		name:    namespace + name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series, 0),
+	}
+
+	r.families = append(r.families, f)
+	return f
+}
+
+// get is used to retrieve the series of the given label values, the series is created if it does not exist.
+// The registry must be locked by the caller.
+func (f *family) get(values ...string) *series {
+	key := strings.Join(values, "\xff")
+
+	s, exist := f.series[key]
+	if !exist {
+		s = &series{
+			values:  values,
+			buckets: make([]uint64, len(f.buckets)),
+		}
+		f.series[key] = s
+	}
+
+	return s
+}
+
+// observe is used to add the given value to the histogram series.
+func (s *series) observe(buckets []float64, value float64) {
+	for i, upper := range buckets {
+		if value <= upper {
+			s.buckets[i]++
+			break
+		}
+	}
+
+	s.sum += value
+	s.count++
+}
+
+// outcome is used to retrieve the outcome label value matching the given error.
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+
+	return OutcomeSuccess
+}
+
+// ObserveAPICall is used to record a call to the given Zabbix API method (ex: 'host.get').
+func (r *Registry) ObserveAPICall(method string, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.apiCalls.get(method, outcome(err)).value++
+	r.apiDuration.get(method).observe(r.apiDuration.buckets, duration.Seconds())
+}
+
+// ObserveBuild is used to record a build of the given map.
+func (r *Registry) ObserveBuild(name string, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.builds.get(name, outcome(err)).value++
+	r.buildDuration.get(name).observe(r.buildDuration.buckets, duration.Seconds())
+}
+
+// SetHosts is used to record the number of hosts resolved and not found during the last build of the given map.
+func (r *Registry) SetHosts(name string, resolved int, unresolved int) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.hosts.get(name).value = float64(resolved)
+	r.unresolved.get(name).value = float64(unresolved)
+}
+
+// SetMapStats is used to record the elements of the last build of the given map.
+func (r *Registry) SetMapStats(name string, stats *MapStats) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.links.get(name).value = float64(stats.Links)
+	r.skipped.get(name).value = float64(stats.SkippedLinks)
+	r.triggers.get(name).value = float64(stats.Triggers)
+}
+
+// SetLastSuccess is used to record the time of the last successful sync of the given map with the server.
+func (r *Registry) SetLastSuccess(name string, at time.Time) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastSuccess.get(name).value = float64(at.UnixNano()) / 1e9
+}
+
+// formatFloat is used to format a value using the Prometheus text format.
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// escape is used to escape a label value.
+var escape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels is used to format the given labels as '{name="value",...}', an empty string is returned if no label is given.
+func formatLabels(names []string, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0)
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escape.Replace(values[i])))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// write is used to write the metric and its series, sorted by label values.
+func (f *family) write(w io.Writer) {
+	if len(f.series) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, f.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+
+	keys := make([]string, 0)
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != histogramType {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(f.labels, s.values), formatFloat(s.value))
+			continue
+		}
+
+		names := append(append([]string{}, f.labels...), "le")
+		var cumulative uint64
+		for i, upper := range f.buckets {
+			cumulative += s.buckets[i]
+			values := append(append([]string{}, s.values...), formatFloat(upper))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(names, values), cumulative)
+		}
+
+		values := append(append([]string{}, s.values...), "+Inf")
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(names, values), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, formatLabels(f.labels, s.values), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, formatLabels(f.labels, s.values), s.count)
+	}
+}
+
+// Write is used to write the metrics using the Prometheus text format.
+// The metrics without any series are omitted.
+func (r *Registry) Write(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	buf := bufio.NewWriter(w)
+	for _, f := range r.families {
+		f.write(buf)
+	}
+
+	return buf.Flush()
+}
+
+// WriteFile is used to write the metrics to the given file, to be read by the textfile collector of the node exporter.
+// The file is replaced atomically to prevent the collector from reading a partial file.
+func (r *Registry) WriteFile(file string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err = r.Write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+// Handler is used to retrieve the HTTP handler exposing the metrics.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestingMetrics is used to retrieve the metrics of the given registry using the Prometheus text format.
+func writeTestingMetrics(t *testing.T, r *Registry) string {
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatalf("error while executing Write function.\nReason : %v", err)
+	}
+
+	return buf.String()
+}
+
+// checkLines is used to check that each of the given lines is part of the output.
+func checkLines(t *testing.T, out string, lines ...string) {
+	for _, line := range lines {
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("missing line in the metrics.\nExpected : %s\nReturned :\n%s", line, out)
+		}
+	}
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+
+	r.ObserveAPICall("host.get", time.Second, nil)
+	r.ObserveBuild("map", time.Second, nil)
+	r.SetHosts("map", 1, 0)
+	r.SetMapStats("map", &MapStats{})
+	r.SetLastSuccess("map", time.Now())
+
+	if out := writeTestingMetrics(t, r); out != "" {
+		t.Fatalf("no metric should be written by a nil registry.\nReturned : %s", out)
+	}
+}
+
+func TestEmptyRegistry(t *testing.T) {
+	if out := writeTestingMetrics(t, NewRegistry()); out != "" {
+		t.Fatalf("the metrics without series should be omitted.\nReturned : %s", out)
+	}
+}
+
+func TestObserveAPICall(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveAPICall("host.get", 20*time.Millisecond, nil)
+	r.ObserveAPICall("host.get", 3*time.Second, nil)
+	r.ObserveAPICall("host.get", 20*time.Millisecond, fmt.Errorf("connection refused"))
+
+	checkLines(t, writeTestingMetrics(t, r),
+		"# TYPE zabbix_map_builder_api_calls_total counter",
+		`zabbix_map_builder_api_calls_total{method="host.get",outcome="error"} 1`,
+		`zabbix_map_builder_api_calls_total{method="host.get",outcome="success"} 2`,
+		"# TYPE zabbix_map_builder_api_call_duration_seconds histogram",
+		`zabbix_map_builder_api_call_duration_seconds_bucket{method="host.get",le="0.01"} 0`,
+		`zabbix_map_builder_api_call_duration_seconds_bucket{method="host.get",le="0.025"} 2`,
+		`zabbix_map_builder_api_call_duration_seconds_bucket{method="host.get",le="2.5"} 2`,
+		`zabbix_map_builder_api_call_duration_seconds_bucket{method="host.get",le="5"} 3`,
+		`zabbix_map_builder_api_call_duration_seconds_bucket{method="host.get",le="+Inf"} 3`,
+		`zabbix_map_builder_api_call_duration_seconds_sum{method="host.get"} 3.04`,
+		`zabbix_map_builder_api_call_duration_seconds_count{method="host.get"} 3`,
+	)
+}
+
+func TestMapMetrics(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveBuild("dc1", time.Second, nil)
+	r.SetHosts("dc1", 3, 1)
+	r.SetMapStats("dc1", &MapStats{Links: 2, SkippedLinks: 1, Triggers: 4})
+	r.SetLastSuccess("dc1", time.Unix(1714644900, 0))
+
+	checkLines(t, writeTestingMetrics(t, r),
+		`zabbix_map_builder_builds_total{map="dc1",outcome="success"} 1`,
+		`zabbix_map_builder_build_duration_seconds_count{map="dc1"} 1`,
+		`zabbix_map_builder_resolved_hosts{map="dc1"} 3`,
+		`zabbix_map_builder_unresolved_hosts{map="dc1"} 1`,
+		`zabbix_map_builder_links{map="dc1"} 2`,
+		`zabbix_map_builder_skipped_links{map="dc1"} 1`,
+		`zabbix_map_builder_triggers{map="dc1"} 4`,
+		`zabbix_map_builder_last_success_timestamp_seconds{map="dc1"} 1.7146449e+09`,
+	)
+}
+
+func TestFormatLabels(t *testing.T) {
+	out := formatLabels([]string{"map"}, []string{"a \"b\"\\c\nd"})
+	expected := `{map="a \"b\"\\c\nd"}`
+
+	if out != expected {
+		t.Fatalf("wrong labels returned.\nExpected : %s\nReturned : %s", expected, out)
+	}
+
+	if out = formatLabels(nil, nil); out != "" {
+		t.Fatalf("an empty string should be returned without labels.\nReturned : %s", out)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveBuild("dc1", time.Second, nil)
+
+	file := filepath.Join(t.TempDir(), "zabbix_map_builder.prom")
+	if err := r.WriteFile(file); err != nil {
+		t.Fatalf("error while executing WriteFile function.\nReason : %v", err)
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("error while reading the file '%s'.\nReason : %v", file, err)
+	}
+
+	checkLines(t, string(b), `zabbix_map_builder_builds_total{map="dc1",outcome="success"} 1`)
+
+	// No temporary file should be left in the directory
+	entries, err := os.ReadDir(filepath.Dir(file))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("a single file should be written.\nReturned : %v", entries)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveBuild("dc1", time.Second, fmt.Errorf("no host found"))
+
+	res := httptest.NewRecorder()
+	r.Handler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if res.Code != http.StatusOK || !strings.HasPrefix(res.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("wrong response returned.\nReturned : %d %s", res.Code, res.Header().Get("Content-Type"))
+	}
+
+	checkLines(t, res.Body.String(), `zabbix_map_builder_builds_total{map="dc1",outcome="error"} 1`)
+
+	res = httptest.NewRecorder()
+	r.Handler().ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if res.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("wrong status code returned.\nExpected : %d\nReturned : %d", http.StatusMethodNotAllowed, res.Code)
+	}
+}
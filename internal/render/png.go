@@ -0,0 +1,131 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// parseColor is used to convert an hexadecimal color (without '#') to a color.RGBA.
+// Black is returned if the value cannot be parsed.
+func parseColor(h string) color.RGBA {
+	value, err := strconv.ParseUint(h, 16, 32)
+	if err != nil || len(h) != 6 {
+		return color.RGBA{A: 0xFF}
+	}
+
+	return color.RGBA{
+		R: uint8(value >> 16),
+		G: uint8(value >> 8),
+		B: uint8(value),
+		A: 0xFF,
+	}
+}
+
+// drawLine is used to draw a line between two points using Bresenham's algorithm.
+// The line is drawn with a width of 2 pixels.
+func drawLine(img *image.RGBA, x1 int, y1 int, x2 int, y2 int, c color.RGBA) {
+	dx := abs(x2 - x1)
+	dy := -abs(y2 - y1)
+	sx, sy := 1, 1
+	if x1 > x2 {
+		sx = -1
+	}
+	if y1 > y2 {
+		sy = -1
+	}
+
+	e := dx + dy
+	for {
+		img.Set(x1, y1, c)
+		img.Set(x1+1, y1, c)
+		img.Set(x1, y1+1, c)
+
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x1 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y1 += sy
+		}
+	}
+}
+
+// drawText is used to draw a text centered on the given x position.
+func drawText(img *image.RGBA, x int, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.Black),
+		Face: basicfont.Face7x13,
+	}
+
+	width := d.MeasureString(text).Round()
+	d.Dot = fixed.P(x-width/2, y)
+	d.DrawString(text)
+}
+
+// abs is used to return the absolute value of an int.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// PNG is used to draw the given map to a PNG image.
+func PNG(m *zabbixgosdk.MapCreateParameters, options *Options) ([]byte, error) {
+	l, err := newLayout(m, options)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	// Draw the links first to keep the elements on top
+	for _, lk := range l.links {
+		x1, y1 := lk.from.center()
+		x2, y2 := lk.to.center()
+		drawLine(img, x1, y1, x2, y2, parseColor(lk.color))
+
+		if lk.label != "" {
+			drawText(img, (x1+x2)/2, (y1+y2)/2-4, lk.label)
+		}
+	}
+
+	for _, e := range l.elements {
+		rect := image.Rect(e.x, e.y, e.x+e.width, e.y+e.height)
+
+		if e.icon != nil {
+			draw.Draw(img, rect, e.icon.image, e.icon.image.Bounds().Min, draw.Over)
+		} else {
+			draw.Draw(img, rect, image.NewUniform(color.RGBA{R: 0xDD, G: 0xDD, B: 0xDD, A: 0xFF}), image.Point{}, draw.Src)
+		}
+
+		if e.label != "" {
+			drawText(img, e.x+e.width/2, e.y+e.height+13, e.label)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
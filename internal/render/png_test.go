@@ -0,0 +1,38 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestPNG(t *testing.T) {
+	b, err := PNG(newTestingMap(), &Options{
+		Icons: map[string]string{
+			"3": newTestingIcon(t, 64),
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("error while executing PNG function.\nReason : %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("error while decoding the PNG image.\nReason : %v", err)
+	}
+
+	if img.Bounds().Dx() != 400 || img.Bounds().Dy() != 300 {
+		t.Fatalf("wrong image size returned.\nExpected : 400x300\nReturned : %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	c := parseColor("7AC2E1")
+	expected := color.RGBA{R: 0x7A, G: 0xC2, B: 0xE1, A: 0xFF}
+
+	if c != expected {
+		t.Fatalf("wrong color returned.\nExpected : %v\nReturned : %v", expected, c)
+	}
+}
@@ -0,0 +1,208 @@
+package render
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	// Register the decoders for the formats supported by Zabbix images
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+const (
+	// defaultIconSize is the size in pixel used for elements without a known icon.
+	defaultIconSize = 32
+	// defaultLinkColor is the color used for links without a color.
+	defaultLinkColor = "000000"
+)
+
+// Options define the resources used to render a map.
+type Options struct {
+	// Icons associate an imageid to the base64 encoded content of the image.
+	Icons map[string]string
+	// Hosts associate an hostid to the name of the host.
+	Hosts map[string]string
+}
+
+// icon define a decoded image used to draw an element.
+type icon struct {
+	data  []byte
+	image image.Image
+}
+
+// element define the computed properties of a map element.
+type element struct {
+	x      int
+	y      int
+	width  int
+	height int
+	label  string
+	icon   *icon
+}
+
+// center is used to retrieve the center of the element.
+func (e *element) center() (int, int) {
+	return e.x + e.width/2, e.y + e.height/2
+}
+
+// link define the computed properties of a map link.
+type link struct {
+	from  *element
+	to    *element
+	color string
+	label string
+}
+
+// layout define the computed properties of a map, shared by every output format.
+type layout struct {
+	width    int
+	height   int
+	elements []*element
+	links    []*link
+}
+
+// decodeIcons is used to decode the base64 encoded icons.
+// Icons that cannot be decoded are ignored, the elements using them are drawn with the default shape.
+func decodeIcons(icons map[string]string) map[string]*icon {
+	out := make(map[string]*icon, 0)
+
+	for id, data := range icons {
+		b, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			continue
+		}
+
+		img, _, err := image.Decode(strings.NewReader(string(b)))
+		if err != nil {
+			continue
+		}
+
+		out[id] = &icon{
+			data:  b,
+			image: img,
+		}
+	}
+
+	return out
+}
+
+// elementLabel is used to compute the label of an element.
+// Host macros are replaced by the name of the host, the name of the host is used if the element has no label.
+func elementLabel(e *zabbixgosdk.MapElement, hosts map[string]string) string {
+	name := hosts[zbxmap.GetElementHostId(e)]
+
+	if e.Label == "" {
+		return name
+	}
+
+	if name == "" {
+		return e.Label
+	}
+
+	r := strings.NewReplacer("{HOST.NAME}", name, "{HOST.HOST}", name)
+
+	return r.Replace(e.Label)
+}
+
+// parseInt is used to convert a string coordinate to an int.
+func parseInt(value string, field string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' for the field '%s'.\nReason : %v", value, field, err)
+	}
+
+	return i, nil
+}
+
+// newLayout is used to compute the position, size and label of each element and link of the given map.
+func newLayout(m *zabbixgosdk.MapCreateParameters, options *Options) (*layout, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	width, err := parseInt(m.Width, "width")
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := parseInt(m.Height, "height")
+	if err != nil {
+		return nil, err
+	}
+
+	l := &layout{
+		width:    width,
+		height:   height,
+		elements: make([]*element, 0),
+		links:    make([]*link, 0),
+	}
+
+	icons := decodeIcons(options.Icons)
+	elements := make(map[string]*element, 0)
+
+	for _, e := range m.Elements {
+		x, err := parseInt(e.X, "x")
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := parseInt(e.Y, "y")
+		if err != nil {
+			return nil, err
+		}
+
+		el := &element{
+			x:      x,
+			y:      y,
+			width:  defaultIconSize,
+			height: defaultIconSize,
+			label:  elementLabel(e, options.Hosts),
+		}
+
+		if i, exist := icons[e.IconIdOff]; exist {
+			el.icon = i
+			el.width = i.image.Bounds().Dx()
+			el.height = i.image.Bounds().Dy()
+		}
+
+		elements[e.Id] = el
+		l.elements = append(l.elements, el)
+	}
+
+	for _, lk := range m.Links {
+		from, exist := elements[lk.SelementId1]
+		if !exist {
+			return nil, fmt.Errorf("unknown element '%s' referenced by a link", lk.SelementId1)
+		}
+
+		to, exist := elements[lk.SelementId2]
+		if !exist {
+			return nil, fmt.Errorf("unknown element '%s' referenced by a link", lk.SelementId2)
+		}
+
+		color := lk.Color
+		if color == "" {
+			color = defaultLinkColor
+		}
+
+		l.links = append(l.links, &link{
+			from:  from,
+			to:    to,
+			color: color,
+			label: lk.Label,
+		})
+	}
+
+	return l, nil
+}
@@ -0,0 +1,161 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+// newTestingIcon is used to generate a base64 encoded PNG image of the given size.
+func newTestingIcon(t *testing.T, size int) string {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for x := 0; x < size; x++ {
+		img.Set(x, x, color.RGBA{R: 255, A: 255})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("error while encoding the testing icon.\nReason : %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// newTestingMap is used to generate a map with two host elements linked together.
+func newTestingMap() *zabbixgosdk.MapCreateParameters {
+	m := &zabbixgosdk.MapCreateParameters{}
+	m.Name = "test-render"
+	m.Width = "400"
+	m.Height = "300"
+	m.Elements = []*zabbixgosdk.MapElement{
+		{
+			Id:          "1",
+			ElementType: zabbixgosdk.MapHost,
+			Elements: []zabbixgosdk.MapElementHost{
+				{Id: "10501"},
+			},
+			IconIdOff: "3",
+			X:         "50",
+			Y:         "50",
+		},
+		{
+			Id:          "2",
+			ElementType: zabbixgosdk.MapHost,
+			Elements: []zabbixgosdk.MapElementHost{
+				{Id: "10502"},
+			},
+			IconIdOff: "4",
+			Label:     "{HOST.NAME} <core>",
+			X:         "250",
+			Y:         "200",
+		},
+	}
+	m.Links = []*zabbixgosdk.MapLink{
+		{
+			SelementId1: "1",
+			SelementId2: "2",
+			Color:       "7AC2E1",
+			Label:       "eth0 - eth1",
+		},
+	}
+
+	return m
+}
+
+func TestNewLayout(t *testing.T) {
+	l, err := newLayout(newTestingMap(), &Options{
+		Icons: map[string]string{
+			"3": newTestingIcon(t, 64),
+		},
+		Hosts: map[string]string{
+			"10501": "router-1",
+			"10502": "router-2",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("error while executing newLayout function.\nReason : %v", err)
+	}
+
+	if l.width != 400 || l.height != 300 {
+		t.Fatalf("wrong map size returned.\nExpected : 400x300\nReturned : %dx%d", l.width, l.height)
+	}
+
+	if len(l.elements) != 2 {
+		t.Fatalf("wrong number of elements returned.\nExpected : 2\nReturned : %d", len(l.elements))
+	}
+
+	if l.elements[0].width != 64 || l.elements[0].icon == nil {
+		t.Fatalf("the size of the icon was not used for the first element.\nExpected : 64\nReturned : %d", l.elements[0].width)
+	}
+
+	if l.elements[1].width != defaultIconSize || l.elements[1].icon != nil {
+		t.Fatalf("the default size was not used for an element without icon.\nExpected : %d\nReturned : %d", defaultIconSize, l.elements[1].width)
+	}
+
+	if l.elements[0].label != "router-1" {
+		t.Fatalf("wrong label returned.\nExpected : router-1\nReturned : %s", l.elements[0].label)
+	}
+
+	if l.elements[1].label != "router-2 <core>" {
+		t.Fatalf("wrong label returned.\nExpected : router-2 <core>\nReturned : %s", l.elements[1].label)
+	}
+
+	if len(l.links) != 1 {
+		t.Fatalf("wrong number of links returned.\nExpected : 1\nReturned : %d", len(l.links))
+	}
+
+	if l.links[0].from != l.elements[0] || l.links[0].to != l.elements[1] {
+		t.Fatal("the link is not attached to the right elements")
+	}
+}
+
+func TestNewLayoutDefaultColor(t *testing.T) {
+	m := newTestingMap()
+	m.Links[0].Color = ""
+
+	l, err := newLayout(m, nil)
+	if err != nil {
+		t.Fatalf("error while executing newLayout function.\nReason : %v", err)
+	}
+
+	if l.links[0].color != defaultLinkColor {
+		t.Fatalf("wrong link color returned.\nExpected : %s\nReturned : %s", defaultLinkColor, l.links[0].color)
+	}
+}
+
+func TestNewLayoutUnknownElement(t *testing.T) {
+	m := newTestingMap()
+	m.Links[0].SelementId2 = "3"
+
+	_, err := newLayout(m, nil)
+	if err == nil {
+		t.Fatal("an error should be returned when a link references an unknown element")
+	}
+}
+
+func TestNewLayoutInvalidCoordinate(t *testing.T) {
+	m := newTestingMap()
+	m.Elements[0].X = "left"
+
+	_, err := newLayout(m, nil)
+	if err == nil {
+		t.Fatal("an error should be returned when a coordinate is not a number")
+	}
+}
+
+func TestDecodeIconsInvalid(t *testing.T) {
+	icons := decodeIcons(map[string]string{
+		"1": "not-base64",
+		"2": base64.StdEncoding.EncodeToString([]byte("not-an-image")),
+	})
+
+	if len(icons) != 0 {
+		t.Fatalf("invalid icons should be ignored.\nExpected : 0\nReturned : %d", len(icons))
+	}
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+)
+
+// escape is used to escape a string before writing it to the SVG document.
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
+
+// SVG is used to draw the given map to an SVG document.
+func SVG(m *zabbixgosdk.MapCreateParameters, options *Options) ([]byte, error) {
+	l, err := newLayout(m, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", l.width, l.height, l.width, l.height)
+	fmt.Fprintf(&buf, "  <title>%s</title>\n", escape(m.Name))
+	fmt.Fprintf(&buf, "  <rect width=\"%d\" height=\"%d\" fill=\"#FFFFFF\" stroke=\"#CCCCCC\"/>\n", l.width, l.height)
+
+	// Draw the links first to keep the elements on top
+	for _, lk := range l.links {
+		x1, y1 := lk.from.center()
+		x2, y2 := lk.to.center()
+		fmt.Fprintf(&buf, "  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#%s\" stroke-width=\"2\"/>\n", x1, y1, x2, y2, escape(lk.color))
+
+		if lk.label != "" {
+			fmt.Fprintf(&buf, "  <text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"10\" text-anchor=\"middle\">%s</text>\n", (x1+x2)/2, (y1+y2)/2-4, escape(lk.label))
+		}
+	}
+
+	for _, e := range l.elements {
+		if e.icon != nil {
+			mime := http.DetectContentType(e.icon.data)
+			fmt.Fprintf(&buf, "  <image x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" href=\"data:%s;base64,%s\"/>\n", e.x, e.y, e.width, e.height, mime, base64.StdEncoding.EncodeToString(e.icon.data))
+		} else {
+			fmt.Fprintf(&buf, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#DDDDDD\" stroke=\"#666666\"/>\n", e.x, e.y, e.width, e.height)
+		}
+
+		if e.label != "" {
+			fmt.Fprintf(&buf, "  <text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"11\" text-anchor=\"middle\">%s</text>\n", e.x+e.width/2, e.y+e.height+12, escape(e.label))
+		}
+	}
+
+	buf.WriteString("</svg>\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	b, err := SVG(newTestingMap(), &Options{
+		Icons: map[string]string{
+			"3": newTestingIcon(t, 64),
+		},
+		Hosts: map[string]string{
+			"10501": "router-1",
+			"10502": "router-2",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("error while executing SVG function.\nReason : %v", err)
+	}
+
+	// Make sure the document is valid XML
+	decoder := xml.NewDecoder(strings.NewReader(string(b)))
+	for {
+		_, err = decoder.Token()
+		if err != nil {
+			break
+		}
+	}
+
+	if err.Error() != "EOF" {
+		t.Fatalf("the SVG document is not a valid XML document.\nReason : %v", err)
+	}
+
+	out := string(b)
+	for _, expected := range []string{"#7AC2E1", "router-1", "router-2 &lt;core&gt;", "eth0 - eth1", "data:image/png;base64,"} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("the SVG document does not contain the expected value.\nExpected : %s\nReturned : %s", expected, out)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	v := escape("<a & \"b\">")
+	if v != "&lt;a &amp; &#34;b&#34;&gt;" {
+		t.Fatalf("wrong escaped value returned.\nExpected : &lt;a &amp; &#34;b&#34;&gt;\nReturned : %s", v)
+	}
+}
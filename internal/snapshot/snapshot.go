@@ -0,0 +1,439 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/utils"
+)
+
+// Snapshot define a local copy of the Zabbix objects referenced by a list of mappings.
+// A Snapshot implements the api.ZabbixAPI interface and can be used to build a map without a Zabbix server.
+type Snapshot struct {
+	Date   string       `json:"date"`
+	Hosts  []*api.Host  `json:"hosts"`
+	Images []*api.Image `json:"images"`
+	// Interfaces contains the host interfaces used to resolve the hosts with the 'interface' strategy.
+	Interfaces []*api.HostInterface `json:"interfaces,omitempty"`
+	// Triggers associate an hostid to the list of triggers configured for the host.
+	Triggers map[string][]*api.Trigger `json:"triggers"`
+	// Items associate an hostid to the list of items configured for the host.
+	Items map[string][]*api.Item `json:"items"`
+	// HostGroups associate an hostid to the list of groups of the host.
+	HostGroups map[string][]*api.HostGroup `json:"host_groups"`
+	// Groups contains the host groups used as map elements.
+	Groups []*api.HostGroup `json:"groups,omitempty"`
+}
+
+// Create is used to retrieve the hosts, images, triggers and items referenced by the given mappings.
+// Hosts are resolved using the given options, the visible name, the tags and the matching interfaces of each host are stored
+// to be able to resolve them again from the snapshot.
+// Extra images (the images of the icon rules for example) can be added to the snapshot.
+// The host groups used as map elements are stored, maps used as map elements are not supported.
+func Create(client api.ZabbixAPI, mappings []*zbxmap.Mapping, options *api.ResolveOptions, extraImages ...string) (*Snapshot, error) {
+	hosts := make(map[string]string, 0)
+	images := make(map[string]string, 0)
+	groups := make(map[string]string, 0)
+
+	for _, image := range extraImages {
+		images[image] = ""
+	}
+
+	for _, m := range mappings {
+		endpoints := []struct {
+			elementType string
+			name        string
+		}{
+			{m.LocalElementType(), m.LocalHost},
+			{m.RemoteElementType(), m.RemoteHost},
+		}
+
+		for _, e := range endpoints {
+			switch e.elementType {
+			case zbxmap.ElementHostGroup:
+				groups[e.name] = ""
+			case zbxmap.ElementImage:
+				images[e.name] = ""
+			case zbxmap.ElementMap:
+				return nil, failure.Errorf(failure.Validation, "the map '%s' cannot be used as map element with a snapshot", e.name)
+			default:
+				hosts[e.name] = ""
+			}
+		}
+
+		images[m.LocalImage] = ""
+		images[m.RemoteImage] = ""
+	}
+
+	s := &Snapshot{
+		Date:       time.Now().UTC().Format(time.RFC3339),
+		Triggers:   make(map[string][]*api.Trigger, 0),
+		Items:      make(map[string][]*api.Item, 0),
+		HostGroups: make(map[string][]*api.HostGroup, 0),
+	}
+
+	resolutions, err := api.ResolveHosts(client, utils.GetMapKey(hosts), options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) > 0 {
+		s.Groups, err = client.GetHostGroupsByName(utils.GetMapKey(groups))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]string, 0)
+	addresses := make([]string, 0)
+	for _, r := range resolutions {
+		if r.Id != "" && !utils.Contains(ids, r.Id) {
+			ids = append(ids, r.Id)
+		}
+
+		if r.Strategy == api.StrategyInterface {
+			addresses = append(addresses, r.Lookup)
+		}
+	}
+
+	s.Hosts = make([]*api.Host, 0)
+	if len(ids) > 0 {
+		s.Hosts, err = client.GetHostsById(ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(addresses) > 0 {
+		s.Interfaces, err = client.GetHostInterfaces(addresses)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	i, err := client.GetImages(utils.GetMapKey(images))
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve the content of each image to be able to render the map from the snapshot
+	imagesId := make([]string, 0)
+	for _, image := range i {
+		imagesId = append(imagesId, image.Id)
+	}
+
+	s.Images, err = client.GetImagesData(imagesId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range s.Hosts {
+		s.Triggers[host.Id], err = client.GetTriggers(host.Id, "")
+		if err != nil {
+			return nil, err
+		}
+
+		s.Items[host.Id], err = client.GetItems(host.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		// The host groups are retrieved with the hosts and stored separately to keep the format of the snapshot
+		s.HostGroups[host.Id] = host.HostGroups
+		host.HostGroups = nil
+	}
+
+	return s, nil
+}
+
+// Load is used to read a snapshot from the given file.
+func Load(file string) (*Snapshot, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, failure.New(failure.Config, err)
+	}
+
+	s := &Snapshot{}
+	err = json.Unmarshal(b, s)
+	if err != nil {
+		return nil, failure.Errorf(failure.Config, "error while reading snapshot '%s'.\nReason : %v", file, err)
+	}
+
+	if s.Triggers == nil {
+		s.Triggers = make(map[string][]*api.Trigger, 0)
+	}
+
+	if s.Items == nil {
+		s.Items = make(map[string][]*api.Item, 0)
+	}
+
+	if s.HostGroups == nil {
+		s.HostGroups = make(map[string][]*api.HostGroup, 0)
+	}
+
+	return s, nil
+}
+
+// Write is used to write the snapshot to the given file.
+func (s *Snapshot) Write(file string) error {
+	if file == "" {
+		return fmt.Errorf("file name cannot be empty")
+	}
+
+	b, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, b, 0644)
+}
+
+// GetHosts is used to retrieve the hosts matching the given technical names.
+func (s *Snapshot) GetHosts(names []string) ([]*api.Host, error) {
+	out := make([]*api.Host, 0)
+
+	for _, host := range s.Hosts {
+		if utils.Contains(names, host.Host) {
+			out = append(out, host)
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostsById is used to retrieve the hosts matching the given ids.
+func (s *Snapshot) GetHostsById(ids []string) ([]*api.Host, error) {
+	out := make([]*api.Host, 0)
+
+	for _, host := range s.Hosts {
+		if utils.Contains(ids, host.Id) {
+			h := *host
+			h.HostGroups = append(make([]*api.HostGroup, 0), s.HostGroups[host.Id]...)
+			out = append(out, &h)
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostsByName is used to retrieve the hosts matching the given visible names.
+func (s *Snapshot) GetHostsByName(names []string) ([]*api.Host, error) {
+	out := make([]*api.Host, 0)
+
+	for _, host := range s.Hosts {
+		if host.Name != "" && utils.Contains(names, host.Name) {
+			out = append(out, host)
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostsByTag is used to retrieve the hosts with the given tag set to one of the given values.
+func (s *Snapshot) GetHostsByTag(tag string, values []string) ([]*api.Host, error) {
+	out := make([]*api.Host, 0)
+
+	for _, host := range s.Hosts {
+		for _, t := range host.Tags {
+			if t.Tag == tag && utils.Contains(values, t.Value) {
+				out = append(out, host)
+				break
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostInterfaces is used to retrieve the host interfaces with an IP address or a DNS name matching one of the given addresses.
+func (s *Snapshot) GetHostInterfaces(addresses []string) ([]*api.HostInterface, error) {
+	out := make([]*api.HostInterface, 0)
+
+	for _, i := range s.Interfaces {
+		if utils.Contains(addresses, i.Ip) || (i.Dns != "" && utils.Contains(addresses, i.Dns)) {
+			out = append(out, i)
+		}
+	}
+
+	return out, nil
+}
+
+// GetImages is used to retrieve the images matching the given names.
+func (s *Snapshot) GetImages(names []string) ([]*api.Image, error) {
+	out := make([]*api.Image, 0)
+
+	for _, image := range s.Images {
+		if utils.Contains(names, image.Name) {
+			out = append(out, image)
+		}
+	}
+
+	return out, nil
+}
+
+// GetImagesData is used to retrieve the images matching the given ids, including the content of each image.
+func (s *Snapshot) GetImagesData(ids []string) ([]*api.Image, error) {
+	out := make([]*api.Image, 0)
+
+	for _, image := range s.Images {
+		if utils.Contains(ids, image.Id) {
+			out = append(out, image)
+		}
+	}
+
+	return out, nil
+}
+
+// GetTriggers is used to retrieve the triggers of the given host matching the given description.
+// If the description is empty, all the triggers of the host are returned.
+func (s *Snapshot) GetTriggers(hostId string, description string) ([]*api.Trigger, error) {
+	out := make([]*api.Trigger, 0)
+
+	for _, trigger := range s.Triggers[hostId] {
+		if description == "" || trigger.Description == description {
+			out = append(out, trigger)
+		}
+	}
+
+	return out, nil
+}
+
+// GetTriggersById is used to retrieve the triggers matching the given ids, including the host of each trigger.
+func (s *Snapshot) GetTriggersById(ids []string) ([]*api.Trigger, error) {
+	out := make([]*api.Trigger, 0)
+
+	for _, host := range s.Hosts {
+		for _, trigger := range s.Triggers[host.Id] {
+			if utils.Contains(ids, trigger.Id) {
+				out = append(out, &api.Trigger{
+					Id:          trigger.Id,
+					Description: trigger.Description,
+					Hosts: []*api.Host{
+						{Id: host.Id, Host: host.Host},
+					},
+				})
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// GetItems is used to retrieve the items of the given host.
+func (s *Snapshot) GetItems(hostId string) ([]*api.Item, error) {
+	out := make([]*api.Item, 0)
+	out = append(out, s.Items[hostId]...)
+
+	return out, nil
+}
+
+// GetHostGroups is used to retrieve the host groups of the given host.
+func (s *Snapshot) GetHostGroups(hostId string) ([]*api.HostGroup, error) {
+	out := make([]*api.HostGroup, 0)
+	out = append(out, s.HostGroups[hostId]...)
+
+	return out, nil
+}
+
+// groups is used to retrieve all the host groups stored in the snapshot.
+func (s *Snapshot) groups() []*api.HostGroup {
+	out := make([]*api.HostGroup, 0)
+	out = append(out, s.Groups...)
+
+	for _, g := range s.HostGroups {
+		out = append(out, g...)
+	}
+
+	return out
+}
+
+// GetHostGroupsByName is used to retrieve the host groups matching the given names.
+func (s *Snapshot) GetHostGroupsByName(names []string) ([]*api.HostGroup, error) {
+	out := make([]*api.HostGroup, 0)
+	ids := make([]string, 0)
+
+	for _, g := range s.groups() {
+		if utils.Contains(names, g.Name) && !utils.Contains(ids, g.Id) {
+			ids = append(ids, g.Id)
+			out = append(out, g)
+		}
+	}
+
+	return out, nil
+}
+
+// GetHostGroupsById is used to retrieve the host groups matching the given ids.
+func (s *Snapshot) GetHostGroupsById(ids []string) ([]*api.HostGroup, error) {
+	out := make([]*api.HostGroup, 0)
+	found := make([]string, 0)
+
+	for _, g := range s.groups() {
+		if utils.Contains(ids, g.Id) && !utils.Contains(found, g.Id) {
+			found = append(found, g.Id)
+			out = append(out, g)
+		}
+	}
+
+	return out, nil
+}
+
+// GetMaps always returns an error, maps are not stored in a snapshot.
+func (s *Snapshot) GetMaps(names []string) ([]*api.Map, error) {
+	return nil, fmt.Errorf("maps cannot be retrieved from a snapshot")
+}
+
+// GetMapsById always returns an error, maps are not stored in a snapshot.
+func (s *Snapshot) GetMapsById(ids []string) ([]*api.Map, error) {
+	return nil, fmt.Errorf("maps cannot be retrieved from a snapshot")
+}
+
+// SearchMaps always returns an error, maps are not stored in a snapshot.
+func (s *Snapshot) SearchMaps(prefix string) ([]*api.Map, error) {
+	return nil, fmt.Errorf("maps cannot be retrieved from a snapshot")
+}
+
+// DeleteMaps always returns an error, a map cannot be deleted from a snapshot.
+func (s *Snapshot) DeleteMaps(ids []string) error {
+	return fmt.Errorf("a map cannot be deleted on the server when using a snapshot")
+}
+
+// CreateImage always returns an error, an image cannot be created from a snapshot.
+func (s *Snapshot) CreateImage(name string, data string) (string, error) {
+	return "", fmt.Errorf("an image cannot be created on the server when using a snapshot")
+}
+
+// UpdateImage always returns an error, an image cannot be updated from a snapshot.
+func (s *Snapshot) UpdateImage(id string, data string) error {
+	return fmt.Errorf("an image cannot be updated on the server when using a snapshot")
+}
+
+// CreateMap always returns an error, a map cannot be created from a snapshot.
+func (s *Snapshot) CreateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error) {
+	return nil, fmt.Errorf("a map cannot be created on the server when using a snapshot")
+}
+
+// UpdateMap always returns an error, a map cannot be updated from a snapshot.
+func (s *Snapshot) UpdateMap(m *zabbixgosdk.MapCreateParameters) ([]string, error) {
+	return nil, fmt.Errorf("a map cannot be updated on the server when using a snapshot")
+}
+
+// GetUsers always returns an error, users are not stored in a snapshot.
+func (s *Snapshot) GetUsers(names []string) ([]*api.User, error) {
+	return nil, fmt.Errorf("users cannot be retrieved from a snapshot")
+}
+
+// GetUserGroups always returns an error, user groups are not stored in a snapshot.
+func (s *Snapshot) GetUserGroups(names []string) ([]*api.UserGroup, error) {
+	return nil, fmt.Errorf("user groups cannot be retrieved from a snapshot")
+}
+
+// Logout does nothing, no token is used with a snapshot.
+func (s *Snapshot) Logout() error {
+	return nil
+}
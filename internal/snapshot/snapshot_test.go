@@ -0,0 +1,245 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	zabbixgosdk "github.com/Spartan0nix/zabbix-go-sdk/v2"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/api"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/failure"
+	"github.com/Spartan0nix/zabbix-map-builder-go/internal/fake"
+	zbxmap "github.com/Spartan0nix/zabbix-map-builder-go/internal/map"
+)
+
+// Ensure a snapshot can be used in place of the SDK backed client.
+var _ api.ZabbixAPI = (*Snapshot)(nil)
+
+// newTestingSnapshot is used to create a snapshot from a fake client.
+func newTestingSnapshot(t *testing.T) *Snapshot {
+	client := fake.NewClient().
+		AddHost("1", "router-1").
+		AddHost("2", "router-2").
+		AddHost("3", "router-3").
+		AddImage("11", "Firewall_(64)").
+		AddImage("12", "Switch_(64)").
+		AddTrigger("1", "21", "Interface eth0(): Link down").
+		AddTrigger("1", "22", "Interface eth1(): Link down").
+		AddTrigger("2", "23", "Interface eth0(): Link down").
+		AddItem("1", "31", "Interface eth0(): Operational status", "net.if.status[ifOperStatus.1]").
+		AddHostGroup("1", "41", "Routers")
+
+	s, err := Create(client, []*zbxmap.Mapping{
+		{
+			LocalHost:   "router-1",
+			LocalImage:  "Firewall_(64)",
+			RemoteHost:  "router-2",
+			RemoteImage: "Switch_(64)",
+		},
+	}, nil)
+
+	if err != nil {
+		t.Fatalf("error while executing Create function.\nReason : %v", err)
+	}
+
+	return s
+}
+
+func TestCreate(t *testing.T) {
+	s := newTestingSnapshot(t)
+
+	if len(s.Hosts) != 2 {
+		t.Fatalf("wrong number of hosts stored.\nExpected : 2\nReturned : %d", len(s.Hosts))
+	}
+
+	if len(s.Images) != 2 {
+		t.Fatalf("wrong number of images stored.\nExpected : 2\nReturned : %d", len(s.Images))
+	}
+
+	if len(s.Triggers["1"]) != 2 {
+		t.Fatalf("wrong number of triggers stored for host '1'.\nExpected : 2\nReturned : %d", len(s.Triggers["1"]))
+	}
+
+	if len(s.Items["1"]) != 1 {
+		t.Fatalf("wrong number of items stored for host '1'.\nExpected : 1\nReturned : %d", len(s.Items["1"]))
+	}
+
+	if len(s.HostGroups["1"]) != 1 {
+		t.Fatalf("wrong number of host groups stored for host '1'.\nExpected : 1\nReturned : %d", len(s.HostGroups["1"]))
+	}
+
+	if s.Date == "" {
+		t.Fatal("no date was set for the snapshot")
+	}
+}
+
+func TestCreateElementTypes(t *testing.T) {
+	client := fake.NewClient().
+		AddHost("1", "router-1").
+		AddImage("11", "Firewall_(64)").
+		AddImage("13", "Cloud_(24)").
+		AddHostGroup("1", "41", "Routers")
+
+	s, err := Create(client, []*zbxmap.Mapping{
+		{
+			LocalHost:   "router-1",
+			LocalImage:  "Firewall_(64)",
+			RemoteHost:  "Routers",
+			RemoteType:  zbxmap.ElementHostGroup,
+			RemoteImage: "Cloud_(24)",
+		},
+	}, nil)
+
+	if err != nil {
+		t.Fatalf("error while executing Create function.\nReason : %v", err)
+	}
+
+	if len(s.Hosts) != 1 {
+		t.Fatalf("wrong number of hosts stored.\nExpected : 1\nReturned : %d", len(s.Hosts))
+	}
+
+	groups, err := s.GetHostGroupsByName([]string{"Routers"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroupsByName function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Id != "41" {
+		t.Fatalf("wrong host groups returned.\nExpected : [41]\nReturned : %v", groups)
+	}
+
+	groups, err = s.GetHostGroupsById([]string{"41"})
+	if err != nil {
+		t.Fatalf("error while executing GetHostGroupsById function.\nReason : %v", err)
+	}
+
+	if len(groups) != 1 || groups[0].Name != "Routers" {
+		t.Fatalf("wrong host groups returned.\nExpected : [Routers]\nReturned : %v", groups)
+	}
+
+	_, err = Create(client, []*zbxmap.Mapping{
+		{
+			LocalHost:  "router-1",
+			RemoteHost: "Datacenter",
+			RemoteType: zbxmap.ElementMap,
+		},
+	}, nil)
+
+	if err == nil {
+		t.Fatal("an error should be returned when a map is used as element")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Validation {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Validation, kind)
+	}
+}
+
+func TestCreateResolveOptions(t *testing.T) {
+	client := fake.NewClient().
+		AddHost("1", "router-1").
+		AddHost("2", "router-2").
+		AddHostInterface("1", "10.0.0.1", "").
+		AddHostTag("2", "sysName", "rt2").
+		AddImage("11", "Firewall_(64)")
+
+	options := &api.ResolveOptions{
+		Strategies: []string{api.StrategyHost, api.StrategyInterface, api.StrategyTag},
+		Tag:        "sysName",
+	}
+
+	s, err := Create(client, []*zbxmap.Mapping{
+		{
+			LocalHost:   "10.0.0.1",
+			LocalImage:  "Firewall_(64)",
+			RemoteHost:  "rt2",
+			RemoteImage: "Firewall_(64)",
+		},
+	}, options)
+
+	if err != nil {
+		t.Fatalf("error while executing Create function.\nReason : %v", err)
+	}
+
+	if len(s.Hosts) != 2 {
+		t.Fatalf("wrong number of hosts stored.\nExpected : 2\nReturned : %d", len(s.Hosts))
+	}
+
+	if len(s.Interfaces) != 1 {
+		t.Fatalf("wrong number of interfaces stored.\nExpected : 1\nReturned : %d", len(s.Interfaces))
+	}
+
+	// The hosts should be resolved the same way from the snapshot
+	resolutions, err := api.ResolveHosts(s, []string{"10.0.0.1", "rt2"}, options)
+	if err != nil {
+		t.Fatalf("error while executing ResolveHosts function.\nReason : %v", err)
+	}
+
+	if resolutions["10.0.0.1"].Id != "1" || resolutions["rt2"].Id != "2" {
+		t.Fatalf("wrong hosts resolved from the snapshot.\nReturned : %+v %+v", resolutions["10.0.0.1"], resolutions["rt2"])
+	}
+}
+
+func TestWriteLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+
+	err := newTestingSnapshot(t).Write(file)
+	if err != nil {
+		t.Fatalf("error while executing Write function.\nReason : %v", err)
+	}
+
+	s, err := Load(file)
+	if err != nil {
+		t.Fatalf("error while executing Load function.\nReason : %v", err)
+	}
+
+	triggers, err := s.GetTriggers("1", "Interface eth1(): Link down")
+	if err != nil {
+		t.Fatalf("error while executing GetTriggers function.\nReason : %v", err)
+	}
+
+	if len(triggers) != 1 || triggers[0].Id != "22" {
+		t.Fatalf("wrong triggers returned.\nReturned : %v", triggers)
+	}
+
+	hosts, err := s.GetHosts([]string{"router-2"})
+	if err != nil {
+		t.Fatalf("error while executing GetHosts function.\nReason : %v", err)
+	}
+
+	if len(hosts) != 1 || hosts[0].Id != "2" {
+		t.Fatalf("wrong hosts returned.\nReturned : %v", hosts)
+	}
+}
+
+func TestWriteEmptyName(t *testing.T) {
+	err := newTestingSnapshot(t).Write("")
+	if err == nil {
+		t.Fatal("an error should be returned when an empty file name is passed to the Write function")
+	}
+}
+
+func TestLoadWrongFormat(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(file, []byte("[]"), 0644); err != nil {
+		t.Fatalf("error while writing test data to file '%s'.\nReason : %v", file, err)
+	}
+
+	s, err := Load(file)
+	if err == nil {
+		t.Fatal("an error should be returned when the snapshot is not in the expected format")
+	}
+
+	if s != nil {
+		t.Fatal("a nil pointer should be returned instead of *Snapshot when the processing fails")
+	}
+
+	if kind := failure.KindOf(err); kind != failure.Config {
+		t.Fatalf("wrong kind of error returned.\nExpected : %s\nReturned : %s", failure.Config, kind)
+	}
+}
+
+func TestCreateMap(t *testing.T) {
+	_, err := newTestingSnapshot(t).CreateMap(&zabbixgosdk.MapCreateParameters{})
+	if err == nil {
+		t.Fatal("an error should be returned when creating a map from a snapshot")
+	}
+}
@@ -10,3 +10,14 @@ func GetMapKey(m map[string]string) []string {
 
 	return out
 }
+
+// Contains is used to check if the given value is present in a list of string.
+func Contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
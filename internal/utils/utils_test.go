@@ -22,3 +22,15 @@ func TestGetMapKey(t *testing.T) {
 		t.Fatalf("failed to find key 'key2'.\nReturned : %v", out)
 	}
 }
+
+func TestContains(t *testing.T) {
+	list := []string{"value1", "value2"}
+
+	if !Contains(list, "value2") {
+		t.Fatalf("expected 'value2' to be found in the list.\nList : %v", list)
+	}
+
+	if Contains(list, "value3") {
+		t.Fatalf("expected 'value3' not to be found in the list.\nList : %v", list)
+	}
+}